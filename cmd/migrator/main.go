@@ -0,0 +1,553 @@
+// Command migrator runs migrations without the web UI. It uses the same database,
+// providers and job queue as the HTTP server, configured from the same environment.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/app"
+	"github.com/TWRT/integration-mapper/internal/repository"
+	"github.com/TWRT/integration-mapper/internal/service"
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage: migrator [-db path] [-json] <command> [arguments]
+
+commands:
+  providers                              list the registered providers
+  workspaces [-connection id] <provider>
+                                         list the workspaces of a provider
+  projects [-connection id] <provider> <workspace-id>
+                                         list the projects of a workspace
+  list                                   list migrations
+  create [flags]                         create a migration and discover its mappings
+  import-mappings <id> <file>            apply a JSON or YAML mapping document
+  validate <id>                          report what keeps a migration from starting
+  dry-run <id>                           count the tasks a run would migrate
+  start [-detach] <id>                   queue a migration and run it until it finishes
+  tail <id>                              follow the progress of a migration
+  users                                  list API users
+  create-user -name n -email e [-admin]  create an API user and print its API key
+  connections -user <id>                 list the OAuth connections of a user
+
+Without -detach, start runs the migration in this process once a slot is free, unless a
+server picks it up first. Interrupting start puts the migration back in the queue.
+`
+
+// errUsage reports a malformed command line; it exits with status 2.
+var errUsage = errors.New("invalid usage")
+
+// errNotOK makes a command exit with status 1 after printing its result, e.g. a
+// migration that is not ready or that failed.
+var errNotOK = errors.New("not ok")
+
+type cli struct {
+	svcs   *app.Services
+	cfg    app.Config
+	json   bool
+	stdout io.Writer
+}
+
+func main() {
+	// Unlike the server, a missing .env is fine: scripts usually set the environment.
+	_ = godotenv.Load()
+
+	flags := flag.NewFlagSet("migrator", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	dbPath := flags.String("db", "", "SQLite database (default $DB_PATH or "+app.DefaultDBPath+")")
+	jsonOut := flags.Bool("json", false, "print JSON instead of text")
+	flags.Parse(os.Args[1:])
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := app.ConfigFromEnv()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrator: invalid configuration:", err)
+		os.Exit(1)
+	}
+	if *dbPath != "" {
+		cfg.DBPath = *dbPath
+	}
+	db, err := repository.InitDB(cfg.DBPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrator: failed to initialize database:", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c := &cli{svcs: app.NewServices(db, cfg), cfg: cfg, json: *jsonOut, stdout: os.Stdout}
+	err = c.run(ctx, flags.Arg(0), flags.Args()[1:])
+	switch {
+	case err == nil:
+	case errors.Is(err, errNotOK):
+		os.Exit(1)
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(os.Stderr, "migrator:", err)
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, "migrator:", err)
+		os.Exit(1)
+	}
+}
+
+func (c *cli) run(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "providers":
+		return c.providers(args)
+	case "workspaces":
+		return c.workspaces(ctx, args)
+	case "projects":
+		return c.projects(ctx, args)
+	case "list":
+		return c.list(args)
+	case "create":
+		return c.create(ctx, args)
+	case "import-mappings":
+		return c.importMappings(ctx, args)
+	case "validate":
+		return c.validate(args)
+	case "dry-run":
+		return c.dryRun(ctx, args)
+	case "start":
+		return c.start(ctx, args)
+	case "tail":
+		return c.tail(ctx, args)
+	case "users":
+		return c.users(args)
+	case "create-user":
+		return c.createUser(args)
+	case "connections":
+		return c.connections(args)
+	}
+	return fmt.Errorf("%w: unknown command %q", errUsage, command)
+}
+
+// ---- Users ----
+
+func (c *cli) users(args []string) error {
+	if _, err := parseFlags(flag.NewFlagSet("users", flag.ContinueOnError), args, 0); err != nil {
+		return err
+	}
+	users, err := c.svcs.Users.GetUsers()
+	if err != nil {
+		return err
+	}
+	return c.print(users, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tEMAIL\tROLE")
+		for _, u := range users {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", u.ID, u.Name, u.Email, u.Role)
+		}
+	})
+}
+
+// createUser is also how the first admin is created, since the API only lets admins
+// create users.
+func (c *cli) createUser(args []string) error {
+	fs := flag.NewFlagSet("create-user", flag.ContinueOnError)
+	var input service.CreateUserInput
+	fs.StringVar(&input.Name, "name", "", "user name")
+	fs.StringVar(&input.Email, "email", "", "user email")
+	fs.BoolVar(&input.Admin, "admin", false, "let the user see every migration and manage users")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	user, apiKey, err := c.svcs.Users.CreateUser(input)
+	if err != nil {
+		return err
+	}
+	return c.print(map[string]any{"user": user, "api_key": apiKey}, func(w io.Writer) {
+		fmt.Fprintf(w, "created %s %d (%s)\n", user.Role, user.ID, user.Email)
+		fmt.Fprintf(w, "API key: %s\n", apiKey)
+		fmt.Fprintln(w, "the key is not stored and cannot be shown again")
+	})
+}
+
+// connections lists a user's OAuth connections. Connections are made in the browser, through
+// the server's /connections/{provider}/authorize route.
+func (c *cli) connections(args []string) error {
+	fs := flag.NewFlagSet("connections", flag.ContinueOnError)
+	userID := fs.Int64("user", 0, "user ID")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if *userID == 0 {
+		return fmt.Errorf("%w: connections: -user is required", errUsage)
+	}
+	connections, err := c.svcs.Connections.GetConnections(repository.User{ID: *userID})
+	if err != nil {
+		return err
+	}
+	return c.print(connections, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tPROVIDER\tACCOUNT\tEXPIRES")
+		for _, conn := range connections {
+			expires := "never"
+			if conn.ExpiresAt != nil {
+				expires = conn.ExpiresAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", conn.ID, conn.Provider, conn.AccountName, expires)
+		}
+	})
+}
+
+// ---- Output ----
+
+// print writes v as JSON with -json, and otherwise calls text to write it as text.
+func (c *cli) print(v any, text func(w io.Writer)) error {
+	if c.json {
+		enc := json.NewEncoder(c.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	text(tw)
+	return tw.Flush()
+}
+
+func parseFlags(fs *flag.FlagSet, args []string, positional int) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", errUsage, fs.Name(), err)
+	}
+	if fs.NArg() != positional {
+		return nil, fmt.Errorf("%w: %s takes %d argument(s)", errUsage, fs.Name(), positional)
+	}
+	return fs.Args(), nil
+}
+
+func parseID(s string) (int64, error) {
+	var id int64
+	if _, err := fmt.Sscan(s, &id); err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: invalid migration id %q", errUsage, s)
+	}
+	return id, nil
+}
+
+// optionalID turns the value of an ID flag into the nil-able ID the services take; 0
+// means the flag was not set.
+func optionalID(id int64) *int64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+// operatorAccess is how the CLI reaches a provider: it runs as the server's operator, so
+// it may use the server's credentials when no connection is given.
+func operatorAccess(connectionID int64) service.ProviderAccess {
+	return service.ProviderAccess{ConnectionID: optionalID(connectionID), ServerCredentials: true}
+}
+
+// ---- Browsing ----
+
+func (c *cli) providers(args []string) error {
+	if _, err := parseFlags(flag.NewFlagSet("providers", flag.ContinueOnError), args, 0); err != nil {
+		return err
+	}
+	providers := c.svcs.ProviderCatalog.ListProviders()
+	return c.print(providers, func(w io.Writer) {
+		fmt.Fprintln(w, "NAME\tDISPLAY NAME\tCAPABILITIES")
+		for _, p := range providers {
+			fmt.Fprintf(w, "%s\t%s\t%v\n", p.Name, p.DisplayName, p.Capabilities)
+		}
+	})
+}
+
+func (c *cli) workspaces(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("workspaces", flag.ContinueOnError)
+	connection := fs.Int64("connection", 0, "ID of the OAuth connection to browse as")
+	pos, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	workspaces, err := c.svcs.ProviderCatalog.ListWorkspaces(ctx, pos[0], operatorAccess(*connection))
+	if err != nil {
+		return err
+	}
+	return c.print(workspaces, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME")
+		for _, ws := range workspaces {
+			fmt.Fprintf(w, "%s\t%s\n", ws.ID, ws.Name)
+		}
+	})
+}
+
+func (c *cli) projects(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("projects", flag.ContinueOnError)
+	connection := fs.Int64("connection", 0, "ID of the OAuth connection to browse as")
+	pos, err := parseFlags(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	projects, err := c.svcs.ProviderCatalog.ListProjects(ctx, pos[0], operatorAccess(*connection), pos[1])
+	if err != nil {
+		return err
+	}
+	return c.print(projects, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME")
+		for _, p := range projects {
+			fmt.Fprintf(w, "%s\t%s\n", p.ID, p.Name)
+		}
+	})
+}
+
+// ---- Migrations ----
+
+func (c *cli) list(args []string) error {
+	if _, err := parseFlags(flag.NewFlagSet("list", flag.ContinueOnError), args, 0); err != nil {
+		return err
+	}
+	migrations, err := c.svcs.Migrations.GetMigrations()
+	if err != nil {
+		return err
+	}
+	return c.print(migrations, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tSOURCE\tDESTINATION\tPROJECT\tSTATUS\tTASKS")
+		for _, m := range migrations {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d/%d\n", m.ID, m.Source, m.Destination, m.SourceProjectID, m.Status, m.CompletedTasks, m.TotalTasks)
+		}
+	})
+}
+
+func (c *cli) create(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	var input service.CreateMigrationInput
+	fs.StringVar(&input.Source, "source", "asana", "source provider")
+	fs.StringVar(&input.Destination, "destination", "clickup", "destination provider")
+	fs.StringVar(&input.SourceProjectID, "source-project", "", "source project ID")
+	fs.StringVar(&input.DestListID, "dest-list", "", "destination list or project ID")
+	fs.StringVar(&input.DestWorkspaceID, "dest-workspace", "", "destination workspace ID")
+	fs.StringVar(&input.DestSpaceID, "dest-space", "", "destination space ID")
+	fs.StringVar(&input.TimeZone, "time-zone", "", "IANA time zone for all-day dates")
+	fs.StringVar(&input.ContainerStrategy, "container-strategy", "", `"list" or "status"`)
+	owner := fs.Int64("owner", 0, "ID of the user owning the migration; only admins see it without one")
+	sourceConnection := fs.Int64("source-connection", 0, "ID of the owner's OAuth connection to the source")
+	destConnection := fs.Int64("dest-connection", 0, "ID of the owner's OAuth connection to the destination")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	input.OwnerID = optionalID(*owner)
+	input.SourceConnectionID = optionalID(*sourceConnection)
+	input.DestConnectionID = optionalID(*destConnection)
+	input.ServerCredentials = true
+
+	id, state, err := c.svcs.Migrations.CreateMigration(ctx, input)
+	if err != nil {
+		return err
+	}
+	return c.print(map[string]any{"migration_id": id, "mappings": state}, func(w io.Writer) {
+		fmt.Fprintf(w, "created migration %d\n", id)
+		fmt.Fprintf(w, "%d assignees, %d tags, %d containers discovered\n", len(state.Assignees), len(state.Tags), len(state.ContainerMappings))
+	})
+}
+
+func (c *cli) importMappings(ctx context.Context, args []string) error {
+	pos, err := parseFlags(flag.NewFlagSet("import-mappings", flag.ContinueOnError), args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(pos[0])
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(pos[1])
+	if err != nil {
+		return err
+	}
+
+	_, changes, unmatched, err := c.svcs.Migrations.ImportMappings(ctx, id, data)
+	if err != nil {
+		return err
+	}
+	return c.print(map[string]any{"changes": changes, "unmatched": unmatched}, func(w io.Writer) {
+		fmt.Fprintf(w, "%d mappings changed, %d entries matched nothing\n", len(changes), len(unmatched))
+		for _, ch := range changes {
+			fmt.Fprintf(w, "%+v\n", ch)
+		}
+		for _, u := range unmatched {
+			fmt.Fprintf(w, "unmatched: %+v\n", u)
+		}
+	})
+}
+
+func (c *cli) validate(args []string) error {
+	pos, err := parseFlags(flag.NewFlagSet("validate", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(pos[0])
+	if err != nil {
+		return err
+	}
+	report, err := c.svcs.Migrations.ValidateMigration(id)
+	if err != nil {
+		return err
+	}
+	if err := c.print(report, func(w io.Writer) {
+		if report.Ready {
+			fmt.Fprintf(w, "migration %d is ready to start\n", id)
+		}
+		for _, p := range report.Problems {
+			fmt.Fprintln(w, p)
+		}
+	}); err != nil {
+		return err
+	}
+	if !report.Ready {
+		return errNotOK
+	}
+	return nil
+}
+
+func (c *cli) dryRun(ctx context.Context, args []string) error {
+	pos, err := parseFlags(flag.NewFlagSet("dry-run", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(pos[0])
+	if err != nil {
+		return err
+	}
+	report, err := c.svcs.Migrations.DryRunMigration(ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.print(report, func(w io.Writer) {
+		if len(report.Containers) > 0 {
+			fmt.Fprintln(w, "SOURCE\tDESTINATION\tTASKS\tEXCLUDED\tALREADY MIGRATED")
+			for _, ct := range report.Containers {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\n", ct.SourceName, ct.DestID, ct.Tasks, ct.Excluded, ct.AlreadyMigrated)
+			}
+		}
+		fmt.Fprintf(w, "total\t\t%d\t%d\t%d\n", report.Tasks, report.Excluded, report.AlreadyMigrated)
+	})
+}
+
+func (c *cli) start(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("start", flag.ContinueOnError)
+	detach := fs.Bool("detach", false, "only queue the migration, for a running server to pick up")
+	pos, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(pos[0])
+	if err != nil {
+		return err
+	}
+
+	if err := c.svcs.Migrations.StartMigration(id); err != nil {
+		return err
+	}
+	if *detach {
+		job, err := c.svcs.Migrations.GetMigrationJob(id)
+		if err != nil {
+			return err
+		}
+		return c.print(job, func(w io.Writer) {
+			fmt.Fprintf(w, "migration %d queued at position %d\n", id, job.QueuePosition)
+		})
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	ran := make(chan struct{})
+	go func() {
+		defer close(ran)
+		c.svcs.Migrations.RunMigration(workerCtx, id, c.cfg.MaxConcurrentMigrations)
+	}()
+	err = c.follow(ctx, id)
+	cancel()
+	<-ran
+	return err
+}
+
+func (c *cli) tail(ctx context.Context, args []string) error {
+	pos, err := parseFlags(flag.NewFlagSet("tail", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(pos[0])
+	if err != nil {
+		return err
+	}
+	return c.follow(ctx, id)
+}
+
+// progress is one line of followed progress; with -json each is printed as one JSON
+// object per line.
+type progress struct {
+	MigrationID    int64                      `json:"migration_id"`
+	Status         repository.MigrationStatus `json:"status"`
+	QueuePosition  int                        `json:"queue_position,omitempty"`
+	TotalTasks     int                        `json:"total_tasks"`
+	CompletedTasks int                        `json:"completed_tasks"`
+	FailedTasks    int                        `json:"failed_tasks"`
+}
+
+const followInterval = 2 * time.Second
+
+// follow prints the progress of a migration whenever it changes, until the migration is
+// no longer queued or running.
+func (c *cli) follow(ctx context.Context, id int64) error {
+	var last progress
+	for {
+		m, err := c.svcs.Migrations.GetMigration(id)
+		if err != nil {
+			return err
+		}
+		p := progress{MigrationID: id, Status: m.Status, TotalTasks: m.TotalTasks, CompletedTasks: m.CompletedTasks, FailedTasks: m.FailedTasks}
+		if m.Status == repository.MigrationStatusQueued {
+			job, err := c.svcs.Migrations.GetMigrationJob(id)
+			if err != nil {
+				return err
+			}
+			if job != nil {
+				p.QueuePosition = job.QueuePosition
+			}
+		}
+
+		if p != last {
+			if c.json {
+				if err := json.NewEncoder(c.stdout).Encode(p); err != nil {
+					return err
+				}
+			} else {
+				line := fmt.Sprintf("%s  %d/%d tasks, %d failed", p.Status, p.CompletedTasks, p.TotalTasks, p.FailedTasks)
+				if p.QueuePosition > 0 {
+					line = fmt.Sprintf("%s  queue position %d", p.Status, p.QueuePosition)
+				}
+				fmt.Fprintln(c.stdout, time.Now().Format(time.TimeOnly), line)
+			}
+			last = p
+		}
+
+		switch m.Status {
+		case repository.MigrationStatusQueued, repository.MigrationStatusRunning:
+		case repository.MigrationStatusFailed:
+			return errNotOK
+		default:
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(followInterval):
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/TWRT/integration-mapper/internal/repository"
+	"github.com/TWRT/integration-mapper/internal/service"
+)
+
+type ArchiveHandler struct {
+	archiveService service.ArchiveServiceProvider
+}
+
+func NewArchiveHandler(archiveService service.ArchiveServiceProvider) *ArchiveHandler {
+	return &ArchiveHandler{archiveService: archiveService}
+}
+
+type CreateArchiveRequestBody struct {
+	Provider    string `json:"provider"`
+	WorkspaceId string `json:"workspace_id"`
+	ProjectId   string `json:"project_id"`
+	// ConnectionID is a connection of the caller to read the project through; only admins
+	// may omit it and use the server's credentials.
+	ConnectionID *int64 `json:"connection_id"`
+}
+
+func (h *ArchiveHandler) CreateArchive(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(w, r)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		} else {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+		}
+		return
+	}
+
+	var req CreateArchiveRequestBody
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request format")
+		return
+	}
+
+	user := currentUser(r)
+	id, err := h.archiveService.StartExport(service.ArchiveExportInput{
+		Provider:    req.Provider,
+		WorkspaceID: req.WorkspaceId,
+		ProjectID:   req.ProjectId,
+		OwnerID:     &user.ID,
+
+		ConnectionID:      req.ConnectionID,
+		ServerCredentials: user.IsAdmin(),
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.Error("failed to start archive export", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to start archive export")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"archive_id": id,
+		"status":     repository.ArchiveExportStatusRunning,
+	})
+}
+
+func (h *ArchiveHandler) GetArchive(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid archive id")
+		return
+	}
+
+	export, err := h.archiveService.GetExport(id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "archive export not found")
+			return
+		}
+		slog.Error("failed to get archive export", "archive_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to get archive export")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"archive": export})
+}
+
+func (h *ArchiveHandler) ListArchives(w http.ResponseWriter, r *http.Request) {
+	exports, err := h.archiveService.GetExportsFor(currentUser(r))
+	if err != nil {
+		slog.Error("failed to list archive exports", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to list archive exports")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"archives": exports})
+}
+
+// DownloadArchive streams the zip file of a completed export.
+func (h *ArchiveHandler) DownloadArchive(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid archive id")
+		return
+	}
+
+	export, f, err := h.archiveService.OpenExport(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			writeError(w, http.StatusNotFound, "archive export not found")
+			return
+		case errors.Is(err, service.ErrInvalidMigrationState):
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		slog.Error("failed to open archive", "archive_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to open archive")
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+export.FileName+`"`)
+	http.ServeContent(w, r, export.FileName, export.CreatedAt, f)
+}
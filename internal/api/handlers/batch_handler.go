@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/TWRT/integration-mapper/internal/repository"
+	"github.com/TWRT/integration-mapper/internal/service"
+)
+
+type CreateBatchRequestBody struct {
+	Name            string `json:"name"`
+	Source          string `json:"source"`
+	Destination     string `json:"destination"`
+	DestWorkspaceId string `json:"dest_workspace_id"`
+	Concurrency     int    `json:"concurrency"`
+	Routes          []struct {
+		SourceProjectId string `json:"source_project_id"`
+		DestListId      string `json:"dest_list_id"`
+		DestSpaceId     string `json:"dest_space_id"`
+	} `json:"routes"`
+	Filters         []FilterRuleBody `json:"filters"`
+	TimeZone        string           `json:"time_zone"`
+	BacklinkMode    string           `json:"backlink_mode"`
+	BacklinkFieldID string           `json:"backlink_field_id"`
+	ReverseBacklink bool             `json:"reverse_backlink"`
+
+	ContainerStrategy string `json:"container_strategy"`
+	// SourceConnectionID and DestConnectionID are connections of the caller to reach the
+	// providers through; only admins may omit them and use the server's credentials.
+	SourceConnectionID *int64 `json:"source_connection_id"`
+	DestConnectionID   *int64 `json:"dest_connection_id"`
+}
+
+type SaveBatchAssigneesRequestBody struct {
+	Assignees []struct {
+		SourceValue string `json:"source_value"`
+		DestValue   string `json:"dest_value"`
+		Skip        bool   `json:"skip"`
+	} `json:"assignees"`
+}
+
+// CreateBatch creates a batch with one migration per routed source project.
+func (h *MigrationHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	var req CreateBatchRequestBody
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	routes := make([]repository.BatchRoute, 0, len(req.Routes))
+	for _, route := range req.Routes {
+		routes = append(routes, repository.BatchRoute{
+			SourceProjectID: route.SourceProjectId,
+			DestListID:      route.DestListId,
+			DestSpaceID:     route.DestSpaceId,
+		})
+	}
+
+	user := currentUser(r)
+	state, err := h.migrationService.CreateBatch(r.Context(), service.CreateBatchInput{
+		Name:            req.Name,
+		Source:          req.Source,
+		Destination:     req.Destination,
+		DestWorkspaceID: req.DestWorkspaceId,
+		Concurrency:     req.Concurrency,
+		Routes:          routes,
+		Filters:         toFilterRules(req.Filters),
+		TimeZone:        req.TimeZone,
+		BacklinkMode:    req.BacklinkMode,
+		BacklinkFieldID: req.BacklinkFieldID,
+		ReverseBacklink: req.ReverseBacklink,
+
+		ContainerStrategy:  req.ContainerStrategy,
+		OwnerID:            &user.ID,
+		SourceConnectionID: req.SourceConnectionID,
+		DestConnectionID:   req.DestConnectionID,
+		ServerCredentials:  user.IsAdmin(),
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.Error("failed to create migration batch", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to create migration batch")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"batch": state,
+	})
+}
+
+func (h *MigrationHandler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid batch id")
+		return
+	}
+
+	state, err := h.migrationService.GetBatch(id)
+	if err != nil {
+		slog.Error("failed to get migration batch", "batch_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to get migration batch")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"batch": state,
+	})
+}
+
+func (h *MigrationHandler) ListBatches(w http.ResponseWriter, r *http.Request) {
+	batches, err := h.migrationService.GetBatchesFor(currentUser(r))
+	if err != nil {
+		slog.Error("failed to list migration batches", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to list migration batches")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"batches": batches,
+	})
+}
+
+// GetBatchAssignees returns the assignees shared by the migrations of a batch.
+func (h *MigrationHandler) GetBatchAssignees(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid batch id")
+		return
+	}
+
+	state, err := h.migrationService.GetBatchAssignees(r.Context(), id)
+	if err != nil {
+		slog.Error("failed to get batch assignees", "batch_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to get batch assignees")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"assignees": state,
+	})
+}
+
+// SaveBatchAssignees maps assignees once for every migration of a batch.
+func (h *MigrationHandler) SaveBatchAssignees(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid batch id")
+		return
+	}
+	var req SaveBatchAssigneesRequestBody
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	assignees := make([]service.AssigneeMappingInput, 0, len(req.Assignees))
+	for _, a := range req.Assignees {
+		assignees = append(assignees, service.AssigneeMappingInput{
+			SourceValue: a.SourceValue,
+			DestValue:   a.DestValue,
+			Skip:        a.Skip,
+		})
+	}
+
+	state, err := h.migrationService.SaveBatchAssignees(r.Context(), id, assignees)
+	if err != nil {
+		slog.Error("failed to save batch assignees", "batch_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to save batch assignees")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"assignees": state,
+	})
+}
+
+func (h *MigrationHandler) StartBatch(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid batch id")
+		return
+	}
+
+	if err := h.migrationService.StartBatch(id); err != nil {
+		if errors.Is(err, service.ErrInvalidMigrationState) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		slog.Error("failed to start migration batch", "batch_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to start migration batch")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"batch_id": id,
+		"status":   repository.MigrationBatchStatusRunning,
+		"message":  "Migration batch started successfully",
+	})
+}
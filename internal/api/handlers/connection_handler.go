@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/TWRT/integration-mapper/internal/service"
+)
+
+type ConnectionHandler struct {
+	connectionService service.ConnectionServiceProvider
+	returnURL         string
+}
+
+// NewConnectionHandler creates the handler of the OAuth connection routes. returnURL is
+// the frontend page the OAuth callback redirects to; empty answers it with JSON.
+func NewConnectionHandler(connectionService service.ConnectionServiceProvider, returnURL string) *ConnectionHandler {
+	return &ConnectionHandler{connectionService: connectionService, returnURL: returnURL}
+}
+
+// ListConnections returns the caller's connections and the providers they can connect.
+func (h *ConnectionHandler) ListConnections(w http.ResponseWriter, r *http.Request) {
+	connections, err := h.connectionService.GetConnections(currentUser(r))
+	if err != nil {
+		slog.Error("failed to list connections", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to list connections")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"connections": connections,
+		"providers":   h.connectionService.ConnectableProviders(),
+	})
+}
+
+// Authorize starts connecting an account of a provider. The frontend sends the user to
+// the returned URL; the provider redirects back to OAuthCallback.
+func (h *ConnectionHandler) Authorize(w http.ResponseWriter, r *http.Request) {
+	authorizeURL, err := h.connectionService.AuthorizeURL(currentUser(r), r.PathValue("provider"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.Error("failed to start authorization", "provider", r.PathValue("provider"), "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to start authorization")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"authorize_url": authorizeURL,
+	})
+}
+
+// OAuthCallback is where providers redirect the user after they granted or denied access.
+// It is reached by the browser without an API key; the state parameter identifies the
+// user who started the authorization.
+func (h *ConnectionHandler) OAuthCallback(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+	q := r.URL.Query()
+	if denied := q.Get("error"); denied != "" {
+		h.finishCallback(w, r, http.StatusBadRequest, url.Values{"provider": {provider}, "error": {denied}},
+			map[string]any{"error": "authorization denied: " + denied})
+		return
+	}
+
+	conn, err := h.connectionService.CompleteAuthorization(r.Context(), provider, q.Get("state"), q.Get("code"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			h.finishCallback(w, r, http.StatusBadRequest, url.Values{"provider": {provider}, "error": {err.Error()}},
+				map[string]any{"error": err.Error()})
+			return
+		}
+		slog.Error("failed to complete authorization", "provider", provider, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to complete authorization")
+		return
+	}
+
+	h.finishCallback(w, r, http.StatusCreated,
+		url.Values{"provider": {provider}, "connection_id": {strconv.FormatInt(conn.ID, 10)}},
+		map[string]any{"connection": conn})
+}
+
+// finishCallback sends the user back to the frontend with the outcome in the query string,
+// or writes it as JSON when no return URL is configured.
+func (h *ConnectionHandler) finishCallback(w http.ResponseWriter, r *http.Request, status int, params url.Values, body map[string]any) {
+	if h.returnURL == "" {
+		writeJSON(w, status, body)
+		return
+	}
+	target, err := url.Parse(h.returnURL)
+	if err != nil {
+		slog.Error("invalid oauth return url", "url", h.returnURL, "error", err)
+		writeJSON(w, status, body)
+		return
+	}
+	query := target.Query()
+	for k, v := range params {
+		query[k] = v
+	}
+	target.RawQuery = query.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (h *ConnectionHandler) DeleteConnection(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.ownConnection(w, r)
+	if !ok {
+		return
+	}
+	if err := h.connectionService.DeleteConnection(id); err != nil {
+		slog.Error("failed to delete connection", "connection_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to delete connection")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ownConnection reads the {id} of a connection route. A connection of another user is
+// reported as not found.
+func (h *ConnectionHandler) ownConnection(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid connection id")
+		return 0, false
+	}
+	if !canUseConnection(w, r, h.connectionService, id) {
+		return 0, false
+	}
+	return id, true
+}
+
+func canUseConnection(w http.ResponseWriter, r *http.Request, connections service.ConnectionServiceProvider, id int64) bool {
+	ok, err := connections.CanAccessConnection(currentUser(r), id)
+	if err != nil {
+		slog.Error("failed to check connection access", "connection_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to get connection")
+		return false
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "connection not found")
+		return false
+	}
+	return true
+}
+
+// connectionParam reads the optional connection_id query parameter of a request, which
+// must name a connection of the caller. It writes the error response and reports false
+// when the parameter is invalid.
+func connectionParam(w http.ResponseWriter, r *http.Request, connections service.ConnectionServiceProvider) (*int64, bool) {
+	raw := r.URL.Query().Get("connection_id")
+	if raw == "" {
+		return nil, true
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid connection_id")
+		return nil, false
+	}
+	if !canUseConnection(w, r, connections, id) {
+		return nil, false
+	}
+	return &id, true
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/TWRT/integration-mapper/internal/service"
+)
+
+type SaveMappingTemplateRequestBody struct {
+	Name string `json:"name"`
+}
+
+type ApplyMappingTemplateRequestBody struct {
+	TemplateID int64 `json:"template_id"`
+}
+
+// decodeBody reads a JSON request body into v, writing the error response if that fails.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	body, err := readBody(w, r)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		} else {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+		}
+		return false
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request format")
+		return false
+	}
+	return true
+}
+
+// SaveMappingTemplate saves the mappings of a migration as a named template.
+func (h *MigrationHandler) SaveMappingTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid migration id")
+		return
+	}
+	var req SaveMappingTemplateRequestBody
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	user := currentUser(r)
+	template, err := h.migrationService.SaveMappingTemplate(id, req.Name, &user.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.Error("failed to save mapping template", "migration_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to save mapping template")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]any{"template": template})
+}
+
+// ApplyMappingTemplate applies a template to a migration and reports the template entries
+// that matched nothing.
+func (h *MigrationHandler) ApplyMappingTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid migration id")
+		return
+	}
+	var req ApplyMappingTemplateRequestBody
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	ok, err := h.migrationService.CanAccessMappingTemplate(currentUser(r), req.TemplateID)
+	if err != nil {
+		slog.Error("failed to check mapping template access", "template_id", req.TemplateID, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to get mapping template")
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "mapping template not found")
+		return
+	}
+
+	state, unmatched, err := h.migrationService.ApplyMappingTemplate(r.Context(), id, req.TemplateID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrInvalidMigrationState):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			slog.Error("failed to apply mapping template", "migration_id", id, "template_id", req.TemplateID, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to apply mapping template")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"mappings":  state,
+		"unmatched": unmatched,
+	})
+}
+
+func (h *MigrationHandler) GetMappingTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid template id")
+		return
+	}
+
+	template, err := h.migrationService.GetMappingTemplate(id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "mapping template not found")
+			return
+		}
+		slog.Error("failed to get mapping template", "template_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to get mapping template")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"template": template})
+}
+
+func (h *MigrationHandler) ListMappingTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.migrationService.GetMappingTemplatesFor(currentUser(r))
+	if err != nil {
+		slog.Error("failed to list mapping templates", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to list mapping templates")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"templates": templates})
+}
+
+func (h *MigrationHandler) DeleteMappingTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid template id")
+		return
+	}
+
+	if err := h.migrationService.DeleteMappingTemplate(id); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "mapping template not found")
+			return
+		}
+		slog.Error("failed to delete mapping template", "template_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to delete mapping template")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ExportMappings downloads the mapping configuration of a migration as a JSON document, or
+// YAML with ?format=yaml.
+func (h *MigrationHandler) ExportMappings(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid migration id")
+		return
+	}
+	format := r.URL.Query().Get("format")
+
+	doc, err := h.migrationService.ExportMappings(id, format)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.Error("failed to export mappings", "migration_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to export mappings")
+		return
+	}
+
+	ext, contentType := "json", "application/json"
+	if format == "yaml" {
+		ext, contentType = "yaml", "application/yaml"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="migration-%d-mappings.%s"`, id, ext))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(doc); err != nil {
+		slog.Error("failed to write mappings export", "error", err)
+	}
+}
+
+// ImportMappings applies a JSON or YAML mapping document and reports what changed.
+func (h *MigrationHandler) ImportMappings(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid migration id")
+		return
+	}
+	body, err := readBody(w, r)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		} else {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+		}
+		return
+	}
+
+	state, changes, unmatched, err := h.migrationService.ImportMappings(r.Context(), id, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrInvalidMigrationState):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			slog.Error("failed to import mappings", "migration_id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to import mappings")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"mappings":  state,
+		"changes":   changes,
+		"unmatched": unmatched,
+	})
+}
@@ -21,12 +21,50 @@ func NewMigrationHandler(migrationService service.MigrationServiceProvider) *Mig
 }
 
 type CreateMigrationRequestBody struct {
-	Source          string `json:"source"`
-	Destination     string `json:"destination"`
-	SourceProjectId string `json:"source_project_id"`
-	DestListId      string `json:"dest_list_id"`
-	DestSpaceId     string `json:"dest_space_id"`
-	DestWorkspaceId string `json:"dest_workspace_id"`
+	Source          string           `json:"source"`
+	Destination     string           `json:"destination"`
+	SourceProjectId string           `json:"source_project_id"`
+	DestListId      string           `json:"dest_list_id"`
+	DestSpaceId     string           `json:"dest_space_id"`
+	DestWorkspaceId string           `json:"dest_workspace_id"`
+	Filters         []FilterRuleBody `json:"filters"`
+	TimeZone        string           `json:"time_zone"`
+	BacklinkMode    string           `json:"backlink_mode"`     // "comment", "description" or "field"
+	BacklinkFieldID string           `json:"backlink_field_id"` // destination URL field, for mode "field"
+	ReverseBacklink bool             `json:"reverse_backlink"`
+	// ContainerStrategy is "list" (each section to a list) or "status" (sections become
+	// statuses of dest_list_id).
+	ContainerStrategy string `json:"container_strategy"`
+	// SourceConnectionID and DestConnectionID are connections of the caller to reach the
+	// providers through; only admins may omit them and use the server's credentials.
+	SourceConnectionID *int64 `json:"source_connection_id"`
+	DestConnectionID   *int64 `json:"dest_connection_id"`
+}
+
+type FilterRuleBody struct {
+	Field    string   `json:"field"`
+	Operator string   `json:"operator"`
+	Value    string   `json:"value"`
+	Values   []string `json:"values"`
+	FieldID  string   `json:"field_id"`
+}
+
+type UpdateFiltersRequestBody struct {
+	Filters []FilterRuleBody `json:"filters"`
+}
+
+func toFilterRules(body []FilterRuleBody) []repository.FilterRule {
+	rules := make([]repository.FilterRule, 0, len(body))
+	for _, f := range body {
+		rules = append(rules, repository.FilterRule{
+			Field:    f.Field,
+			Operator: f.Operator,
+			Value:    f.Value,
+			Values:   f.Values,
+			FieldID:  f.FieldID,
+		})
+	}
+	return rules
 }
 
 // SaveMappingsRequestBody is the new per-container mapping format.
@@ -34,13 +72,19 @@ type SaveMappingsRequestBody struct {
 	Assignees []struct {
 		SourceValue string `json:"source_value"`
 		DestValue   string `json:"dest_value"`
+		Skip        bool   `json:"skip"`
 	} `json:"assignees"`
+	Tags []struct {
+		SourceValue string `json:"source_value"`
+		DestValue   string `json:"dest_value"`
+		Drop        bool   `json:"drop"`
+	} `json:"tags"`
 	ContainerMappings []struct {
-		SourceID         string  `json:"source_id"`
-		DestID           *string `json:"dest_id"`
-		DestName         *string `json:"dest_name"`
-		Enabled          bool    `json:"enabled"`
-		StatusMappings   []struct {
+		SourceID       string  `json:"source_id"`
+		DestID         *string `json:"dest_id"`
+		DestName       *string `json:"dest_name"`
+		Enabled        bool    `json:"enabled"`
+		StatusMappings []struct {
 			SourceValue string `json:"source_value"`
 			DestValue   string `json:"dest_value"`
 		} `json:"status_mappings"`
@@ -49,12 +93,21 @@ type SaveMappingsRequestBody struct {
 			DestValue   string `json:"dest_value"`
 		} `json:"priority_mappings"`
 		CustomFields []struct {
-			FieldID string `json:"field_id"`
-			Enabled bool   `json:"enabled"`
+			FieldID        string            `json:"field_id"`
+			Enabled        bool              `json:"enabled"`
+			DestFieldID    *string           `json:"dest_field_id"`
+			Conversion     *string           `json:"conversion"`
+			OptionMappings map[string]string `json:"option_mappings"`
 		} `json:"custom_fields"`
 	} `json:"container_mappings"`
 }
 
+type RollbackRequestBody struct {
+	RemoveCustomFields bool `json:"remove_custom_fields"`
+	RemoveTags         bool `json:"remove_tags"`
+	RemoveContainers   bool `json:"remove_containers"`
+}
+
 func writeJSON(w http.ResponseWriter, status int, body any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -67,7 +120,8 @@ func writeError(w http.ResponseWriter, status int, msg string) {
 	writeJSON(w, status, map[string]string{"error": msg})
 }
 
-func parseMigrationID(r *http.Request) (int64, error) {
+// parsePathID reads the {id} path value of a route, whatever it identifies.
+func parsePathID(r *http.Request) (int64, error) {
 	return strconv.ParseInt(r.PathValue("id"), 10, 64)
 }
 
@@ -96,31 +150,7 @@ func (h *MigrationHandler) CreateMigration(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if req.Source != "asana" && req.Source != "clickup" {
-		writeError(w, http.StatusBadRequest, "source must be 'asana' or 'clickup'")
-		return
-	}
-	if req.Destination != "asana" && req.Destination != "clickup" {
-		writeError(w, http.StatusBadRequest, "destination must be 'asana' or 'clickup'")
-		return
-	}
-	if req.Source == req.Destination {
-		writeError(w, http.StatusBadRequest, "source and destination must be different")
-		return
-	}
-	if req.DestWorkspaceId == "" {
-		writeError(w, http.StatusBadRequest, "dest_workspace_id is required")
-		return
-	}
-	if req.Destination == "clickup" && req.DestSpaceId == "" {
-		writeError(w, http.StatusBadRequest, "dest_space_id is required for ClickUp destination")
-		return
-	}
-	if req.Destination == "asana" && req.DestListId == "" {
-		writeError(w, http.StatusBadRequest, "dest_list_id (project GID) is required for Asana destination")
-		return
-	}
-
+	user := currentUser(r)
 	migrationID, state, err := h.migrationService.CreateMigration(r.Context(), service.CreateMigrationInput{
 		Source:          req.Source,
 		Destination:     req.Destination,
@@ -128,8 +158,23 @@ func (h *MigrationHandler) CreateMigration(w http.ResponseWriter, r *http.Reques
 		DestListID:      req.DestListId,
 		DestWorkspaceID: req.DestWorkspaceId,
 		DestSpaceID:     req.DestSpaceId,
+		Filters:         toFilterRules(req.Filters),
+		TimeZone:        req.TimeZone,
+		BacklinkMode:    req.BacklinkMode,
+		BacklinkFieldID: req.BacklinkFieldID,
+		ReverseBacklink: req.ReverseBacklink,
+
+		ContainerStrategy:  req.ContainerStrategy,
+		OwnerID:            &user.ID,
+		SourceConnectionID: req.SourceConnectionID,
+		DestConnectionID:   req.DestConnectionID,
+		ServerCredentials:  user.IsAdmin(),
 	})
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		slog.Error("failed to create migration", "error", err)
 		writeError(w, http.StatusInternalServerError, "failed to create migration")
 		return
@@ -143,7 +188,7 @@ func (h *MigrationHandler) CreateMigration(w http.ResponseWriter, r *http.Reques
 }
 
 func (h *MigrationHandler) GetMappings(w http.ResponseWriter, r *http.Request) {
-	id, err := parseMigrationID(r)
+	id, err := parsePathID(r)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid migration id")
 		return
@@ -162,7 +207,7 @@ func (h *MigrationHandler) GetMappings(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *MigrationHandler) SaveMappings(w http.ResponseWriter, r *http.Request) {
-	id, err := parseMigrationID(r)
+	id, err := parsePathID(r)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid migration id")
 		return
@@ -190,6 +235,20 @@ func (h *MigrationHandler) SaveMappings(w http.ResponseWriter, r *http.Request)
 		assignees = append(assignees, service.AssigneeMappingInput{
 			SourceValue: a.SourceValue,
 			DestValue:   a.DestValue,
+			Skip:        a.Skip,
+		})
+	}
+
+	tags := make([]service.TagMappingInput, 0, len(req.Tags))
+	for _, t := range req.Tags {
+		if t.SourceValue == "" {
+			writeError(w, http.StatusBadRequest, "tag source_value is required")
+			return
+		}
+		tags = append(tags, service.TagMappingInput{
+			SourceValue: t.SourceValue,
+			DestValue:   t.DestValue,
+			Drop:        t.Drop,
 		})
 	}
 
@@ -222,10 +281,21 @@ func (h *MigrationHandler) SaveMappings(w http.ResponseWriter, r *http.Request)
 
 		customFields := make([]service.CustomFieldSelection, 0, len(cm.CustomFields))
 		for _, cf := range cm.CustomFields {
-			customFields = append(customFields, service.CustomFieldSelection{
+			selection := service.CustomFieldSelection{
 				FieldID: cf.FieldID,
 				Enabled: cf.Enabled,
-			})
+			}
+			// The mapping is only replaced when the client sends any of its keys.
+			if cf.DestFieldID != nil || cf.Conversion != nil || cf.OptionMappings != nil {
+				selection.Mapping = &service.CustomFieldMappingInput{OptionMappings: cf.OptionMappings}
+				if cf.DestFieldID != nil {
+					selection.Mapping.DestFieldID = *cf.DestFieldID
+				}
+				if cf.Conversion != nil {
+					selection.Mapping.Conversion = *cf.Conversion
+				}
+			}
+			customFields = append(customFields, selection)
 		}
 
 		containerInputs = append(containerInputs, service.ContainerMappingInput{
@@ -239,8 +309,12 @@ func (h *MigrationHandler) SaveMappings(w http.ResponseWriter, r *http.Request)
 		})
 	}
 
-	state, err := h.migrationService.SaveMappings(r.Context(), id, assignees, containerInputs)
+	state, err := h.migrationService.SaveMappings(r.Context(), id, assignees, tags, containerInputs)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		slog.Error("failed to save mappings", "error", err)
 		writeError(w, http.StatusInternalServerError, "failed to save mappings")
 		return
@@ -251,9 +325,53 @@ func (h *MigrationHandler) SaveMappings(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// UpdateFilters replaces the source task filter rules of a migration that has not started yet.
+func (h *MigrationHandler) UpdateFilters(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid migration id")
+		return
+	}
+
+	body, err := readBody(w, r)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		} else {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+		}
+		return
+	}
+
+	var req UpdateFiltersRequestBody
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request format")
+		return
+	}
+
+	state, err := h.migrationService.UpdateFilters(r.Context(), id, toFilterRules(req.Filters))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrInvalidMigrationState):
+			writeError(w, http.StatusConflict, "filters can only be changed before the migration starts")
+		default:
+			slog.Error("failed to update filters", "migration_id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to update filters")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"mappings": state,
+	})
+}
+
 // GetDestContainerOptions returns available statuses and priorities for a given destination container.
 func (h *MigrationHandler) GetDestContainerOptions(w http.ResponseWriter, r *http.Request) {
-	id, err := parseMigrationID(r)
+	id, err := parsePathID(r)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid migration id")
 		return
@@ -279,13 +397,17 @@ func (h *MigrationHandler) GetDestContainerOptions(w http.ResponseWriter, r *htt
 }
 
 func (h *MigrationHandler) StartMigration(w http.ResponseWriter, r *http.Request) {
-	id, err := parseMigrationID(r)
+	id, err := parsePathID(r)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid migration id")
 		return
 	}
 
 	if err := h.migrationService.StartMigration(id); err != nil {
+		if errors.Is(err, service.ErrInvalidMigrationState) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
 		slog.Error("failed to start migration", "migration_id", id, "error", err)
 		writeError(w, http.StatusInternalServerError, "failed to start migration")
 		return
@@ -293,13 +415,66 @@ func (h *MigrationHandler) StartMigration(w http.ResponseWriter, r *http.Request
 
 	writeJSON(w, http.StatusAccepted, map[string]any{
 		"migration_id": id,
-		"status":       repository.MigrationStatusRunning,
-		"message":      "Migration started successfully",
+		"status":       repository.MigrationStatusQueued,
+		"message":      "Migration queued successfully",
+	})
+}
+
+func (h *MigrationHandler) RollbackMigration(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid migration id")
+		return
+	}
+
+	body, err := readBody(w, r)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		} else {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+		}
+		return
+	}
+
+	// The body is optional: an empty body only deletes the migrated tasks.
+	var req RollbackRequestBody
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request format")
+			return
+		}
+	}
+
+	err = h.migrationService.RollbackMigration(id, service.RollbackOptions{
+		RemoveCustomFields: req.RemoveCustomFields,
+		RemoveTags:         req.RemoveTags,
+		RemoveContainers:   req.RemoveContainers,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMigrationState) {
+			writeError(w, http.StatusConflict, "migration cannot be rolled back in its current status")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidInput) || errors.Is(err, service.ErrUnsupported) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.Error("failed to start rollback", "migration_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to start rollback")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"migration_id": id,
+		"status":       repository.MigrationStatusRollingBack,
+		"message":      "Rollback started successfully",
 	})
 }
 
 func (h *MigrationHandler) GetMigration(w http.ResponseWriter, r *http.Request) {
-	id, err := parseMigrationID(r)
+	id, err := parsePathID(r)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid migration id")
 		return
@@ -311,14 +486,21 @@ func (h *MigrationHandler) GetMigration(w http.ResponseWriter, r *http.Request)
 		writeError(w, http.StatusInternalServerError, "failed to get migration")
 		return
 	}
+	job, err := h.migrationService.GetMigrationJob(id)
+	if err != nil {
+		slog.Error("failed to get migration job", "migration_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to get migration")
+		return
+	}
 
 	writeJSON(w, http.StatusOK, map[string]any{
 		"migration": migration,
+		"job":       job,
 	})
 }
 
 func (h *MigrationHandler) ListMigrations(w http.ResponseWriter, r *http.Request) {
-	migrations, err := h.migrationService.GetMigrations()
+	migrations, err := h.migrationService.GetMigrationsFor(currentUser(r))
 	if err != nil {
 		slog.Error("failed to list migrations", "error", err)
 		writeError(w, http.StatusInternalServerError, "failed to list migrations")
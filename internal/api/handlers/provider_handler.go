@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/TWRT/integration-mapper/internal/service"
+)
+
+type ProviderHandler struct {
+	providerService   service.ProviderServiceProvider
+	connectionService service.ConnectionServiceProvider
+}
+
+func NewProviderHandler(providerService service.ProviderServiceProvider, connectionService service.ConnectionServiceProvider) *ProviderHandler {
+	return &ProviderHandler{
+		providerService:   providerService,
+		connectionService: connectionService,
+	}
+}
+
+func (h *ProviderHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"providers": h.providerService.ListProviders()})
+}
+
+// ListWorkspaces lists the workspaces of a provider, as the account of the caller's
+// connection when ?connection_id= is given. Without one, only admins may browse providers
+// the server holds credentials for. So do ListProjects and ListContainers.
+func (h *ProviderHandler) ListWorkspaces(w http.ResponseWriter, r *http.Request) {
+	access, ok := h.providerAccess(w, r)
+	if !ok {
+		return
+	}
+	workspaces, err := h.providerService.ListWorkspaces(r.Context(), r.PathValue("provider"), access)
+	if err != nil {
+		writeProviderError(w, err, "failed to get workspaces")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"workspaces": workspaces})
+}
+
+func (h *ProviderHandler) ListProjects(w http.ResponseWriter, r *http.Request) {
+	access, ok := h.providerAccess(w, r)
+	if !ok {
+		return
+	}
+	projects, err := h.providerService.ListProjects(r.Context(), r.PathValue("provider"), access, r.PathValue("id"))
+	if err != nil {
+		writeProviderError(w, err, "failed to get projects")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"projects": projects})
+}
+
+func (h *ProviderHandler) ListContainers(w http.ResponseWriter, r *http.Request) {
+	access, ok := h.providerAccess(w, r)
+	if !ok {
+		return
+	}
+	containers, err := h.providerService.ListContainers(r.Context(), r.PathValue("provider"), access, r.PathValue("id"))
+	if err != nil {
+		writeProviderError(w, err, "failed to get containers")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"containers": containers})
+}
+
+func (h *ProviderHandler) providerAccess(w http.ResponseWriter, r *http.Request) (service.ProviderAccess, bool) {
+	connectionID, ok := connectionParam(w, r, h.connectionService)
+	if !ok {
+		return service.ProviderAccess{}, false
+	}
+	return service.ProviderAccess{ConnectionID: connectionID, ServerCredentials: currentUser(r).IsAdmin()}, true
+}
+
+func writeProviderError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, service.ErrUnknownProvider):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrUnsupported), errors.Is(err, service.ErrInvalidInput):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		slog.Error(msg, "error", err)
+		writeError(w, http.StatusInternalServerError, msg)
+	}
+}
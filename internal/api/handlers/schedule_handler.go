@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/service"
+)
+
+type ScheduleMigrationRequestBody struct {
+	ScheduledAt *time.Time `json:"scheduled_at"` // RFC 3339
+	Cron        string     `json:"cron"`         // e.g. "0 2 * * *", in the migration's time zone
+}
+
+// ScheduleMigration sets when a migration starts, once or on a recurring cron schedule.
+func (h *MigrationHandler) ScheduleMigration(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid migration id")
+		return
+	}
+	var req ScheduleMigrationRequestBody
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	migration, err := h.migrationService.ScheduleMigration(id, service.ScheduleInput{
+		At:   req.ScheduledAt,
+		Cron: req.Cron,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrInvalidMigrationState):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			slog.Error("failed to schedule migration", "migration_id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to schedule migration")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"migration": migration,
+	})
+}
+
+func (h *MigrationHandler) UnscheduleMigration(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid migration id")
+		return
+	}
+
+	if err := h.migrationService.UnscheduleMigration(id); err != nil {
+		slog.Error("failed to unschedule migration", "migration_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to unschedule migration")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/TWRT/integration-mapper/internal/repository"
+	"github.com/TWRT/integration-mapper/internal/service"
+)
+
+type UserHandler struct {
+	userService service.UserServiceProvider
+}
+
+func NewUserHandler(userService service.UserServiceProvider) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+type CreateUserRequestBody struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Admin bool   `json:"admin"`
+}
+
+// currentUser returns the user the auth middleware authenticated the request as.
+func currentUser(r *http.Request) repository.User {
+	user, _ := service.UserFromContext(r.Context())
+	return user
+}
+
+// requireAdmin writes a 403 and reports false unless the request comes from an admin.
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if !currentUser(r).IsAdmin() {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return false
+	}
+	return true
+}
+
+// CreateUser creates a user. The response holds its API key, which cannot be retrieved
+// again.
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	var req CreateUserRequestBody
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	user, apiKey, err := h.userService.CreateUser(service.CreateUserInput{
+		Name:  req.Name,
+		Email: req.Email,
+		Admin: req.Admin,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.Error("failed to create user", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to create user")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"user":    user,
+		"api_key": apiKey,
+	})
+}
+
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	users, err := h.userService.GetUsers()
+	if err != nil {
+		slog.Error("failed to list users", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to list users")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"users": users,
+	})
+}
+
+// GetCurrentUser returns the user the API key belongs to.
+func (h *UserHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"user": currentUser(r),
+	})
+}
+
+// AdminOnly wraps the handler of a route that only admins may call.
+func AdminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requireAdmin(w, r) {
+			next(w, r)
+		}
+	}
+}
+
+// OwnMigration wraps the handler of a /migrations/{id} route so that it only runs for the
+// owner of the migration or an admin. Anyone else gets a 404, as if the migration did not
+// exist.
+func (h *MigrationHandler) OwnMigration(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid migration id")
+			return
+		}
+		ok, err := h.migrationService.CanAccessMigration(currentUser(r), id)
+		if err != nil {
+			slog.Error("failed to check migration access", "migration_id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to get migration")
+			return
+		}
+		if !ok {
+			writeError(w, http.StatusNotFound, "migration not found")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// OwnBatch is OwnMigration for /batches/{id} routes.
+func (h *MigrationHandler) OwnBatch(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid batch id")
+			return
+		}
+		ok, err := h.migrationService.CanAccessBatch(currentUser(r), id)
+		if err != nil {
+			slog.Error("failed to check batch access", "batch_id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to get migration batch")
+			return
+		}
+		if !ok {
+			writeError(w, http.StatusNotFound, "migration batch not found")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// OwnMappingTemplate is OwnMigration for /mapping-templates/{id} routes.
+func (h *MigrationHandler) OwnMappingTemplate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid template id")
+			return
+		}
+		ok, err := h.migrationService.CanAccessMappingTemplate(currentUser(r), id)
+		if err != nil {
+			slog.Error("failed to check mapping template access", "template_id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to get mapping template")
+			return
+		}
+		if !ok {
+			writeError(w, http.StatusNotFound, "mapping template not found")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// OwnArchive is OwnMigration for /archives/{id} routes.
+func (h *ArchiveHandler) OwnArchive(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid archive id")
+			return
+		}
+		ok, err := h.archiveService.CanAccessExport(currentUser(r), id)
+		if err != nil {
+			slog.Error("failed to check archive export access", "archive_id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "failed to get archive export")
+			return
+		}
+		if !ok {
+			writeError(w, http.StatusNotFound, "archive export not found")
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/TWRT/integration-mapper/internal/service"
+)
+
+type WorkspaceMigrationHandler struct {
+	workspaceMigrationService service.WorkspaceMigrationServiceProvider
+}
+
+func NewWorkspaceMigrationHandler(workspaceMigrationService service.WorkspaceMigrationServiceProvider) *WorkspaceMigrationHandler {
+	return &WorkspaceMigrationHandler{workspaceMigrationService: workspaceMigrationService}
+}
+
+type CreateWorkspaceMigrationRequestBody struct {
+	Name              string   `json:"name"`
+	SourceWorkspaceId string   `json:"source_workspace_id"`
+	TeamIds           []string `json:"team_ids"`
+	DestWorkspaceId   string   `json:"dest_workspace_id"`
+	DestSpaceId       string   `json:"dest_space_id"`
+	ProjectStrategy   string   `json:"project_strategy"` // "list" (default) or "folder"
+	SectionStrategy   string   `json:"section_strategy"` // "status" (default) or "list"
+	Concurrency       int      `json:"concurrency"`
+	DryRun            bool     `json:"dry_run"`
+	// SourceConnectionID (Asana) and DestConnectionID (ClickUp) are connections of the
+	// caller; only admins may omit them and use the server's credentials.
+	SourceConnectionID *int64 `json:"source_connection_id"`
+	DestConnectionID   *int64 `json:"dest_connection_id"`
+}
+
+// CreateWorkspaceMigration mirrors an Asana workspace in a ClickUp space. With dry_run it
+// only returns the planned hierarchy.
+func (h *WorkspaceMigrationHandler) CreateWorkspaceMigration(w http.ResponseWriter, r *http.Request) {
+	var req CreateWorkspaceMigrationRequestBody
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	user := currentUser(r)
+	result, err := h.workspaceMigrationService.CreateWorkspaceMigration(r.Context(), service.WorkspaceMigrationInput{
+		Name:              req.Name,
+		SourceWorkspaceID: req.SourceWorkspaceId,
+		TeamIDs:           req.TeamIds,
+		DestWorkspaceID:   req.DestWorkspaceId,
+		DestSpaceID:       req.DestSpaceId,
+		ProjectStrategy:   req.ProjectStrategy,
+		SectionStrategy:   req.SectionStrategy,
+		Concurrency:       req.Concurrency,
+		DryRun:            req.DryRun,
+		OwnerID:           &user.ID,
+
+		SourceConnectionID: req.SourceConnectionID,
+		DestConnectionID:   req.DestConnectionID,
+		ServerCredentials:  user.IsAdmin(),
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.Error("failed to create workspace migration", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to create workspace migration")
+		return
+	}
+
+	status := http.StatusCreated
+	if req.DryRun {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, map[string]any{
+		"projects": result.Projects,
+		"batch":    result.Batch,
+	})
+}
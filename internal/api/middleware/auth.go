@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/TWRT/integration-mapper/internal/service"
+)
+
+// Authenticate wraps a handler requiring an API key, sent as "Authorization: Bearer <key>"
+// or in the X-API-Key header. The authenticated user is put on the request context; see
+// service.UserFromContext.
+func Authenticate(users service.UserServiceProvider) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey := r.Header.Get("X-API-Key")
+			if auth := r.Header.Get("Authorization"); auth != "" {
+				scheme, token, ok := strings.Cut(auth, " ")
+				if ok && strings.EqualFold(scheme, "Bearer") {
+					apiKey = strings.TrimSpace(token)
+				}
+			}
+
+			user, err := users.Authenticate(apiKey)
+			if err != nil {
+				if !errors.Is(err, service.ErrUnauthenticated) {
+					slog.Error("failed to authenticate request", "error", err)
+					writeError(w, http.StatusInternalServerError, "failed to authenticate request")
+					return
+				}
+				w.Header().Set("WWW-Authenticate", `Bearer realm="integration-mapper"`)
+				writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(service.WithUser(r.Context(), user)))
+		})
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]string{"error": msg}); err != nil {
+		slog.Error("failed to encode response", "error", err)
+	}
+}
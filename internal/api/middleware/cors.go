@@ -22,7 +22,7 @@ func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
 			if allowed[origin] {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 				w.Header().Set("Access-Control-Max-Age", "86400")
 				w.Header().Add("Vary", "Origin")
 			}
@@ -1,64 +1,88 @@
 package api
 
 import (
-	"database/sql"
 	"net/http"
 
 	"github.com/TWRT/integration-mapper/internal/api/handlers"
 	"github.com/TWRT/integration-mapper/internal/api/middleware"
-	"github.com/TWRT/integration-mapper/internal/client"
-	"github.com/TWRT/integration-mapper/internal/client/asana"
-	"github.com/TWRT/integration-mapper/internal/client/clickup"
-	"github.com/TWRT/integration-mapper/internal/repository"
-	"github.com/TWRT/integration-mapper/internal/service"
+	"github.com/TWRT/integration-mapper/internal/app"
 )
 
-func SetupRouter(db *sql.DB, asanaToken string, clickupToken string, allowedOrigins []string) http.Handler {
+// SetupRouter builds the API. Every route but the OAuth callback requires an API key;
+// migrations, batches, mapping templates and archives are only visible to their owner and
+// to admins. The legacy /asana and /clickup routes browse with the server's credentials,
+// so only admins may call them.
+func SetupRouter(svcs *app.Services, cfg app.Config) http.Handler {
 	mux := http.NewServeMux()
 
-	asanaClient := asana.NewAsanaClient(asanaToken)
-	clickUpClient := clickup.NewClickUpClient(clickupToken)
+	migrationHandler := handlers.NewMigrationHandler(svcs.Migrations)
+	integrationHandler := handlers.NewIntegrationHandler(svcs.Integrations)
+	providerHandler := handlers.NewProviderHandler(svcs.ProviderCatalog, svcs.Connections)
+	archiveHandler := handlers.NewArchiveHandler(svcs.Archives)
+	workspaceMigrationHandler := handlers.NewWorkspaceMigrationHandler(svcs.WorkspaceMigrations)
+	userHandler := handlers.NewUserHandler(svcs.Users)
+	connectionHandler := handlers.NewConnectionHandler(svcs.Connections, cfg.OAuthReturnURL)
 
-	migrationRepo := repository.NewMigrationRepository(db)
-	taskMappingRepo := repository.NewTaskMappingRepository(db)
-	migrationMappingRepo := repository.NewMigrationMappingRepository(db)
-	containerMappingRepo := repository.NewContainerMappingRepository(db)
+	mux.HandleFunc("POST /migrations/create", migrationHandler.CreateMigration)
+	mux.HandleFunc("GET /migrations/{id}/mappings", migrationHandler.OwnMigration(migrationHandler.GetMappings))
+	mux.HandleFunc("POST /migrations/{id}/mappings", migrationHandler.OwnMigration(migrationHandler.SaveMappings))
+	mux.HandleFunc("GET /migrations/{id}/mappings/export", migrationHandler.OwnMigration(migrationHandler.ExportMappings))
+	mux.HandleFunc("POST /migrations/{id}/mappings/import", migrationHandler.OwnMigration(migrationHandler.ImportMappings))
+	mux.HandleFunc("PUT /migrations/{id}/filters", migrationHandler.OwnMigration(migrationHandler.UpdateFilters))
+	mux.HandleFunc("GET /migrations/{id}/dest-container-options", migrationHandler.OwnMigration(migrationHandler.GetDestContainerOptions))
+	mux.HandleFunc("POST /migrations/{id}/start", migrationHandler.OwnMigration(migrationHandler.StartMigration))
+	mux.HandleFunc("PUT /migrations/{id}/schedule", migrationHandler.OwnMigration(migrationHandler.ScheduleMigration))
+	mux.HandleFunc("DELETE /migrations/{id}/schedule", migrationHandler.OwnMigration(migrationHandler.UnscheduleMigration))
+	mux.HandleFunc("POST /migrations/{id}/rollback", migrationHandler.OwnMigration(migrationHandler.RollbackMigration))
+	mux.HandleFunc("POST /migrations/{id}/templates", migrationHandler.OwnMigration(migrationHandler.SaveMappingTemplate))
+	mux.HandleFunc("POST /migrations/{id}/apply-template", migrationHandler.OwnMigration(migrationHandler.ApplyMappingTemplate))
+	mux.HandleFunc("GET /migrations/{id}", migrationHandler.OwnMigration(migrationHandler.GetMigration))
+	mux.HandleFunc("GET /migrations", migrationHandler.ListMigrations)
 
-	providers := map[string]client.IntegrationProvider{
-		"asana":   asanaClient,
-		"clickup": clickUpClient,
-	}
-	migrationService := service.NewMigrationService(
-		providers,
-		migrationRepo,
-		taskMappingRepo,
-		migrationMappingRepo,
-		containerMappingRepo,
-	)
+	mux.HandleFunc("GET /mapping-templates/{id}", migrationHandler.OwnMappingTemplate(migrationHandler.GetMappingTemplate))
+	mux.HandleFunc("DELETE /mapping-templates/{id}", migrationHandler.OwnMappingTemplate(migrationHandler.DeleteMappingTemplate))
+	mux.HandleFunc("GET /mapping-templates", migrationHandler.ListMappingTemplates)
 
-	integrationService := service.NewIntegrationService(
-		asanaClient,
-		clickUpClient,
-	)
+	mux.HandleFunc("POST /batches", migrationHandler.CreateBatch)
+	mux.HandleFunc("GET /batches/{id}/assignees", migrationHandler.OwnBatch(migrationHandler.GetBatchAssignees))
+	mux.HandleFunc("POST /batches/{id}/assignees", migrationHandler.OwnBatch(migrationHandler.SaveBatchAssignees))
+	mux.HandleFunc("POST /batches/{id}/start", migrationHandler.OwnBatch(migrationHandler.StartBatch))
+	mux.HandleFunc("GET /batches/{id}", migrationHandler.OwnBatch(migrationHandler.GetBatch))
+	mux.HandleFunc("GET /batches", migrationHandler.ListBatches)
 
-	migrationHandler := handlers.NewMigrationHandler(migrationService)
-	integrationHandler := handlers.NewIntegrationHandler(integrationService)
+	mux.HandleFunc("POST /workspace-migrations", workspaceMigrationHandler.CreateWorkspaceMigration)
 
-	mux.HandleFunc("POST /migrations/create", migrationHandler.CreateMigration)
-	mux.HandleFunc("GET /migrations/{id}/mappings", migrationHandler.GetMappings)
-	mux.HandleFunc("POST /migrations/{id}/mappings", migrationHandler.SaveMappings)
-	mux.HandleFunc("GET /migrations/{id}/dest-container-options", migrationHandler.GetDestContainerOptions)
-	mux.HandleFunc("POST /migrations/{id}/start", migrationHandler.StartMigration)
-	mux.HandleFunc("GET /migrations/{id}", migrationHandler.GetMigration)
-	mux.HandleFunc("GET /migrations", migrationHandler.ListMigrations)
+	mux.HandleFunc("GET /me", userHandler.GetCurrentUser)
+	mux.HandleFunc("POST /users", userHandler.CreateUser)
+	mux.HandleFunc("GET /users", userHandler.ListUsers)
+
+	mux.HandleFunc("GET /connections", connectionHandler.ListConnections)
+	mux.HandleFunc("POST /connections/{provider}/authorize", connectionHandler.Authorize)
+	mux.HandleFunc("DELETE /connections/{id}", connectionHandler.DeleteConnection)
+
+	mux.HandleFunc("GET /providers", providerHandler.ListProviders)
+	mux.HandleFunc("GET /providers/{provider}/workspaces", providerHandler.ListWorkspaces)
+	mux.HandleFunc("GET /providers/{provider}/workspaces/{id}/projects", providerHandler.ListProjects)
+	mux.HandleFunc("GET /providers/{provider}/projects/{id}/containers", providerHandler.ListContainers)
+
+	mux.HandleFunc("POST /archives", archiveHandler.CreateArchive)
+	mux.HandleFunc("GET /archives/{id}/download", archiveHandler.OwnArchive(archiveHandler.DownloadArchive))
+	mux.HandleFunc("GET /archives/{id}", archiveHandler.OwnArchive(archiveHandler.GetArchive))
+	mux.HandleFunc("GET /archives", archiveHandler.ListArchives)
+
+	mux.HandleFunc("GET /asana/workspaces", handlers.AdminOnly(integrationHandler.GetAsanaWorkspaces))
+	mux.HandleFunc("GET /asana/workspaces/{id}/projects", handlers.AdminOnly(integrationHandler.GetAsanaProjects))
+	mux.HandleFunc("GET /asana/projects/{id}/sections", handlers.AdminOnly(integrationHandler.GetAsanaSections))
+	mux.HandleFunc("GET /clickup/workspaces", handlers.AdminOnly(integrationHandler.GetClickupWorkspaces))
+	mux.HandleFunc("GET /clickup/workspaces/{id}/spaces", handlers.AdminOnly(integrationHandler.GetClickupSpaces))
+	mux.HandleFunc("GET /clickup/spaces/{id}/lists", handlers.AdminOnly(integrationHandler.GetClickupLists))
+	mux.HandleFunc("GET /clickup/lists/{id}/fields", handlers.AdminOnly(integrationHandler.GetClickupListCustomFields))
 
-	mux.HandleFunc("GET /asana/workspaces", integrationHandler.GetAsanaWorkspaces)
-	mux.HandleFunc("GET /asana/workspaces/{id}/projects", integrationHandler.GetAsanaProjects)
-	mux.HandleFunc("GET /asana/projects/{id}/sections", integrationHandler.GetAsanaSections)
-	mux.HandleFunc("GET /clickup/workspaces", integrationHandler.GetClickupWorkspaces)
-	mux.HandleFunc("GET /clickup/workspaces/{id}/spaces", integrationHandler.GetClickupSpaces)
-	mux.HandleFunc("GET /clickup/spaces/{id}/lists", integrationHandler.GetClickupLists)
-	mux.HandleFunc("GET /clickup/lists/{id}/fields", integrationHandler.GetClickupListCustomFields)
+	// Providers redirect the browser to the OAuth callback, which carries no API key; the
+	// state it comes back with identifies the user instead.
+	root := http.NewServeMux()
+	root.HandleFunc("GET /oauth/{provider}/callback", connectionHandler.OAuthCallback)
+	root.Handle("/", middleware.Authenticate(svcs.Users)(mux))
 
-	return middleware.CORS(allowedOrigins)(mux)
+	return middleware.CORS(cfg.AllowedOrigins)(root)
 }
@@ -0,0 +1,222 @@
+// Package app wires the repositories, providers and services shared by the HTTP server
+// and the migrator CLI, so both run migrations against the same database and providers.
+package app
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/client/archive"
+	"github.com/TWRT/integration-mapper/internal/client/asana"
+	"github.com/TWRT/integration-mapper/internal/client/clickup"
+	"github.com/TWRT/integration-mapper/internal/client/csvfile"
+	"github.com/TWRT/integration-mapper/internal/client/github"
+	"github.com/TWRT/integration-mapper/internal/client/jira"
+	"github.com/TWRT/integration-mapper/internal/client/linear"
+	"github.com/TWRT/integration-mapper/internal/client/trello"
+	"github.com/TWRT/integration-mapper/internal/repository"
+	"github.com/TWRT/integration-mapper/internal/service"
+)
+
+// DefaultDBPath is the SQLite database used unless DB_PATH is set.
+const DefaultDBPath = "./migrator.db"
+
+// Config holds the credentials and settings the services are built from. Providers other
+// than Asana, ClickUp and the archive provider are only registered when their credentials
+// are set. Asana and ClickUp need the server's token, an OAuth app users connect their own
+// accounts through, or both.
+type Config struct {
+	DBPath       string
+	AsanaToken   string
+	ClickUpToken string
+	AsanaOAuth   service.OAuthApp
+	ClickUpOAuth service.OAuthApp
+	// TokenCipher encrypts the tokens of OAuth connections; nil disables connections.
+	TokenCipher *service.TokenCipher
+	// PublicURL is where the server is reached from browsers; providers redirect to
+	// PublicURL + "/oauth/{provider}/callback" after a user granted access.
+	PublicURL string
+	// OAuthReturnURL is the frontend page users are sent back to once a connection is
+	// made. Empty answers the callback with the connection as JSON.
+	OAuthReturnURL string
+	TrelloAPIKey   string
+	TrelloToken    string
+	JiraBaseURL    string
+	JiraEmail      string
+	JiraAPIToken   string
+	LinearAPIKey   string
+	GitHubToken    string
+	GitHubAPIURL   string // REST API base URL; defaults to api.github.com
+	CSVDir         string // directory of the CSV files offered as projects
+	CSVColumns     csvfile.Columns
+	ArchiveDir     string // directory archive exports are written to and read from as a source
+	AllowedOrigins []string
+	// MaxConcurrentMigrations bounds the migrations running at once across every process
+	// sharing the database.
+	MaxConcurrentMigrations int
+}
+
+// ConfigFromEnv reads the configuration from the environment and creates the archive
+// directory.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		DBPath:         os.Getenv("DB_PATH"),
+		AsanaToken:     os.Getenv("ASANA_TOKEN"),
+		ClickUpToken:   os.Getenv("CLICKUP_TOKEN"),
+		AsanaOAuth:     service.OAuthApp{ClientID: os.Getenv("ASANA_CLIENT_ID"), ClientSecret: os.Getenv("ASANA_CLIENT_SECRET")},
+		ClickUpOAuth:   service.OAuthApp{ClientID: os.Getenv("CLICKUP_CLIENT_ID"), ClientSecret: os.Getenv("CLICKUP_CLIENT_SECRET")},
+		PublicURL:      os.Getenv("PUBLIC_URL"),
+		OAuthReturnURL: os.Getenv("OAUTH_RETURN_URL"),
+		TrelloAPIKey:   os.Getenv("TRELLO_API_KEY"),
+		TrelloToken:    os.Getenv("TRELLO_TOKEN"),
+		JiraBaseURL:    os.Getenv("JIRA_BASE_URL"),
+		JiraEmail:      os.Getenv("JIRA_EMAIL"),
+		JiraAPIToken:   os.Getenv("JIRA_API_TOKEN"),
+		LinearAPIKey:   os.Getenv("LINEAR_API_KEY"),
+		GitHubToken:    os.Getenv("GITHUB_TOKEN"),
+		GitHubAPIURL:   os.Getenv("GITHUB_API_URL"),
+		CSVDir:         os.Getenv("CSV_DIR"),
+		ArchiveDir:     os.Getenv("ARCHIVE_DIR"),
+		AllowedOrigins: strings.Split(os.Getenv("ALLOWED_ORIGINS"), ","),
+
+		MaxConcurrentMigrations: 2,
+	}
+	if cfg.AsanaToken == "" && cfg.AsanaOAuth.ClientID == "" {
+		return Config{}, errors.New("ASANA_TOKEN or ASANA_CLIENT_ID must be set")
+	}
+	if cfg.ClickUpToken == "" && cfg.ClickUpOAuth.ClientID == "" {
+		return Config{}, errors.New("CLICKUP_TOKEN or CLICKUP_CLIENT_ID must be set")
+	}
+	if cfg.DBPath == "" {
+		cfg.DBPath = DefaultDBPath
+	}
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = "http://localhost:8080"
+	}
+
+	var err error
+	if key := os.Getenv("TOKEN_ENCRYPTION_KEY"); key != "" {
+		raw, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return Config{}, fmt.Errorf("TOKEN_ENCRYPTION_KEY must be base64: %w", err)
+		}
+		if cfg.TokenCipher, err = service.NewTokenCipher(raw); err != nil {
+			return Config{}, fmt.Errorf("invalid TOKEN_ENCRYPTION_KEY: %w", err)
+		}
+	} else if cfg.AsanaOAuth.ClientID != "" || cfg.ClickUpOAuth.ClientID != "" {
+		return Config{}, errors.New("TOKEN_ENCRYPTION_KEY must be set to store OAuth connections")
+	}
+
+	if cfg.CSVColumns, err = csvfile.ParseColumns(os.Getenv("CSV_COLUMNS")); err != nil {
+		return Config{}, fmt.Errorf("invalid CSV_COLUMNS: %w", err)
+	}
+
+	if v := os.Getenv("MAX_CONCURRENT_MIGRATIONS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return Config{}, fmt.Errorf("MAX_CONCURRENT_MIGRATIONS must be a positive number, got %q", v)
+		}
+		cfg.MaxConcurrentMigrations = n
+	}
+
+	if cfg.ArchiveDir == "" {
+		cfg.ArchiveDir = "./archives"
+	}
+	if err := os.MkdirAll(cfg.ArchiveDir, 0o755); err != nil {
+		return Config{}, fmt.Errorf("create archive directory: %w", err)
+	}
+	return cfg, nil
+}
+
+// Services are the services built on one database and provider registry.
+type Services struct {
+	Providers           *client.Registry
+	Migrations          *service.MigrationService
+	Integrations        *service.IntegrationService
+	ProviderCatalog     *service.ProviderService
+	Archives            *service.ArchiveService
+	WorkspaceMigrations *service.WorkspaceMigrationService
+	Users               *service.UserService
+	Connections         *service.ConnectionService
+}
+
+func NewServices(db *sql.DB, cfg Config) *Services {
+	asanaClient := asana.NewAsanaClient(cfg.AsanaToken)
+	clickUpClient := clickup.NewClickUpClient(cfg.ClickUpToken)
+
+	migrationRepo := repository.NewMigrationRepository(db)
+	taskMappingRepo := repository.NewTaskMappingRepository(db)
+	migrationMappingRepo := repository.NewMigrationMappingRepository(db)
+	containerMappingRepo := repository.NewContainerMappingRepository(db)
+	createdResourceRepo := repository.NewCreatedResourceRepository(db)
+	mappingTemplateRepo := repository.NewMappingTemplateRepository(db)
+	archiveExportRepo := repository.NewArchiveExportRepository(db)
+	migrationBatchRepo := repository.NewMigrationBatchRepository(db)
+	migrationJobRepo := repository.NewMigrationJobRepository(db)
+
+	providers := client.NewRegistry()
+	asanaDescriptor := asana.Descriptor(asanaClient)
+	asanaDescriptor.ConnectionRequired = cfg.AsanaToken == ""
+	providers.MustRegister(asanaDescriptor)
+	clickUpDescriptor := clickup.Descriptor(clickUpClient)
+	clickUpDescriptor.ConnectionRequired = cfg.ClickUpToken == ""
+	providers.MustRegister(clickUpDescriptor)
+	providers.MustRegister(archive.Descriptor(archive.NewArchiveClient(cfg.ArchiveDir)))
+	if cfg.TrelloAPIKey != "" && cfg.TrelloToken != "" {
+		providers.MustRegister(trello.Descriptor(trello.NewTrelloClient(cfg.TrelloAPIKey, cfg.TrelloToken)))
+	}
+	if cfg.JiraBaseURL != "" && cfg.JiraEmail != "" && cfg.JiraAPIToken != "" {
+		providers.MustRegister(jira.Descriptor(jira.NewJiraClient(cfg.JiraBaseURL, cfg.JiraEmail, cfg.JiraAPIToken)))
+	}
+	if cfg.LinearAPIKey != "" {
+		providers.MustRegister(linear.Descriptor(linear.NewLinearClient(cfg.LinearAPIKey)))
+	}
+	if cfg.GitHubToken != "" {
+		githubClient := github.NewGitHubClient(cfg.GitHubToken)
+		if cfg.GitHubAPIURL != "" {
+			githubClient = github.NewGitHubClientWithBaseURL(cfg.GitHubAPIURL, cfg.GitHubToken)
+		}
+		providers.MustRegister(github.Descriptor(githubClient))
+	}
+	if cfg.CSVDir != "" {
+		providers.MustRegister(csvfile.Descriptor(csvfile.NewCSVClient(cfg.CSVDir, cfg.CSVColumns)))
+	}
+
+	connectionService := service.NewConnectionService(
+		repository.NewConnectionRepository(db),
+		providers,
+		cfg.TokenCipher,
+		map[string]service.OAuthApp{asana.Name: cfg.AsanaOAuth, clickup.Name: cfg.ClickUpOAuth},
+		cfg.PublicURL,
+	)
+
+	migrationService := service.NewMigrationService(
+		providers,
+		migrationRepo,
+		taskMappingRepo,
+		migrationMappingRepo,
+		containerMappingRepo,
+		createdResourceRepo,
+		mappingTemplateRepo,
+		migrationBatchRepo,
+		migrationJobRepo,
+		connectionService,
+	)
+
+	return &Services{
+		Providers:           providers,
+		Migrations:          migrationService,
+		Integrations:        service.NewIntegrationService(asanaClient, clickUpClient),
+		ProviderCatalog:     service.NewProviderService(providers, connectionService),
+		Archives:            service.NewArchiveService(providers, archiveExportRepo, cfg.ArchiveDir, connectionService),
+		WorkspaceMigrations: service.NewWorkspaceMigrationService(asanaClient, clickUpClient, migrationService, createdResourceRepo, connectionService),
+		Users:               service.NewUserService(repository.NewUserRepository(db)),
+		Connections:         connectionService,
+	}
+}
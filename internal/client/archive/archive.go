@@ -0,0 +1,175 @@
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// Snapshot is the content of an archive besides the attachment files.
+type Snapshot struct {
+	Manifest   Manifest
+	Containers []Container
+	Fields     []Field
+	Members    []User
+	Tasks      []Task
+}
+
+// ---- Writing ----
+
+// Writer writes an archive. Attachment files are streamed into it as they are downloaded;
+// the JSON documents are written by Close once everything has been read.
+type Writer struct {
+	zw    *zip.Writer
+	files int
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{zw: zip.NewWriter(w)}
+}
+
+// AddAttachment copies an attachment file into the archive and returns its archived form.
+func (w *Writer) AddAttachment(a models.Attachment, r io.Reader) (Attachment, error) {
+	w.files++
+	name := path.Base(strings.ReplaceAll(a.Name, "\\", "/"))
+	if name == "." || name == "/" || name == ".." {
+		name = "file"
+	}
+	p := attachmentsDir + strconv.Itoa(w.files) + "/" + name
+	f, err := w.zw.Create(p)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("add attachment %s (archive): %w", a.Name, err)
+	}
+	n, err := io.Copy(f, r)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("add attachment %s (archive): %w", a.Name, err)
+	}
+	return Attachment{ID: a.ID, Name: a.Name, URL: a.URL, Size: n, Path: p}, nil
+}
+
+// Close writes the snapshot's documents and finishes the archive. The manifest's format
+// and version are set by the writer.
+func (w *Writer) Close(s Snapshot) error {
+	s.Manifest.Format = FormatName
+	s.Manifest.Version = FormatVersion
+	docs := []struct {
+		name string
+		v    any
+	}{
+		{manifestFile, s.Manifest},
+		{containersFile, nonNil(s.Containers)},
+		{fieldsFile, nonNil(s.Fields)},
+		{membersFile, nonNil(s.Members)},
+		{tasksFile, nonNil(s.Tasks)},
+	}
+	for _, d := range docs {
+		f, err := w.zw.Create(d.name)
+		if err != nil {
+			return fmt.Errorf("write %s (archive): %w", d.name, err)
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(d.v); err != nil {
+			return fmt.Errorf("write %s (archive): %w", d.name, err)
+		}
+	}
+	if err := w.zw.Close(); err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+	return nil
+}
+
+// nonNil makes empty lists encode as [] rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// ---- Reading ----
+
+// Archive is an archive file read back from disk.
+type Archive struct {
+	Snapshot
+	path string
+}
+
+// Open reads the documents of an archive. Attachment files are only read by OpenFile.
+func Open(file string) (*Archive, error) {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("open %s (archive): %w", path.Base(file), err)
+	}
+	defer zr.Close()
+
+	a := &Archive{path: file}
+	if err := readJSON(&zr.Reader, manifestFile, &a.Manifest); err != nil {
+		return nil, err
+	}
+	if a.Manifest.Format != FormatName {
+		return nil, fmt.Errorf("open %s (archive): not an integration-mapper archive", path.Base(file))
+	}
+	if a.Manifest.Version < 1 || a.Manifest.Version > FormatVersion {
+		return nil, fmt.Errorf("open %s (archive): unsupported archive version %d", path.Base(file), a.Manifest.Version)
+	}
+	for _, d := range []struct {
+		name string
+		v    any
+	}{
+		{containersFile, &a.Containers},
+		{fieldsFile, &a.Fields},
+		{membersFile, &a.Members},
+		{tasksFile, &a.Tasks},
+	} {
+		if err := readJSON(&zr.Reader, d.name, d.v); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func readJSON(zr *zip.Reader, name string, v any) error {
+	f, err := zr.Open(name)
+	if err != nil {
+		return fmt.Errorf("read %s (archive): %w", name, err)
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("parse %s (archive): %w", name, err)
+	}
+	return nil
+}
+
+// OpenFile opens an attachment file by its path inside the archive.
+func (a *Archive) OpenFile(name string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(name, attachmentsDir) {
+		return nil, fmt.Errorf("open %s (archive): not an attachment", name)
+	}
+	zr, err := zip.OpenReader(a.path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s (archive): %w", path.Base(a.path), err)
+	}
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("open %s (archive): %w", name, err), zr.Close())
+	}
+	return &zipFile{ReadCloser: f, zr: zr}, nil
+}
+
+// zipFile closes the archive along with the file read from it.
+type zipFile struct {
+	io.ReadCloser
+	zr *zip.ReadCloser
+}
+
+func (f *zipFile) Close() error {
+	return errors.Join(f.ReadCloser.Close(), f.zr.Close())
+}
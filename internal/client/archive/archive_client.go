@@ -0,0 +1,338 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// WorkspaceID is the single workspace of the provider: the archive directory.
+const WorkspaceID = "local"
+
+// ArchiveClient serves the archives of a directory as a read-only source. An archive is a
+// project; its containers and tasks keep the IDs of the tool they were exported from.
+//
+// IDs take the forms "export.zip" (an archive), "export.zip|123" (container 123 of the
+// archive) and "export.zip#456" (task 456 of the archive).
+type ArchiveClient struct {
+	dir string
+
+	mu       sync.Mutex
+	archives map[string]*cachedArchive // file name → last read content
+}
+
+type cachedArchive struct {
+	modTime time.Time
+	size    int64
+	archive *Archive
+}
+
+func NewArchiveClient(dir string) *ArchiveClient {
+	return &ArchiveClient{dir: dir, archives: make(map[string]*cachedArchive)}
+}
+
+// splitID returns the archive an ID refers to and the container or task after it.
+func splitID(id string) (file, container, task string, err error) {
+	file = id
+	if i := strings.Index(file, "|"); i != -1 {
+		file, container = file[:i], file[i+1:]
+	} else if i := strings.Index(file, "#"); i != -1 {
+		file, task = file[:i], file[i+1:]
+	}
+	if file == "" || filepath.Base(file) != file || !strings.EqualFold(filepath.Ext(file), ".zip") {
+		return "", "", "", fmt.Errorf("invalid archive %q, expected a .zip file name (archive)", file)
+	}
+	return file, container, task, nil
+}
+
+// load returns an archive of the directory, reading it again only when the file changed.
+func (c *ArchiveClient) load(file string) (*Archive, error) {
+	full := filepath.Join(c.dir, file)
+	info, err := os.Stat(full)
+	if err != nil {
+		return nil, fmt.Errorf("open %s (archive): %w", file, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.archives[file]; ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.archive, nil
+	}
+	a, err := Open(full)
+	if err != nil {
+		return nil, err
+	}
+	c.archives[file] = &cachedArchive{modTime: info.ModTime(), size: info.Size(), archive: a}
+	return a, nil
+}
+
+// findTask returns the archived task an ID refers to.
+func (c *ArchiveClient) findTask(taskId string) (*Archive, Task, error) {
+	file, _, id, err := splitID(taskId)
+	if err != nil {
+		return nil, Task{}, err
+	}
+	a, err := c.load(file)
+	if err != nil {
+		return nil, Task{}, err
+	}
+	for _, t := range a.Tasks {
+		if t.ID == id {
+			return a, t, nil
+		}
+	}
+	return nil, Task{}, fmt.Errorf("task %s not found (archive)", taskId)
+}
+
+func (c *ArchiveClient) listFiles() ([]string, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, fmt.Errorf("list archives (archive): %w", err)
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.EqualFold(filepath.Ext(e.Name()), ".zip") && !strings.ContainsAny(e.Name(), "|#") {
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ---- Browsing ----
+
+// ListWorkspaces implements client.WorkspaceBrowser. The directory is the only workspace.
+func (c *ArchiveClient) ListWorkspaces(_ context.Context) ([]client.Container, error) {
+	return []client.Container{{ID: WorkspaceID, Name: filepath.Base(c.dir)}}, nil
+}
+
+// ListProjects implements client.WorkspaceBrowser with the archives of the directory, named
+// after the project they were exported from. Files that are not readable archives are skipped.
+func (c *ArchiveClient) ListProjects(_ context.Context, _ string) ([]client.Container, error) {
+	files, err := c.listFiles()
+	if err != nil {
+		return nil, err
+	}
+	var result []client.Container
+	for _, f := range files {
+		a, err := c.load(f)
+		if err != nil {
+			continue
+		}
+		name := a.Manifest.ProjectName
+		if name == "" {
+			name = a.Manifest.ProjectID
+		}
+		result = append(result, client.Container{
+			ID:   f,
+			Name: fmt.Sprintf("%s (%s, %s)", name, a.Manifest.Provider, a.Manifest.ExportedAt.Format("2006-01-02")),
+		})
+	}
+	return result, nil
+}
+
+// ---- Tasks and containers ----
+
+// GetTasks returns every task of an archive.
+func (c *ArchiveClient) GetTasks(_ context.Context, id string) ([]models.Task, error) {
+	file, container, _, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	a, err := c.load(file)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []models.Task
+	for _, t := range a.Tasks {
+		if container != "" && t.ContainerID != container {
+			continue
+		}
+		task := t.Model()
+		task.Id = file + "#" + t.ID
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// CreateTask always fails: archives are only read.
+func (c *ArchiveClient) CreateTask(_ context.Context, _ string, _ string, _ models.Task) (*models.Task, error) {
+	return nil, fmt.Errorf("archives are read-only (archive)")
+}
+
+// GetSourceContainers returns the containers an archive's tasks were exported from.
+func (c *ArchiveClient) GetSourceContainers(_ context.Context, id string) ([]client.Container, error) {
+	file, _, _, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	a, err := c.load(file)
+	if err != nil {
+		return nil, err
+	}
+	containers := make([]client.Container, len(a.Containers))
+	for i, ct := range a.Containers {
+		containers[i] = client.Container{ID: file + "|" + ct.ID, Name: ct.Name}
+	}
+	return containers, nil
+}
+
+// GetTasksByContainer returns the tasks of one container of an archive.
+func (c *ArchiveClient) GetTasksByContainer(ctx context.Context, containerId string) ([]models.Task, error) {
+	return c.GetTasks(ctx, containerId)
+}
+
+func (c *ArchiveClient) GetDestContainers(ctx context.Context, id string) ([]client.Container, error) {
+	return c.GetSourceContainers(ctx, id)
+}
+
+// GetListStatuses returns the statuses of a container, or of all containers of an archive.
+func (c *ArchiveClient) GetListStatuses(_ context.Context, id string) ([]string, error) {
+	file, container, _, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	a, err := c.load(file)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var statuses []string
+	for _, ct := range a.Containers {
+		if container != "" && ct.ID != container {
+			continue
+		}
+		for _, s := range ct.Statuses {
+			if !seen[s] {
+				seen[s] = true
+				statuses = append(statuses, s)
+			}
+		}
+	}
+	return statuses, nil
+}
+
+// GetFieldDefinitions returns the custom fields of a container, including the fields of
+// the whole project, or every field of an archive.
+func (c *ArchiveClient) GetFieldDefinitions(_ context.Context, id string) ([]models.CustomFieldDefinition, error) {
+	file, container, _, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	a, err := c.load(file)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var defs []models.CustomFieldDefinition
+	for _, f := range a.Fields {
+		if container != "" && f.ContainerID != "" && f.ContainerID != container {
+			continue
+		}
+		if !seen[f.ID] {
+			seen[f.ID] = true
+			defs = append(defs, f.Model())
+		}
+	}
+	return defs, nil
+}
+
+// GetMembers returns the members recorded in the directory's archives.
+func (c *ArchiveClient) GetMembers(_ context.Context, _ string) ([]models.Member, error) {
+	files, err := c.listFiles()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var members []models.Member
+	for _, f := range files {
+		a, err := c.load(f)
+		if err != nil {
+			continue
+		}
+		for _, u := range a.Members {
+			key := a.Manifest.Provider + ":" + u.ID
+			if !seen[key] {
+				seen[key] = true
+				members = append(members, models.Member{ID: u.ID, Name: u.Name, Email: u.Email})
+			}
+		}
+	}
+	return members, nil
+}
+
+// ---- Links, comments and attachments ----
+
+// TaskURL returns the link the task had in the tool it was exported from.
+func (c *ArchiveClient) TaskURL(taskId string) string {
+	_, t, err := c.findTask(taskId)
+	if err != nil {
+		return ""
+	}
+	return t.URL
+}
+
+func (c *ArchiveClient) GetComments(_ context.Context, taskId string) ([]models.Comment, error) {
+	_, t, err := c.findTask(taskId)
+	if err != nil {
+		return nil, err
+	}
+	comments := make([]models.Comment, len(t.Comments))
+	for i, cm := range t.Comments {
+		comments[i] = models.Comment{
+			ID:        cm.ID,
+			Author:    models.TaskAssignee{ID: cm.Author.ID, Name: cm.Author.Name, Email: cm.Author.Email},
+			Text:      cm.Text,
+			CreatedAt: cm.CreatedAt,
+		}
+	}
+	return comments, nil
+}
+
+// GetAttachments returns the attachments of a task. The URL of an archived file is its
+// location in the archive ("export.zip/attachments/1/report.pdf"); files that could not be
+// downloaded at export time keep their original link.
+func (c *ArchiveClient) GetAttachments(_ context.Context, taskId string) ([]models.Attachment, error) {
+	file, _, _, err := splitID(taskId)
+	if err != nil {
+		return nil, err
+	}
+	_, t, err := c.findTask(taskId)
+	if err != nil {
+		return nil, err
+	}
+	attachments := make([]models.Attachment, len(t.Attachments))
+	for i, at := range t.Attachments {
+		link := at.URL
+		if at.Path != "" {
+			link = file + "/" + at.Path
+		}
+		attachments[i] = models.Attachment{ID: at.ID, Name: at.Name, URL: link, Size: at.Size}
+	}
+	return attachments, nil
+}
+
+// OpenAttachment reads an archived attachment file.
+func (c *ArchiveClient) OpenAttachment(_ context.Context, attachment models.Attachment) (io.ReadCloser, error) {
+	file, name, ok := strings.Cut(attachment.URL, "/")
+	if !ok || !strings.HasPrefix(name, attachmentsDir) {
+		return nil, fmt.Errorf("attachment %s is not stored in the archive", attachment.Name)
+	}
+	if _, _, _, err := splitID(file); err != nil {
+		return nil, err
+	}
+	a, err := c.load(file)
+	if err != nil {
+		return nil, err
+	}
+	return a.OpenFile(name)
+}
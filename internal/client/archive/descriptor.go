@@ -0,0 +1,24 @@
+package archive
+
+import (
+	"fmt"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+)
+
+// Name is the provider name migrations use for archives.
+const Name = "archive"
+
+// Descriptor registers the archive client. Archives can only be migrated from, so every
+// destination is rejected.
+func Descriptor(c *ArchiveClient) client.Descriptor {
+	return client.Descriptor{
+		Name:        Name,
+		DisplayName: "Archive",
+		Client:      c,
+		Validate: func(_ client.Destination) error {
+			return fmt.Errorf("archives can only be used as a migration source")
+		},
+		NoCredentials: true,
+	}
+}
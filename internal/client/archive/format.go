@@ -0,0 +1,233 @@
+// Package archive writes offline snapshots of a source project into versioned zip archives
+// and reads them back as a read-only migration source.
+//
+// An archive contains manifest.json, containers.json, fields.json, members.json and
+// tasks.json at its root, plus the downloaded attachment files under attachments/.
+package archive
+
+import (
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// FormatName identifies integration-mapper archives in their manifest.
+const FormatName = "integration-mapper-archive"
+
+// FormatVersion is the version of the archive layout written by this build. Readers accept
+// archives up to this version; fields are only ever added in later versions.
+const FormatVersion = 1
+
+const (
+	manifestFile   = "manifest.json"
+	containersFile = "containers.json"
+	fieldsFile     = "fields.json"
+	membersFile    = "members.json"
+	tasksFile      = "tasks.json"
+	attachmentsDir = "attachments/"
+)
+
+// Manifest describes where an archive was exported from.
+type Manifest struct {
+	Format      string    `json:"format"`
+	Version     int       `json:"version"`
+	Provider    string    `json:"provider"`
+	WorkspaceID string    `json:"workspace_id"`
+	ProjectID   string    `json:"project_id"`
+	ProjectName string    `json:"project_name"`
+	ExportedAt  time.Time `json:"exported_at"`
+}
+
+// Container is a source container (Asana section, ClickUp list) with its statuses.
+type Container struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Statuses []string `json:"statuses,omitempty"`
+}
+
+// Field is a custom field definition. ContainerID is empty for fields of the whole project.
+type Field struct {
+	ContainerID string        `json:"container_id,omitempty"`
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Type        string        `json:"type"` // ClickUp field type, as in models.CustomFieldDefinition
+	Options     []FieldOption `json:"options,omitempty"`
+}
+
+type FieldOption struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	OrderIndex int    `json:"order_index"`
+}
+
+type User struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+type Checklist struct {
+	Name  string          `json:"name"`
+	Items []ChecklistItem `json:"items"`
+}
+
+type ChecklistItem struct {
+	Name    string `json:"name"`
+	Checked bool   `json:"checked"`
+}
+
+type FieldValue struct {
+	FieldID string      `json:"field_id"`
+	Value   interface{} `json:"value"`
+}
+
+type Comment struct {
+	ID        string    `json:"id"`
+	Author    User      `json:"author"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Attachment is a file attached to a task. Path locates the file inside the archive and is
+// empty when it could not be downloaded; URL is the link it was downloaded from.
+type Attachment struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	Size int64  `json:"size"`
+	Path string `json:"path,omitempty"`
+}
+
+// Task is a source task with everything read about it. IDs are those of the source tool.
+type Task struct {
+	ID              string       `json:"id"`
+	ContainerID     string       `json:"container_id,omitempty"`
+	URL             string       `json:"url,omitempty"`
+	Name            string       `json:"name"`
+	Description     string       `json:"description,omitempty"`
+	RichDescription string       `json:"rich_description,omitempty"`
+	Status          string       `json:"status,omitempty"`
+	Completed       bool         `json:"completed"`
+	Assignees       []User       `json:"assignees,omitempty"`
+	Followers       []User       `json:"followers,omitempty"`
+	DueDate         *time.Time   `json:"due_date,omitempty"`
+	DueHasTime      bool         `json:"due_has_time,omitempty"`
+	StartDate       *time.Time   `json:"start_date,omitempty"`
+	StartHasTime    bool         `json:"start_has_time,omitempty"`
+	TimeZone        string       `json:"time_zone,omitempty"`
+	Tags            []string     `json:"tags,omitempty"`
+	Priority        string       `json:"priority,omitempty"`
+	CustomFields    []FieldValue `json:"custom_fields,omitempty"`
+	Checklists      []Checklist  `json:"checklists,omitempty"`
+	Comments        []Comment    `json:"comments,omitempty"`
+	Attachments     []Attachment `json:"attachments,omitempty"`
+}
+
+// NewTask converts a task read from a source client.
+func NewTask(t models.Task, containerID string) Task {
+	task := Task{
+		ID:              t.Id,
+		ContainerID:     containerID,
+		Name:            t.Name,
+		Description:     t.Description,
+		RichDescription: t.RichDescription,
+		Status:          t.Status,
+		Completed:       t.Completed,
+		Assignees:       toUsers(t.Assignees),
+		Followers:       toUsers(t.Followers),
+		DueDate:         t.DueDate,
+		DueHasTime:      t.DueHasTime,
+		StartDate:       t.StartDate,
+		StartHasTime:    t.StartHasTime,
+		TimeZone:        t.TimeZone,
+		Tags:            t.Tags,
+		Priority:        t.Priority,
+	}
+	for _, cf := range t.CustomFields {
+		task.CustomFields = append(task.CustomFields, FieldValue{FieldID: cf.FieldID, Value: cf.Value})
+	}
+	for _, cl := range t.Checklists {
+		checklist := Checklist{Name: cl.Name, Items: make([]ChecklistItem, len(cl.Items))}
+		for i, item := range cl.Items {
+			checklist.Items[i] = ChecklistItem{Name: item.Name, Checked: item.Checked}
+		}
+		task.Checklists = append(task.Checklists, checklist)
+	}
+	return task
+}
+
+// Model converts an archived task back into the form migrations work with.
+func (t Task) Model() models.Task {
+	task := models.Task{
+		Id:              t.ID,
+		Name:            t.Name,
+		Description:     t.Description,
+		RichDescription: t.RichDescription,
+		Status:          t.Status,
+		Completed:       t.Completed,
+		Assignees:       fromUsers(t.Assignees),
+		Followers:       fromUsers(t.Followers),
+		DueDate:         t.DueDate,
+		DueHasTime:      t.DueHasTime,
+		StartDate:       t.StartDate,
+		StartHasTime:    t.StartHasTime,
+		TimeZone:        t.TimeZone,
+		Tags:            t.Tags,
+		Priority:        t.Priority,
+	}
+	for _, cf := range t.CustomFields {
+		task.CustomFields = append(task.CustomFields, models.TaskCustomField{FieldID: cf.FieldID, Value: cf.Value})
+	}
+	for _, cl := range t.Checklists {
+		checklist := models.Checklist{Name: cl.Name, Items: make([]models.ChecklistItem, len(cl.Items))}
+		for i, item := range cl.Items {
+			checklist.Items[i] = models.ChecklistItem{Name: item.Name, Checked: item.Checked}
+		}
+		task.Checklists = append(task.Checklists, checklist)
+	}
+	return task
+}
+
+// NewComment converts a comment read from a source client.
+func NewComment(c models.Comment) Comment {
+	return Comment{
+		ID:        c.ID,
+		Author:    User{ID: c.Author.ID, Name: c.Author.Name, Email: c.Author.Email},
+		Text:      c.Text,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+// NewField converts a custom field definition read from a source client.
+func NewField(d models.CustomFieldDefinition, containerID string) Field {
+	field := Field{ContainerID: containerID, ID: d.ID, Name: d.Name, Type: d.ClickUpType}
+	for _, o := range d.Options {
+		field.Options = append(field.Options, FieldOption{ID: o.ID, Name: o.Name, OrderIndex: o.OrderIndex})
+	}
+	return field
+}
+
+// Model converts an archived field back into a custom field definition.
+func (f Field) Model() models.CustomFieldDefinition {
+	def := models.CustomFieldDefinition{ID: f.ID, Name: f.Name, ClickUpType: f.Type}
+	for _, o := range f.Options {
+		def.Options = append(def.Options, models.CustomFieldOption{ID: o.ID, Name: o.Name, OrderIndex: o.OrderIndex})
+	}
+	return def
+}
+
+func toUsers(assignees []models.TaskAssignee) []User {
+	var users []User
+	for _, a := range assignees {
+		users = append(users, User{ID: a.ID, Name: a.Name, Email: a.Email})
+	}
+	return users
+}
+
+func fromUsers(users []User) []models.TaskAssignee {
+	var assignees []models.TaskAssignee
+	for _, u := range users {
+		assignees = append(assignees, models.TaskAssignee{ID: u.ID, Name: u.Name, Email: u.Email})
+	}
+	return assignees
+}
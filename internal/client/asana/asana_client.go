@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/converter"
 	"github.com/TWRT/integration-mapper/internal/models"
 )
 
@@ -33,27 +34,42 @@ func NewAsanaClient(token string) *AsanaClient {
 	}
 }
 
-func parseDueDate(s string) (*time.Time, error) {
-	if s == "" {
-		return nil, nil
+// parseAsanaDate reads an Asana date pair such as due_on/due_at. A datetime wins over the date;
+// date-only values become midnight of that day in loc. It reports whether the value has a time.
+func parseAsanaDate(on, at string, loc *time.Location) (*time.Time, bool, error) {
+	if at != "" {
+		t, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return nil, false, fmt.Errorf("parse datetime (asana): %w", err)
+		}
+		return &t, true, nil
+	}
+	if on == "" {
+		return nil, false, nil
 	}
-	t, err := time.Parse("2006-01-02", s)
+	t, err := time.ParseInLocation("2006-01-02", on, loc)
 	if err != nil {
-		return nil, fmt.Errorf("parse due_date (asana): %w", err)
+		return nil, false, fmt.Errorf("parse date (asana): %w", err)
+	}
+	return &t, false, nil
+}
+
+func formatAsanaDate(t *time.Time, loc *time.Location) string {
+	if t == nil {
+		return ""
 	}
-	utc := time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, time.UTC)
-	return &utc, nil
+	return t.In(loc).Format("2006-01-02")
 }
 
-func formatDueDate(t *time.Time) string {
+func formatAsanaDateTime(t *time.Time) string {
 	if t == nil {
 		return ""
 	}
-	return t.UTC().Format("2006-01-02")
+	return t.UTC().Format(time.RFC3339)
 }
 
 // parseAsanaTask converts an AsanaTasks (API type) into a models.Task (domain type).
-func parseAsanaTask(asanaTask AsanaTasks) (models.Task, error) {
+func parseAsanaTask(asanaTask AsanaTasks, loc *time.Location) (models.Task, error) {
 	status := "Incomplete"
 	if asanaTask.Completed {
 		status = "Completed"
@@ -68,7 +84,19 @@ func parseAsanaTask(asanaTask AsanaTasks) (models.Task, error) {
 		}}
 	}
 
-	dueDate, err := parseDueDate(asanaTask.DueOn)
+	var followers []models.TaskAssignee
+	for _, f := range asanaTask.Followers {
+		if asanaTask.Assignee != nil && f.Gid == asanaTask.Assignee.Gid {
+			continue
+		}
+		followers = append(followers, models.TaskAssignee{ID: f.Gid, Name: f.Name, Email: f.Email})
+	}
+
+	dueDate, dueHasTime, err := parseAsanaDate(asanaTask.DueOn, asanaTask.DueAt, loc)
+	if err != nil {
+		return models.Task{}, err
+	}
+	startDate, startHasTime, err := parseAsanaDate(asanaTask.StartOn, asanaTask.StartAt, loc)
 	if err != nil {
 		return models.Task{}, err
 	}
@@ -86,21 +114,36 @@ func parseAsanaTask(asanaTask AsanaTasks) (models.Task, error) {
 		tags = append(tags, t.Name)
 	}
 
+	// Rich text is best effort: the plain notes are always carried as well.
+	var richDescription string
+	if asanaTask.HTMLNotes != "" {
+		if md, err := converter.AsanaHTMLToMarkdown(asanaTask.HTMLNotes); err == nil {
+			richDescription = md
+		}
+	}
+
 	return models.Task{
-		Id:          asanaTask.Gid,
-		Name:        asanaTask.Name,
-		Description: asanaTask.Notes,
-		Status:      status,
-		Assignees:   assignees,
-		DueDate:     dueDate,
-		Priority:    priority,
-		Tags:        tags,
+		Id:              asanaTask.Gid,
+		Name:            asanaTask.Name,
+		Description:     asanaTask.Notes,
+		RichDescription: richDescription,
+		Status:          status,
+		Completed:       asanaTask.Completed,
+		Assignees:       assignees,
+		Followers:       followers,
+		DueDate:         dueDate,
+		DueHasTime:      dueHasTime,
+		StartDate:       startDate,
+		StartHasTime:    startHasTime,
+		TimeZone:        loc.String(),
+		Priority:        priority,
+		Tags:            tags,
 	}, nil
 }
 
 func (c *AsanaClient) GetTasks(ctx context.Context, projectId string) ([]models.Task, error) {
 	url := c.baseUrl + "/tasks?project=" + projectId +
-		"&opt_fields=name,notes,completed,assignee,assignee.gid,assignee.name,assignee.email,due_on,custom_fields,custom_fields.name,custom_fields.enum_value,custom_fields.enum_value.name,tags,tags.name"
+		"&opt_fields=name,notes,html_notes,completed,assignee,assignee.gid,assignee.name,assignee.email,followers,followers.gid,followers.name,followers.email,due_on,due_at,start_on,start_at,custom_fields,custom_fields.name,custom_fields.enum_value,custom_fields.enum_value.name,tags,tags.name"
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -143,7 +186,7 @@ func (c *AsanaClient) GetTasks(ctx context.Context, projectId string) ([]models.
 
 	tasks := make([]models.Task, len(asanaResp.Data))
 	for i, t := range asanaResp.Data {
-		task, err := parseAsanaTask(t)
+		task, err := parseAsanaTask(t, client.LocationFromContext(ctx))
 		if err != nil {
 			return nil, err
 		}
@@ -153,6 +196,33 @@ func (c *AsanaClient) GetTasks(ctx context.Context, projectId string) ([]models.
 	return tasks, nil
 }
 
+// setAsanaDates fills the due and start dates of a create request. Asana only accepts a start
+// together with a due date, and a start time only together with a due time.
+func setAsanaDates(reqBody *CreateTaskRequest, task models.Task, loc *time.Location) {
+	if task.DueDate == nil {
+		return
+	}
+	if task.DueHasTime {
+		reqBody.DueAt = formatAsanaDateTime(task.DueDate)
+	} else {
+		reqBody.DueOn = formatAsanaDate(task.DueDate, loc)
+	}
+
+	if task.StartDate == nil || task.StartDate.After(*task.DueDate) {
+		return
+	}
+	if task.StartHasTime && task.DueHasTime {
+		reqBody.StartAt = formatAsanaDateTime(task.StartDate)
+	} else {
+		reqBody.StartOn = formatAsanaDate(task.StartDate, loc)
+	}
+}
+
+// MaxAssignees reports that Asana tasks have at most one assignee.
+func (c *AsanaClient) MaxAssignees() int {
+	return 1
+}
+
 // CreateTask creates a task in Asana. The projectId param may be in the form
 // "projectGid|sectionGid" to place the task inside a specific section.
 func (c *AsanaClient) CreateTask(ctx context.Context, projectId string, workspaceId string, task models.Task) (*models.Task, error) {
@@ -168,17 +238,28 @@ func (c *AsanaClient) CreateTask(ctx context.Context, projectId string, workspac
 		Name:      task.Name,
 		Notes:     task.Description,
 		Completed: task.Status == "Completed",
-		DueOn:     formatDueDate(task.DueDate),
 	}
+	if task.RichDescription != "" {
+		reqBody.Notes = ""
+		reqBody.HTMLNotes = converter.MarkdownToAsanaHTML(task.RichDescription)
+	}
+	setAsanaDates(&reqBody, task, client.TaskLocation(ctx, task))
 
 	reqBody.Projects = []string{actualProjectId}
 	if sectionId != "" {
 		reqBody.Memberships = []AsanaMembership{{Project: actualProjectId, Section: sectionId}}
 	}
 
+	// Asana tasks have a single assignee; any others are added as followers.
 	if len(task.Assignees) > 0 {
 		reqBody.Assignee = task.Assignees[0].ID
 	}
+	for _, a := range task.Assignees[min(1, len(task.Assignees)):] {
+		reqBody.Followers = append(reqBody.Followers, a.ID)
+	}
+	for _, f := range task.Followers {
+		reqBody.Followers = append(reqBody.Followers, f.ID)
+	}
 
 	reqBody.CustomFields = make(map[string]interface{})
 
@@ -429,8 +510,13 @@ func (c *AsanaClient) GetProjects(ctx context.Context, workspaceId string) ([]Ge
 	return asanaResp.Data, nil
 }
 
-func (c *AsanaClient) fetchTagsFromAPI(ctx context.Context, workspaceId string) (map[string]string, error) {
-	tagMap := make(map[string]string)
+// GetTeamProjects returns the unarchived projects of an Asana team.
+func (c *AsanaClient) GetTeamProjects(ctx context.Context, teamId string) ([]GetMultipleProjectsResponse, error) {
+	return getAllPages[GetMultipleProjectsResponse](ctx, c, "/teams/"+teamId+"/projects?archived=false&opt_fields=name", "team projects")
+}
+
+func (c *AsanaClient) fetchTagsFromAPI(ctx context.Context, workspaceId string) ([]AsanaTag, error) {
+	var tags []AsanaTag
 	offset := ""
 
 	for {
@@ -478,9 +564,7 @@ func (c *AsanaClient) fetchTagsFromAPI(ctx context.Context, workspaceId string)
 			return nil, fmt.Errorf("parse tags (asana): %w", err)
 		}
 
-		for _, tag := range result.Data {
-			tagMap[strings.ToLower(tag.Name)] = tag.Gid
-		}
+		tags = append(tags, result.Data...)
 
 		if result.NextPage == nil || result.NextPage.Offset == "" {
 			break
@@ -488,7 +572,7 @@ func (c *AsanaClient) fetchTagsFromAPI(ctx context.Context, workspaceId string)
 		offset = result.NextPage.Offset
 	}
 
-	return tagMap, nil
+	return tags, nil
 }
 
 func (c *AsanaClient) GetTagsForWorkspace(ctx context.Context, workspaceId string) (map[string]string, error) {
@@ -501,10 +585,14 @@ func (c *AsanaClient) GetTagsForWorkspace(ctx context.Context, workspaceId strin
 	c.tagCacheMu.RUnlock()
 
 	// Slow path: fetch from API without holding the lock
-	tagMap, err := c.fetchTagsFromAPI(ctx, workspaceId)
+	tags, err := c.fetchTagsFromAPI(ctx, workspaceId)
 	if err != nil {
 		return nil, err
 	}
+	tagMap := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		tagMap[strings.ToLower(tag.Name)] = tag.Gid
+	}
 
 	// Double-check: another goroutine may have populated the cache while we fetched
 	c.tagCacheMu.Lock()
@@ -567,9 +655,42 @@ func (c *AsanaClient) CreateTag(ctx context.Context, workspaceId, name string) (
 		return "", fmt.Errorf("parse create tag response (asana): %w", err)
 	}
 
+	c.tagCacheMu.Lock()
+	if cached, ok := c.tagCache[workspaceId]; ok {
+		cached[strings.ToLower(name)] = result.Data.Gid
+	}
+	c.tagCacheMu.Unlock()
+
 	return result.Data.Gid, nil
 }
 
+// ListTags returns the names of all tags of an Asana workspace, fetched fresh from the API.
+func (c *AsanaClient) ListTags(ctx context.Context, workspaceId string) ([]string, error) {
+	tags, err := c.fetchTagsFromAPI(ctx, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names, nil
+}
+
+// FindTag looks up a workspace tag by name (case-insensitive) using the tag cache.
+func (c *AsanaClient) FindTag(ctx context.Context, workspaceId, name string) (string, bool, error) {
+	tags, err := c.GetTagsForWorkspace(ctx, workspaceId)
+	if err != nil {
+		return "", false, err
+	}
+
+	c.tagCacheMu.RLock()
+	gid, ok := tags[strings.ToLower(name)]
+	c.tagCacheMu.RUnlock()
+
+	return gid, ok, nil
+}
+
 // GetSourceContainers returns the sections of an Asana project (used as source containers).
 func (c *AsanaClient) GetSourceContainers(ctx context.Context, projectId string) ([]client.Container, error) {
 	sections, err := c.GetSections(ctx, projectId)
@@ -636,7 +757,7 @@ func (c *AsanaClient) GetSections(ctx context.Context, projectId string) ([]Asan
 // GetTasksBySection fetches all tasks belonging to a specific Asana section.
 func (c *AsanaClient) GetTasksBySection(ctx context.Context, sectionId string) ([]models.Task, error) {
 	url := c.baseUrl + "/tasks?section=" + sectionId +
-		"&opt_fields=name,notes,completed,assignee,assignee.gid,assignee.name,assignee.email,due_on,custom_fields,custom_fields.name,custom_fields.enum_value,custom_fields.enum_value.name,tags,tags.name"
+		"&opt_fields=name,notes,html_notes,completed,assignee,assignee.gid,assignee.name,assignee.email,followers,followers.gid,followers.name,followers.email,due_on,due_at,start_on,start_at,custom_fields,custom_fields.name,custom_fields.enum_value,custom_fields.enum_value.name,tags,tags.name"
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -670,7 +791,7 @@ func (c *AsanaClient) GetTasksBySection(ctx context.Context, sectionId string) (
 
 	tasks := make([]models.Task, len(asanaResp.Data))
 	for i, t := range asanaResp.Data {
-		task, err := parseAsanaTask(t)
+		task, err := parseAsanaTask(t, client.LocationFromContext(ctx))
 		if err != nil {
 			return nil, err
 		}
@@ -834,6 +955,78 @@ func (c *AsanaClient) GetProjectCustomField(ctx context.Context, projectGid, nam
 	return "", nil, false, nil
 }
 
+// GetDestFieldDefinitions lists the custom fields attached to a project, with their types
+// expressed in the ClickUp vocabulary used by models.CustomFieldDefinition.
+func (c *AsanaClient) GetDestFieldDefinitions(ctx context.Context, projectGid string) ([]models.CustomFieldDefinition, error) {
+	url := c.baseUrl + "/projects/" + projectGid + "/custom_field_settings" +
+		"?opt_fields=custom_field.gid,custom_field.name,custom_field.resource_subtype,custom_field.enum_options,custom_field.enum_options.gid,custom_field.enum_options.name"
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request (asana project custom fields): %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get project custom fields (asana): %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response (asana project custom fields): %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var asanaErr AsanaErrors
+		if err := json.Unmarshal(body, &asanaErr); err != nil {
+			return nil, fmt.Errorf("error status (asana): %d", resp.StatusCode)
+		}
+		if len(asanaErr.Errors) > 0 {
+			return nil, fmt.Errorf("Asana error: %s", asanaErr.Errors[0].Message)
+		}
+		return nil, fmt.Errorf("API error status: %d", resp.StatusCode)
+	}
+
+	var result AsanaResponse[AsanaCustomFieldSetting]
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parse project custom fields (asana): %w", err)
+	}
+
+	defs := make([]models.CustomFieldDefinition, 0, len(result.Data))
+	for _, setting := range result.Data {
+		cf := setting.CustomField
+		def := models.CustomFieldDefinition{
+			ID:          cf.Gid,
+			Name:        cf.Name,
+			ClickUpType: asanaSubtypeToClickUpType(cf.ResourceSubtype),
+		}
+		for i, opt := range cf.EnumOptions {
+			def.Options = append(def.Options, models.CustomFieldOption{ID: opt.Gid, Name: opt.Name, OrderIndex: i})
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+func asanaSubtypeToClickUpType(subtype string) string {
+	switch subtype {
+	case "enum":
+		return "drop_down"
+	case "multi_enum":
+		return "labels"
+	case "number":
+		return "number"
+	case "date":
+		return "date"
+	case "people":
+		return "users"
+	default:
+		return "text"
+	}
+}
+
 func (c *AsanaClient) FindCustomFieldByName(ctx context.Context, workspaceId, name string) (string, []string, error) {
 	baseURL := fmt.Sprintf("%s/workspaces/%s/custom_fields?opt_fields=gid,name,enum_options,enum_options.gid,enum_options.name&limit=100", c.baseUrl, workspaceId)
 	nextURL := baseURL
@@ -931,3 +1124,101 @@ func (c *AsanaClient) AttachCustomFieldToProject(ctx context.Context, projectGid
 
 	return nil
 }
+
+// deleteResource issues a DELETE against the given API path. A 404 is treated as success
+// so that rollbacks can be retried after partial failures.
+func (c *AsanaClient) deleteResource(ctx context.Context, path, what string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseUrl+path, nil)
+	if err != nil {
+		return fmt.Errorf("build request (asana delete %s): %w", what, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("delete %s (asana): %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	errorBody, _ := io.ReadAll(resp.Body) //nolint:errcheck // best-effort read for error message
+	var asanaErr AsanaErrors
+	if err := json.Unmarshal(errorBody, &asanaErr); err == nil && len(asanaErr.Errors) > 0 {
+		return fmt.Errorf("Asana error: %s", asanaErr.Errors[0].Message)
+	}
+	return fmt.Errorf("API error status (asana delete %s): %d", what, resp.StatusCode)
+}
+
+func (c *AsanaClient) DeleteTask(ctx context.Context, taskId string) error {
+	return c.deleteResource(ctx, "/tasks/"+taskId, "task")
+}
+
+func (c *AsanaClient) DeleteCustomField(ctx context.Context, fieldGid string) error {
+	return c.deleteResource(ctx, "/custom_fields/"+fieldGid, "custom field")
+}
+
+func (c *AsanaClient) DeleteTag(ctx context.Context, tagGid string) error {
+	if err := c.deleteResource(ctx, "/tags/"+tagGid, "tag"); err != nil {
+		return err
+	}
+
+	c.tagCacheMu.Lock()
+	for _, tags := range c.tagCache {
+		for name, gid := range tags {
+			if gid == tagGid {
+				delete(tags, name)
+			}
+		}
+	}
+	c.tagCacheMu.Unlock()
+
+	return nil
+}
+
+// DeleteContainer deletes an Asana section. Asana only allows deleting empty sections.
+func (c *AsanaClient) DeleteContainer(ctx context.Context, sectionId string) error {
+	return c.deleteResource(ctx, "/sections/"+sectionId, "section")
+}
+
+// TaskURL returns the Asana web link of a task.
+func (c *AsanaClient) TaskURL(taskId string) string {
+	return "https://app.asana.com/0/0/" + taskId
+}
+
+// CreateComment adds a comment (story) to an Asana task.
+func (c *AsanaClient) CreateComment(ctx context.Context, taskId, text string) error {
+	body, err := json.Marshal(CreateStoryRequestWrapper{Data: CreateStoryRequest{Text: text}})
+	if err != nil {
+		return fmt.Errorf("marshal create comment request (asana): %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseUrl+"/tasks/"+taskId+"/stories", bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("build request (asana create comment): %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("create comment (asana): %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		errorBody, _ := io.ReadAll(resp.Body) //nolint:errcheck // best-effort read for error message
+		var asanaErr AsanaErrors
+		if err := json.Unmarshal(errorBody, &asanaErr); err != nil {
+			return fmt.Errorf("error status (asana create comment): %d", resp.StatusCode)
+		}
+		if len(asanaErr.Errors) > 0 {
+			return fmt.Errorf("Asana error: %s", asanaErr.Errors[0].Message)
+		}
+		return fmt.Errorf("API error status: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// getAllPages fetches every page of a paginated Asana collection starting at path.
+func getAllPages[T any](ctx context.Context, c *AsanaClient, path, what string) ([]T, error) {
+	var all []T
+	offset := ""
+	for {
+		pageURL := c.baseUrl + path + "&limit=100"
+		if offset != "" {
+			pageURL += "&offset=" + url.QueryEscape(offset)
+		}
+		req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("build request (asana get %s): %w", what, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("get %s (asana): %w", what, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read response body (asana get %s): %w", what, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			var asanaErr AsanaErrors
+			if err := json.Unmarshal(body, &asanaErr); err == nil && len(asanaErr.Errors) > 0 {
+				return nil, fmt.Errorf("Asana error: %s", asanaErr.Errors[0].Message)
+			}
+			return nil, fmt.Errorf("API error status (asana get %s): %d", what, resp.StatusCode)
+		}
+
+		var page AsanaResponse[T]
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("parse %s (asana): %w", what, err)
+		}
+		all = append(all, page.Data...)
+		if page.NextPage == nil || page.NextPage.Offset == "" {
+			return all, nil
+		}
+		offset = page.NextPage.Offset
+	}
+}
+
+// GetComments returns the comments of an Asana task, oldest first. System stories
+// (assignments, moves, ...) are left out.
+func (c *AsanaClient) GetComments(ctx context.Context, taskId string) ([]models.Comment, error) {
+	stories, err := getAllPages[AsanaStory](ctx, c,
+		"/tasks/"+taskId+"/stories?opt_fields=type,text,created_at,created_by.name,created_by.email", "stories")
+	if err != nil {
+		return nil, err
+	}
+	var comments []models.Comment
+	for _, s := range stories {
+		if s.Type != "comment" {
+			continue
+		}
+		comment := models.Comment{ID: s.Gid, Text: s.Text, CreatedAt: s.CreatedAt}
+		if s.CreatedBy != nil {
+			comment.Author = models.TaskAssignee{ID: s.CreatedBy.Gid, Name: s.CreatedBy.Name, Email: s.CreatedBy.Email}
+		}
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+// GetAttachments returns the attachments of an Asana task. Files linked from other services
+// (Google Drive, Dropbox, ...) carry their view link and cannot be downloaded.
+func (c *AsanaClient) GetAttachments(ctx context.Context, taskId string) ([]models.Attachment, error) {
+	attachments, err := getAllPages[AsanaAttachment](ctx, c,
+		"/attachments?parent="+taskId+"&opt_fields=name,host,download_url,view_url,size", "attachments")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.Attachment, len(attachments))
+	for i, a := range attachments {
+		link := a.DownloadUrl
+		if link == "" {
+			link = a.ViewUrl
+		}
+		result[i] = models.Attachment{ID: a.Gid, Name: a.Name, URL: link, Size: a.Size}
+	}
+	return result, nil
+}
+
+// OpenAttachment downloads an attachment. Asana download links are pre-signed and
+// expire after a few minutes, so attachments must be opened soon after listing them.
+func (c *AsanaClient) OpenAttachment(ctx context.Context, attachment models.Attachment) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", attachment.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request (asana download attachment): %w", err)
+	}
+	// The download has no client timeout: files can be large.
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download attachment (asana): %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("API error status (asana download attachment): %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,62 @@
+package asana
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+)
+
+// Name is the provider name migrations use for Asana.
+const Name = "asana"
+
+// Descriptor registers the Asana client. Destination tasks are created in a section of the
+// migration's project, addressed as "project|section".
+func Descriptor(c *AsanaClient) client.Descriptor {
+	return client.Descriptor{
+		Name:        Name,
+		DisplayName: "Asana",
+		Client:      c,
+		Validate: func(dest client.Destination) error {
+			if dest.WorkspaceID == "" {
+				return fmt.Errorf("dest_workspace_id is required")
+			}
+			if dest.ListID == "" {
+				return fmt.Errorf("dest_list_id (project GID) is required for Asana destination")
+			}
+			return nil
+		},
+		ContainerAddress: func(dest client.Destination, containerID string) string {
+			return dest.ListID + "|" + containerID
+		},
+		DefaultPriorities: []string{"High", "Medium", "Low"},
+		OAuth:             &OAuthEndpoint,
+		ConnectionClient:  newConnectionClient,
+	}
+}
+
+// ListWorkspaces implements client.WorkspaceBrowser.
+func (c *AsanaClient) ListWorkspaces(ctx context.Context) ([]client.Container, error) {
+	workspaces, err := c.GetWorkspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]client.Container, len(workspaces))
+	for i, w := range workspaces {
+		result[i] = client.Container{ID: w.Id, Name: w.Name}
+	}
+	return result, nil
+}
+
+// ListProjects implements client.WorkspaceBrowser.
+func (c *AsanaClient) ListProjects(ctx context.Context, workspaceId string) ([]client.Container, error) {
+	projects, err := c.GetProjects(ctx, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]client.Container, len(projects))
+	for i, p := range projects {
+		result[i] = client.Container{ID: p.Id, Name: p.Name}
+	}
+	return result, nil
+}
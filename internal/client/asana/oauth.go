@@ -0,0 +1,24 @@
+package asana
+
+import (
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/oauth"
+)
+
+// OAuthEndpoint is Asana's OAuth app endpoint. Access tokens expire after an hour and are
+// renewed with the refresh token, which does not change.
+var OAuthEndpoint = oauth.Endpoint{
+	AuthURL:  "https://app.asana.com/-/oauth_authorize",
+	TokenURL: "https://app.asana.com/-/oauth_token",
+}
+
+// NewAsanaOAuthClient returns a client acting as the user behind an OAuth connection.
+func NewAsanaOAuthClient(source oauth.TokenSource) *AsanaClient {
+	c := NewAsanaClient("")
+	c.httpClient.Transport = &oauth.Transport{Source: source}
+	return c
+}
+
+func newConnectionClient(source oauth.TokenSource) client.IntegrationProvider {
+	return NewAsanaOAuthClient(source)
+}
@@ -1,12 +1,14 @@
 package asana
 
+import "time"
+
 type AsanaNextPage struct {
 	Offset string `json:"offset"`
 }
 
 type AsanaResponse[T any] struct {
-	Data     []T             `json:"data"`
-	NextPage *AsanaNextPage  `json:"next_page"`
+	Data     []T            `json:"data"`
+	NextPage *AsanaNextPage `json:"next_page"`
 }
 
 type AsanaSingleResponse[T any] struct {
@@ -36,9 +38,10 @@ type AsanaEnumOption struct {
 }
 
 type AsanaProjectCustomField struct {
-	Gid         string            `json:"gid"`
-	Name        string            `json:"name"`
-	EnumOptions []AsanaEnumOption `json:"enum_options"`
+	Gid             string            `json:"gid"`
+	Name            string            `json:"name"`
+	ResourceSubtype string            `json:"resource_subtype"`
+	EnumOptions     []AsanaEnumOption `json:"enum_options"`
 }
 
 type AsanaProject struct {
@@ -60,9 +63,14 @@ type AsanaTasks struct {
 	Gid          string             `json:"gid"`
 	Name         string             `json:"name"`
 	Notes        string             `json:"notes"`
+	HTMLNotes    string             `json:"html_notes"`
 	Completed    bool               `json:"completed"`
 	Assignee     *AsanaUser         `json:"assignee"`
+	Followers    []AsanaUser        `json:"followers"`
 	DueOn        string             `json:"due_on"`
+	DueAt        string             `json:"due_at"`
+	StartOn      string             `json:"start_on"`
+	StartAt      string             `json:"start_at"`
 	CustomFields []AsanaCustomField `json:"custom_fields"`
 	Tags         []AsanaTag         `json:"tags"`
 }
@@ -79,11 +87,16 @@ type AsanaErrors struct {
 type CreateTaskRequest struct {
 	Name         string                 `json:"name"`
 	Notes        string                 `json:"notes,omitempty"`
+	HTMLNotes    string                 `json:"html_notes,omitempty"`
 	Projects     []string               `json:"projects,omitempty"`
 	Memberships  []AsanaMembership      `json:"memberships,omitempty"`
 	Completed    bool                   `json:"completed"`
 	Assignee     string                 `json:"assignee,omitempty"`
+	Followers    []string               `json:"followers,omitempty"`
 	DueOn        string                 `json:"due_on,omitempty"`
+	DueAt        string                 `json:"due_at,omitempty"`
+	StartOn      string                 `json:"start_on,omitempty"`
+	StartAt      string                 `json:"start_at,omitempty"`
 	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
 	Tags         []string               `json:"tags,omitempty"`
 }
@@ -135,6 +148,14 @@ type CreateTagRequestWrapper struct {
 	Data CreateTagRequest `json:"data"`
 }
 
+type CreateStoryRequest struct {
+	Text string `json:"text"`
+}
+
+type CreateStoryRequestWrapper struct {
+	Data CreateStoryRequest `json:"data"`
+}
+
 type CreateTagResponse struct {
 	Data AsanaTag `json:"data"`
 }
@@ -173,3 +194,20 @@ type AsanaMembership struct {
 	Project string `json:"project"`
 	Section string `json:"section"`
 }
+
+type AsanaStory struct {
+	Gid       string     `json:"gid"`
+	Type      string     `json:"type"` // comment, system
+	Text      string     `json:"text"`
+	CreatedAt time.Time  `json:"created_at"`
+	CreatedBy *AsanaUser `json:"created_by"`
+}
+
+type AsanaAttachment struct {
+	Gid         string `json:"gid"`
+	Name        string `json:"name"`
+	Host        string `json:"host"` // asana for uploaded files, otherwise the linking service
+	DownloadUrl string `json:"download_url"`
+	ViewUrl     string `json:"view_url"`
+	Size        int64  `json:"size"`
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/converter"
 	"github.com/TWRT/integration-mapper/internal/models"
 )
 
@@ -45,26 +47,44 @@ func NewClickUpClient(token string) *ClickUpClient {
 	}
 }
 
-func parseClickUpDueDate(s string) (*time.Time, error) {
+// parseClickUpDate reads a ClickUp millisecond timestamp. When the value has no time of day
+// (due_date_time/start_date_time false) it becomes midnight of its calendar day in loc.
+func parseClickUpDate(s string, hasTime bool, loc *time.Location) (*time.Time, error) {
 	if s == "" {
 		return nil, nil
 	}
 	ms, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("parse due_date (clickup): %w", err)
+		return nil, fmt.Errorf("parse date (clickup): %w", err)
 	}
 	t := time.UnixMilli(ms).UTC()
+	if !hasTime {
+		local := t.In(loc)
+		t = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	}
 	return &t, nil
 }
 
-func timeToMs(t *time.Time) *int64 {
+// timeToMs converts a date to a ClickUp timestamp. Date-only values are sent as midnight
+// of their day in loc.
+func timeToMs(t *time.Time, hasTime bool, loc *time.Location) *int64 {
 	if t == nil {
 		return nil
 	}
+	if !hasTime {
+		local := t.In(loc)
+		midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		t = &midnight
+	}
 	ms := t.UnixMilli()
 	return &ms
 }
 
+// isClosedStatus reports whether a ClickUp status type marks the task as done.
+func isClosedStatus(statusType string) bool {
+	return statusType == "closed" || statusType == "done"
+}
+
 func priorityStringToInt(p string) *int {
 	m := map[string]int{
 		"urgent": 1,
@@ -79,7 +99,7 @@ func priorityStringToInt(p string) *int {
 }
 
 func (c *ClickUpClient) GetTasks(ctx context.Context, listId string) ([]models.Task, error) {
-	url := c.baseUrl + "/list/" + listId + "/task?include_closed=true"
+	url := c.baseUrl + "/list/" + listId + "/task?include_closed=true&include_markdown_description=true"
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -120,6 +140,7 @@ func (c *ClickUpClient) GetTasks(ctx context.Context, listId string) ([]models.T
 		return nil, fmt.Errorf("parse tasks (clickup): %w", err)
 	}
 
+	loc := client.LocationFromContext(ctx)
 	tasks := make([]models.Task, len(clickUpResp.Tasks))
 	for i, clickUpTask := range clickUpResp.Tasks {
 		assignees := make([]models.TaskAssignee, 0, len(clickUpTask.Assignees))
@@ -131,7 +152,27 @@ func (c *ClickUpClient) GetTasks(ctx context.Context, listId string) ([]models.T
 			})
 		}
 
-		dueDate, err := parseClickUpDueDate(clickUpTask.DueDate)
+		isAssignee := make(map[int]bool, len(clickUpTask.Assignees))
+		for _, a := range clickUpTask.Assignees {
+			isAssignee[a.Id] = true
+		}
+		followers := make([]models.TaskAssignee, 0, len(clickUpTask.Watchers))
+		for _, w := range clickUpTask.Watchers {
+			if isAssignee[w.Id] {
+				continue
+			}
+			followers = append(followers, models.TaskAssignee{
+				ID:    fmt.Sprintf("%d", w.Id),
+				Name:  w.Username,
+				Email: w.Email,
+			})
+		}
+
+		dueDate, err := parseClickUpDate(clickUpTask.DueDate, clickUpTask.DueDateTime, loc)
+		if err != nil {
+			return nil, err
+		}
+		startDate, err := parseClickUpDate(clickUpTask.StartDate, clickUpTask.StartDateTime, loc)
 		if err != nil {
 			return nil, err
 		}
@@ -162,15 +203,22 @@ func (c *ClickUpClient) GetTasks(ctx context.Context, listId string) ([]models.T
 		}
 
 		tasks[i] = models.Task{
-			Id:           clickUpTask.Id,
-			Name:         clickUpTask.Name,
-			Description:  clickUpTask.Description,
-			Status:       clickUpTask.Status.Status,
-			Assignees:    assignees,
-			DueDate:      dueDate,
-			Priority:     priority,
-			Tags:         tags,
-			CustomFields: customFields,
+			Id:              clickUpTask.Id,
+			Name:            clickUpTask.Name,
+			Description:     clickUpTask.Description,
+			RichDescription: clickUpTask.MarkdownDescription,
+			Status:          clickUpTask.Status.Status,
+			Completed:       isClosedStatus(clickUpTask.Status.Type),
+			Assignees:       assignees,
+			Followers:       followers,
+			DueDate:         dueDate,
+			DueHasTime:      dueDate != nil && clickUpTask.DueDateTime,
+			StartDate:       startDate,
+			StartHasTime:    startDate != nil && clickUpTask.StartDateTime,
+			TimeZone:        loc.String(),
+			Priority:        priority,
+			Tags:            tags,
+			CustomFields:    customFields,
 		}
 	}
 
@@ -210,24 +258,30 @@ func (c *ClickUpClient) GetFieldDefinitions(ctx context.Context, listId string)
 	return defs, nil
 }
 
+// CreateTask creates a task in a ClickUp list. All assignees are kept; followers become
+// watchers of the task.
 func (c *ClickUpClient) CreateTask(ctx context.Context, listId string, _ string, task models.Task) (*models.Task, error) {
-	assignees := make([]int, 0, len(task.Assignees))
-	for _, a := range task.Assignees {
-		id, err := strconv.Atoi(a.ID)
-		if err != nil {
-			continue
-		}
-		assignees = append(assignees, id)
-	}
+	assignees := userIDs(task.Assignees)
 
+	loc := client.TaskLocation(ctx, task)
 	reqBody := CreateTaskRequest{
-		Name:        task.Name,
-		Description: task.Description,
-		Status:      task.Status,
-		Assignees:   assignees,
-		DueDate:     timeToMs(task.DueDate),
-		Priority:    priorityStringToInt(task.Priority),
-		Tags:        task.Tags,
+		Name:            task.Name,
+		Description:     task.Description,
+		MarkdownContent: converter.PlainMentions(task.RichDescription),
+		Status:          task.Status,
+		Assignees:       assignees,
+		DueDate:         timeToMs(task.DueDate, task.DueHasTime, loc),
+		DueDateTime:     task.DueDate != nil && task.DueHasTime,
+		StartDate:       timeToMs(task.StartDate, task.StartHasTime, loc),
+		StartDateTime:   task.StartDate != nil && task.StartHasTime,
+		Priority:        priorityStringToInt(task.Priority),
+		Tags:            task.Tags,
+	}
+
+	for _, cf := range task.CustomFields {
+		if cf.Value != nil {
+			reqBody.CustomFields = append(reqBody.CustomFields, ClickUpCustomFieldValue{ID: cf.FieldID, Value: cf.Value})
+		}
 	}
 
 	url := c.baseUrl + "/list/" + listId + "/task"
@@ -277,6 +331,17 @@ func (c *ClickUpClient) CreateTask(ctx context.Context, listId string, _ string,
 		return nil, fmt.Errorf("parse create task response (clickup): %w", err)
 	}
 
+	// Watchers can only be added once the task exists. A task whose watchers could not be
+	// written is removed again so the task is reported as failed as a whole.
+	if watchers := userIDs(task.Followers); len(watchers) > 0 {
+		if err := c.addWatchers(ctx, createdTask.Id, watchers); err != nil {
+			if delErr := c.DeleteTask(ctx, createdTask.Id); delErr != nil {
+				return nil, errors.Join(err, delErr)
+			}
+			return nil, err
+		}
+	}
+
 	return &models.Task{
 		Id:     createdTask.Id,
 		Name:   createdTask.Name,
@@ -284,6 +349,58 @@ func (c *ClickUpClient) CreateTask(ctx context.Context, listId string, _ string,
 	}, nil
 }
 
+// userIDs returns the numeric ClickUp IDs of users; IDs that are not numbers are skipped.
+func userIDs(users []models.TaskAssignee) []int {
+	ids := make([]int, 0, len(users))
+	for _, u := range users {
+		id, err := strconv.Atoi(u.ID)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// addWatchers makes users watchers of a task.
+func (c *ClickUpClient) addWatchers(ctx context.Context, taskId string, users []int) error {
+	body, err := json.Marshal(UpdateTaskWatchersRequest{Watchers: ClickUpUserChanges{Add: users}})
+	if err != nil {
+		return fmt.Errorf("marshal update task request (clickup): %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", c.baseUrl+"/task/"+taskId, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("build request (clickup): %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("add task watchers (clickup): %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errorBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("read error body (clickup): %w", err)
+		}
+
+		var clickupErr ClickUpErrors
+		if err := json.Unmarshal(errorBody, &clickupErr); err != nil {
+			return fmt.Errorf("error status (clickup): %d", resp.StatusCode)
+		}
+		if len(clickupErr.Err) > 0 {
+			return fmt.Errorf("ClickUp error: %s", clickupErr.Err)
+		}
+		return fmt.Errorf("API error status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
 func (c *ClickUpClient) GetWorkspaces(ctx context.Context) ([]ClickUpTeams, error) {
 	url := c.baseUrl + "/team"
 
@@ -523,11 +640,6 @@ func (c *ClickUpClient) GetTasksByContainer(ctx context.Context, listId string)
 	return c.GetTasks(ctx, listId)
 }
 
-// GetDestContainers returns the lists of a ClickUp space (used as destination containers).
-func (c *ClickUpClient) GetDestContainers(ctx context.Context, spaceId string) ([]client.Container, error) {
-	return c.GetSourceContainers(ctx, spaceId)
-}
-
 func (c *ClickUpClient) GetListStatuses(ctx context.Context, listId string) ([]string, error) {
 	url := c.baseUrl + "/list/" + listId
 
@@ -576,3 +688,125 @@ func (c *ClickUpClient) GetListStatuses(ctx context.Context, listId string) ([]s
 	}
 	return statuses, nil
 }
+
+// ListTags returns the names of the tags of a ClickUp space.
+func (c *ClickUpClient) ListTags(ctx context.Context, spaceId string) ([]string, error) {
+	url := c.baseUrl + "/space/" + spaceId + "/tag"
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request (clickup): %w", err)
+	}
+
+	req.Header.Set("Authorization", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get space tags (clickup): %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body (clickup): %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var clickupErr ClickUpErrors
+		if err := json.Unmarshal(body, &clickupErr); err == nil && clickupErr.Err != "" {
+			return nil, fmt.Errorf("ClickUp error: %s", clickupErr.Err)
+		}
+		return nil, fmt.Errorf("API error status %d", resp.StatusCode)
+	}
+
+	var clickupResp GetSpaceTagsResponse
+	if err := json.Unmarshal(body, &clickupResp); err != nil {
+		return nil, fmt.Errorf("parse space tags (clickup): %w", err)
+	}
+
+	names := make([]string, 0, len(clickupResp.Tags))
+	for _, t := range clickupResp.Tags {
+		names = append(names, t.Name)
+	}
+	return names, nil
+}
+
+// deleteResource issues a DELETE against the given API path. A 404 is treated as success
+// so that rollbacks can be retried after partial failures.
+func (c *ClickUpClient) deleteResource(ctx context.Context, path, what string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseUrl+path, nil)
+	if err != nil {
+		return fmt.Errorf("build request (clickup): %w", err)
+	}
+	req.Header.Set("Authorization", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("delete %s (clickup): %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	errorBody, _ := io.ReadAll(resp.Body) //nolint:errcheck // best-effort read for error message
+	var clickupErr ClickUpErrors
+	if err := json.Unmarshal(errorBody, &clickupErr); err == nil && clickupErr.Err != "" {
+		return fmt.Errorf("ClickUp error: %s", clickupErr.Err)
+	}
+	return fmt.Errorf("API error status (clickup delete %s): %d", what, resp.StatusCode)
+}
+
+func (c *ClickUpClient) DeleteTask(ctx context.Context, taskId string) error {
+	return c.deleteResource(ctx, "/task/"+taskId, "task")
+}
+
+// DeleteContainer deletes a ClickUp list.
+func (c *ClickUpClient) DeleteContainer(ctx context.Context, listId string) error {
+	return c.deleteResource(ctx, "/list/"+listId, "list")
+}
+
+// TaskURL returns the ClickUp web link of a task.
+func (c *ClickUpClient) TaskURL(taskId string) string {
+	return "https://app.clickup.com/t/" + taskId
+}
+
+// CreateComment adds a comment to a ClickUp task without notifying watchers.
+func (c *ClickUpClient) CreateComment(ctx context.Context, taskId, text string) error {
+	body, err := json.Marshal(CreateCommentRequest{CommentText: text})
+	if err != nil {
+		return fmt.Errorf("marshal create comment request (clickup): %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseUrl+"/task/"+taskId+"/comment", bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("build request (clickup): %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("create comment (clickup): %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errorBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("read error body (clickup): %w", err)
+		}
+
+		var clickupErr ClickUpErrors
+		if err := json.Unmarshal(errorBody, &clickupErr); err != nil {
+			return fmt.Errorf("error status (clickup): %d", resp.StatusCode)
+		}
+		if len(clickupErr.Err) > 0 {
+			return fmt.Errorf("ClickUp error: %s", clickupErr.Err)
+		}
+		return fmt.Errorf("API error status: %d", resp.StatusCode)
+	}
+	return nil
+}
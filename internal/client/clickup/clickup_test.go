@@ -0,0 +1,125 @@
+package clickup
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// fakeClickUp records the task requests the client sends to the ClickUp API.
+type fakeClickUp struct {
+	created       []CreateTaskRequest
+	watchers      map[string]UpdateTaskWatchersRequest // taskId → update
+	failWatchers  bool
+	deletedTasks  []string
+	authorization []string
+}
+
+func (f *fakeClickUp) start(t *testing.T) *ClickUpClient {
+	t.Helper()
+	f.watchers = make(map[string]UpdateTaskWatchersRequest)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /list/{id}/task", func(w http.ResponseWriter, r *http.Request) {
+		var req CreateTaskRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.created = append(f.created, req)
+		writeTestJSON(w, ClickUpTask{Id: "t1", Name: req.Name})
+	})
+	mux.HandleFunc("PUT /task/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if f.failWatchers {
+			w.WriteHeader(http.StatusBadRequest)
+			writeTestJSON(w, ClickUpErrors{Err: "invalid watcher"})
+			return
+		}
+		var req UpdateTaskWatchersRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.watchers[r.PathValue("id")] = req
+		writeTestJSON(w, ClickUpTask{Id: r.PathValue("id")})
+	})
+	mux.HandleFunc("DELETE /task/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.deletedTasks = append(f.deletedTasks, r.PathValue("id"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.authorization = append(f.authorization, r.Header.Get("Authorization"))
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClickUpClient("pk_test")
+	c.baseUrl = srv.URL
+	c.httpClient = srv.Client()
+	return c
+}
+
+func writeTestJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v) //nolint:errcheck // test server
+}
+
+func TestCreateTaskWritesAssigneesAndWatchers(t *testing.T) {
+	f := &fakeClickUp{}
+	c := f.start(t)
+
+	created, err := c.CreateTask(context.Background(), "l1", "", models.Task{
+		Name:      "Ship it",
+		Assignees: []models.TaskAssignee{{ID: "11"}, {ID: "12"}},
+		Followers: []models.TaskAssignee{{ID: "21"}, {ID: "not-a-user"}, {ID: "22"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if created.Id != "t1" {
+		t.Errorf("created task id = %q, want t1", created.Id)
+	}
+	if got := f.created[0].Assignees; !reflect.DeepEqual(got, []int{11, 12}) {
+		t.Errorf("assignees = %v, want [11 12]", got)
+	}
+	if got := f.watchers["t1"].Watchers.Add; !reflect.DeepEqual(got, []int{21, 22}) {
+		t.Errorf("watchers added = %v, want [21 22]", got)
+	}
+	for _, a := range f.authorization {
+		if a != "pk_test" {
+			t.Errorf("Authorization header = %q, want the token", a)
+		}
+	}
+}
+
+func TestCreateTaskWithoutFollowersSkipsWatcherUpdate(t *testing.T) {
+	f := &fakeClickUp{}
+	c := f.start(t)
+
+	if _, err := c.CreateTask(context.Background(), "l1", "", models.Task{Name: "Solo"}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if len(f.watchers) != 0 {
+		t.Errorf("watchers updated for a task without followers: %v", f.watchers)
+	}
+}
+
+func TestCreateTaskRemovesTaskWhenWatchersFail(t *testing.T) {
+	f := &fakeClickUp{failWatchers: true}
+	c := f.start(t)
+
+	_, err := c.CreateTask(context.Background(), "l1", "", models.Task{
+		Name:      "Ship it",
+		Followers: []models.TaskAssignee{{ID: "21"}},
+	})
+	if err == nil {
+		t.Fatal("CreateTask succeeded although the watchers could not be written")
+	}
+	if !reflect.DeepEqual(f.deletedTasks, []string{"t1"}) {
+		t.Errorf("deleted tasks = %v, want [t1]", f.deletedTasks)
+	}
+}
@@ -0,0 +1,114 @@
+package clickup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// get fetches an API path into out.
+func (c *ClickUpClient) get(ctx context.Context, path, what string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseUrl+path, nil)
+	if err != nil {
+		return fmt.Errorf("build request (clickup): %w", err)
+	}
+	req.Header.Set("Authorization", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("get %s (clickup): %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response body (clickup): %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var clickupErr ClickUpErrors
+		if err := json.Unmarshal(body, &clickupErr); err == nil && clickupErr.Err != "" {
+			return fmt.Errorf("ClickUp error: %s", clickupErr.Err)
+		}
+		return fmt.Errorf("API error status (clickup get %s): %d", what, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("parse %s (clickup): %w", what, err)
+	}
+	return nil
+}
+
+// GetComments returns the comments of a ClickUp task, oldest first. ClickUp pages comments
+// backwards from the newest by the date and id of the last comment seen.
+func (c *ClickUpClient) GetComments(ctx context.Context, taskId string) ([]models.Comment, error) {
+	var comments []models.Comment
+	path := "/task/" + taskId + "/comment"
+	for {
+		var page GetTaskCommentsResponse
+		if err := c.get(ctx, path, "comments", &page); err != nil {
+			return nil, err
+		}
+		for _, cm := range page.Comments {
+			comment := models.Comment{
+				ID:     cm.Id,
+				Text:   cm.CommentText,
+				Author: models.TaskAssignee{ID: strconv.Itoa(cm.User.Id), Name: cm.User.Username, Email: cm.User.Email},
+			}
+			if ms, err := strconv.ParseInt(cm.Date, 10, 64); err == nil {
+				comment.CreatedAt = time.UnixMilli(ms).UTC()
+			}
+			comments = append(comments, comment)
+		}
+		// A full page holds 25 comments; anything shorter is the last one.
+		if len(page.Comments) < 25 {
+			break
+		}
+		last := page.Comments[len(page.Comments)-1]
+		path = "/task/" + taskId + "/comment?start=" + last.Date + "&start_id=" + last.Id
+	}
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].CreatedAt.Before(comments[j].CreatedAt) })
+	return comments, nil
+}
+
+// GetAttachments returns the files attached to a ClickUp task.
+func (c *ClickUpClient) GetAttachments(ctx context.Context, taskId string) ([]models.Attachment, error) {
+	var task GetTaskAttachmentsResponse
+	if err := c.get(ctx, "/task/"+taskId, "task attachments", &task); err != nil {
+		return nil, err
+	}
+	result := make([]models.Attachment, len(task.Attachments))
+	for i, a := range task.Attachments {
+		// size is a number or a numeric string depending on how the file was uploaded.
+		size, _ := strconv.ParseInt(strings.Trim(string(a.Size), `"`), 10, 64) //nolint:errcheck // unknown size stays 0
+		result[i] = models.Attachment{ID: a.Id, Name: a.Title, URL: a.Url, Size: size}
+	}
+	return result, nil
+}
+
+// OpenAttachment downloads an attachment. ClickUp attachment links are public to anyone
+// holding them, so no credentials are sent.
+func (c *ClickUpClient) OpenAttachment(ctx context.Context, attachment models.Attachment) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", attachment.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request (clickup download attachment): %w", err)
+	}
+	// The download has no client timeout: files can be large.
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download attachment (clickup): %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("API error status (clickup download attachment): %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
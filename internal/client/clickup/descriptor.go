@@ -0,0 +1,65 @@
+package clickup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+)
+
+// Name is the provider name migrations use for ClickUp.
+const Name = "clickup"
+
+// Descriptor registers the ClickUp client. Destination tasks are created directly in a list
+// of the migration's space; tags belong to the space.
+func Descriptor(c *ClickUpClient) client.Descriptor {
+	return client.Descriptor{
+		Name:        Name,
+		DisplayName: "ClickUp",
+		Client:      c,
+		Validate: func(dest client.Destination) error {
+			if dest.WorkspaceID == "" {
+				return fmt.Errorf("dest_workspace_id is required")
+			}
+			if dest.SpaceID == "" {
+				return fmt.Errorf("dest_space_id is required for ClickUp destination")
+			}
+			return nil
+		},
+		ContainerScope: func(dest client.Destination) string {
+			return dest.SpaceID
+		},
+		TagScope: func(dest client.Destination) string {
+			return dest.SpaceID
+		},
+		DefaultPriorities: []string{"urgent", "high", "normal", "low"},
+		OAuth:             &OAuthEndpoint,
+		ConnectionClient:  newConnectionClient,
+	}
+}
+
+// ListWorkspaces implements client.WorkspaceBrowser. ClickUp workspaces are teams.
+func (c *ClickUpClient) ListWorkspaces(ctx context.Context) ([]client.Container, error) {
+	teams, err := c.GetWorkspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]client.Container, len(teams))
+	for i, t := range teams {
+		result[i] = client.Container{ID: t.Id, Name: t.Name}
+	}
+	return result, nil
+}
+
+// ListProjects implements client.WorkspaceBrowser. ClickUp projects are spaces.
+func (c *ClickUpClient) ListProjects(ctx context.Context, workspaceId string) ([]client.Container, error) {
+	spaces, err := c.GetSpaces(ctx, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]client.Container, len(spaces))
+	for i, s := range spaces {
+		result[i] = client.Container{ID: s.Id, Name: s.Name}
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package clickup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+)
+
+// post sends a JSON body to an API path and reads the response into out.
+func (c *ClickUpClient) post(ctx context.Context, path, what string, in, out interface{}) error {
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("marshal %s request (clickup): %w", what, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseUrl+path, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("build request (clickup): %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("create %s (clickup): %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response body (clickup): %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var clickupErr ClickUpErrors
+		if err := json.Unmarshal(body, &clickupErr); err == nil && clickupErr.Err != "" {
+			return fmt.Errorf("ClickUp error: %s", clickupErr.Err)
+		}
+		return fmt.Errorf("API error status (clickup create %s): %d", what, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("parse %s (clickup): %w", what, err)
+	}
+	return nil
+}
+
+// GetFolders returns the folders of a space with their lists.
+func (c *ClickUpClient) GetFolders(ctx context.Context, spaceId string) ([]ClickUpFolder, error) {
+	var resp GetMultipleFoldersResponse
+	if err := c.get(ctx, "/space/"+spaceId+"/folder", "folders", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Folders, nil
+}
+
+// CreateFolder creates a folder in a space.
+func (c *ClickUpClient) CreateFolder(ctx context.Context, spaceId, name string) (*ClickUpFolder, error) {
+	var folder ClickUpFolder
+	if err := c.post(ctx, "/space/"+spaceId+"/folder", "folder", CreateContainerRequest{Name: name}, &folder); err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// CreateList creates a list directly in a space, outside any folder.
+func (c *ClickUpClient) CreateList(ctx context.Context, spaceId, name string) (*ClickUpList, error) {
+	var list ClickUpList
+	if err := c.post(ctx, "/space/"+spaceId+"/list", "list", CreateContainerRequest{Name: name}, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// CreateFolderList creates a list in a folder.
+func (c *ClickUpClient) CreateFolderList(ctx context.Context, folderId, name string) (*ClickUpList, error) {
+	var list ClickUpList
+	if err := c.post(ctx, "/folder/"+folderId+"/list", "list", CreateContainerRequest{Name: name}, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetDestContainers returns the lists of a ClickUp space (used as destination containers):
+// the lists outside folders, then the lists of every folder named "Folder / List".
+func (c *ClickUpClient) GetDestContainers(ctx context.Context, spaceId string) ([]client.Container, error) {
+	containers, err := c.GetSourceContainers(ctx, spaceId)
+	if err != nil {
+		return nil, err
+	}
+	folders, err := c.GetFolders(ctx, spaceId)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range folders {
+		for _, l := range f.Lists {
+			containers = append(containers, client.Container{ID: l.Id, Name: f.Name + " / " + l.Name})
+		}
+	}
+	return containers, nil
+}
@@ -0,0 +1,25 @@
+package clickup
+
+import (
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/oauth"
+)
+
+// OAuthEndpoint is ClickUp's OAuth app endpoint. ClickUp access tokens do not expire and
+// come without a refresh token.
+var OAuthEndpoint = oauth.Endpoint{
+	AuthURL:            "https://app.clickup.com/api",
+	TokenURL:           "https://api.clickup.com/api/v2/oauth/token",
+	TokenParamsInQuery: true,
+}
+
+// NewClickUpOAuthClient returns a client acting as the user behind an OAuth connection.
+func NewClickUpOAuthClient(source oauth.TokenSource) *ClickUpClient {
+	c := NewClickUpClient("")
+	c.httpClient.Transport = &oauth.Transport{Source: source}
+	return c
+}
+
+func newConnectionClient(source oauth.TokenSource) client.IntegrationProvider {
+	return NewClickUpOAuthClient(source)
+}
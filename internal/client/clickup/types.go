@@ -95,30 +95,61 @@ type ClickUpTaskCustomField struct {
 }
 
 type ClickUpTask struct {
-	Id           string                   `json:"id"`
-	Name         string                   `json:"name"`
-	Description  string                   `json:"description"`
-	Status       ClickUpStatus            `json:"status"`
-	OrderIndex   string                   `json:"orderindex"`
-	DateCreated  string                   `json:"date_created"`
-	DateUpdated  string                   `json:"date_updated"`
-	DateClosed   *string                  `json:"date_closed"`
-	Creator      ClickUpCreator           `json:"creator"`
-	Assignees    []ClickUpAssignees       `json:"assignees"`
-	Priority     *ClickUpPriority         `json:"priority"`
-	DueDate      string                   `json:"due_date"`
-	Tags         []ClickUpTag             `json:"tags"`
-	CustomFields []ClickUpTaskCustomField `json:"custom_fields"`
+	Id                  string                   `json:"id"`
+	Name                string                   `json:"name"`
+	Description         string                   `json:"description"`
+	MarkdownDescription string                   `json:"markdown_description"`
+	Status              ClickUpStatus            `json:"status"`
+	OrderIndex          string                   `json:"orderindex"`
+	DateCreated         string                   `json:"date_created"`
+	DateUpdated         string                   `json:"date_updated"`
+	DateClosed          *string                  `json:"date_closed"`
+	Creator             ClickUpCreator           `json:"creator"`
+	Assignees           []ClickUpAssignees       `json:"assignees"`
+	Watchers            []ClickUpAssignees       `json:"watchers"`
+	Priority            *ClickUpPriority         `json:"priority"`
+	DueDate             string                   `json:"due_date"`
+	DueDateTime         bool                     `json:"due_date_time"`
+	StartDate           string                   `json:"start_date"`
+	StartDateTime       bool                     `json:"start_date_time"`
+	Tags                []ClickUpTag             `json:"tags"`
+	CustomFields        []ClickUpTaskCustomField `json:"custom_fields"`
 }
 
 type CreateTaskRequest struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description,omitempty"`
-	Status      string   `json:"status,omitempty"`
-	Assignees   []int    `json:"assignees,omitempty"`
-	DueDate     *int64   `json:"due_date,omitempty"`
-	Priority    *int     `json:"priority,omitempty"`
-	Tags        []string `json:"tags,omitempty"`
+	Name            string                    `json:"name"`
+	Description     string                    `json:"description,omitempty"`
+	MarkdownContent string                    `json:"markdown_content,omitempty"`
+	Status          string                    `json:"status,omitempty"`
+	Assignees       []int                     `json:"assignees,omitempty"`
+	DueDate         *int64                    `json:"due_date,omitempty"`
+	DueDateTime     bool                      `json:"due_date_time,omitempty"`
+	StartDate       *int64                    `json:"start_date,omitempty"`
+	StartDateTime   bool                      `json:"start_date_time,omitempty"`
+	Priority        *int                      `json:"priority,omitempty"`
+	Tags            []string                  `json:"tags,omitempty"`
+	CustomFields    []ClickUpCustomFieldValue `json:"custom_fields,omitempty"`
+}
+
+// UpdateTaskWatchersRequest adds watchers to an existing task; the create task endpoint
+// takes none.
+type UpdateTaskWatchersRequest struct {
+	Watchers ClickUpUserChanges `json:"watchers"`
+}
+
+type ClickUpUserChanges struct {
+	Add []int `json:"add,omitempty"`
+	Rem []int `json:"rem,omitempty"`
+}
+
+type ClickUpCustomFieldValue struct {
+	ID    string      `json:"id"`
+	Value interface{} `json:"value"`
+}
+
+type CreateCommentRequest struct {
+	CommentText string `json:"comment_text"`
+	NotifyAll   bool   `json:"notify_all"`
 }
 
 type ClickUpListStatus struct {
@@ -138,6 +169,20 @@ type GetMultipleListsResponse struct {
 	Lists []ClickUpList `json:"lists"`
 }
 
+type ClickUpFolder struct {
+	Id    string        `json:"id"`
+	Name  string        `json:"name"`
+	Lists []ClickUpList `json:"lists"`
+}
+
+type GetMultipleFoldersResponse struct {
+	Folders []ClickUpFolder `json:"folders"`
+}
+
+type CreateContainerRequest struct {
+	Name string `json:"name"`
+}
+
 type ClickUpCustomFieldOption struct {
 	Id         string `json:"id"`
 	Name       string `json:"name"`
@@ -162,3 +207,35 @@ type ClickUpCustomField struct {
 type GetListCustomFieldsResponse struct {
 	Fields []ClickUpCustomField `json:"fields"`
 }
+
+type ClickUpSpaceTag struct {
+	Name  string `json:"name"`
+	TagFg string `json:"tag_fg"`
+	TagBg string `json:"tag_bg"`
+}
+
+type GetSpaceTagsResponse struct {
+	Tags []ClickUpSpaceTag `json:"tags"`
+}
+
+type ClickUpComment struct {
+	Id          string           `json:"id"`
+	CommentText string           `json:"comment_text"`
+	User        ClickUpAssignees `json:"user"`
+	Date        string           `json:"date"`
+}
+
+type GetTaskCommentsResponse struct {
+	Comments []ClickUpComment `json:"comments"`
+}
+
+type ClickUpAttachment struct {
+	Id    string          `json:"id"`
+	Title string          `json:"title"`
+	Url   string          `json:"url"`
+	Size  json.RawMessage `json:"size"`
+}
+
+type GetTaskAttachmentsResponse struct {
+	Attachments []ClickUpAttachment `json:"attachments"`
+}
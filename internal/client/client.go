@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"io"
 
 	"github.com/TWRT/integration-mapper/internal/models"
 )
@@ -53,8 +54,88 @@ type FieldCreator interface {
 	FindCustomFieldByName(ctx context.Context, workspaceId, name string) (fieldGID string, optionGIDs []string, err error)
 }
 
+// DestFieldProvider is implemented by destination clients that can list the custom fields
+// available in a destination project, so source fields can be mapped onto existing ones.
+type DestFieldProvider interface {
+	GetDestFieldDefinitions(ctx context.Context, projectId string) ([]models.CustomFieldDefinition, error)
+}
+
 type IntegrationProvider interface {
 	TaskClient
 	MemberProvider
 	StatusProvider
 }
+
+// AssigneeLimiter is implemented by destination clients that accept a limited number of
+// assignees per task. Extra assignees are migrated as followers.
+type AssigneeLimiter interface {
+	MaxAssignees() int
+}
+
+// TaskURLBuilder is implemented by clients that can build a browser link to one of their tasks.
+type TaskURLBuilder interface {
+	TaskURL(taskId string) string
+}
+
+// CommentCreator is implemented by clients that can add a plain-text comment to a task.
+type CommentCreator interface {
+	CreateComment(ctx context.Context, taskId, text string) error
+}
+
+// ChecklistCreator is implemented by destination clients that support task checklists.
+// For other destinations checklists are folded into the task description.
+type ChecklistCreator interface {
+	CreateChecklist(ctx context.Context, taskId string, checklist models.Checklist) error
+}
+
+// CommentReader is implemented by source clients that can list the comments of a task.
+type CommentReader interface {
+	GetComments(ctx context.Context, taskId string) ([]models.Comment, error)
+}
+
+// AttachmentReader is implemented by source clients that can list and download the files
+// attached to a task.
+type AttachmentReader interface {
+	GetAttachments(ctx context.Context, taskId string) ([]models.Attachment, error)
+	OpenAttachment(ctx context.Context, attachment models.Attachment) (io.ReadCloser, error)
+}
+
+// TaskDeleter is implemented by clients that can delete tasks they previously created (used by rollback).
+type TaskDeleter interface {
+	DeleteTask(ctx context.Context, taskId string) error
+}
+
+// FieldDeleter is implemented by clients that can delete custom fields created by FieldCreator.
+type FieldDeleter interface {
+	DeleteCustomField(ctx context.Context, fieldGid string) error
+}
+
+// TagLister is implemented by clients that can list existing tags.
+// Asana: tags of a workspace. ClickUp: tags of a space.
+type TagLister interface {
+	ListTags(ctx context.Context, scopeId string) ([]string, error)
+}
+
+// TagCreator is implemented by clients whose tags are workspace objects that must exist before use.
+type TagCreator interface {
+	FindTag(ctx context.Context, workspaceId, name string) (tagGid string, found bool, err error)
+	CreateTag(ctx context.Context, workspaceId, name string) (tagGid string, err error)
+}
+
+// TagDeleter is implemented by clients that can delete tags created by TagCreator.
+type TagDeleter interface {
+	DeleteTag(ctx context.Context, tagGid string) error
+}
+
+// WorkspaceBrowser is implemented by clients whose workspaces and projects can be browsed
+// when setting up a migration. Asana: workspaces and projects. ClickUp: teams and spaces.
+type WorkspaceBrowser interface {
+	ListWorkspaces(ctx context.Context) ([]Container, error)
+	ListProjects(ctx context.Context, workspaceId string) ([]Container, error)
+}
+
+// ContainerDeleter is implemented by clients that can delete a destination container
+// (Asana section, ClickUp list).
+type ContainerDeleter interface {
+	DeleteContainer(ctx context.Context, containerId string) error
+}
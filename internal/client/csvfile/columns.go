@@ -0,0 +1,84 @@
+package csvfile
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Columns maps models.Task fields to CSV header names. An empty name leaves the field out.
+// Every other column of a file is read and written as a text custom field named after its header.
+type Columns struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Container   string `json:"container"`
+	Status      string `json:"status"`
+	Completed   string `json:"completed"`
+	Assignees   string `json:"assignees"`
+	Followers   string `json:"followers"`
+	Tags        string `json:"tags"`
+	Priority    string `json:"priority"`
+	DueDate     string `json:"due_date"`
+	StartDate   string `json:"start_date"`
+	// Separator splits multi-value cells (assignees, followers, tags).
+	Separator string `json:"separator"`
+}
+
+// DefaultColumns returns the column names used unless configured otherwise.
+func DefaultColumns() Columns {
+	return Columns{
+		ID:          "ID",
+		Name:        "Name",
+		Description: "Description",
+		Container:   "Section",
+		Status:      "Status",
+		Completed:   "Completed",
+		Assignees:   "Assignees",
+		Followers:   "Followers",
+		Tags:        "Tags",
+		Priority:    "Priority",
+		DueDate:     "Due Date",
+		StartDate:   "Start Date",
+		Separator:   ";",
+	}
+}
+
+// ParseColumns reads a JSON column mapping such as {"name": "Title", "container": "List"}
+// over the defaults. Mapping a field to "" leaves it out; an empty config keeps the defaults.
+func ParseColumns(config string) (Columns, error) {
+	cols := DefaultColumns()
+	if config == "" {
+		return cols, nil
+	}
+	if err := json.Unmarshal([]byte(config), &cols); err != nil {
+		return Columns{}, fmt.Errorf("parse column mapping (csv): %w", err)
+	}
+	if cols.Name == "" {
+		return Columns{}, fmt.Errorf("parse column mapping (csv): the name column is required")
+	}
+	if cols.Separator == "" {
+		cols.Separator = ";"
+	}
+	return cols, nil
+}
+
+// standard returns the configured column names in the order new files are written in.
+func (c Columns) standard() []string {
+	var names []string
+	for _, n := range []string{c.ID, c.Name, c.Description, c.Container, c.Status, c.Completed,
+		c.Assignees, c.Followers, c.Tags, c.Priority, c.DueDate, c.StartDate} {
+		if n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+func (c Columns) isStandard(header string) bool {
+	for _, n := range c.standard() {
+		if n == header {
+			return true
+		}
+	}
+	return false
+}
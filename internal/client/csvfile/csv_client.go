@@ -0,0 +1,738 @@
+package csvfile
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/converter"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// WorkspaceID is the single workspace of the provider: the configured directory.
+const WorkspaceID = "local"
+
+// CSVClient reads and writes tasks as rows of CSV files in one directory. A file is a
+// project; the container column groups its rows into containers.
+//
+// IDs take the forms "tasks.csv" (a file), "tasks.csv|Backlog" (the rows whose container
+// column is "Backlog") and "tasks.csv#12" (the row with ID 12, or the 12th row when the
+// mapping has no ID column).
+type CSVClient struct {
+	dir     string
+	columns Columns
+
+	mu         sync.Mutex
+	files      map[string]*fileState // file name → write state, loaded on first write
+	fieldTypes map[string]string     // column → Asana-style type of the fields created as columns
+}
+
+// fileState is what appending rows to a file needs to know about it.
+type fileState struct {
+	header []string
+	rows   int
+	nextID int
+}
+
+func NewCSVClient(dir string, columns Columns) *CSVClient {
+	return &CSVClient{
+		dir:        dir,
+		columns:    columns,
+		files:      make(map[string]*fileState),
+		fieldTypes: make(map[string]string),
+	}
+}
+
+// ---- Files ----
+
+// table is the content of a CSV file.
+type table struct {
+	header []string
+	index  map[string]int
+	rows   [][]string
+}
+
+func (t *table) get(row []string, column string) string {
+	i, ok := t.index[column]
+	if column == "" || !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// splitID returns the file an ID refers to and the container or row after it.
+func splitID(id string) (file, container, row string, err error) {
+	file = id
+	if i := strings.Index(file, "|"); i != -1 {
+		file, container = file[:i], file[i+1:]
+	} else if i := strings.Index(file, "#"); i != -1 {
+		file, row = file[:i], file[i+1:]
+	}
+	if file == "" || filepath.Base(file) != file || !strings.EqualFold(filepath.Ext(file), ".csv") {
+		return "", "", "", fmt.Errorf("invalid file %q, expected a .csv file name (csv)", file)
+	}
+	return file, container, row, nil
+}
+
+// readTable reads a file of the directory. A missing file is reported with os.ErrNotExist.
+func (c *CSVClient) readTable(file string) (*table, error) {
+	f, err := os.Open(filepath.Join(c.dir, file))
+	if err != nil {
+		return nil, fmt.Errorf("open %s (csv): %w", file, err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	header, err := r.Read()
+	if err == io.EOF {
+		return &table{index: map[string]int{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s (csv): %w", file, err)
+	}
+	if len(header) > 0 {
+		// Spreadsheet exports often start with a byte order mark.
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	t := &table{header: header, index: make(map[string]int, len(header))}
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		t.header[i] = h
+		if _, dup := t.index[h]; !dup {
+			t.index[h] = i
+		}
+	}
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return t, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s (csv): %w", file, err)
+		}
+		t.rows = append(t.rows, row)
+	}
+}
+
+// readTableIfExists reads a file, returning an empty table if it does not exist yet.
+func (c *CSVClient) readTableIfExists(file string) (*table, error) {
+	t, err := c.readTable(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return &table{index: map[string]int{}}, nil
+	}
+	return t, err
+}
+
+// distinct returns the distinct non-empty values of a column, split into single values
+// when multi is set, in order of first appearance.
+func (c *CSVClient) distinct(t *table, column string, multi bool) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, row := range t.rows {
+		cell := t.get(row, column)
+		parts := []string{cell}
+		if multi {
+			parts = c.splitCell(cell)
+		}
+		for _, v := range parts {
+			if v != "" && !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+func (c *CSVClient) splitCell(cell string) []string {
+	var values []string
+	for _, v := range strings.Split(cell, c.columns.Separator) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// ---- Browsing and members ----
+
+// ListWorkspaces implements client.WorkspaceBrowser. The directory is the only workspace.
+func (c *CSVClient) ListWorkspaces(_ context.Context) ([]client.Container, error) {
+	return []client.Container{{ID: WorkspaceID, Name: filepath.Base(c.dir)}}, nil
+}
+
+// ListProjects implements client.WorkspaceBrowser with the CSV files of the directory.
+func (c *CSVClient) ListProjects(_ context.Context, _ string) ([]client.Container, error) {
+	files, err := c.listFiles()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]client.Container, len(files))
+	for i, f := range files {
+		result[i] = client.Container{ID: f, Name: strings.TrimSuffix(f, filepath.Ext(f))}
+	}
+	return result, nil
+}
+
+func (c *CSVClient) listFiles() ([]string, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, fmt.Errorf("list files (csv): %w", err)
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.EqualFold(filepath.Ext(e.Name()), ".csv") && !strings.ContainsAny(e.Name(), "|#") {
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// GetMembers returns the people named in the assignee and follower columns of all files.
+// A cell value is the member's ID and name, and their e-mail if it looks like one.
+func (c *CSVClient) GetMembers(_ context.Context, _ string) ([]models.Member, error) {
+	files, err := c.listFiles()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var members []models.Member
+	for _, f := range files {
+		t, err := c.readTable(f)
+		if err != nil {
+			return nil, err
+		}
+		people := append(c.distinct(t, c.columns.Assignees, true), c.distinct(t, c.columns.Followers, true)...)
+		for _, p := range people {
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+			m := models.Member{ID: p, Name: p}
+			if strings.Contains(p, "@") {
+				m.Email = p
+			}
+			members = append(members, m)
+		}
+	}
+	return members, nil
+}
+
+// ---- Containers, statuses and tags ----
+
+// GetSourceContainers returns the distinct values of the container column of a file.
+func (c *CSVClient) GetSourceContainers(_ context.Context, id string) ([]client.Container, error) {
+	file, _, _, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	t, err := c.readTable(file)
+	if err != nil {
+		return nil, err
+	}
+	values := c.distinct(t, c.columns.Container, false)
+	containers := make([]client.Container, len(values))
+	for i, v := range values {
+		containers[i] = client.Container{ID: file + "|" + v, Name: v}
+	}
+	return containers, nil
+}
+
+// GetDestContainers returns the container values already used in a destination file. Any
+// other value can be mapped to as well; it is written into the container column as is.
+func (c *CSVClient) GetDestContainers(_ context.Context, id string) ([]client.Container, error) {
+	file, _, _, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	t, err := c.readTableIfExists(file)
+	if err != nil {
+		return nil, err
+	}
+	values := c.distinct(t, c.columns.Container, false)
+	containers := make([]client.Container, len(values))
+	for i, v := range values {
+		containers[i] = client.Container{ID: v, Name: v}
+	}
+	return containers, nil
+}
+
+// GetListStatuses returns the values of the status column of a file, or of a container's rows.
+func (c *CSVClient) GetListStatuses(_ context.Context, id string) ([]string, error) {
+	file, container, _, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	t, err := c.readTableIfExists(file)
+	if err != nil {
+		return nil, err
+	}
+	if container != "" {
+		t = c.filterContainer(t, container)
+	}
+	return c.distinct(t, c.columns.Status, false), nil
+}
+
+// ListTags returns the tags used in a file.
+func (c *CSVClient) ListTags(_ context.Context, id string) ([]string, error) {
+	file, _, _, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	t, err := c.readTableIfExists(file)
+	if err != nil {
+		return nil, err
+	}
+	return c.distinct(t, c.columns.Tags, true), nil
+}
+
+func (c *CSVClient) filterContainer(t *table, container string) *table {
+	filtered := &table{header: t.header, index: t.index}
+	for _, row := range t.rows {
+		if t.get(row, c.columns.Container) == container {
+			filtered.rows = append(filtered.rows, row)
+		}
+	}
+	return filtered
+}
+
+// ---- Custom fields ----
+
+// GetFieldDefinitions returns the columns of a file that no task field is mapped to, as text fields.
+func (c *CSVClient) GetFieldDefinitions(_ context.Context, id string) ([]models.CustomFieldDefinition, error) {
+	file, _, _, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	t, err := c.readTable(file)
+	if err != nil {
+		return nil, err
+	}
+	var defs []models.CustomFieldDefinition
+	for _, h := range t.header {
+		if h != "" && !c.columns.isStandard(h) {
+			defs = append(defs, models.CustomFieldDefinition{ID: h, Name: h, ClickUpType: "short_text"})
+		}
+	}
+	return defs, nil
+}
+
+// CreateCustomField declares a column for a source field. The column is added to the file
+// when the first row with a value for it is written. Options are written by name.
+func (c *CSVClient) CreateCustomField(_ context.Context, _ string, name, asanaType string, options []string) (string, []string, error) {
+	c.mu.Lock()
+	c.fieldTypes[name] = asanaType
+	c.mu.Unlock()
+	return name, options, nil
+}
+
+// AttachCustomFieldToProject is a no-op: columns belong to the rows written with them.
+func (c *CSVClient) AttachCustomFieldToProject(_ context.Context, _, _ string) error {
+	return nil
+}
+
+// GetProjectCustomField never reports an existing column, so that every field is declared
+// with its type and options through CreateCustomField.
+func (c *CSVClient) GetProjectCustomField(_ context.Context, _, _ string) (string, []string, bool, error) {
+	return "", nil, false, nil
+}
+
+// FindCustomFieldByName is only called after CreateCustomField failed, which it does not.
+func (c *CSVClient) FindCustomFieldByName(_ context.Context, _, name string) (string, []string, error) {
+	return name, nil, nil
+}
+
+// DeleteCustomField is a no-op: columns are left in place and are empty once the rows
+// written with them are deleted.
+func (c *CSVClient) DeleteCustomField(_ context.Context, _ string) error {
+	return nil
+}
+
+// ---- Reading tasks ----
+
+// GetTasks returns the rows of a file as tasks.
+func (c *CSVClient) GetTasks(ctx context.Context, id string) ([]models.Task, error) {
+	file, _, _, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	t, err := c.readTable(file)
+	if err != nil {
+		return nil, err
+	}
+	return c.parseRows(ctx, file, t, "")
+}
+
+// GetTasksByContainer returns the rows of a file with the given container value.
+func (c *CSVClient) GetTasksByContainer(ctx context.Context, id string) ([]models.Task, error) {
+	file, container, _, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+	t, err := c.readTable(file)
+	if err != nil {
+		return nil, err
+	}
+	return c.parseRows(ctx, file, t, container)
+}
+
+func (c *CSVClient) parseRows(ctx context.Context, file string, t *table, container string) ([]models.Task, error) {
+	loc := client.LocationFromContext(ctx)
+	var tasks []models.Task
+	for i, row := range t.rows {
+		if container != "" && t.get(row, c.columns.Container) != container {
+			continue
+		}
+		task, err := c.parseRow(file, t, i, row, loc)
+		if err != nil {
+			return nil, err
+		}
+		if task.Name == "" {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func (c *CSVClient) parseRow(file string, t *table, i int, row []string, loc *time.Location) (models.Task, error) {
+	rowID := t.get(row, c.columns.ID)
+	if rowID == "" {
+		rowID = strconv.Itoa(i + 1)
+	}
+	task := models.Task{
+		Id:          file + "#" + rowID,
+		Name:        t.get(row, c.columns.Name),
+		Description: t.get(row, c.columns.Description),
+		Status:      t.get(row, c.columns.Status),
+		Completed:   parseBool(t.get(row, c.columns.Completed)),
+		Tags:        c.splitCell(t.get(row, c.columns.Tags)),
+		Priority:    t.get(row, c.columns.Priority),
+		TimeZone:    loc.String(),
+	}
+	task.RichDescription = task.Description
+	for _, p := range c.splitCell(t.get(row, c.columns.Assignees)) {
+		task.Assignees = append(task.Assignees, models.TaskAssignee{ID: p, Name: p})
+	}
+	for _, p := range c.splitCell(t.get(row, c.columns.Followers)) {
+		task.Followers = append(task.Followers, models.TaskAssignee{ID: p, Name: p})
+	}
+
+	var err error
+	if task.DueDate, task.DueHasTime, err = parseCellDate(t.get(row, c.columns.DueDate), loc); err != nil {
+		return models.Task{}, fmt.Errorf("row %s of %s: %w", rowID, file, err)
+	}
+	if task.StartDate, task.StartHasTime, err = parseCellDate(t.get(row, c.columns.StartDate), loc); err != nil {
+		return models.Task{}, fmt.Errorf("row %s of %s: %w", rowID, file, err)
+	}
+
+	for _, h := range t.header {
+		if h == "" || c.columns.isStandard(h) {
+			continue
+		}
+		if v := t.get(row, h); v != "" {
+			task.CustomFields = append(task.CustomFields, models.TaskCustomField{FieldID: h, Value: v})
+		}
+	}
+	return task, nil
+}
+
+func parseBool(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "yes", "y", "1", "x", "done", "completed":
+		return true
+	}
+	return false
+}
+
+var cellDateLayouts = []struct {
+	layout  string
+	hasTime bool
+}{
+	{"2006-01-02", false},
+	{"2006-01-02 15:04", true},
+	{"2006-01-02 15:04:05", true},
+	{"2006-01-02T15:04", true},
+	{"2006-01-02T15:04:05", true},
+}
+
+// parseCellDate reads a date or date and time in loc; RFC 3339 values carry their own offset.
+func parseCellDate(s string, loc *time.Location) (*time.Time, bool, error) {
+	if s == "" {
+		return nil, false, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, true, nil
+	}
+	for _, l := range cellDateLayouts {
+		if t, err := time.ParseInLocation(l.layout, s, loc); err == nil {
+			return &t, l.hasTime, nil
+		}
+	}
+	return nil, false, fmt.Errorf("parse date %q (csv): expected YYYY-MM-DD or YYYY-MM-DD HH:MM", s)
+}
+
+func formatCellDate(t *time.Time, hasTime bool, loc *time.Location) string {
+	if t == nil {
+		return ""
+	}
+	if hasTime {
+		return t.In(loc).Format("2006-01-02 15:04")
+	}
+	return t.In(loc).Format("2006-01-02")
+}
+
+// ---- Writing tasks ----
+
+// loadFileState returns the header and row counts of a destination file, reading it on first use.
+func (c *CSVClient) loadFileState(file string) (*fileState, error) {
+	if st, ok := c.files[file]; ok {
+		return st, nil
+	}
+	t, err := c.readTable(file)
+	if errors.Is(err, os.ErrNotExist) {
+		st := &fileState{nextID: 1}
+		c.files[file] = st
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	st := &fileState{header: t.header, rows: len(t.rows), nextID: 1}
+	for _, row := range t.rows {
+		if n, err := strconv.Atoi(t.get(row, c.columns.ID)); err == nil && n >= st.nextID {
+			st.nextID = n + 1
+		}
+	}
+	c.files[file] = st
+	return st, nil
+}
+
+// formatValue writes a converted custom field value as cell text.
+func (c *CSVClient) formatValue(column string, value any, loc *time.Location) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if c.fieldTypes[column] == "date" {
+			if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+				t := time.UnixMilli(ms)
+				return formatCellDate(&t, false, loc)
+			}
+		}
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []string:
+		return strings.Join(v, c.columns.Separator+" ")
+	case []any:
+		parts := make([]string, len(v))
+		for i, p := range v {
+			parts[i] = fmt.Sprintf("%v", p)
+		}
+		return strings.Join(parts, c.columns.Separator+" ")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func names(people []models.TaskAssignee) []string {
+	ids := make([]string, len(people))
+	for i, p := range people {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+// CreateTask appends a row to a file, which is created when missing. id is the file, or
+// "file|container" to write a container value. Custom fields become columns named after
+// the field; the file is rewritten when a row needs columns it does not have yet.
+func (c *CSVClient) CreateTask(ctx context.Context, id string, _ string, task models.Task) (*models.Task, error) {
+	file, container, _, err := splitID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st, err := c.loadFileState(file)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := client.TaskLocation(ctx, task)
+	sep := c.columns.Separator + " "
+	cells := map[string]string{
+		c.columns.Name:      task.Name,
+		c.columns.Container: container,
+		c.columns.Status:    task.Status,
+		c.columns.Completed: strconv.FormatBool(task.Completed),
+		c.columns.Assignees: strings.Join(names(task.Assignees), sep),
+		c.columns.Followers: strings.Join(names(task.Followers), sep),
+		c.columns.Tags:      strings.Join(task.Tags, sep),
+		c.columns.Priority:  task.Priority,
+		c.columns.DueDate:   formatCellDate(task.DueDate, task.DueHasTime, loc),
+		c.columns.StartDate: formatCellDate(task.StartDate, task.StartHasTime, loc),
+	}
+	cells[c.columns.Description] = task.Description
+	if task.Description == "" {
+		cells[c.columns.Description] = converter.PlainMentions(task.RichDescription)
+	}
+	rowID := strconv.Itoa(st.rows + 1)
+	if c.columns.ID != "" {
+		rowID = strconv.Itoa(st.nextID)
+		cells[c.columns.ID] = rowID
+	}
+	delete(cells, "")
+
+	header := st.header
+	if header == nil {
+		header = c.columns.standard()
+	}
+	known := make(map[string]bool, len(header))
+	for _, h := range header {
+		known[h] = true
+	}
+	grown := st.header == nil
+	for _, cf := range task.CustomFields {
+		value := c.formatValue(cf.FieldID, cf.Value, loc)
+		if value == "" || c.columns.isStandard(cf.FieldID) {
+			continue
+		}
+		cells[cf.FieldID] = value
+		if !known[cf.FieldID] {
+			known[cf.FieldID] = true
+			header = append(header, cf.FieldID)
+			grown = true
+		}
+	}
+
+	row := make([]string, len(header))
+	for i, h := range header {
+		row[i] = cells[h]
+	}
+	if grown {
+		err = c.rewrite(file, header, row)
+	} else {
+		err = c.appendRow(file, row)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	st.header = header
+	st.rows++
+	st.nextID++
+	return &models.Task{
+		Id:        file + "#" + rowID,
+		Name:      task.Name,
+		Status:    task.Status,
+		Completed: task.Completed,
+	}, nil
+}
+
+func (c *CSVClient) appendRow(file string, row []string) error {
+	f, err := os.OpenFile(filepath.Join(c.dir, file), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open %s (csv): %w", file, err)
+	}
+	w := csv.NewWriter(f)
+	w.Write(row)
+	w.Flush()
+	if err := errors.Join(w.Error(), f.Close()); err != nil {
+		return fmt.Errorf("write %s (csv): %w", file, err)
+	}
+	return nil
+}
+
+// rewrite writes a file with a new header, keeping its existing rows (if any) and adding
+// extra rows. The file is replaced atomically.
+func (c *CSVClient) rewrite(file string, header []string, extra ...[]string) error {
+	t, err := c.readTableIfExists(file)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(t.rows)+len(extra))
+	for _, old := range t.rows {
+		row := make([]string, len(header))
+		for i, h := range header {
+			if j, ok := t.index[h]; ok && j < len(old) {
+				row[i] = old[j]
+			}
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows, extra...)
+	return c.writeAll(file, header, rows)
+}
+
+func (c *CSVClient) writeAll(file string, header []string, rows [][]string) error {
+	tmp, err := os.CreateTemp(c.dir, "."+file+".*")
+	if err != nil {
+		return fmt.Errorf("write %s (csv): %w", file, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := csv.NewWriter(tmp)
+	w.Write(header)
+	w.WriteAll(rows)
+	if err := errors.Join(w.Error(), tmp.Close()); err != nil {
+		return fmt.Errorf("write %s (csv): %w", file, err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir, file)); err != nil {
+		return fmt.Errorf("write %s (csv): %w", file, err)
+	}
+	return nil
+}
+
+// DeleteTask removes the row with the given ID from its file. Rows can only be deleted
+// when the mapping has an ID column; a row that is already gone counts as deleted.
+func (c *CSVClient) DeleteTask(_ context.Context, id string) error {
+	file, _, rowID, err := splitID(id)
+	if err != nil {
+		return err
+	}
+	if c.columns.ID == "" {
+		return fmt.Errorf("delete row %s (csv): deleting rows requires an ID column", id)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.readTableIfExists(file)
+	if err != nil {
+		return err
+	}
+	kept := make([][]string, 0, len(t.rows))
+	for _, row := range t.rows {
+		if t.get(row, c.columns.ID) != rowID {
+			kept = append(kept, row)
+		}
+	}
+	if len(kept) == len(t.rows) {
+		return nil
+	}
+	if err := c.writeAll(file, t.header, kept); err != nil {
+		return err
+	}
+	if st, ok := c.files[file]; ok {
+		st.rows = len(kept)
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package csvfile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+func TestParseColumns(t *testing.T) {
+	custom := DefaultColumns()
+	custom.Name, custom.Container, custom.Priority = "Title", "List", ""
+
+	pipe := DefaultColumns()
+	pipe.Separator = "|"
+
+	for _, tc := range []struct {
+		config string
+		want   Columns
+	}{
+		{"", DefaultColumns()},
+		{`{"name": "Title", "container": "List", "priority": ""}`, custom},
+		{`{"separator": "|"}`, pipe},
+		{`{"separator": ""}`, DefaultColumns()},
+	} {
+		got, err := ParseColumns(tc.config)
+		if err != nil {
+			t.Errorf("ParseColumns(%q): %v", tc.config, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseColumns(%q) = %+v, want %+v", tc.config, got, tc.want)
+		}
+	}
+}
+
+func TestParseColumnsRejectsMalformedConfig(t *testing.T) {
+	for _, config := range []string{
+		`name=Title`,
+		`{"name": "Title"`,
+		`{"name": 3}`,
+		`["Name", "Status"]`,
+		`{"name": ""}`,
+	} {
+		if cols, err := ParseColumns(config); err == nil {
+			t.Errorf("ParseColumns(%q) = %+v, want an error", config, cols)
+		}
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(b)
+}
+
+// testColumns is a mapping for a spreadsheet with its own header names and no ID column.
+func testColumns(t *testing.T) Columns {
+	t.Helper()
+	cols, err := ParseColumns(`{"id": "", "name": "Title", "container": "List", "status": "State",
+		"assignees": "Owner", "due_date": "Due", "separator": ","}`)
+	if err != nil {
+		t.Fatalf("ParseColumns: %v", err)
+	}
+	return cols
+}
+
+func TestReadWithCustomColumns(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "tasks.csv", "\ufeffTitle,List,State,Owner,Due,Tags,Estimate\n"+
+		"Write spec,Backlog,Open,\"ann@example.com, bob\",2024-06-01,docs,3\n"+
+		"Ship it,Doing,In review,bob,2024-06-02 14:30,,\n"+
+		",Backlog,Open,,,,\n"+
+		"Polish,Backlog,Done,,,\"ui,copy\",1\n")
+	c := NewCSVClient(dir, testColumns(t))
+	ctx := client.WithLocation(context.Background(), time.UTC)
+
+	containers, err := c.GetSourceContainers(ctx, "tasks.csv")
+	if err != nil {
+		t.Fatalf("GetSourceContainers: %v", err)
+	}
+	wantContainers := []client.Container{{ID: "tasks.csv|Backlog", Name: "Backlog"}, {ID: "tasks.csv|Doing", Name: "Doing"}}
+	if !reflect.DeepEqual(containers, wantContainers) {
+		t.Errorf("containers = %+v, want %+v", containers, wantContainers)
+	}
+
+	statuses, err := c.GetListStatuses(ctx, "tasks.csv|Backlog")
+	if err != nil {
+		t.Fatalf("GetListStatuses: %v", err)
+	}
+	if !reflect.DeepEqual(statuses, []string{"Open", "Done"}) {
+		t.Errorf("statuses = %v, want [Open Done]", statuses)
+	}
+
+	defs, err := c.GetFieldDefinitions(ctx, "tasks.csv")
+	if err != nil {
+		t.Fatalf("GetFieldDefinitions: %v", err)
+	}
+	if len(defs) != 1 || defs[0].ID != "Estimate" {
+		t.Errorf("field definitions = %+v, want only the unmapped Estimate column", defs)
+	}
+
+	members, err := c.GetMembers(ctx, "")
+	if err != nil {
+		t.Fatalf("GetMembers: %v", err)
+	}
+	wantMembers := []models.Member{{ID: "ann@example.com", Name: "ann@example.com", Email: "ann@example.com"}, {ID: "bob", Name: "bob"}}
+	if !reflect.DeepEqual(members, wantMembers) {
+		t.Errorf("members = %+v, want %+v", members, wantMembers)
+	}
+
+	tasks, err := c.GetTasksByContainer(ctx, "tasks.csv|Backlog")
+	if err != nil {
+		t.Fatalf("GetTasksByContainer: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("got %d tasks, want 2 (the row without a title is skipped)", len(tasks))
+	}
+	spec := tasks[0]
+	if spec.Id != "tasks.csv#1" || spec.Name != "Write spec" || spec.Status != "Open" {
+		t.Errorf("task = %+v, want row 1 \"Write spec\" in Open", spec)
+	}
+	if got := names(spec.Assignees); !reflect.DeepEqual(got, []string{"ann@example.com", "bob"}) {
+		t.Errorf("assignees = %v, want both owners split on the separator", got)
+	}
+	if spec.DueDate == nil || spec.DueHasTime || !spec.DueDate.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("due date = %v (has time %v), want 2024-06-01", spec.DueDate, spec.DueHasTime)
+	}
+	if want := []models.TaskCustomField{{FieldID: "Estimate", Value: "3"}}; !reflect.DeepEqual(spec.CustomFields, want) {
+		t.Errorf("custom fields = %+v, want %+v", spec.CustomFields, want)
+	}
+	polish := tasks[1]
+	if polish.Id != "tasks.csv#4" || !reflect.DeepEqual(polish.Tags, []string{"ui", "copy"}) {
+		t.Errorf("task = %+v, want row tasks.csv#4 tagged ui and copy", polish)
+	}
+}
+
+func TestWriteWithCustomColumns(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCSVClient(dir, testColumns(t))
+	ctx := client.WithLocation(context.Background(), time.UTC)
+
+	due := time.Date(2024, 6, 2, 14, 30, 0, 0, time.UTC)
+	first, err := c.CreateTask(ctx, "out.csv|Doing", "", models.Task{
+		Name:       "Ship it",
+		Status:     "In review",
+		Assignees:  []models.TaskAssignee{{ID: "ann"}, {ID: "bob"}},
+		Tags:       []string{"release"},
+		DueDate:    &due,
+		DueHasTime: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if first.Id != "out.csv#1" {
+		t.Errorf("created id = %q, want out.csv#1", first.Id)
+	}
+	if _, err := c.CreateTask(ctx, "out.csv", "", models.Task{
+		Name:         "Estimate it",
+		Completed:    true,
+		CustomFields: []models.TaskCustomField{{FieldID: "Estimate", Value: float64(5)}},
+	}); err != nil {
+		t.Fatalf("CreateTask with a new column: %v", err)
+	}
+
+	// The second row adds a column, which rewrites the file with the first row kept.
+	want := "Title,Description,List,State,Completed,Owner,Followers,Tags,Priority,Due,Start Date,Estimate\n" +
+		"Ship it,,Doing,In review,false,\"ann, bob\",,release,,2024-06-02 14:30,,\n" +
+		"Estimate it,,,,true,,,,,,,5\n"
+	if got := readFile(t, dir, "out.csv"); got != want {
+		t.Errorf("file =\n%s\nwant\n%s", got, want)
+	}
+
+	// What was written reads back with the same mapping.
+	tasks, err := c.GetTasks(ctx, "out.csv")
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("got %d tasks, want 2", len(tasks))
+	}
+	if got := tasks[0]; got.Name != "Ship it" || !reflect.DeepEqual(names(got.Assignees), []string{"ann", "bob"}) ||
+		got.DueDate == nil || !got.DueDate.Equal(due) || !got.DueHasTime {
+		t.Errorf("first task = %+v, want it as written", got)
+	}
+	if got := tasks[1]; !got.Completed || !reflect.DeepEqual(got.CustomFields, []models.TaskCustomField{{FieldID: "Estimate", Value: "5"}}) {
+		t.Errorf("second task = %+v, want it completed with Estimate 5", got)
+	}
+}
+
+// A file without the configured container and status columns still reads: every row is a
+// task without container or status, and the file offers none to map.
+func TestReadWithoutContainerAndStatusColumns(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "plain.csv", "Title,Owner\nFirst,ann\nSecond,\n")
+	c := NewCSVClient(dir, testColumns(t))
+	ctx := context.Background()
+
+	containers, err := c.GetSourceContainers(ctx, "plain.csv")
+	if err != nil {
+		t.Fatalf("GetSourceContainers: %v", err)
+	}
+	if len(containers) != 0 {
+		t.Errorf("containers = %+v, want none", containers)
+	}
+	statuses, err := c.GetListStatuses(ctx, "plain.csv")
+	if err != nil {
+		t.Fatalf("GetListStatuses: %v", err)
+	}
+	if len(statuses) != 0 {
+		t.Errorf("statuses = %v, want none", statuses)
+	}
+
+	tasks, err := c.GetTasks(ctx, "plain.csv")
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Status != "" || tasks[1].Name != "Second" {
+		t.Errorf("tasks = %+v, want both rows without a status", tasks)
+	}
+	byContainer, err := c.GetTasksByContainer(ctx, "plain.csv|Backlog")
+	if err != nil {
+		t.Fatalf("GetTasksByContainer: %v", err)
+	}
+	if len(byContainer) != 0 {
+		t.Errorf("tasks in Backlog = %+v, want none", byContainer)
+	}
+}
+
+func TestReadRejectsBadDates(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "tasks.csv", "Title,Due\nShip it,next friday\n")
+	c := NewCSVClient(dir, testColumns(t))
+
+	_, err := c.GetTasks(context.Background(), "tasks.csv")
+	if err == nil || !strings.Contains(err.Error(), "row 1 of tasks.csv") {
+		t.Errorf("GetTasks error = %v, want the row and file of the bad date", err)
+	}
+}
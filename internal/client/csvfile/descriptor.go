@@ -0,0 +1,36 @@
+package csvfile
+
+import (
+	"fmt"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+)
+
+// Name is the provider name migrations use for CSV files.
+const Name = "csv"
+
+// Descriptor registers the CSV client. The destination workspace is "local" and the list
+// the file to write; container values are written into the file's container column.
+func Descriptor(c *CSVClient) client.Descriptor {
+	return client.Descriptor{
+		Name:        Name,
+		DisplayName: "CSV file",
+		Client:      c,
+		Validate: func(dest client.Destination) error {
+			if dest.WorkspaceID != WorkspaceID {
+				return fmt.Errorf("dest_workspace_id must be %q for CSV destination", WorkspaceID)
+			}
+			if _, _, _, err := splitID(dest.ListID); err != nil {
+				return fmt.Errorf("dest_list_id: %w", err)
+			}
+			return nil
+		},
+		ContainerAddress: func(dest client.Destination, containerID string) string {
+			return dest.ListID + "|" + containerID
+		},
+		TagScope: func(dest client.Destination) string {
+			return dest.ListID
+		},
+		NoCredentials: true,
+	}
+}
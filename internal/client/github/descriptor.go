@@ -0,0 +1,47 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+)
+
+// Name is the provider name migrations use for GitHub Issues.
+const Name = "github"
+
+// Descriptor registers the GitHub client. The destination workspace is the repository owner
+// and the list the repository ("owner/repo"). When the space is set to the number of a
+// project board of the owner, the board's columns are the destination containers and
+// statuses; otherwise the repository's milestones are the containers.
+func Descriptor(c *GitHubClient) client.Descriptor {
+	return client.Descriptor{
+		Name:        Name,
+		DisplayName: "GitHub Issues",
+		Client:      c,
+		Validate: func(dest client.Destination) error {
+			if dest.WorkspaceID == "" {
+				return fmt.Errorf("dest_workspace_id (repository owner) is required for GitHub destination")
+			}
+			if !strings.HasPrefix(dest.ListID, dest.WorkspaceID+"/") {
+				return fmt.Errorf("dest_list_id must be a repository of %s (owner/repo) for GitHub destination", dest.WorkspaceID)
+			}
+			if _, _, err := parseID(scope(dest)); err != nil {
+				return err
+			}
+			return nil
+		},
+		ContainerScope: scope,
+		TagScope: func(dest client.Destination) string {
+			return dest.ListID
+		},
+	}
+}
+
+// scope is the repository of a destination, with its project board if one is set.
+func scope(dest client.Destination) string {
+	if dest.SpaceID != "" {
+		return dest.ListID + "#" + dest.SpaceID
+	}
+	return dest.ListID
+}
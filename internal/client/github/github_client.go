@@ -0,0 +1,738 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/converter"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// Issue states, used as statuses of repositories without a project board.
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+)
+
+const pageSize = 100
+
+type GitHubClient struct {
+	baseUrl    string
+	graphqlUrl string
+	webUrl     string
+	token      string
+	httpClient *http.Client
+
+	cacheMu  sync.RWMutex
+	projects map[string]Project // "owner#number" → project board with its Status field
+}
+
+func NewGitHubClient(token string) *GitHubClient {
+	return NewGitHubClientWithBaseURL("https://api.github.com", token)
+}
+
+// NewGitHubClientWithBaseURL returns a client for the REST API at baseUrl. For GitHub
+// Enterprise Server this is https://<host>/api/v3; the GraphQL endpoint and browser links
+// are derived from it.
+func NewGitHubClientWithBaseURL(baseUrl, token string) *GitHubClient {
+	baseUrl = strings.TrimRight(baseUrl, "/")
+	c := &GitHubClient{
+		baseUrl:    baseUrl,
+		graphqlUrl: baseUrl + "/graphql",
+		webUrl:     baseUrl,
+		token:      token,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		projects:   make(map[string]Project),
+	}
+	switch {
+	case baseUrl == "https://api.github.com":
+		c.webUrl = "https://github.com"
+	case strings.HasSuffix(baseUrl, "/api/v3"):
+		root := strings.TrimSuffix(baseUrl, "/api/v3")
+		c.graphqlUrl = root + "/api/graphql"
+		c.webUrl = root
+	}
+	return c
+}
+
+// apiError is returned for non-2xx responses and for GraphQL errors.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	if e.message != "" {
+		return fmt.Sprintf("GitHub error: %s", e.message)
+	}
+	return fmt.Sprintf("API error status (github): %d", e.status)
+}
+
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound
+}
+
+// send sends a request to url and returns the response body of a 2xx response.
+func (c *GitHubClient) send(ctx context.Context, method, url string, reqBody any) ([]byte, error) {
+	var body io.Reader
+	if reqBody != nil {
+		b, err := json.Marshal(reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request (github): %w", err)
+		}
+		body = bytes.NewBuffer(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("build request (github): %w", err)
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s (github): %w", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body (github): %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var ghErr GitHubError
+		_ = json.Unmarshal(respBody, &ghErr)
+		return nil, &apiError{status: resp.StatusCode, message: ghErr.Message}
+	}
+	return respBody, nil
+}
+
+// do sends a request to the REST API and decodes a JSON response into out (if not nil).
+// path may carry a query string.
+func (c *GitHubClient) do(ctx context.Context, method, path string, reqBody, out any) error {
+	respBody, err := c.send(ctx, method, c.baseUrl+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("parse response (github): %w", err)
+	}
+	return nil
+}
+
+// getAll follows a paginated REST collection page by page.
+func getAll[T any](ctx context.Context, c *GitHubClient, path string) ([]T, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	var all []T
+	for page := 1; ; page++ {
+		var items []T
+		if err := c.do(ctx, "GET", fmt.Sprintf("%s%sper_page=%d&page=%d", path, sep, pageSize, page), nil, &items); err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+// graphql runs a GraphQL query (used for project boards) and decodes its data into out (if not nil).
+func (c *GitHubClient) graphql(ctx context.Context, q string, vars map[string]any, out any) error {
+	respBody, err := c.send(ctx, "POST", c.graphqlUrl, GraphQLRequest{Query: q, Variables: vars})
+	if err != nil {
+		return err
+	}
+	var gqlResp GraphQLResponse
+	if err := json.Unmarshal(respBody, &gqlResp); err != nil {
+		return fmt.Errorf("parse response (github): %w", err)
+	}
+	if len(gqlResp.Errors) > 0 {
+		msgs := make([]string, len(gqlResp.Errors))
+		for i, e := range gqlResp.Errors {
+			msgs[i] = e.Message
+		}
+		return &apiError{status: http.StatusOK, message: strings.Join(msgs, "; ")}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(gqlResp.Data, out); err != nil {
+		return fmt.Errorf("parse response (github): %w", err)
+	}
+	return nil
+}
+
+// ---- Addressing ----
+
+// repoRef is a repository, optionally together with a project board of its owner.
+//
+// IDs take the forms "owner/repo" (milestones are the containers and the issue state is the
+// status), "owner/repo#3" (the columns of project board 3 are the containers and statuses),
+// "owner/repo|7" (milestone 7), "owner/repo#3|<option>" (a board column) and "owner/repo/12"
+// (issue 12).
+type repoRef struct {
+	owner   string
+	repo    string
+	project int
+}
+
+func (r repoRef) String() string {
+	s := r.owner + "/" + r.repo
+	if r.project != 0 {
+		s += "#" + strconv.Itoa(r.project)
+	}
+	return s
+}
+
+func (r repoRef) path() string {
+	return "/repos/" + url.PathEscape(r.owner) + "/" + url.PathEscape(r.repo)
+}
+
+// parseID splits an ID into its repository reference and the rest after "|" or the issue
+// number.
+func parseID(id string) (repoRef, string, error) {
+	var rest string
+	if i := strings.Index(id, "|"); i != -1 {
+		id, rest = id[:i], id[i+1:]
+	}
+	var ref repoRef
+	if i := strings.Index(id, "#"); i != -1 {
+		n, err := strconv.Atoi(id[i+1:])
+		if err != nil || n <= 0 {
+			return repoRef{}, "", fmt.Errorf("invalid project number in %q (github)", id)
+		}
+		ref.project, id = n, id[:i]
+	}
+	parts := strings.Split(id, "/")
+	if len(parts) == 3 && rest == "" {
+		rest = parts[2]
+		parts = parts[:2]
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return repoRef{}, "", fmt.Errorf("invalid repository %q, expected owner/repo (github)", id)
+	}
+	ref.owner, ref.repo = parts[0], parts[1]
+	return ref, rest, nil
+}
+
+func issueID(ref repoRef, number int) string {
+	return ref.owner + "/" + ref.repo + "/" + strconv.Itoa(number)
+}
+
+// parseIssueID returns the repository and number of an issue ID ("owner/repo/12").
+func parseIssueID(id string) (repoRef, int, error) {
+	ref, rest, err := parseID(id)
+	if err != nil {
+		return repoRef{}, 0, err
+	}
+	n, err := strconv.Atoi(rest)
+	if err != nil {
+		return repoRef{}, 0, fmt.Errorf("invalid issue %q (github)", id)
+	}
+	return ref, n, nil
+}
+
+// ---- Owners, repositories and members ----
+
+// ListWorkspaces implements client.WorkspaceBrowser: the authenticated user and their
+// organizations are the workspaces.
+func (c *GitHubClient) ListWorkspaces(ctx context.Context) ([]client.Container, error) {
+	var me GitHubUser
+	if err := c.do(ctx, "GET", "/user", nil, &me); err != nil {
+		return nil, fmt.Errorf("get user (github): %w", err)
+	}
+	orgs, err := getAll[GitHubOrg](ctx, c, "/user/orgs")
+	if err != nil {
+		return nil, fmt.Errorf("get organizations (github): %w", err)
+	}
+	result := []client.Container{{ID: me.Login, Name: me.Login}}
+	for _, o := range orgs {
+		result = append(result, client.Container{ID: o.Login, Name: o.Login})
+	}
+	return result, nil
+}
+
+// ListProjects implements client.WorkspaceBrowser with the repositories of an organization
+// or user, identified as "owner/repo".
+func (c *GitHubClient) ListProjects(ctx context.Context, owner string) ([]client.Container, error) {
+	repos, err := getAll[GitHubRepo](ctx, c, "/orgs/"+url.PathEscape(owner)+"/repos")
+	if isNotFound(err) {
+		repos, err = getAll[GitHubRepo](ctx, c, "/users/"+url.PathEscape(owner)+"/repos")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get repositories (github): %w", err)
+	}
+	result := make([]client.Container, len(repos))
+	for i, r := range repos {
+		result[i] = client.Container{ID: r.FullName, Name: r.Name}
+	}
+	return result, nil
+}
+
+// GetMembers returns the users issues can be assigned to: the assignable users of a
+// repository, or the members of an organization. GitHub identifies users by login and
+// rarely exposes e-mail addresses.
+func (c *GitHubClient) GetMembers(ctx context.Context, id string) ([]models.Member, error) {
+	var users []GitHubUser
+	var err error
+	if strings.Contains(id, "/") {
+		var ref repoRef
+		if ref, _, err = parseID(id); err != nil {
+			return nil, err
+		}
+		users, err = getAll[GitHubUser](ctx, c, ref.path()+"/assignees")
+	} else {
+		users, err = getAll[GitHubUser](ctx, c, "/orgs/"+url.PathEscape(id)+"/members")
+		if isNotFound(err) {
+			// A personal account: its owner is the only member.
+			var u GitHubUser
+			err = c.do(ctx, "GET", "/users/"+url.PathEscape(id), nil, &u)
+			users = []GitHubUser{u}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get members (github): %w", err)
+	}
+	members := make([]models.Member, len(users))
+	for i, u := range users {
+		members[i] = models.Member{ID: u.Login, Name: u.Login, Email: u.Email}
+	}
+	return members, nil
+}
+
+// MaxAssignees reports GitHub's limit of assignees per issue.
+func (c *GitHubClient) MaxAssignees() int {
+	return 10
+}
+
+// ---- Project boards ----
+
+const projectQuery = `query Project($owner: String!, $number: Int!) {
+  repositoryOwner(login: $owner) {
+    ... on Organization { projectV2(number: $number) { ...board } }
+    ... on User { projectV2(number: $number) { ...board } }
+  }
+}
+fragment board on ProjectV2 {
+  id title
+  field(name: "Status") { ... on ProjectV2SingleSelectField { id options { id name } } }
+}`
+
+const projectItemsQuery = `query ProjectItems($id: ID!, $after: String) {
+  node(id: $id) { ... on ProjectV2 {
+    items(first: 100, after: $after) {
+      nodes {
+        id
+        fieldValueByName(name: "Status") { ... on ProjectV2ItemFieldSingleSelectValue { optionId } }
+        content { ... on Issue { number repository { nameWithOwner } } }
+      }
+      pageInfo { hasNextPage endCursor }
+    }
+  } }
+}`
+
+// getProject returns a project board of the repository owner with its Status field.
+func (c *GitHubClient) getProject(ctx context.Context, ref repoRef) (Project, error) {
+	key := ref.owner + "#" + strconv.Itoa(ref.project)
+	c.cacheMu.RLock()
+	p, ok := c.projects[key]
+	c.cacheMu.RUnlock()
+	if ok {
+		return p, nil
+	}
+
+	var data struct {
+		RepositoryOwner *struct {
+			ProjectV2 *Project `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+	if err := c.graphql(ctx, projectQuery, map[string]any{"owner": ref.owner, "number": ref.project}, &data); err != nil {
+		return Project{}, fmt.Errorf("get project (github): %w", err)
+	}
+	if data.RepositoryOwner == nil || data.RepositoryOwner.ProjectV2 == nil {
+		return Project{}, fmt.Errorf("get project (github): project %d of %s not found", ref.project, ref.owner)
+	}
+	p = *data.RepositoryOwner.ProjectV2
+	if p.Status == nil {
+		return Project{}, fmt.Errorf("get project (github): project %q has no Status field", p.Title)
+	}
+
+	c.cacheMu.Lock()
+	c.projects[key] = p
+	c.cacheMu.Unlock()
+	return p, nil
+}
+
+// getItemColumns returns the Status option of each issue of the repository on the board, by issue number.
+func (c *GitHubClient) getItemColumns(ctx context.Context, ref repoRef, project Project) (map[int]string, error) {
+	repoName := ref.owner + "/" + ref.repo
+	columns := make(map[int]string)
+	vars := map[string]any{"id": project.Id}
+	for {
+		var data struct {
+			Node struct {
+				Items struct {
+					Nodes    []ProjectItem `json:"nodes"`
+					PageInfo PageInfo      `json:"pageInfo"`
+				} `json:"items"`
+			} `json:"node"`
+		}
+		if err := c.graphql(ctx, projectItemsQuery, vars, &data); err != nil {
+			return nil, fmt.Errorf("get project items (github): %w", err)
+		}
+		for _, item := range data.Node.Items.Nodes {
+			if item.Content == nil || item.Content.Repository == nil || item.Status == nil {
+				continue
+			}
+			if strings.EqualFold(item.Content.Repository.NameWithOwner, repoName) {
+				columns[item.Content.Number] = item.Status.OptionId
+			}
+		}
+		page := data.Node.Items.PageInfo
+		if !page.HasNextPage || page.EndCursor == "" {
+			return columns, nil
+		}
+		vars["after"] = page.EndCursor
+	}
+}
+
+// ---- Containers and statuses ----
+
+// GetSourceContainers returns the milestones of a repository, or the columns of a project
+// board for "owner/repo#N".
+func (c *GitHubClient) GetSourceContainers(ctx context.Context, id string) ([]client.Container, error) {
+	ref, _, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if ref.project != 0 {
+		project, err := c.getProject(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		containers := make([]client.Container, len(project.Status.Options))
+		for i, o := range project.Status.Options {
+			containers[i] = client.Container{ID: ref.String() + "|" + o.Id, Name: o.Name}
+		}
+		return containers, nil
+	}
+
+	milestones, err := getAll[GitHubMilestone](ctx, c, ref.path()+"/milestones?state=all")
+	if err != nil {
+		return nil, fmt.Errorf("get milestones (github): %w", err)
+	}
+	containers := make([]client.Container, len(milestones))
+	for i, m := range milestones {
+		containers[i] = client.Container{ID: ref.String() + "|" + strconv.Itoa(m.Number), Name: m.Title}
+	}
+	return containers, nil
+}
+
+// GetDestContainers returns the milestones or board columns of a destination repository.
+func (c *GitHubClient) GetDestContainers(ctx context.Context, id string) ([]client.Container, error) {
+	return c.GetSourceContainers(ctx, id)
+}
+
+// GetListStatuses returns the columns of the project board, or the issue states when the
+// ID has no board.
+func (c *GitHubClient) GetListStatuses(ctx context.Context, id string) ([]string, error) {
+	ref, _, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if ref.project == 0 {
+		return []string{StateOpen, StateClosed}, nil
+	}
+	project, err := c.getProject(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(project.Status.Options))
+	for i, o := range project.Status.Options {
+		names[i] = o.Name
+	}
+	return names, nil
+}
+
+// ListTags returns the labels of a repository.
+func (c *GitHubClient) ListTags(ctx context.Context, id string) ([]string, error) {
+	ref, _, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := getAll[GitHubLabel](ctx, c, ref.path()+"/labels")
+	if err != nil {
+		return nil, fmt.Errorf("get labels (github): %w", err)
+	}
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = l.Name
+	}
+	return names, nil
+}
+
+// ---- Issues ----
+
+// GetTasks returns the issues of a repository, without pull requests. For "owner/repo#N"
+// the status of each issue is its column on the board.
+func (c *GitHubClient) GetTasks(ctx context.Context, id string) ([]models.Task, error) {
+	ref, _, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	return c.getIssuesAsTasks(ctx, ref, ref.path()+"/issues?state=all", "")
+}
+
+// GetTasksByContainer returns the issues of a milestone or board column.
+func (c *GitHubClient) GetTasksByContainer(ctx context.Context, id string) ([]models.Task, error) {
+	ref, container, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	if ref.project != 0 {
+		return c.getIssuesAsTasks(ctx, ref, ref.path()+"/issues?state=all", container)
+	}
+	return c.getIssuesAsTasks(ctx, ref, ref.path()+"/issues?state=all&milestone="+url.QueryEscape(container), "")
+}
+
+// getIssuesAsTasks lists issues; with a board, only the issues in column (if set) are kept.
+func (c *GitHubClient) getIssuesAsTasks(ctx context.Context, ref repoRef, path, column string) ([]models.Task, error) {
+	var columns map[int]string
+	optionNames := make(map[string]string)
+	if ref.project != 0 {
+		project, err := c.getProject(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range project.Status.Options {
+			optionNames[o.Id] = o.Name
+		}
+		if columns, err = c.getItemColumns(ctx, ref, project); err != nil {
+			return nil, err
+		}
+	}
+
+	issues, err := getAll[GitHubIssue](ctx, c, path)
+	if err != nil {
+		return nil, fmt.Errorf("get issues (github): %w", err)
+	}
+
+	loc := client.LocationFromContext(ctx)
+	tasks := make([]models.Task, 0, len(issues))
+	for _, issue := range issues {
+		if len(issue.PullRequest) > 0 {
+			continue
+		}
+		task := models.Task{
+			Id:              issueID(ref, issue.Number),
+			Name:            issue.Title,
+			Description:     issue.Body,
+			RichDescription: issue.Body,
+			Status:          issue.State,
+			Completed:       issue.State == StateClosed,
+			TimeZone:        loc.String(),
+		}
+		if ref.project != 0 {
+			if column != "" && columns[issue.Number] != column {
+				continue
+			}
+			task.Status = optionNames[columns[issue.Number]]
+		}
+		for _, a := range issue.Assignees {
+			task.Assignees = append(task.Assignees, models.TaskAssignee{ID: a.Login, Name: a.Login, Email: a.Email})
+		}
+		for _, l := range issue.Labels {
+			task.Tags = append(task.Tags, l.Name)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+const addProjectItemMutation = `mutation AddItem($project: ID!, $content: ID!) {
+  addProjectV2ItemById(input: { projectId: $project, contentId: $content }) { item { id } }
+}`
+
+const setItemStatusMutation = `mutation SetStatus($project: ID!, $item: ID!, $field: ID!, $option: String!) {
+  updateProjectV2ItemFieldValue(input: { projectId: $project, itemId: $item, fieldId: $field, value: { singleSelectOptionId: $option } }) { projectV2Item { id } }
+}`
+
+const deleteIssueMutation = `mutation DeleteIssue($id: ID!) {
+  deleteIssue(input: { issueId: $id }) { clientMutationId }
+}`
+
+// CreateTask creates an issue in a repository, in a milestone ("owner/repo|7") or on a
+// project board ("owner/repo#3" or a column "owner/repo#3|<option>"). On a board the task's
+// status picks the column if it names one; otherwise the container's column is used.
+// Without a board, a "closed" status or a completed task closes the issue. Labels that do
+// not exist yet are created by GitHub.
+func (c *GitHubClient) CreateTask(ctx context.Context, id string, _ string, task models.Task) (*models.Task, error) {
+	ref, container, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var project Project
+	column := container
+	if ref.project != 0 {
+		if project, err = c.getProject(ctx, ref); err != nil {
+			return nil, err
+		}
+		for _, o := range project.Status.Options {
+			if task.Status != "" && strings.EqualFold(o.Name, task.Status) {
+				column = o.Id
+			}
+		}
+	}
+
+	reqBody := CreateIssueRequest{
+		Title:  task.Name,
+		Body:   task.Description,
+		Labels: task.Tags,
+	}
+	if task.RichDescription != "" {
+		reqBody.Body = converter.PlainMentions(task.RichDescription)
+	}
+	for _, a := range task.Assignees {
+		reqBody.Assignees = append(reqBody.Assignees, a.ID)
+	}
+	if ref.project == 0 && container != "" {
+		n, err := strconv.Atoi(container)
+		if err != nil {
+			return nil, fmt.Errorf("invalid milestone %q (github)", container)
+		}
+		reqBody.Milestone = &n
+	}
+
+	var created GitHubIssue
+	if err := c.do(ctx, "POST", ref.path()+"/issues", reqBody, &created); err != nil {
+		return nil, fmt.Errorf("create issue (github): %w", err)
+	}
+	createdID := issueID(ref, created.Number)
+
+	status, err := c.placeIssue(ctx, ref, project, created, column, task)
+	if err != nil {
+		if delErr := c.DeleteTask(ctx, createdID); delErr != nil {
+			return nil, errors.Join(err, delErr)
+		}
+		return nil, err
+	}
+
+	return &models.Task{
+		Id:        createdID,
+		Name:      created.Title,
+		Status:    status,
+		Completed: status == StateClosed,
+	}, nil
+}
+
+// placeIssue closes a created issue or puts it in its board column, and returns its status.
+func (c *GitHubClient) placeIssue(ctx context.Context, ref repoRef, project Project, issue GitHubIssue, column string, task models.Task) (string, error) {
+	if ref.project == 0 {
+		if !strings.EqualFold(task.Status, StateClosed) && !(task.Completed && task.Status == "") {
+			return issue.State, nil
+		}
+		path := ref.path() + "/issues/" + strconv.Itoa(issue.Number)
+		if err := c.do(ctx, "PATCH", path, UpdateIssueRequest{State: StateClosed}, nil); err != nil {
+			return "", fmt.Errorf("close issue (github): %w", err)
+		}
+		return StateClosed, nil
+	}
+
+	var added struct {
+		AddProjectV2ItemById struct {
+			Item struct {
+				Id string `json:"id"`
+			} `json:"item"`
+		} `json:"addProjectV2ItemById"`
+	}
+	vars := map[string]any{"project": project.Id, "content": issue.NodeId}
+	if err := c.graphql(ctx, addProjectItemMutation, vars, &added); err != nil {
+		return "", fmt.Errorf("add issue to project (github): %w", err)
+	}
+	if column == "" {
+		return "", nil
+	}
+	vars = map[string]any{
+		"project": project.Id,
+		"item":    added.AddProjectV2ItemById.Item.Id,
+		"field":   project.Status.Id,
+		"option":  column,
+	}
+	if err := c.graphql(ctx, setItemStatusMutation, vars, nil); err != nil {
+		return "", fmt.Errorf("set project status (github): %w", err)
+	}
+	for _, o := range project.Status.Options {
+		if o.Id == column {
+			return o.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// TaskURL returns the browser link of an issue.
+func (c *GitHubClient) TaskURL(id string) string {
+	ref, number, err := parseIssueID(id)
+	if err != nil {
+		return ""
+	}
+	return c.webUrl + "/" + ref.owner + "/" + ref.repo + "/issues/" + strconv.Itoa(number)
+}
+
+// CreateComment adds a comment to an issue.
+func (c *GitHubClient) CreateComment(ctx context.Context, id, text string) error {
+	ref, number, err := parseIssueID(id)
+	if err != nil {
+		return err
+	}
+	path := ref.path() + "/issues/" + strconv.Itoa(number) + "/comments"
+	if err := c.do(ctx, "POST", path, CreateCommentRequest{Body: text}, nil); err != nil {
+		return fmt.Errorf("create comment (github): %w", err)
+	}
+	return nil
+}
+
+// DeleteTask deletes an issue, which the REST API cannot do and requires admin rights on the
+// repository. A 404 is treated as success so that rollbacks can be retried after partial
+// failures.
+func (c *GitHubClient) DeleteTask(ctx context.Context, id string) error {
+	ref, number, err := parseIssueID(id)
+	if err != nil {
+		return err
+	}
+	var issue GitHubIssue
+	if err := c.do(ctx, "GET", ref.path()+"/issues/"+strconv.Itoa(number), nil, &issue); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("delete issue (github): %w", err)
+	}
+	if err := c.graphql(ctx, deleteIssueMutation, map[string]any{"id": issue.NodeId}, nil); err != nil {
+		return fmt.Errorf("delete issue (github): %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,406 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// fakeGitHub is an in-memory stand-in for the REST and GraphQL endpoints the client uses,
+// for the repository acme/app and project board 3 of acme.
+type fakeGitHub struct {
+	mu         sync.Mutex
+	issues     []GitHubIssue
+	milestones []GitHubMilestone
+	labels     []GitHubLabel
+	project    Project
+	items      []ProjectItem
+	itemsPage  int // project items per GraphQL page
+
+	createdIssues []CreateIssueRequest
+	closedIssues  []string
+	addedItems    []string         // node IDs added to the board
+	statusSets    []map[string]any // variables of the status mutations
+	requests      []string
+}
+
+func (f *fakeGitHub) start(t *testing.T) *GitHubClient {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/acme/app/issues", func(w http.ResponseWriter, r *http.Request) {
+		issues := f.issues
+		if m := r.URL.Query().Get("milestone"); m != "" {
+			issues = nil
+			for _, i := range f.issues {
+				if i.Milestone != nil && strconv.Itoa(i.Milestone.Number) == m {
+					issues = append(issues, i)
+				}
+			}
+		}
+		writeTestJSON(w, restIssues(paginate(r, issues)))
+	})
+	mux.HandleFunc("GET /repos/acme/app/milestones", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, paginate(r, f.milestones))
+	})
+	mux.HandleFunc("GET /repos/acme/app/labels", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, paginate(r, f.labels))
+	})
+	mux.HandleFunc("POST /repos/acme/app/issues", func(w http.ResponseWriter, r *http.Request) {
+		var req CreateIssueRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.createdIssues = append(f.createdIssues, req)
+		writeTestJSON(w, GitHubIssue{NodeId: "I_new", Number: 42, Title: req.Title, State: StateOpen})
+	})
+	mux.HandleFunc("PATCH /repos/acme/app/issues/{number}", func(w http.ResponseWriter, r *http.Request) {
+		var req UpdateIssueRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.State == StateClosed {
+			f.closedIssues = append(f.closedIssues, r.PathValue("number"))
+		}
+		writeTestJSON(w, GitHubIssue{Number: 42, State: req.State})
+	})
+	mux.HandleFunc("POST /graphql", f.graphql)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeTestJSON(w, GitHubError{Message: "Bad credentials"})
+			return
+		}
+		f.requests = append(f.requests, r.Method+" "+r.URL.RequestURI())
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return NewGitHubClientWithBaseURL(srv.URL, "token")
+}
+
+func (f *fakeGitHub) graphql(w http.ResponseWriter, r *http.Request) {
+	var req GraphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var data any
+	switch {
+	case strings.HasPrefix(req.Query, "query Project("):
+		data = map[string]any{"repositoryOwner": map[string]any{"projectV2": f.project}}
+	case strings.HasPrefix(req.Query, "query ProjectItems("):
+		start := 0
+		if after, ok := req.Variables["after"].(string); ok {
+			start, _ = strconv.Atoi(after)
+		}
+		end := min(start+f.itemsPage, len(f.items))
+		page := PageInfo{HasNextPage: end < len(f.items)}
+		if page.HasNextPage {
+			page.EndCursor = strconv.Itoa(end)
+		}
+		data = map[string]any{"node": map[string]any{"items": map[string]any{"nodes": f.items[start:end], "pageInfo": page}}}
+	case strings.HasPrefix(req.Query, "mutation AddItem("):
+		f.addedItems = append(f.addedItems, req.Variables["content"].(string))
+		data = map[string]any{"addProjectV2ItemById": map[string]any{"item": map[string]any{"id": "PVTI_new"}}}
+	case strings.HasPrefix(req.Query, "mutation SetStatus("):
+		f.statusSets = append(f.statusSets, req.Variables)
+		data = map[string]any{}
+	default:
+		writeTestJSON(w, map[string]any{"errors": []GitHubError{{Message: "unknown query"}}})
+		return
+	}
+	writeTestJSON(w, map[string]any{"data": data})
+}
+
+// restIssues encodes issues as the REST API does, which only sends pull_request for pull
+// requests.
+func restIssues(issues []GitHubIssue) []map[string]any {
+	result := make([]map[string]any, len(issues))
+	for i, issue := range issues {
+		b, _ := json.Marshal(issue)
+		var m map[string]any
+		json.Unmarshal(b, &m)
+		if issue.PullRequest == nil {
+			delete(m, "pull_request")
+		}
+		result[i] = m
+	}
+	return result
+}
+
+// paginate serves the page of items a REST request asks for with page and per_page.
+func paginate[T any](r *http.Request, items []T) []T {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if page < 1 || perPage < 1 {
+		return items
+	}
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	return items[start:end]
+}
+
+func (f *fakeGitHub) count(prefix string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, r := range f.requests {
+		if strings.HasPrefix(r, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func writeTestJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func testBoard() Project {
+	p := Project{Id: "PVT_1", Title: "Roadmap"}
+	p.Status = &struct {
+		Id      string          `json:"id"`
+		Options []ProjectOption `json:"options"`
+	}{Id: "F_status", Options: []ProjectOption{{Id: "opt-todo", Name: "Todo"}, {Id: "opt-done", Name: "Done"}}}
+	return p
+}
+
+func boardItem(number int, repo, option string) ProjectItem {
+	var item ProjectItem
+	item.Id = fmt.Sprintf("PVTI_%d", number)
+	item.Status = &struct {
+		OptionId string `json:"optionId"`
+	}{OptionId: option}
+	item.Content = &struct {
+		Number     int `json:"number"`
+		Repository *struct {
+			NameWithOwner string `json:"nameWithOwner"`
+		} `json:"repository"`
+	}{Number: number, Repository: &struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	}{NameWithOwner: repo}}
+	return item
+}
+
+func TestGetTasksFollowsPagesAndMapsIssues(t *testing.T) {
+	fake := &fakeGitHub{}
+	for n := 1; n <= pageSize+20; n++ {
+		fake.issues = append(fake.issues, GitHubIssue{Number: n, Title: fmt.Sprintf("Issue %d", n), State: StateOpen})
+	}
+	fake.issues[0] = GitHubIssue{
+		Number:    1,
+		Title:     "Crash on start",
+		Body:      "Steps",
+		State:     StateClosed,
+		Assignees: []GitHubUser{{Login: "ann"}, {Login: "bob", Email: "bob@example.com"}},
+		Labels:    []GitHubLabel{{Name: "bug"}, {Name: "p1"}},
+	}
+	fake.issues[1].PullRequest = json.RawMessage(`{"url":"https://example.com/pulls/2"}`)
+	c := fake.start(t)
+
+	tasks, err := c.GetTasks(context.Background(), "acme/app")
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	if want := pageSize + 20 - 1; len(tasks) != want {
+		t.Fatalf("got %d tasks, want %d without the pull request", len(tasks), want)
+	}
+	if n := fake.count("GET /repos/acme/app/issues"); n != 2 {
+		t.Errorf("issues fetched in %d pages, want 2", n)
+	}
+
+	task := tasks[0]
+	if task.Id != "acme/app/1" || task.Name != "Crash on start" || task.Description != "Steps" {
+		t.Errorf("task = %+v", task)
+	}
+	if task.Status != StateClosed || !task.Completed {
+		t.Errorf("status = %q, completed = %v; want closed", task.Status, task.Completed)
+	}
+	wantAssignees := []models.TaskAssignee{{ID: "ann", Name: "ann"}, {ID: "bob", Name: "bob", Email: "bob@example.com"}}
+	if !reflect.DeepEqual(task.Assignees, wantAssignees) {
+		t.Errorf("assignees = %v, want %v", task.Assignees, wantAssignees)
+	}
+	if want := []string{"bug", "p1"}; !reflect.DeepEqual(task.Tags, want) {
+		t.Errorf("tags = %v, want %v", task.Tags, want)
+	}
+	if tasks[1].Id != "acme/app/3" || tasks[1].Status != StateOpen || tasks[1].Completed {
+		t.Errorf("second task = %+v, want open issue 3", tasks[1])
+	}
+}
+
+func TestMilestonesAreContainers(t *testing.T) {
+	fake := &fakeGitHub{
+		milestones: []GitHubMilestone{{Number: 7, Title: "v1.0"}, {Number: 8, Title: "v1.1"}},
+		labels:     []GitHubLabel{{Name: "bug"}, {Name: "docs"}},
+		issues: []GitHubIssue{
+			{Number: 1, Title: "In v1.0", State: StateOpen, Milestone: &GitHubMilestone{Number: 7}},
+			{Number: 2, Title: "In v1.1", State: StateOpen, Milestone: &GitHubMilestone{Number: 8}},
+		},
+	}
+	c := fake.start(t)
+	ctx := context.Background()
+
+	containers, err := c.GetSourceContainers(ctx, "acme/app")
+	if err != nil {
+		t.Fatalf("GetSourceContainers: %v", err)
+	}
+	want := []client.Container{{ID: "acme/app|7", Name: "v1.0"}, {ID: "acme/app|8", Name: "v1.1"}}
+	if !reflect.DeepEqual(containers, want) {
+		t.Errorf("containers = %v, want %v", containers, want)
+	}
+
+	tasks, err := c.GetTasksByContainer(ctx, "acme/app|7")
+	if err != nil {
+		t.Fatalf("GetTasksByContainer: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Id != "acme/app/1" {
+		t.Errorf("tasks of milestone 7 = %+v, want issue 1", tasks)
+	}
+
+	statuses, err := c.GetListStatuses(ctx, "acme/app|7")
+	if err != nil {
+		t.Fatalf("GetListStatuses: %v", err)
+	}
+	if want := []string{StateOpen, StateClosed}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+
+	tags, err := c.ListTags(ctx, "acme/app")
+	if err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	if want := []string{"bug", "docs"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %v, want %v", tags, want)
+	}
+}
+
+func TestBoardColumnsAreContainersAndStatuses(t *testing.T) {
+	fake := &fakeGitHub{
+		project:   testBoard(),
+		itemsPage: 2,
+		issues: []GitHubIssue{
+			{Number: 1, Title: "Planned", State: StateOpen},
+			{Number: 2, Title: "Shipped", State: StateClosed},
+			{Number: 3, Title: "Not on the board", State: StateOpen},
+		},
+		items: []ProjectItem{
+			boardItem(1, "acme/app", "opt-todo"),
+			boardItem(9, "acme/other", "opt-done"), // same number in another repository
+			boardItem(2, "ACME/app", "opt-done"),
+		},
+	}
+	c := fake.start(t)
+	ctx := context.Background()
+
+	containers, err := c.GetSourceContainers(ctx, "acme/app#3")
+	if err != nil {
+		t.Fatalf("GetSourceContainers: %v", err)
+	}
+	want := []client.Container{{ID: "acme/app#3|opt-todo", Name: "Todo"}, {ID: "acme/app#3|opt-done", Name: "Done"}}
+	if !reflect.DeepEqual(containers, want) {
+		t.Errorf("containers = %v, want %v", containers, want)
+	}
+	statuses, err := c.GetListStatuses(ctx, "acme/app#3")
+	if err != nil {
+		t.Fatalf("GetListStatuses: %v", err)
+	}
+	if want := []string{"Todo", "Done"}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+
+	tasks, err := c.GetTasks(ctx, "acme/app#3")
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	got := make(map[string]string)
+	for _, task := range tasks {
+		got[task.Id] = task.Status
+	}
+	wantStatus := map[string]string{"acme/app/1": "Todo", "acme/app/2": "Done", "acme/app/3": ""}
+	if !reflect.DeepEqual(got, wantStatus) {
+		t.Errorf("statuses by issue = %v, want %v", got, wantStatus)
+	}
+
+	done, err := c.GetTasksByContainer(ctx, "acme/app#3|opt-done")
+	if err != nil {
+		t.Fatalf("GetTasksByContainer: %v", err)
+	}
+	if len(done) != 1 || done[0].Id != "acme/app/2" || !done[0].Completed {
+		t.Errorf("tasks of the Done column = %+v, want closed issue 2", done)
+	}
+	// The board is fetched once; its items are paged through on every listing.
+	if n := fake.count("POST /graphql"); n != 1+2*2 {
+		t.Errorf("GraphQL requests = %d, want one board lookup and two item pages per listing", n)
+	}
+}
+
+func TestCreateTaskClosesOrPlacesIssue(t *testing.T) {
+	fake := &fakeGitHub{project: testBoard()}
+	c := fake.start(t)
+	ctx := context.Background()
+
+	created, err := c.CreateTask(ctx, "acme/app|7", "", models.Task{
+		Name:            "Ship it",
+		Description:     "plain",
+		RichDescription: "Ask [@Ann](mention:user/u1)",
+		Completed:       true,
+		Assignees:       []models.TaskAssignee{{ID: "ann"}},
+		Tags:            []string{"bug"},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask in milestone: %v", err)
+	}
+	req := fake.createdIssues[0]
+	if req.Milestone == nil || *req.Milestone != 7 || !reflect.DeepEqual(req.Assignees, []string{"ann"}) || !reflect.DeepEqual(req.Labels, []string{"bug"}) {
+		t.Errorf("create request = %+v", req)
+	}
+	if req.Body != "Ask @Ann" {
+		t.Errorf("body = %q, want the mention as plain text", req.Body)
+	}
+	if created.Id != "acme/app/42" || created.Status != StateClosed || !created.Completed {
+		t.Errorf("created = %+v, want closed issue 42", created)
+	}
+	if !reflect.DeepEqual(fake.closedIssues, []string{"42"}) {
+		t.Errorf("closed issues = %v, want [42]", fake.closedIssues)
+	}
+
+	created, err = c.CreateTask(ctx, "acme/app#3|opt-todo", "", models.Task{Name: "Board item", Status: "done"})
+	if err != nil {
+		t.Fatalf("CreateTask on board: %v", err)
+	}
+	if created.Status != "Done" {
+		t.Errorf("status = %q, want the Done column named by the task status", created.Status)
+	}
+	if !reflect.DeepEqual(fake.addedItems, []string{"I_new"}) {
+		t.Errorf("items added to the board = %v, want [I_new]", fake.addedItems)
+	}
+	if len(fake.statusSets) != 1 || fake.statusSets[0]["option"] != "opt-done" || fake.statusSets[0]["field"] != "F_status" {
+		t.Errorf("status updates = %v, want option opt-done of F_status", fake.statusSets)
+	}
+	if len(fake.closedIssues) != 1 {
+		t.Errorf("a board issue was closed through the REST API: %v", fake.closedIssues)
+	}
+}
+
+func TestRejectedTokenIsReported(t *testing.T) {
+	fake := &fakeGitHub{}
+	valid := fake.start(t)
+	c := NewGitHubClientWithBaseURL(valid.baseUrl, "wrong")
+
+	_, err := c.ListTags(context.Background(), "acme/app")
+	if err == nil || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Fatalf("err = %v, want the API message", err)
+	}
+	if strings.Contains(err.Error(), "wrong") {
+		t.Errorf("error %q contains the token", err)
+	}
+}
@@ -0,0 +1,104 @@
+package github
+
+import "encoding/json"
+
+type GitHubError struct {
+	Message string `json:"message"`
+}
+
+type GitHubUser struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type GitHubOrg struct {
+	Login string `json:"login"`
+}
+
+type GitHubRepo struct {
+	FullName string `json:"full_name"`
+	Name     string `json:"name"`
+}
+
+type GitHubMilestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+type GitHubLabel struct {
+	Name string `json:"name"`
+}
+
+type GitHubIssue struct {
+	NodeId      string           `json:"node_id"`
+	Number      int              `json:"number"`
+	Title       string           `json:"title"`
+	Body        string           `json:"body"`
+	State       string           `json:"state"` // open, closed
+	Assignees   []GitHubUser     `json:"assignees"`
+	Labels      []GitHubLabel    `json:"labels"`
+	Milestone   *GitHubMilestone `json:"milestone"`
+	PullRequest json.RawMessage  `json:"pull_request"`
+}
+
+type CreateIssueRequest struct {
+	Title     string   `json:"title"`
+	Body      string   `json:"body,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Milestone *int     `json:"milestone,omitempty"`
+}
+
+type UpdateIssueRequest struct {
+	State string `json:"state"`
+}
+
+type CreateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// ---- GraphQL (Projects) ----
+
+type GraphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type GraphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []GitHubError   `json:"errors"`
+}
+
+type PageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type ProjectOption struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Project is a project board (Projects v2) with its Status field.
+type Project struct {
+	Id     string `json:"id"`
+	Title  string `json:"title"`
+	Status *struct {
+		Id      string          `json:"id"`
+		Options []ProjectOption `json:"options"`
+	} `json:"field"`
+}
+
+type ProjectItem struct {
+	Id     string `json:"id"`
+	Status *struct {
+		OptionId string `json:"optionId"`
+	} `json:"fieldValueByName"`
+	Content *struct {
+		Number     int `json:"number"`
+		Repository *struct {
+			NameWithOwner string `json:"nameWithOwner"`
+		} `json:"repository"`
+	} `json:"content"`
+}
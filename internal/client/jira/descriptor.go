@@ -0,0 +1,30 @@
+package jira
+
+import (
+	"fmt"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+)
+
+// Name is the provider name migrations use for Jira Cloud.
+const Name = "jira"
+
+// Descriptor registers the Jira client. The destination project is given by its key as the
+// list; its epics are the destination containers, addressed as "project|epic".
+func Descriptor(c *JiraClient) client.Descriptor {
+	return client.Descriptor{
+		Name:        Name,
+		DisplayName: "Jira",
+		Client:      c,
+		Validate: func(dest client.Destination) error {
+			if dest.ListID == "" {
+				return fmt.Errorf("dest_list_id (project key) is required for Jira destination")
+			}
+			return nil
+		},
+		ContainerAddress: func(dest client.Destination, containerID string) string {
+			return dest.ListID + "|" + containerID
+		},
+		DefaultPriorities: []string{"Highest", "High", "Medium", "Low", "Lowest"},
+	}
+}
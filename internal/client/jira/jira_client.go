@@ -0,0 +1,779 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/converter"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// issueTypeFieldID is the ID the issue type is exposed under as a dropdown field, so that
+// a source field can decide the issue type of created issues.
+const issueTypeFieldID = "issuetype"
+
+const sprintFieldType = "com.pyxis.greenhopper.jira:gh-sprint"
+
+type JiraClient struct {
+	baseUrl    string
+	email      string
+	apiToken   string
+	httpClient *http.Client
+
+	fieldsOnce   sync.Once
+	fieldsErr    error
+	sprintField  string // custom field ID of the sprint field, "" when Jira Software is not installed
+	startField   string // custom field ID of the "Start date" field, if any
+	cacheMu      sync.RWMutex
+	projects     map[string]JiraProject                    // key → project with issue types
+	projectDefs  map[string][]models.CustomFieldDefinition // key → issue type and sprint fields
+	memberCache  []models.Member
+	membersValid bool
+}
+
+// NewJiraClient returns a client for the Jira Cloud site at baseUrl
+// (https://<site>.atlassian.net), authenticating with an account e-mail and API token.
+func NewJiraClient(baseUrl, email, apiToken string) *JiraClient {
+	return &JiraClient{
+		baseUrl:     strings.TrimRight(baseUrl, "/"),
+		email:       email,
+		apiToken:    apiToken,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		projects:    make(map[string]JiraProject),
+		projectDefs: make(map[string][]models.CustomFieldDefinition),
+	}
+}
+
+// apiError is returned for non-2xx responses.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	if e.message != "" {
+		return fmt.Sprintf("Jira error: %s", e.message)
+	}
+	return fmt.Sprintf("API error status (jira): %d", e.status)
+}
+
+// do sends a request to the Jira API and decodes a JSON response into out (if not nil).
+func (c *JiraClient) do(ctx context.Context, method, path string, reqBody, out any) error {
+	var body io.Reader
+	if reqBody != nil {
+		b, err := json.Marshal(reqBody)
+		if err != nil {
+			return fmt.Errorf("marshal request (jira): %w", err)
+		}
+		body = bytes.NewBuffer(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseUrl+path, body)
+	if err != nil {
+		return fmt.Errorf("build request (jira): %w", err)
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(c.email, c.apiToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s (jira): %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response body (jira): %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var jiraErr JiraErrors
+		var msgs []string
+		if json.Unmarshal(respBody, &jiraErr) == nil {
+			msgs = append(msgs, jiraErr.ErrorMessages...)
+			keys := make([]string, 0, len(jiraErr.Errors))
+			for k := range jiraErr.Errors {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				msgs = append(msgs, k+": "+jiraErr.Errors[k])
+			}
+		}
+		return &apiError{status: resp.StatusCode, message: strings.Join(msgs, "; ")}
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("parse response (jira): %w", err)
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound
+}
+
+// projectKeyOf returns the project key of a project key, issue key ("PROJ-12") or
+// destination container address ("PROJ|PROJ-12"). Project keys never contain a hyphen.
+func projectKeyOf(id string) string {
+	if i := strings.Index(id, "|"); i != -1 {
+		id = id[:i]
+	}
+	if i := strings.Index(id, "-"); i != -1 {
+		id = id[:i]
+	}
+	return id
+}
+
+// jqlQuote quotes a value for use in JQL.
+func jqlQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// ---- Site, projects and users ----
+
+// ListWorkspaces implements client.WorkspaceBrowser. A Jira Cloud site is a single workspace.
+func (c *JiraClient) ListWorkspaces(ctx context.Context) ([]client.Container, error) {
+	var info JiraServerInfo
+	if err := c.do(ctx, "GET", "/rest/api/3/serverInfo", nil, &info); err != nil {
+		return nil, fmt.Errorf("get server info (jira): %w", err)
+	}
+	id := info.BaseUrl
+	if u, err := url.Parse(info.BaseUrl); err == nil && u.Host != "" {
+		id = u.Host
+	}
+	return []client.Container{{ID: id, Name: info.ServerTitle}}, nil
+}
+
+func (c *JiraClient) GetProjects(ctx context.Context) ([]JiraProject, error) {
+	var projects []JiraProject
+	for startAt := 0; ; {
+		var page GetProjectsResponse
+		path := "/rest/api/3/project/search?maxResults=100&startAt=" + strconv.Itoa(startAt)
+		if err := c.do(ctx, "GET", path, nil, &page); err != nil {
+			return nil, fmt.Errorf("get projects (jira): %w", err)
+		}
+		projects = append(projects, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			return projects, nil
+		}
+	}
+}
+
+// ListProjects implements client.WorkspaceBrowser. Projects are identified by their key.
+func (c *JiraClient) ListProjects(ctx context.Context, _ string) ([]client.Container, error) {
+	projects, err := c.GetProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]client.Container, len(projects))
+	for i, p := range projects {
+		result[i] = client.Container{ID: p.Key, Name: p.Name}
+	}
+	return result, nil
+}
+
+// getProject returns a project with its issue types.
+func (c *JiraClient) getProject(ctx context.Context, key string) (JiraProject, error) {
+	c.cacheMu.RLock()
+	p, ok := c.projects[key]
+	c.cacheMu.RUnlock()
+	if ok {
+		return p, nil
+	}
+
+	if err := c.do(ctx, "GET", "/rest/api/3/project/"+url.PathEscape(key), nil, &p); err != nil {
+		return JiraProject{}, fmt.Errorf("get project (jira): %w", err)
+	}
+	c.cacheMu.Lock()
+	c.projects[key] = p
+	c.cacheMu.Unlock()
+	return p, nil
+}
+
+// GetMembers returns the active user accounts of the site. E-mail addresses are only
+// returned for users whose profile visibility allows it.
+func (c *JiraClient) GetMembers(ctx context.Context, _ string) ([]models.Member, error) {
+	c.cacheMu.RLock()
+	cached, ok := c.memberCache, c.membersValid
+	c.cacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	var members []models.Member
+	const pageSize = 1000
+	for startAt := 0; ; startAt += pageSize {
+		var users []JiraUser
+		path := fmt.Sprintf("/rest/api/3/users/search?startAt=%d&maxResults=%d", startAt, pageSize)
+		if err := c.do(ctx, "GET", path, nil, &users); err != nil {
+			return nil, fmt.Errorf("get users (jira): %w", err)
+		}
+		for _, u := range users {
+			if u.AccountType != "atlassian" || !u.Active {
+				continue
+			}
+			members = append(members, models.Member{ID: u.AccountId, Name: u.DisplayName, Email: u.EmailAddress})
+		}
+		if len(users) < pageSize {
+			break
+		}
+	}
+
+	c.cacheMu.Lock()
+	c.memberCache, c.membersValid = members, true
+	c.cacheMu.Unlock()
+	return members, nil
+}
+
+// MaxAssignees reports that Jira issues have a single assignee.
+func (c *JiraClient) MaxAssignees() int {
+	return 1
+}
+
+// ---- Workflow, priorities and labels ----
+
+// GetListStatuses returns the workflow statuses of the project an ID belongs to, across
+// all standard issue types.
+func (c *JiraClient) GetListStatuses(ctx context.Context, id string) ([]string, error) {
+	var issueTypes []JiraProjectStatuses
+	path := "/rest/api/3/project/" + url.PathEscape(projectKeyOf(id)) + "/statuses"
+	if err := c.do(ctx, "GET", path, nil, &issueTypes); err != nil {
+		return nil, fmt.Errorf("get statuses (jira): %w", err)
+	}
+	seen := make(map[string]bool)
+	var statuses []string
+	for _, it := range issueTypes {
+		if it.Subtask {
+			continue
+		}
+		for _, s := range it.Statuses {
+			if !seen[s.Name] {
+				seen[s.Name] = true
+				statuses = append(statuses, s.Name)
+			}
+		}
+	}
+	return statuses, nil
+}
+
+// GetProjectCustomFieldOptions returns the priorities of the project's priority scheme as
+// name → priority ID. The "__field_gid__" entry names the field the migration writes them to.
+func (c *JiraClient) GetProjectCustomFieldOptions(ctx context.Context, projectKey string) (map[string]string, error) {
+	project, err := c.getProject(ctx, projectKeyOf(projectKey))
+	if err != nil {
+		return nil, err
+	}
+
+	options := map[string]string{"__field_gid__": "priority"}
+	for startAt := 0; ; {
+		var page GetPrioritiesResponse
+		path := fmt.Sprintf("/rest/api/3/priority/search?projectId=%s&maxResults=100&startAt=%d", url.QueryEscape(project.Id), startAt)
+		if err := c.do(ctx, "GET", path, nil, &page); err != nil {
+			return nil, fmt.Errorf("get priorities (jira): %w", err)
+		}
+		for _, p := range page.Values {
+			options[p.Name] = p.Id
+		}
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+	}
+	return options, nil
+}
+
+// ListTags returns the labels used on the site. Jira labels are global.
+func (c *JiraClient) ListTags(ctx context.Context, _ string) ([]string, error) {
+	var labels []string
+	for startAt := 0; ; {
+		var page GetLabelsResponse
+		if err := c.do(ctx, "GET", "/rest/api/3/label?maxResults=1000&startAt="+strconv.Itoa(startAt), nil, &page); err != nil {
+			return nil, fmt.Errorf("get labels (jira): %w", err)
+		}
+		labels = append(labels, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			return labels, nil
+		}
+	}
+}
+
+// labelOf turns a tag into a Jira label, which cannot contain spaces.
+func labelOf(tag string) string {
+	return strings.Join(strings.Fields(tag), "_")
+}
+
+// ---- Fields: issue types and sprints ----
+
+// discoverFields looks up the IDs of the sprint and start date custom fields once.
+func (c *JiraClient) discoverFields(ctx context.Context) error {
+	c.fieldsOnce.Do(func() {
+		var fields []JiraField
+		if err := c.do(ctx, "GET", "/rest/api/3/field", nil, &fields); err != nil {
+			c.fieldsErr = fmt.Errorf("get fields (jira): %w", err)
+			return
+		}
+		for _, f := range fields {
+			switch {
+			case f.Schema.Custom == sprintFieldType && c.sprintField == "":
+				c.sprintField = f.Id
+			case f.Custom && f.Schema.Type == "date" && strings.EqualFold(f.Name, "Start date") && c.startField == "":
+				c.startField = f.Id
+			}
+		}
+	})
+	return c.fieldsErr
+}
+
+// getSprints returns the sprints of the project's scrum boards, oldest first.
+func (c *JiraClient) getSprints(ctx context.Context, projectKey string) ([]JiraSprint, error) {
+	var boards GetBoardsResponse
+	path := "/rest/agile/1.0/board?type=scrum&projectKeyOrId=" + url.QueryEscape(projectKey)
+	if err := c.do(ctx, "GET", path, nil, &boards); err != nil {
+		return nil, fmt.Errorf("get boards (jira): %w", err)
+	}
+
+	seen := make(map[int]bool)
+	var sprints []JiraSprint
+	for _, b := range boards.Values {
+		for startAt := 0; ; {
+			var page GetSprintsResponse
+			path := fmt.Sprintf("/rest/agile/1.0/board/%d/sprint?maxResults=50&startAt=%d", b.Id, startAt)
+			if err := c.do(ctx, "GET", path, nil, &page); err != nil {
+				return nil, fmt.Errorf("get sprints (jira): %w", err)
+			}
+			for _, s := range page.Values {
+				if !seen[s.Id] {
+					seen[s.Id] = true
+					sprints = append(sprints, s)
+				}
+			}
+			startAt += len(page.Values)
+			if page.IsLast || len(page.Values) == 0 {
+				break
+			}
+		}
+	}
+	return sprints, nil
+}
+
+// projectFieldDefinitions describes the issue type and, with Jira Software, the sprint of
+// the project's issues as dropdown fields, so they can be mapped like custom fields.
+func (c *JiraClient) projectFieldDefinitions(ctx context.Context, projectKey string) ([]models.CustomFieldDefinition, error) {
+	c.cacheMu.RLock()
+	cached, ok := c.projectDefs[projectKey]
+	c.cacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	project, err := c.getProject(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.discoverFields(ctx); err != nil {
+		return nil, err
+	}
+
+	issueTypes := models.CustomFieldDefinition{ID: issueTypeFieldID, Name: "Issue Type", ClickUpType: "drop_down"}
+	for _, it := range project.IssueTypes {
+		if it.Subtask || it.HierarchyLevel > 0 {
+			continue
+		}
+		issueTypes.Options = append(issueTypes.Options, models.CustomFieldOption{ID: it.Id, Name: it.Name, OrderIndex: len(issueTypes.Options)})
+	}
+	defs := []models.CustomFieldDefinition{issueTypes}
+
+	if c.sprintField != "" {
+		sprints, err := c.getSprints(ctx, projectKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(sprints) > 0 {
+			sprintDef := models.CustomFieldDefinition{ID: c.sprintField, Name: "Sprint", ClickUpType: "drop_down"}
+			for i, s := range sprints {
+				sprintDef.Options = append(sprintDef.Options, models.CustomFieldOption{ID: strconv.Itoa(s.Id), Name: s.Name, OrderIndex: i})
+			}
+			defs = append(defs, sprintDef)
+		}
+	}
+
+	c.cacheMu.Lock()
+	c.projectDefs[projectKey] = defs
+	c.cacheMu.Unlock()
+	return defs, nil
+}
+
+// GetFieldDefinitions returns the issue type and sprint fields of the project a container belongs to.
+func (c *JiraClient) GetFieldDefinitions(ctx context.Context, id string) ([]models.CustomFieldDefinition, error) {
+	defs, err := c.projectFieldDefinitions(ctx, projectKeyOf(id))
+	if err != nil {
+		return nil, fmt.Errorf("get field definitions (jira): %w", err)
+	}
+	return defs, nil
+}
+
+// GetDestFieldDefinitions returns the issue type and sprint fields of a destination project.
+func (c *JiraClient) GetDestFieldDefinitions(ctx context.Context, projectKey string) ([]models.CustomFieldDefinition, error) {
+	return c.GetFieldDefinitions(ctx, projectKey)
+}
+
+// ---- Issues ----
+
+func (c *JiraClient) searchFields() []string {
+	fields := []string{"summary", "description", "status", "priority", "assignee", "duedate", "issuetype", "labels", "parent"}
+	if c.sprintField != "" {
+		fields = append(fields, c.sprintField)
+	}
+	if c.startField != "" {
+		fields = append(fields, c.startField)
+	}
+	return fields
+}
+
+func (c *JiraClient) searchIssues(ctx context.Context, jql string, fields []string) ([]JiraIssue, error) {
+	var issues []JiraIssue
+	req := SearchRequest{JQL: jql, Fields: fields, MaxResults: 100}
+	for {
+		var page SearchResponse
+		if err := c.do(ctx, "POST", "/rest/api/3/search/jql", req, &page); err != nil {
+			return nil, fmt.Errorf("search issues (jira): %w", err)
+		}
+		issues = append(issues, page.Issues...)
+		if page.IsLast || page.NextPageToken == "" {
+			return issues, nil
+		}
+		req.NextPageToken = page.NextPageToken
+	}
+}
+
+// GetTasks returns the issues of a project, without epics and sub-tasks.
+func (c *JiraClient) GetTasks(ctx context.Context, projectKey string) ([]models.Task, error) {
+	jql := "project = " + jqlQuote(projectKey) + " AND issuetype != Epic AND issuetype not in subTaskIssueTypes() ORDER BY created ASC"
+	return c.getIssuesAsTasks(ctx, projectKey, jql)
+}
+
+// GetTasksByContainer returns the issues of an epic.
+func (c *JiraClient) GetTasksByContainer(ctx context.Context, epicKey string) ([]models.Task, error) {
+	jql := "parent = " + jqlQuote(epicKey) + " ORDER BY created ASC"
+	return c.getIssuesAsTasks(ctx, projectKeyOf(epicKey), jql)
+}
+
+func (c *JiraClient) getIssuesAsTasks(ctx context.Context, projectKey, jql string) ([]models.Task, error) {
+	defs, err := c.projectFieldDefinitions(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := c.searchIssues(ctx, jql, c.searchFields())
+	if err != nil {
+		return nil, err
+	}
+
+	loc := client.LocationFromContext(ctx)
+	tasks := make([]models.Task, 0, len(issues))
+	for _, issue := range issues {
+		task, err := c.parseIssue(issue, defs, loc)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// parseJiraDate reads a Jira date field (YYYY-MM-DD) as midnight of that day in loc.
+func parseJiraDate(s string, loc *time.Location) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, loc)
+	if err != nil {
+		return nil, fmt.Errorf("parse date (jira): %w", err)
+	}
+	return &t, nil
+}
+
+func optionIndex(defs []models.CustomFieldDefinition, fieldID, optionID string) (float64, bool) {
+	for _, d := range defs {
+		if d.ID != fieldID {
+			continue
+		}
+		for _, o := range d.Options {
+			if o.ID == optionID {
+				return float64(o.OrderIndex), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (c *JiraClient) parseIssue(issue JiraIssue, defs []models.CustomFieldDefinition, loc *time.Location) (models.Task, error) {
+	var fields JiraIssueFields
+	if err := json.Unmarshal(issue.Fields, &fields); err != nil {
+		return models.Task{}, fmt.Errorf("parse issue %s (jira): %w", issue.Key, err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(issue.Fields, &raw); err != nil {
+		return models.Task{}, fmt.Errorf("parse issue %s (jira): %w", issue.Key, err)
+	}
+
+	task := models.Task{
+		Id:              issue.Key,
+		Name:            fields.Summary,
+		Description:     converter.ADFToText(fields.Description),
+		RichDescription: converter.ADFToMarkdown(fields.Description),
+		TimeZone:        loc.String(),
+		Tags:            fields.Labels,
+	}
+	if fields.Status != nil {
+		task.Status = fields.Status.Name
+		task.Completed = fields.Status.StatusCategory.Key == "done"
+	}
+	if fields.Priority != nil {
+		task.Priority = fields.Priority.Name
+	}
+	if fields.Assignee != nil {
+		task.Assignees = []models.TaskAssignee{{
+			ID:    fields.Assignee.AccountId,
+			Name:  fields.Assignee.DisplayName,
+			Email: fields.Assignee.EmailAddress,
+		}}
+	}
+
+	var err error
+	if task.DueDate, err = parseJiraDate(fields.DueDate, loc); err != nil {
+		return models.Task{}, err
+	}
+	if c.startField != "" {
+		var start string
+		if v, ok := raw[c.startField]; ok && json.Unmarshal(v, &start) == nil {
+			if task.StartDate, err = parseJiraDate(start, loc); err != nil {
+				return models.Task{}, err
+			}
+		}
+	}
+
+	if fields.IssueType != nil {
+		if idx, ok := optionIndex(defs, issueTypeFieldID, fields.IssueType.Id); ok {
+			task.CustomFields = append(task.CustomFields, models.TaskCustomField{FieldID: issueTypeFieldID, Value: idx})
+		}
+	}
+	if c.sprintField != "" {
+		// An issue carried over between sprints lists all of them; the last one is current.
+		var sprints []JiraSprint
+		if v, ok := raw[c.sprintField]; ok && json.Unmarshal(v, &sprints) == nil && len(sprints) > 0 {
+			current := strconv.Itoa(sprints[len(sprints)-1].Id)
+			if idx, ok := optionIndex(defs, c.sprintField, current); ok {
+				task.CustomFields = append(task.CustomFields, models.TaskCustomField{FieldID: c.sprintField, Value: idx})
+			}
+		}
+	}
+	return task, nil
+}
+
+// ---- Epics as containers ----
+
+// GetSourceContainers returns the epics of a project.
+func (c *JiraClient) GetSourceContainers(ctx context.Context, projectKey string) ([]client.Container, error) {
+	jql := "project = " + jqlQuote(projectKey) + " AND issuetype = Epic ORDER BY created ASC"
+	issues, err := c.searchIssues(ctx, jql, []string{"summary"})
+	if err != nil {
+		return nil, err
+	}
+	containers := make([]client.Container, 0, len(issues))
+	for _, issue := range issues {
+		var fields JiraIssueFields
+		if err := json.Unmarshal(issue.Fields, &fields); err != nil {
+			return nil, fmt.Errorf("parse epic %s (jira): %w", issue.Key, err)
+		}
+		containers = append(containers, client.Container{ID: issue.Key, Name: fields.Summary})
+	}
+	return containers, nil
+}
+
+// GetDestContainers returns the epics of a project (used as destination containers).
+func (c *JiraClient) GetDestContainers(ctx context.Context, projectKey string) ([]client.Container, error) {
+	return c.GetSourceContainers(ctx, projectKey)
+}
+
+// defaultIssueType picks the issue type of created issues when no field mapping decides it:
+// "Task" if the project has it, otherwise its first standard issue type.
+func defaultIssueType(project JiraProject) string {
+	var first string
+	for _, it := range project.IssueTypes {
+		if it.Subtask || it.HierarchyLevel > 0 {
+			continue
+		}
+		if strings.EqualFold(it.Name, "Task") {
+			return it.Id
+		}
+		if first == "" {
+			first = it.Id
+		}
+	}
+	return first
+}
+
+// CreateTask creates an issue. id is a project key, or "project|epic" to create the issue
+// in an epic. The status is reached with a workflow transition after creation when the
+// workflow allows a direct one; otherwise the issue keeps its initial status. Extra assignees
+// and followers are added as watchers on a best-effort basis.
+func (c *JiraClient) CreateTask(ctx context.Context, id string, _ string, task models.Task) (*models.Task, error) {
+	projectKey, epicKey := id, ""
+	if i := strings.Index(id, "|"); i != -1 {
+		projectKey, epicKey = id[:i], id[i+1:]
+	}
+	project, err := c.getProject(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.discoverFields(ctx); err != nil {
+		return nil, err
+	}
+
+	loc := client.TaskLocation(ctx, task)
+	fields := map[string]any{
+		"project":   map[string]string{"key": projectKey},
+		"summary":   task.Name,
+		"issuetype": map[string]string{"id": defaultIssueType(project)},
+	}
+	switch {
+	case task.RichDescription != "":
+		fields["description"] = converter.MarkdownToADF(task.RichDescription)
+	case task.Description != "":
+		fields["description"] = converter.TextToADF(task.Description)
+	}
+	if epicKey != "" {
+		fields["parent"] = map[string]string{"key": epicKey}
+	}
+	if len(task.Assignees) > 0 {
+		fields["assignee"] = map[string]string{"accountId": task.Assignees[0].ID}
+	}
+	if parts := strings.SplitN(task.Priority, ":", 2); len(parts) == 2 && parts[0] == "priority" {
+		fields["priority"] = map[string]string{"id": parts[1]}
+	}
+	if task.DueDate != nil {
+		fields["duedate"] = task.DueDate.In(loc).Format("2006-01-02")
+	}
+	if task.StartDate != nil && c.startField != "" {
+		fields[c.startField] = task.StartDate.In(loc).Format("2006-01-02")
+	}
+	if len(task.Tags) > 0 {
+		labels := make([]string, 0, len(task.Tags))
+		for _, t := range task.Tags {
+			labels = append(labels, labelOf(t))
+		}
+		fields["labels"] = labels
+	}
+	for _, cf := range task.CustomFields {
+		if cf.Value == nil {
+			continue
+		}
+		switch cf.FieldID {
+		case issueTypeFieldID:
+			fields["issuetype"] = map[string]string{"id": fmt.Sprintf("%v", cf.Value)}
+		case c.sprintField:
+			if sprintID, err := strconv.Atoi(fmt.Sprintf("%v", cf.Value)); err == nil {
+				fields[c.sprintField] = sprintID
+			}
+		default:
+			fields[cf.FieldID] = cf.Value
+		}
+	}
+
+	var created CreateIssueResponse
+	if err := c.do(ctx, "POST", "/rest/api/3/issue", CreateIssueRequest{Fields: fields}, &created); err != nil {
+		return nil, fmt.Errorf("create issue (jira): %w", err)
+	}
+
+	status, err := c.transitionTo(ctx, created.Key, task.Status)
+	if err != nil {
+		if delErr := c.DeleteTask(ctx, created.Key); delErr != nil {
+			return nil, errors.Join(err, delErr)
+		}
+		return nil, err
+	}
+
+	var watchers []models.TaskAssignee
+	if len(task.Assignees) > 1 {
+		watchers = append(watchers, task.Assignees[1:]...)
+	}
+	watchers = append(watchers, task.Followers...)
+	for _, w := range watchers {
+		// Watchers are optional: users without browse permission cannot watch, which must
+		// not fail the issue.
+		_ = c.do(ctx, "POST", "/rest/api/3/issue/"+created.Key+"/watchers", w.ID, nil)
+	}
+
+	return &models.Task{
+		Id:     created.Key,
+		Name:   task.Name,
+		Status: status,
+	}, nil
+}
+
+// transitionTo moves an issue to the named status if a transition leads there directly,
+// and returns the status it ends in ("" if unchanged or unknown).
+func (c *JiraClient) transitionTo(ctx context.Context, issueKey, status string) (string, error) {
+	if status == "" {
+		return "", nil
+	}
+	var transitions GetTransitionsResponse
+	if err := c.do(ctx, "GET", "/rest/api/3/issue/"+issueKey+"/transitions", nil, &transitions); err != nil {
+		return "", fmt.Errorf("get transitions (jira): %w", err)
+	}
+	for _, t := range transitions.Transitions {
+		if !strings.EqualFold(t.To.Name, status) {
+			continue
+		}
+		if err := c.do(ctx, "POST", "/rest/api/3/issue/"+issueKey+"/transitions", TransitionRequest{Transition: TransitionRef{Id: t.Id}}, nil); err != nil {
+			return "", fmt.Errorf("transition issue (jira): %w", err)
+		}
+		return t.To.Name, nil
+	}
+	return "", nil
+}
+
+// TaskURL returns the browser link of an issue.
+func (c *JiraClient) TaskURL(issueKey string) string {
+	return c.baseUrl + "/browse/" + issueKey
+}
+
+// CreateComment adds a plain-text comment to an issue.
+func (c *JiraClient) CreateComment(ctx context.Context, issueKey, text string) error {
+	if err := c.do(ctx, "POST", "/rest/api/3/issue/"+issueKey+"/comment", CreateCommentRequest{Body: converter.TextToADF(text)}, nil); err != nil {
+		return fmt.Errorf("create comment (jira): %w", err)
+	}
+	return nil
+}
+
+// DeleteTask deletes an issue with its sub-tasks. A 404 is treated as success so that
+// rollbacks can be retried after partial failures.
+func (c *JiraClient) DeleteTask(ctx context.Context, issueKey string) error {
+	err := c.do(ctx, "DELETE", "/rest/api/3/issue/"+issueKey+"?deleteSubtasks=true", nil, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("delete issue (jira): %w", err)
+	}
+	return nil
+}
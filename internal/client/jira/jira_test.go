@@ -0,0 +1,369 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/converter"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// fakeJira is an in-memory stand-in for the parts of the Jira Cloud REST API the client uses.
+type fakeJira struct {
+	mu          sync.Mutex
+	users       []JiraUser
+	issues      []JiraIssue
+	priorities  []JiraPriority
+	transitions []JiraTransition
+
+	userRequests int
+	created      []map[string]json.RawMessage
+	transitioned []string
+	watchers     []string
+	deleted      []string
+	credentials  []string
+}
+
+const (
+	testEmail = "bot@example.com"
+	testToken = "secret-api-token"
+)
+
+func (f *fakeJira) start(t *testing.T) *JiraClient {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/api/3/users/search", func(w http.ResponseWriter, r *http.Request) {
+		f.userRequests++
+		if r.URL.Query().Get("startAt") != "0" {
+			writeTestJSON(w, []JiraUser{})
+			return
+		}
+		writeTestJSON(w, f.users)
+	})
+	mux.HandleFunc("GET /rest/api/3/project/{key}", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, JiraProject{Id: "10000", Key: r.PathValue("key"), Name: "Platform", IssueTypes: []JiraIssueType{
+			{Id: "1", Name: "Story"},
+			{Id: "2", Name: "Task"},
+			{Id: "3", Name: "Sub-task", Subtask: true},
+			{Id: "4", Name: "Epic", HierarchyLevel: 1},
+		}})
+	})
+	mux.HandleFunc("GET /rest/api/3/priority/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("projectId") != "10000" {
+			http.Error(w, "unknown project", http.StatusBadRequest)
+			return
+		}
+		// One priority per page, to follow the pagination.
+		switch r.URL.Query().Get("startAt") {
+		case "0":
+			writeTestJSON(w, GetPrioritiesResponse{Values: f.priorities[:1]})
+		default:
+			writeTestJSON(w, GetPrioritiesResponse{Values: f.priorities[1:], IsLast: true})
+		}
+	})
+	mux.HandleFunc("GET /rest/api/3/field", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, []JiraField{
+			{Id: "customfield_10020", Name: "Sprint", Custom: true, Schema: JiraFieldSchema{Type: "array", Custom: sprintFieldType}},
+			{Id: "customfield_10015", Name: "Start date", Custom: true, Schema: JiraFieldSchema{Type: "date"}},
+		})
+	})
+	mux.HandleFunc("GET /rest/agile/1.0/board", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, GetBoardsResponse{Values: []JiraBoard{{Id: 7, Name: "PLAT board"}}, IsLast: true})
+	})
+	mux.HandleFunc("GET /rest/agile/1.0/board/{id}/sprint", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, GetSprintsResponse{Values: []JiraSprint{{Id: 31, Name: "Sprint 1"}, {Id: 32, Name: "Sprint 2"}}, IsLast: true})
+	})
+	mux.HandleFunc("POST /rest/api/3/search/jql", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, SearchResponse{Issues: f.issues, IsLast: true})
+	})
+	mux.HandleFunc("POST /rest/api/3/issue", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Fields map[string]json.RawMessage `json:"fields"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.created = append(f.created, req.Fields)
+		writeTestJSON(w, CreateIssueResponse{Id: "10101", Key: "PLAT-101"})
+	})
+	mux.HandleFunc("GET /rest/api/3/issue/{key}/transitions", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, GetTransitionsResponse{Transitions: f.transitions})
+	})
+	mux.HandleFunc("POST /rest/api/3/issue/{key}/transitions", func(w http.ResponseWriter, r *http.Request) {
+		var req TransitionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.transitioned = append(f.transitioned, r.PathValue("key")+"→"+req.Transition.Id)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /rest/api/3/issue/{key}/watchers", func(w http.ResponseWriter, r *http.Request) {
+		var accountID string
+		if err := json.NewDecoder(r.Body).Decode(&accountID); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if accountID == "no-browse" {
+			w.WriteHeader(http.StatusBadRequest)
+			writeTestJSON(w, JiraErrors{ErrorMessages: []string{"The user does not have permission to view this issue."}})
+			return
+		}
+		f.watchers = append(f.watchers, accountID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /rest/api/3/issue/{key}", func(w http.ResponseWriter, r *http.Request) {
+		f.deleted = append(f.deleted, r.PathValue("key"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		user, pass, _ := r.BasicAuth()
+		f.credentials = append(f.credentials, user+":"+pass)
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewJiraClient(srv.URL+"/", testEmail, testToken)
+	c.httpClient = srv.Client()
+	return c
+}
+
+func writeTestJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v) //nolint:errcheck // test server
+}
+
+// issue builds a search result from its fields.
+func issue(t *testing.T, key string, fields map[string]any) JiraIssue {
+	t.Helper()
+	b, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatalf("marshal fields of %s: %v", key, err)
+	}
+	return JiraIssue{Key: key, Fields: b}
+}
+
+func TestGetMembersKeepsActiveAccountsWithEmails(t *testing.T) {
+	f := &fakeJira{users: []JiraUser{
+		{AccountId: "a1", AccountType: "atlassian", DisplayName: "Ann Lee", EmailAddress: "ann@example.com", Active: true},
+		{AccountId: "a2", AccountType: "atlassian", DisplayName: "Hidden Email", Active: true},
+		{AccountId: "a3", AccountType: "atlassian", DisplayName: "Former", EmailAddress: "former@example.com"},
+		{AccountId: "app", AccountType: "app", DisplayName: "Automation for Jira", Active: true},
+		{AccountId: "cust", AccountType: "customer", DisplayName: "Customer", EmailAddress: "c@example.org", Active: true},
+	}}
+	c := f.start(t)
+
+	members, err := c.GetMembers(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetMembers: %v", err)
+	}
+	want := []models.Member{
+		{ID: "a1", Name: "Ann Lee", Email: "ann@example.com"},
+		{ID: "a2", Name: "Hidden Email"},
+	}
+	if !reflect.DeepEqual(members, want) {
+		t.Errorf("members = %+v, want %+v", members, want)
+	}
+
+	if _, err := c.GetMembers(context.Background(), ""); err != nil {
+		t.Fatalf("GetMembers (cached): %v", err)
+	}
+	if f.userRequests != 1 {
+		t.Errorf("user search requests = %d, want 1 (the second call is served from the cache)", f.userRequests)
+	}
+	for _, cred := range f.credentials {
+		if cred != testEmail+":"+testToken {
+			t.Errorf("basic auth = %q, want the account e-mail and API token", cred)
+		}
+	}
+}
+
+func TestGetTasksReadsIssues(t *testing.T) {
+	f := &fakeJira{issues: []JiraIssue{
+		issue(t, "PLAT-1", map[string]any{
+			"summary":           "Ship it",
+			"description":       converter.MarkdownToADF("Do **this** first"),
+			"status":            JiraStatus{Name: "Done", StatusCategory: JiraStatusCategory{Key: "done"}},
+			"priority":          JiraPriority{Id: "2", Name: "High"},
+			"assignee":          JiraUser{AccountId: "a1", DisplayName: "Ann Lee", EmailAddress: "ann@example.com"},
+			"duedate":           "2024-06-01",
+			"customfield_10015": "2024-05-20",
+			"issuetype":         JiraIssueType{Id: "2", Name: "Task"},
+			"labels":            []string{"backend", "q3"},
+			"customfield_10020": []JiraSprint{{Id: 31}, {Id: 32}},
+		}),
+		issue(t, "PLAT-2", map[string]any{
+			"summary": "Later",
+			"status":  JiraStatus{Name: "In Progress", StatusCategory: JiraStatusCategory{Key: "indeterminate"}},
+		}),
+	}}
+	c := f.start(t)
+
+	tasks, err := c.GetTasks(context.Background(), "PLAT")
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("got %d tasks, want 2", len(tasks))
+	}
+
+	got := tasks[0]
+	if got.Id != "PLAT-1" || got.Name != "Ship it" || got.Status != "Done" || !got.Completed || got.Priority != "High" {
+		t.Errorf("task = %+v, want PLAT-1 \"Ship it\", completed in Done with High priority", got)
+	}
+	if got.RichDescription != "Do **this** first" || got.Description != "Do this first" {
+		t.Errorf("description = %q / %q, want the markdown and plain text of the ADF", got.RichDescription, got.Description)
+	}
+	if want := []models.TaskAssignee{{ID: "a1", Name: "Ann Lee", Email: "ann@example.com"}}; !reflect.DeepEqual(got.Assignees, want) {
+		t.Errorf("assignees = %+v, want %+v", got.Assignees, want)
+	}
+	if !reflect.DeepEqual(got.Tags, []string{"backend", "q3"}) {
+		t.Errorf("tags = %v, want [backend q3]", got.Tags)
+	}
+	if got.DueDate == nil || got.DueDate.Format(time.DateOnly) != "2024-06-01" {
+		t.Errorf("due date = %v, want 2024-06-01", got.DueDate)
+	}
+	if got.StartDate == nil || got.StartDate.Format(time.DateOnly) != "2024-05-20" {
+		t.Errorf("start date = %v, want 2024-05-20", got.StartDate)
+	}
+	// Issue types exclude sub-tasks and epics (Story=0, Task=1); the current sprint is the last one.
+	wantFields := []models.TaskCustomField{
+		{FieldID: issueTypeFieldID, Value: float64(1)},
+		{FieldID: "customfield_10020", Value: float64(1)},
+	}
+	if !reflect.DeepEqual(got.CustomFields, wantFields) {
+		t.Errorf("custom fields = %+v, want %+v", got.CustomFields, wantFields)
+	}
+
+	if later := tasks[1]; later.Completed || later.Assignees != nil || later.RichDescription != "" {
+		t.Errorf("second task = %+v, want an open task without assignee or description", later)
+	}
+}
+
+func TestGetProjectCustomFieldOptionsReturnsPriorities(t *testing.T) {
+	f := &fakeJira{priorities: []JiraPriority{{Id: "1", Name: "Highest"}, {Id: "3", Name: "Medium"}, {Id: "5", Name: "Lowest"}}}
+	c := f.start(t)
+
+	options, err := c.GetProjectCustomFieldOptions(context.Background(), "PLAT|PLAT-7")
+	if err != nil {
+		t.Fatalf("GetProjectCustomFieldOptions: %v", err)
+	}
+	want := map[string]string{"__field_gid__": "priority", "Highest": "1", "Medium": "3", "Lowest": "5"}
+	if !reflect.DeepEqual(options, want) {
+		t.Errorf("options = %v, want %v", options, want)
+	}
+}
+
+func TestCreateTaskWritesFieldsAndTransitions(t *testing.T) {
+	f := &fakeJira{transitions: []JiraTransition{
+		{Id: "11", To: JiraStatus{Name: "To Do"}},
+		{Id: "21", To: JiraStatus{Name: "In Progress"}},
+	}}
+	c := f.start(t)
+
+	due := time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC)
+	created, err := c.CreateTask(context.Background(), "PLAT|PLAT-7", "", models.Task{
+		Name:            "Ship it",
+		RichDescription: "Do **this** first",
+		Status:          "in progress",
+		Priority:        "priority:2",
+		DueDate:         &due,
+		TimeZone:        "UTC",
+		Tags:            []string{"needs review", "q3"},
+		Assignees:       []models.TaskAssignee{{ID: "a1"}, {ID: "a2"}},
+		Followers:       []models.TaskAssignee{{ID: "no-browse"}, {ID: "a3"}},
+		CustomFields:    []models.TaskCustomField{{FieldID: "customfield_10020", Value: "32"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if created.Id != "PLAT-101" || created.Status != "In Progress" {
+		t.Errorf("created = %+v, want PLAT-101 in In Progress", created)
+	}
+
+	fields := f.created[0]
+	wantFields := map[string]string{
+		"project":           `{"key":"PLAT"}`,
+		"summary":           `"Ship it"`,
+		"issuetype":         `{"id":"2"}`,
+		"parent":            `{"key":"PLAT-7"}`,
+		"assignee":          `{"accountId":"a1"}`,
+		"priority":          `{"id":"2"}`,
+		"duedate":           `"2024-06-01"`,
+		"labels":            `["needs_review","q3"]`,
+		"customfield_10020": `32`,
+	}
+	for name, want := range wantFields {
+		if got := string(fields[name]); got != want {
+			t.Errorf("field %s = %s, want %s", name, got, want)
+		}
+	}
+	var description converter.ADFNode
+	if err := json.Unmarshal(fields["description"], &description); err != nil {
+		t.Fatalf("description is not ADF: %v", err)
+	}
+	if got := converter.ADFToMarkdown(&description); got != "Do **this** first" {
+		t.Errorf("description = %q, want the markdown converted to ADF", got)
+	}
+
+	if !reflect.DeepEqual(f.transitioned, []string{"PLAT-101→21"}) {
+		t.Errorf("transitions = %v, want PLAT-101 moved through transition 21", f.transitioned)
+	}
+	// The follower Jira refuses is skipped without failing the issue.
+	if !reflect.DeepEqual(f.watchers, []string{"a2", "a3"}) {
+		t.Errorf("watchers = %v, want [a2 a3]", f.watchers)
+	}
+	if len(f.deleted) != 0 {
+		t.Errorf("deleted = %v, want the issue kept", f.deleted)
+	}
+}
+
+func TestCreateTaskWithoutPriorityMappingOmitsPriority(t *testing.T) {
+	f := &fakeJira{}
+	c := f.start(t)
+
+	if _, err := c.CreateTask(context.Background(), "PLAT", "", models.Task{Name: "Plain", Priority: "High", Description: "text"}); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	fields := f.created[0]
+	if _, ok := fields["priority"]; ok {
+		t.Errorf("priority = %s, want none for an unmapped priority name", fields["priority"])
+	}
+	if _, ok := fields["parent"]; ok {
+		t.Errorf("parent = %s, want none outside an epic", fields["parent"])
+	}
+	if got := string(fields["description"]); !strings.Contains(got, `"text":"text"`) {
+		t.Errorf("description = %s, want the plain text as ADF", got)
+	}
+}
+
+func TestAPIErrorsDoNotLeakCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		writeTestJSON(w, JiraErrors{ErrorMessages: []string{"Client must be authenticated to access this resource."}})
+	}))
+	t.Cleanup(srv.Close)
+	c := NewJiraClient(srv.URL, testEmail, testToken)
+	c.httpClient = srv.Client()
+
+	_, err := c.GetMembers(context.Background(), "")
+	if err == nil {
+		t.Fatal("GetMembers succeeded with rejected credentials")
+	}
+	if !strings.Contains(err.Error(), "Client must be authenticated") {
+		t.Errorf("error = %q, want Jira's message", err)
+	}
+	if strings.Contains(err.Error(), testToken) {
+		t.Errorf("error = %q leaks the API token", err)
+	}
+}
@@ -0,0 +1,179 @@
+package jira
+
+import (
+	"encoding/json"
+
+	"github.com/TWRT/integration-mapper/internal/converter"
+)
+
+type JiraErrors struct {
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+type JiraServerInfo struct {
+	BaseUrl     string `json:"baseUrl"`
+	ServerTitle string `json:"serverTitle"`
+}
+
+type JiraProject struct {
+	Id         string          `json:"id"`
+	Key        string          `json:"key"`
+	Name       string          `json:"name"`
+	IssueTypes []JiraIssueType `json:"issueTypes"`
+}
+
+type GetProjectsResponse struct {
+	Values []JiraProject `json:"values"`
+	IsLast bool          `json:"isLast"`
+}
+
+type JiraUser struct {
+	AccountId    string `json:"accountId"`
+	AccountType  string `json:"accountType"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+	Active       bool   `json:"active"`
+}
+
+type JiraIssueType struct {
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	Subtask        bool   `json:"subtask"`
+	HierarchyLevel int    `json:"hierarchyLevel"`
+}
+
+type JiraStatusCategory struct {
+	Key string `json:"key"` // new, indeterminate, done
+}
+
+type JiraStatus struct {
+	Id             string             `json:"id"`
+	Name           string             `json:"name"`
+	StatusCategory JiraStatusCategory `json:"statusCategory"`
+}
+
+// JiraProjectStatuses lists the workflow statuses of one issue type of a project.
+type JiraProjectStatuses struct {
+	Id       string       `json:"id"`
+	Name     string       `json:"name"`
+	Subtask  bool         `json:"subtask"`
+	Statuses []JiraStatus `json:"statuses"`
+}
+
+type JiraPriority struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type GetPrioritiesResponse struct {
+	Values []JiraPriority `json:"values"`
+	IsLast bool           `json:"isLast"`
+}
+
+type JiraFieldSchema struct {
+	Type   string `json:"type"`
+	Custom string `json:"custom"`
+}
+
+type JiraField struct {
+	Id     string          `json:"id"`
+	Name   string          `json:"name"`
+	Custom bool            `json:"custom"`
+	Schema JiraFieldSchema `json:"schema"`
+}
+
+type JiraBoard struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type GetBoardsResponse struct {
+	Values []JiraBoard `json:"values"`
+	IsLast bool        `json:"isLast"`
+}
+
+type JiraSprint struct {
+	Id    int    `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"` // future, active, closed
+}
+
+type GetSprintsResponse struct {
+	Values []JiraSprint `json:"values"`
+	IsLast bool         `json:"isLast"`
+}
+
+type JiraParent struct {
+	Key string `json:"key"`
+}
+
+// JiraIssueFields holds the standard fields of an issue. Custom fields (sprint, start date)
+// are read from the raw field map.
+type JiraIssueFields struct {
+	Summary     string             `json:"summary"`
+	Description *converter.ADFNode `json:"description"`
+	Status      *JiraStatus        `json:"status"`
+	Priority    *JiraPriority      `json:"priority"`
+	Assignee    *JiraUser          `json:"assignee"`
+	DueDate     string             `json:"duedate"`
+	IssueType   *JiraIssueType     `json:"issuetype"`
+	Labels      []string           `json:"labels"`
+	Parent      *JiraParent        `json:"parent"`
+}
+
+// JiraIssue keeps its fields raw: they are decoded once into JiraIssueFields and once into a
+// map for the custom fields whose IDs are only known at runtime.
+type JiraIssue struct {
+	Id     string          `json:"id"`
+	Key    string          `json:"key"`
+	Fields json.RawMessage `json:"fields"`
+}
+
+type SearchRequest struct {
+	JQL           string   `json:"jql"`
+	Fields        []string `json:"fields"`
+	MaxResults    int      `json:"maxResults"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+}
+
+type SearchResponse struct {
+	Issues        []JiraIssue `json:"issues"`
+	NextPageToken string      `json:"nextPageToken"`
+	IsLast        bool        `json:"isLast"`
+}
+
+type CreateIssueRequest struct {
+	Fields map[string]any `json:"fields"`
+}
+
+type CreateIssueResponse struct {
+	Id  string `json:"id"`
+	Key string `json:"key"`
+}
+
+type JiraTransition struct {
+	Id string     `json:"id"`
+	To JiraStatus `json:"to"`
+}
+
+type GetTransitionsResponse struct {
+	Transitions []JiraTransition `json:"transitions"`
+}
+
+type TransitionRef struct {
+	Id string `json:"id"`
+}
+
+type TransitionRequest struct {
+	Transition TransitionRef `json:"transition"`
+}
+
+type CreateCommentRequest struct {
+	Body *converter.ADFNode `json:"body"`
+}
+
+type GetLabelsResponse struct {
+	Values []string `json:"values"`
+	IsLast bool     `json:"isLast"`
+}
@@ -0,0 +1,22 @@
+package linear
+
+import (
+	"github.com/TWRT/integration-mapper/internal/client"
+)
+
+// Name is the provider name migrations use for Linear.
+const Name = "linear"
+
+// Descriptor registers the Linear client. The destination team is the workspace; its
+// projects are the destination containers and the list, if set, is the default project.
+func Descriptor(c *LinearClient) client.Descriptor {
+	return client.Descriptor{
+		Name:        Name,
+		DisplayName: "Linear",
+		Client:      c,
+		ContainerScope: func(dest client.Destination) string {
+			return dest.WorkspaceID
+		},
+		DefaultPriorities: Priorities,
+	}
+}
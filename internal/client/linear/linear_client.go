@@ -0,0 +1,753 @@
+package linear
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/converter"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// Field IDs under which issue attributes without a native counterpart in the other tools are
+// exposed, so they can be mapped like custom fields.
+const (
+	estimateFieldID = "estimate"
+	cycleFieldID    = "cycle"
+)
+
+// priorityNames are Linear's fixed priority levels; 0 means "No priority".
+var priorityNames = map[int]string{
+	1: "Urgent",
+	2: "High",
+	3: "Medium",
+	4: "Low",
+}
+
+// Priorities lists the priority names in descending order.
+var Priorities = []string{"Urgent", "High", "Medium", "Low"}
+
+func priorityValue(name string) *int {
+	for v, n := range priorityNames {
+		if strings.EqualFold(n, name) {
+			return &v
+		}
+	}
+	return nil
+}
+
+type LinearClient struct {
+	endpoint   string
+	apiKey     string
+	httpClient *http.Client
+
+	cacheMu     sync.RWMutex
+	teamOf      map[string]string                // team or project ID → team ID
+	stateCache  map[string][]LinearWorkflowState // teamId → workflow states
+	labelCache  map[string][]LinearLabel         // teamId → team and workspace labels
+	memberCache map[string][]models.Member       // teamId → members
+	urls        map[string]string                // issue ID → browser link
+}
+
+func NewLinearClient(apiKey string) *LinearClient {
+	return NewLinearClientWithEndpoint("https://api.linear.app/graphql", apiKey)
+}
+
+// NewLinearClientWithEndpoint returns a client for a Linear-compatible GraphQL endpoint,
+// e.g. a local stand-in of the API.
+func NewLinearClientWithEndpoint(endpoint, apiKey string) *LinearClient {
+	return &LinearClient{
+		endpoint:    endpoint,
+		apiKey:      apiKey,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		teamOf:      make(map[string]string),
+		stateCache:  make(map[string][]LinearWorkflowState),
+		labelCache:  make(map[string][]LinearLabel),
+		memberCache: make(map[string][]models.Member),
+		urls:        make(map[string]string),
+	}
+}
+
+// apiError is returned for non-2xx responses and for GraphQL errors.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	if e.message != "" {
+		return fmt.Sprintf("Linear error: %s", e.message)
+	}
+	return fmt.Sprintf("API error status (linear): %d", e.status)
+}
+
+// query runs a GraphQL query or mutation and decodes its data into out (if not nil).
+func (c *LinearClient) query(ctx context.Context, q string, vars map[string]any, out any) error {
+	b, err := json.Marshal(GraphQLRequest{Query: q, Variables: vars})
+	if err != nil {
+		return fmt.Errorf("marshal request (linear): %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(b))
+	if err != nil {
+		return fmt.Errorf("build request (linear): %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s (linear): %w", c.endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response body (linear): %w", err)
+	}
+
+	// GraphQL errors come with 200 as well as 4xx statuses.
+	var gqlResp GraphQLResponse
+	parseErr := json.Unmarshal(respBody, &gqlResp)
+	if parseErr == nil && len(gqlResp.Errors) > 0 {
+		msgs := make([]string, len(gqlResp.Errors))
+		for i, e := range gqlResp.Errors {
+			msgs[i] = e.Message
+		}
+		return &apiError{status: resp.StatusCode, message: strings.Join(msgs, "; ")}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &apiError{status: resp.StatusCode}
+	}
+	if parseErr != nil {
+		return fmt.Errorf("parse response (linear): %w", parseErr)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(gqlResp.Data, out); err != nil {
+		return fmt.Errorf("parse response (linear): %w", err)
+	}
+	return nil
+}
+
+// queryAll follows a connection through all of its pages. The query takes an $after cursor
+// and returns the connection at path (e.g. "team.members").
+func queryAll[T any](ctx context.Context, c *LinearClient, q string, vars map[string]any, path string) ([]T, error) {
+	keys := strings.Split(path, ".")
+	args := make(map[string]any, len(vars)+1)
+	for k, v := range vars {
+		args[k] = v
+	}
+
+	var nodes []T
+	for {
+		var data map[string]json.RawMessage
+		if err := c.query(ctx, q, args, &data); err != nil {
+			return nil, err
+		}
+		raw := data[keys[0]]
+		for _, k := range keys[1:] {
+			var inner map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &inner); err != nil {
+				return nil, fmt.Errorf("parse response (linear): %w", err)
+			}
+			raw = inner[k]
+		}
+		if len(raw) == 0 || string(raw) == "null" {
+			return nil, &apiError{message: path + " not found"}
+		}
+
+		var page Connection[T]
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return nil, fmt.Errorf("parse response (linear): %w", err)
+		}
+		nodes = append(nodes, page.Nodes...)
+		if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == "" {
+			return nodes, nil
+		}
+		args["after"] = page.PageInfo.EndCursor
+	}
+}
+
+// ---- Teams and projects ----
+
+const teamsQuery = `query Teams($after: String) {
+  teams(first: 100, after: $after) { nodes { id key name } pageInfo { hasNextPage endCursor } }
+}`
+
+const teamProjectsQuery = `query TeamProjects($id: String!, $after: String) {
+  team(id: $id) { projects(first: 100, after: $after) { nodes { id name } pageInfo { hasNextPage endCursor } } }
+}`
+
+const projectTeamsQuery = `query ProjectTeams($id: String!) {
+  project(id: $id) { teams(first: 1) { nodes { id } } }
+}`
+
+func (c *LinearClient) GetTeams(ctx context.Context) ([]LinearTeam, error) {
+	teams, err := queryAll[LinearTeam](ctx, c, teamsQuery, nil, "teams")
+	if err != nil {
+		return nil, fmt.Errorf("get teams (linear): %w", err)
+	}
+	return teams, nil
+}
+
+// ListWorkspaces implements client.WorkspaceBrowser. Teams are the workspace level.
+func (c *LinearClient) ListWorkspaces(ctx context.Context) ([]client.Container, error) {
+	teams, err := c.GetTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]client.Container, len(teams))
+	for i, t := range teams {
+		result[i] = client.Container{ID: t.Id, Name: t.Name}
+	}
+	return result, nil
+}
+
+// ListProjects implements client.WorkspaceBrowser with the projects of a team.
+func (c *LinearClient) ListProjects(ctx context.Context, teamId string) ([]client.Container, error) {
+	return c.GetSourceContainers(ctx, teamId)
+}
+
+// GetSourceContainers returns the projects of a team.
+func (c *LinearClient) GetSourceContainers(ctx context.Context, teamId string) ([]client.Container, error) {
+	projects, err := queryAll[LinearProject](ctx, c, teamProjectsQuery, map[string]any{"id": teamId}, "team.projects")
+	if err != nil {
+		return nil, fmt.Errorf("get projects (linear): %w", err)
+	}
+
+	c.cacheMu.Lock()
+	c.teamOf[teamId] = teamId
+	for _, p := range projects {
+		if _, ok := c.teamOf[p.Id]; !ok {
+			c.teamOf[p.Id] = teamId
+		}
+	}
+	c.cacheMu.Unlock()
+
+	containers := make([]client.Container, len(projects))
+	for i, p := range projects {
+		containers[i] = client.Container{ID: p.Id, Name: p.Name}
+	}
+	return containers, nil
+}
+
+// GetDestContainers returns the projects of a team (used as destination containers).
+func (c *LinearClient) GetDestContainers(ctx context.Context, teamId string) ([]client.Container, error) {
+	return c.GetSourceContainers(ctx, teamId)
+}
+
+// teamOfID returns the team a team or project ID belongs to. A project shared by several
+// teams is attributed to its first one.
+func (c *LinearClient) teamOfID(ctx context.Context, id string) (string, error) {
+	c.cacheMu.RLock()
+	teamId, ok := c.teamOf[id]
+	c.cacheMu.RUnlock()
+	if ok {
+		return teamId, nil
+	}
+
+	var data struct {
+		Project *struct {
+			Teams Connection[LinearTeam] `json:"teams"`
+		} `json:"project"`
+	}
+	teamId = id
+	if err := c.query(ctx, projectTeamsQuery, map[string]any{"id": id}, &data); err == nil && data.Project != nil && len(data.Project.Teams.Nodes) > 0 {
+		teamId = data.Project.Teams.Nodes[0].Id
+	}
+	// An ID that is not a project is taken to be a team.
+
+	c.cacheMu.Lock()
+	c.teamOf[id] = teamId
+	c.cacheMu.Unlock()
+	return teamId, nil
+}
+
+// ---- Members ----
+
+const teamMembersQuery = `query TeamMembers($id: String!, $after: String) {
+  team(id: $id) { members(first: 100, after: $after) { nodes { id name email active } pageInfo { hasNextPage endCursor } } }
+}`
+
+// GetMembers returns the active members of a team.
+func (c *LinearClient) GetMembers(ctx context.Context, teamId string) ([]models.Member, error) {
+	c.cacheMu.RLock()
+	cached, ok := c.memberCache[teamId]
+	c.cacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	users, err := queryAll[LinearUser](ctx, c, teamMembersQuery, map[string]any{"id": teamId}, "team.members")
+	if err != nil {
+		return nil, fmt.Errorf("get members (linear): %w", err)
+	}
+	var members []models.Member
+	for _, u := range users {
+		if u.Active {
+			members = append(members, models.Member{ID: u.Id, Name: u.Name, Email: u.Email})
+		}
+	}
+
+	c.cacheMu.Lock()
+	c.memberCache[teamId] = members
+	c.cacheMu.Unlock()
+	return members, nil
+}
+
+// MaxAssignees reports that Linear issues have a single assignee.
+func (c *LinearClient) MaxAssignees() int {
+	return 1
+}
+
+// ---- Workflow states and labels ----
+
+const teamStatesQuery = `query TeamStates($id: String!, $after: String) {
+  team(id: $id) { states(first: 100, after: $after) { nodes { id name type position } pageInfo { hasNextPage endCursor } } }
+}`
+
+func (c *LinearClient) getStates(ctx context.Context, teamId string) ([]LinearWorkflowState, error) {
+	c.cacheMu.RLock()
+	cached, ok := c.stateCache[teamId]
+	c.cacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	states, err := queryAll[LinearWorkflowState](ctx, c, teamStatesQuery, map[string]any{"id": teamId}, "team.states")
+	if err != nil {
+		return nil, fmt.Errorf("get workflow states (linear): %w", err)
+	}
+	sort.SliceStable(states, func(i, j int) bool { return states[i].Position < states[j].Position })
+
+	c.cacheMu.Lock()
+	c.stateCache[teamId] = states
+	c.cacheMu.Unlock()
+	return states, nil
+}
+
+// GetListStatuses returns the workflow states of the team a team or project ID belongs to.
+func (c *LinearClient) GetListStatuses(ctx context.Context, id string) ([]string, error) {
+	teamId, err := c.teamOfID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	states, err := c.getStates(ctx, teamId)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(states))
+	for i, s := range states {
+		names[i] = s.Name
+	}
+	return names, nil
+}
+
+// stateFor picks the workflow state of a created issue: the state with the task's status
+// name, or for completed tasks without one the first completed state. "" leaves the team's
+// default state.
+func stateFor(states []LinearWorkflowState, task models.Task) string {
+	for _, s := range states {
+		if task.Status != "" && strings.EqualFold(s.Name, task.Status) {
+			return s.Id
+		}
+	}
+	if task.Completed {
+		for _, s := range states {
+			if s.Type == "completed" {
+				return s.Id
+			}
+		}
+	}
+	return ""
+}
+
+const labelsQuery = `query Labels($id: ID!, $after: String) {
+  issueLabels(first: 100, after: $after, filter: { or: [{ team: { id: { eq: $id } } }, { team: { null: true } }] }) {
+    nodes { id name } pageInfo { hasNextPage endCursor }
+  }
+}`
+
+const createLabelMutation = `mutation CreateLabel($input: IssueLabelCreateInput!) {
+  issueLabelCreate(input: $input) { success issueLabel { id name } }
+}`
+
+// getLabels returns the labels of a team together with the workspace-wide labels.
+func (c *LinearClient) getLabels(ctx context.Context, teamId string) ([]LinearLabel, error) {
+	c.cacheMu.RLock()
+	cached, ok := c.labelCache[teamId]
+	c.cacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	labels, err := queryAll[LinearLabel](ctx, c, labelsQuery, map[string]any{"id": teamId}, "issueLabels")
+	if err != nil {
+		return nil, fmt.Errorf("get labels (linear): %w", err)
+	}
+
+	c.cacheMu.Lock()
+	c.labelCache[teamId] = labels
+	c.cacheMu.Unlock()
+	return labels, nil
+}
+
+// ListTags returns the label names available to a team.
+func (c *LinearClient) ListTags(ctx context.Context, teamId string) ([]string, error) {
+	labels, err := c.getLabels(ctx, teamId)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = l.Name
+	}
+	return names, nil
+}
+
+// resolveLabels returns the IDs of the labels with the given names, creating the missing
+// ones on the team.
+func (c *LinearClient) resolveLabels(ctx context.Context, teamId string, names []string) ([]string, error) {
+	labels, err := c.getLabels(ctx, teamId)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]string, len(labels))
+	for _, l := range labels {
+		if _, dup := byName[strings.ToLower(l.Name)]; !dup {
+			byName[strings.ToLower(l.Name)] = l.Id
+		}
+	}
+
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		if id, ok := byName[strings.ToLower(name)]; ok {
+			ids = append(ids, id)
+			continue
+		}
+		var data struct {
+			IssueLabelCreate struct {
+				Success    bool        `json:"success"`
+				IssueLabel LinearLabel `json:"issueLabel"`
+			} `json:"issueLabelCreate"`
+		}
+		input := map[string]any{"name": name, "teamId": teamId}
+		if err := c.query(ctx, createLabelMutation, map[string]any{"input": input}, &data); err != nil {
+			return nil, fmt.Errorf("create label %q (linear): %w", name, err)
+		}
+		created := data.IssueLabelCreate.IssueLabel
+		byName[strings.ToLower(name)] = created.Id
+		ids = append(ids, created.Id)
+
+		c.cacheMu.Lock()
+		c.labelCache[teamId] = append(c.labelCache[teamId], created)
+		c.cacheMu.Unlock()
+	}
+	return ids, nil
+}
+
+// ---- Fields: estimates and cycles ----
+
+const teamCyclesQuery = `query TeamCycles($id: String!, $after: String) {
+  team(id: $id) { cycles(first: 100, after: $after) { nodes { id number name } pageInfo { hasNextPage endCursor } } }
+}`
+
+func cycleName(cy LinearCycle) string {
+	if cy.Name != "" {
+		return cy.Name
+	}
+	return fmt.Sprintf("Cycle %d", cy.Number)
+}
+
+// teamFieldDefinitions describes the estimate and the cycle of a team's issues as a number
+// and a dropdown field.
+func (c *LinearClient) teamFieldDefinitions(ctx context.Context, teamId string) ([]models.CustomFieldDefinition, error) {
+	cycles, err := queryAll[LinearCycle](ctx, c, teamCyclesQuery, map[string]any{"id": teamId}, "team.cycles")
+	if err != nil {
+		return nil, fmt.Errorf("get cycles (linear): %w", err)
+	}
+	sort.SliceStable(cycles, func(i, j int) bool { return cycles[i].Number < cycles[j].Number })
+
+	defs := []models.CustomFieldDefinition{{ID: estimateFieldID, Name: "Estimate", ClickUpType: "number"}}
+	if len(cycles) > 0 {
+		cycleDef := models.CustomFieldDefinition{ID: cycleFieldID, Name: "Cycle", ClickUpType: "drop_down"}
+		for i, cy := range cycles {
+			cycleDef.Options = append(cycleDef.Options, models.CustomFieldOption{ID: cy.Id, Name: cycleName(cy), OrderIndex: i})
+		}
+		defs = append(defs, cycleDef)
+	}
+	return defs, nil
+}
+
+// GetFieldDefinitions returns the estimate and cycle fields of the team a team or project ID belongs to.
+func (c *LinearClient) GetFieldDefinitions(ctx context.Context, id string) ([]models.CustomFieldDefinition, error) {
+	teamId, err := c.teamOfID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return c.teamFieldDefinitions(ctx, teamId)
+}
+
+// GetDestFieldDefinitions returns the estimate and cycle fields of a destination team.
+func (c *LinearClient) GetDestFieldDefinitions(ctx context.Context, teamId string) ([]models.CustomFieldDefinition, error) {
+	return c.GetFieldDefinitions(ctx, teamId)
+}
+
+// ---- Issues ----
+
+const issueFields = `id identifier title description url priority estimate dueDate
+  state { id name type position }
+  assignee { id name email }
+  subscribers { nodes { id name email } }
+  labels { nodes { id name } }
+  cycle { id number name }`
+
+// Sub-issues are left out, like sub-tasks for the other providers.
+const teamIssuesQuery = `query TeamIssues($id: ID!, $after: String) {
+  issues(first: 100, after: $after, filter: { team: { id: { eq: $id } }, parent: { null: true } }) {
+    nodes { ` + issueFields + ` } pageInfo { hasNextPage endCursor }
+  }
+}`
+
+const projectIssuesQuery = `query ProjectIssues($id: ID!, $after: String) {
+  issues(first: 100, after: $after, filter: { project: { id: { eq: $id } }, parent: { null: true } }) {
+    nodes { ` + issueFields + ` } pageInfo { hasNextPage endCursor }
+  }
+}`
+
+// GetTasks returns the issues of a team.
+func (c *LinearClient) GetTasks(ctx context.Context, teamId string) ([]models.Task, error) {
+	return c.getIssuesAsTasks(ctx, teamId, teamIssuesQuery, teamId)
+}
+
+// GetTasksByContainer returns the issues of a project.
+func (c *LinearClient) GetTasksByContainer(ctx context.Context, projectId string) ([]models.Task, error) {
+	return c.getIssuesAsTasks(ctx, projectId, projectIssuesQuery, projectId)
+}
+
+func (c *LinearClient) getIssuesAsTasks(ctx context.Context, id, q, filterID string) ([]models.Task, error) {
+	defs, err := c.GetFieldDefinitions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := queryAll[LinearIssue](ctx, c, q, map[string]any{"id": filterID}, "issues")
+	if err != nil {
+		return nil, fmt.Errorf("get issues (linear): %w", err)
+	}
+
+	loc := client.LocationFromContext(ctx)
+	tasks := make([]models.Task, 0, len(issues))
+	for _, issue := range issues {
+		task, err := c.parseIssue(issue, defs, loc)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func optionIndex(defs []models.CustomFieldDefinition, fieldID, optionID string) (float64, bool) {
+	for _, d := range defs {
+		if d.ID != fieldID {
+			continue
+		}
+		for _, o := range d.Options {
+			if o.ID == optionID {
+				return float64(o.OrderIndex), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (c *LinearClient) parseIssue(issue LinearIssue, defs []models.CustomFieldDefinition, loc *time.Location) (models.Task, error) {
+	task := models.Task{
+		Id:              issue.Id,
+		Name:            issue.Title,
+		Description:     issue.Description,
+		RichDescription: issue.Description,
+		Priority:        priorityNames[issue.Priority],
+		TimeZone:        loc.String(),
+	}
+	if issue.State != nil {
+		task.Status = issue.State.Name
+		task.Completed = issue.State.Type == "completed"
+	}
+	if issue.Assignee != nil {
+		task.Assignees = []models.TaskAssignee{{ID: issue.Assignee.Id, Name: issue.Assignee.Name, Email: issue.Assignee.Email}}
+	}
+	for _, s := range issue.Subscribers.Nodes {
+		if issue.Assignee != nil && s.Id == issue.Assignee.Id {
+			continue
+		}
+		task.Followers = append(task.Followers, models.TaskAssignee{ID: s.Id, Name: s.Name, Email: s.Email})
+	}
+	for _, l := range issue.Labels.Nodes {
+		task.Tags = append(task.Tags, l.Name)
+	}
+	if issue.DueDate != "" {
+		due, err := time.ParseInLocation("2006-01-02", issue.DueDate, loc)
+		if err != nil {
+			return models.Task{}, fmt.Errorf("parse due date of %s (linear): %w", issue.Identifier, err)
+		}
+		task.DueDate = &due
+	}
+	if issue.Estimate != nil {
+		task.CustomFields = append(task.CustomFields, models.TaskCustomField{FieldID: estimateFieldID, Value: *issue.Estimate})
+	}
+	if issue.Cycle != nil {
+		if idx, ok := optionIndex(defs, cycleFieldID, issue.Cycle.Id); ok {
+			task.CustomFields = append(task.CustomFields, models.TaskCustomField{FieldID: cycleFieldID, Value: idx})
+		}
+	}
+
+	if issue.Url != "" {
+		c.cacheMu.Lock()
+		c.urls[issue.Id] = issue.Url
+		c.cacheMu.Unlock()
+	}
+	return task, nil
+}
+
+const createIssueMutation = `mutation CreateIssue($input: IssueCreateInput!) {
+  issueCreate(input: $input) { success issue { id identifier title url state { id name type position } } }
+}`
+
+// CreateTask creates an issue in a team. id is a project of the team, or the team itself
+// (or "") to create the issue outside of any project. The first assignee becomes the issue's
+// assignee; the others and the followers subscribe to it.
+func (c *LinearClient) CreateTask(ctx context.Context, id string, teamId string, task models.Task) (*models.Task, error) {
+	if teamId == "" {
+		var err error
+		if teamId, err = c.teamOfID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	states, err := c.getStates(ctx, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := client.TaskLocation(ctx, task)
+	input := IssueCreateInput{
+		TeamId:      teamId,
+		Title:       task.Name,
+		Description: task.Description,
+		Priority:    priorityValue(task.Priority),
+		StateId:     stateFor(states, task),
+	}
+	if id != "" && id != teamId {
+		input.ProjectId = id
+	}
+	if task.RichDescription != "" {
+		input.Description = converter.PlainMentions(task.RichDescription)
+	}
+	if task.DueDate != nil {
+		input.DueDate = task.DueDate.In(loc).Format("2006-01-02")
+	}
+	if len(task.Assignees) > 0 {
+		input.AssigneeId = task.Assignees[0].ID
+		for _, a := range task.Assignees[1:] {
+			input.SubscriberIds = append(input.SubscriberIds, a.ID)
+		}
+	}
+	for _, f := range task.Followers {
+		input.SubscriberIds = append(input.SubscriberIds, f.ID)
+	}
+	if len(task.Tags) > 0 {
+		if input.LabelIds, err = c.resolveLabels(ctx, teamId, task.Tags); err != nil {
+			return nil, err
+		}
+	}
+	for _, cf := range task.CustomFields {
+		switch cf.FieldID {
+		case estimateFieldID:
+			if n, ok := cf.Value.(float64); ok {
+				estimate := int(math.Round(n))
+				input.Estimate = &estimate
+			}
+		case cycleFieldID:
+			if s, ok := cf.Value.(string); ok {
+				input.CycleId = s
+			}
+		}
+	}
+
+	var data struct {
+		IssueCreate struct {
+			Success bool         `json:"success"`
+			Issue   *LinearIssue `json:"issue"`
+		} `json:"issueCreate"`
+	}
+	if err := c.query(ctx, createIssueMutation, map[string]any{"input": input}, &data); err != nil {
+		return nil, fmt.Errorf("create issue (linear): %w", err)
+	}
+	created := data.IssueCreate.Issue
+	if !data.IssueCreate.Success || created == nil {
+		return nil, errors.New("create issue (linear): not created")
+	}
+
+	c.cacheMu.Lock()
+	c.urls[created.Id] = created.Url
+	c.cacheMu.Unlock()
+
+	result := &models.Task{Id: created.Id, Name: created.Title}
+	if created.State != nil {
+		result.Status = created.State.Name
+		result.Completed = created.State.Type == "completed"
+	}
+	return result, nil
+}
+
+// TaskURL returns the browser link of an issue read or created by this client.
+func (c *LinearClient) TaskURL(issueId string) string {
+	c.cacheMu.RLock()
+	defer c.cacheMu.RUnlock()
+	if u, ok := c.urls[issueId]; ok {
+		return u
+	}
+	return "https://linear.app/issue/" + issueId
+}
+
+const createCommentMutation = `mutation CreateComment($input: CommentCreateInput!) {
+  commentCreate(input: $input) { success }
+}`
+
+// CreateComment adds a comment to an issue.
+func (c *LinearClient) CreateComment(ctx context.Context, issueId, text string) error {
+	input := map[string]any{"issueId": issueId, "body": text}
+	if err := c.query(ctx, createCommentMutation, map[string]any{"input": input}, nil); err != nil {
+		return fmt.Errorf("create comment (linear): %w", err)
+	}
+	return nil
+}
+
+const deleteIssueMutation = `mutation DeleteIssue($id: String!) {
+  issueDelete(id: $id) { success }
+}`
+
+// DeleteTask moves an issue to the trash. An issue that no longer exists is treated as
+// deleted so that rollbacks can be retried after partial failures.
+func (c *LinearClient) DeleteTask(ctx context.Context, issueId string) error {
+	err := c.query(ctx, deleteIssueMutation, map[string]any{"id": issueId}, nil)
+	var apiErr *apiError
+	if err != nil && !(errors.As(err, &apiErr) && strings.Contains(strings.ToLower(apiErr.message), "not found")) {
+		return fmt.Errorf("delete issue (linear): %w", err)
+	}
+	return nil
+}
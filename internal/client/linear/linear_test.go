@@ -0,0 +1,572 @@
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/converter"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// fakeLinear is a local stand-in for the Linear GraphQL API. It answers the operations the
+// client sends by their operation name and pages every connection pageSize nodes at a time.
+type fakeLinear struct {
+	mu       sync.Mutex
+	pageSize int
+
+	teams    []LinearTeam
+	projects map[string][]LinearProject // teamId → projects
+	members  map[string][]LinearUser
+	states   map[string][]LinearWorkflowState
+	cycles   map[string][]LinearCycle
+	labels   []fakeLabel
+	issues   []fakeIssue
+
+	createdLabels []map[string]any
+	createdIssues []IssueCreateInput
+	operations    []string
+}
+
+type fakeLabel struct {
+	LinearLabel
+	teamId string // "" for workspace labels
+}
+
+type fakeIssue struct {
+	LinearIssue
+	teamId    string
+	projectId string
+}
+
+var operationName = regexp.MustCompile(`^(?:query|mutation) (\w+)`)
+
+func newFakeLinear() *fakeLinear {
+	return &fakeLinear{
+		pageSize: 100,
+		projects: make(map[string][]LinearProject),
+		members:  make(map[string][]LinearUser),
+		states:   make(map[string][]LinearWorkflowState),
+		cycles:   make(map[string][]LinearCycle),
+	}
+}
+
+func (f *fakeLinear) start(t *testing.T) *LinearClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(srv.Close)
+	return NewLinearClientWithEndpoint(srv.URL, "lin_api_test")
+}
+
+func (f *fakeLinear) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Method != http.MethodPost || r.Header.Get("Authorization") != "lin_api_test" {
+		w.WriteHeader(http.StatusUnauthorized)
+		writeTestJSON(w, map[string]any{"errors": []GraphQLError{{Message: "Authentication required"}}})
+		return
+	}
+	var req struct {
+		Query     string          `json:"query"`
+		Variables json.RawMessage `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var vars struct {
+		Id    string         `json:"id"`
+		After string         `json:"after"`
+		Input map[string]any `json:"input"`
+	}
+	json.Unmarshal(req.Variables, &vars)
+
+	m := operationName.FindStringSubmatch(req.Query)
+	if m == nil {
+		graphQLError(w, "syntax error")
+		return
+	}
+	op := m[1]
+	f.operations = append(f.operations, op)
+
+	switch op {
+	case "Teams":
+		writeData(w, map[string]any{"teams": page(f, f.teams, vars.After)})
+	case "TeamProjects":
+		f.teamConnection(w, vars.Id, "projects", page(f, f.projects[vars.Id], vars.After))
+	case "TeamMembers":
+		f.teamConnection(w, vars.Id, "members", page(f, f.members[vars.Id], vars.After))
+	case "TeamStates":
+		f.teamConnection(w, vars.Id, "states", page(f, f.states[vars.Id], vars.After))
+	case "TeamCycles":
+		f.teamConnection(w, vars.Id, "cycles", page(f, f.cycles[vars.Id], vars.After))
+	case "ProjectTeams":
+		for teamId, projects := range f.projects {
+			for _, p := range projects {
+				if p.Id == vars.Id {
+					writeData(w, map[string]any{"project": map[string]any{"teams": map[string]any{"nodes": []LinearTeam{{Id: teamId}}}}})
+					return
+				}
+			}
+		}
+		graphQLError(w, "Entity not found: Project")
+	case "Labels":
+		var labels []LinearLabel
+		for _, l := range f.labels {
+			if l.teamId == "" || l.teamId == vars.Id {
+				labels = append(labels, l.LinearLabel)
+			}
+		}
+		writeData(w, map[string]any{"issueLabels": page(f, labels, vars.After)})
+	case "CreateLabel":
+		f.createdLabels = append(f.createdLabels, vars.Input)
+		label := LinearLabel{Id: "label-" + strconv.Itoa(len(f.labels)+1), Name: vars.Input["name"].(string)}
+		f.labels = append(f.labels, fakeLabel{LinearLabel: label, teamId: vars.Input["teamId"].(string)})
+		writeData(w, map[string]any{"issueLabelCreate": map[string]any{"success": true, "issueLabel": label}})
+	case "TeamIssues", "ProjectIssues":
+		var issues []LinearIssue
+		for _, is := range f.issues {
+			if (op == "TeamIssues" && is.teamId == vars.Id) || (op == "ProjectIssues" && is.projectId == vars.Id) {
+				issues = append(issues, is.LinearIssue)
+			}
+		}
+		writeData(w, map[string]any{"issues": page(f, issues, vars.After)})
+	case "CreateIssue":
+		var input IssueCreateInput
+		b, _ := json.Marshal(vars.Input)
+		json.Unmarshal(b, &input)
+		f.createdIssues = append(f.createdIssues, input)
+		issue := LinearIssue{Id: "issue-new", Identifier: "ENG-99", Title: input.Title, Url: "https://linear.app/acme/issue/ENG-99"}
+		for _, s := range f.states[input.TeamId] {
+			if s.Id == input.StateId {
+				issue.State = &s
+			}
+		}
+		writeData(w, map[string]any{"issueCreate": map[string]any{"success": true, "issue": issue}})
+	case "DeleteIssue":
+		for _, is := range f.issues {
+			if is.Id == vars.Id {
+				writeData(w, map[string]any{"issueDelete": map[string]any{"success": true}})
+				return
+			}
+		}
+		graphQLError(w, "Entity not found: Issue")
+	default:
+		graphQLError(w, "unknown operation "+op)
+	}
+}
+
+// teamConnection answers a team(id:) { <field>(...) } query; unknown teams are null.
+func (f *fakeLinear) teamConnection(w http.ResponseWriter, teamId, field string, conn any) {
+	for _, t := range f.teams {
+		if t.Id == teamId {
+			writeData(w, map[string]any{"team": map[string]any{field: conn}})
+			return
+		}
+	}
+	writeData(w, map[string]any{"team": nil})
+}
+
+// page returns the nodes after the cursor, which is the index of the last node returned.
+func page[T any](f *fakeLinear, nodes []T, after string) Connection[T] {
+	start := 0
+	if after != "" {
+		n, _ := strconv.Atoi(after)
+		start = n + 1
+	}
+	end := min(start+f.pageSize, len(nodes))
+	if start >= end {
+		return Connection[T]{Nodes: []T{}}
+	}
+	conn := Connection[T]{Nodes: nodes[start:end]}
+	if end < len(nodes) {
+		conn.PageInfo = PageInfo{HasNextPage: true, EndCursor: strconv.Itoa(end - 1)}
+	}
+	return conn
+}
+
+func writeData(w http.ResponseWriter, data any) {
+	writeTestJSON(w, map[string]any{"data": data})
+}
+
+func graphQLError(w http.ResponseWriter, message string) {
+	writeTestJSON(w, map[string]any{"data": nil, "errors": []GraphQLError{{Message: message}}})
+}
+
+func writeTestJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeLinear) count(op string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, o := range f.operations {
+		if o == op {
+			n++
+		}
+	}
+	return n
+}
+
+// newEngineeringTeam returns a fake with an ENG team that has projects, states, cycles and
+// labels, and an OPS team with only a label of its own.
+func newEngineeringTeam() *fakeLinear {
+	f := newFakeLinear()
+	f.teams = []LinearTeam{{Id: "team-eng", Key: "ENG", Name: "Engineering"}, {Id: "team-ops", Key: "OPS", Name: "Operations"}}
+	f.projects["team-eng"] = []LinearProject{{Id: "proj-api", Name: "API"}, {Id: "proj-web", Name: "Web"}}
+	f.states["team-eng"] = []LinearWorkflowState{
+		{Id: "st-done", Name: "Done", Type: "completed", Position: 3},
+		{Id: "st-todo", Name: "Todo", Type: "unstarted", Position: 1},
+		{Id: "st-doing", Name: "In Progress", Type: "started", Position: 2},
+		{Id: "st-backlog", Name: "Backlog", Type: "backlog", Position: 0},
+	}
+	f.cycles["team-eng"] = []LinearCycle{{Id: "cy-3", Number: 3}, {Id: "cy-1", Number: 1, Name: "Kickoff"}, {Id: "cy-2", Number: 2}}
+	f.labels = []fakeLabel{
+		{LinearLabel: LinearLabel{Id: "lb-bug", Name: "Bug"}, teamId: "team-eng"},
+		{LinearLabel: LinearLabel{Id: "lb-ops", Name: "Incident"}, teamId: "team-ops"},
+		{LinearLabel: LinearLabel{Id: "lb-sec", Name: "Security"}},
+	}
+	return f
+}
+
+func TestQueryAllFollowsPages(t *testing.T) {
+	fake := newFakeLinear()
+	fake.pageSize = 2
+	for i := 1; i <= 5; i++ {
+		fake.teams = append(fake.teams, LinearTeam{Id: "team-" + strconv.Itoa(i), Name: "Team " + strconv.Itoa(i)})
+	}
+	fake.members["team-1"] = []LinearUser{
+		{Id: "u1", Name: "Ann", Email: "ann@example.com", Active: true},
+		{Id: "u2", Name: "Former", Active: false},
+		{Id: "u3", Name: "Bob", Email: "bob@example.com", Active: true},
+	}
+	c := fake.start(t)
+	ctx := context.Background()
+
+	workspaces, err := c.ListWorkspaces(ctx)
+	if err != nil {
+		t.Fatalf("ListWorkspaces: %v", err)
+	}
+	if len(workspaces) != 5 || workspaces[0].ID != "team-1" || workspaces[4].Name != "Team 5" {
+		t.Errorf("workspaces = %v, want all 5 teams in order", workspaces)
+	}
+	if n := fake.count("Teams"); n != 3 {
+		t.Errorf("Teams queried %d times, want 3 pages", n)
+	}
+
+	members, err := c.GetMembers(ctx, "team-1")
+	if err != nil {
+		t.Fatalf("GetMembers: %v", err)
+	}
+	want := []models.Member{{ID: "u1", Name: "Ann", Email: "ann@example.com"}, {ID: "u3", Name: "Bob", Email: "bob@example.com"}}
+	if !reflect.DeepEqual(members, want) {
+		t.Errorf("members = %v, want the active members %v", members, want)
+	}
+
+	// A connection under a missing parent is an error, not an empty result.
+	if _, err := c.GetSourceContainers(ctx, "team-missing"); err == nil || !strings.Contains(err.Error(), "team.projects not found") {
+		t.Errorf("GetSourceContainers of an unknown team: err = %v", err)
+	}
+}
+
+func TestWorkflowStates(t *testing.T) {
+	fake := newEngineeringTeam()
+	c := fake.start(t)
+	ctx := context.Background()
+
+	want := []string{"Backlog", "Todo", "In Progress", "Done"}
+
+	// A team ID is not a project; it is taken to be the team.
+	statuses, err := c.GetListStatuses(ctx, "team-eng")
+	if err != nil {
+		t.Fatalf("GetListStatuses(team): %v", err)
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v ordered by position", statuses, want)
+	}
+
+	// A project resolves to its team and shares the team's cached states.
+	statuses, err = c.GetListStatuses(ctx, "proj-api")
+	if err != nil {
+		t.Fatalf("GetListStatuses(project): %v", err)
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+	if n := fake.count("TeamStates"); n != 1 {
+		t.Errorf("TeamStates queried %d times, want 1", n)
+	}
+
+	states, _ := c.getStates(ctx, "team-eng")
+	tests := []struct {
+		task models.Task
+		want string
+	}{
+		{models.Task{Status: "in progress"}, "st-doing"},
+		{models.Task{Status: "Closed", Completed: true}, "st-done"},
+		{models.Task{Status: "Closed"}, ""},
+		{models.Task{}, ""},
+	}
+	for _, tt := range tests {
+		if got := stateFor(states, tt.task); got != tt.want {
+			t.Errorf("stateFor(%q, completed=%v) = %q, want %q", tt.task.Status, tt.task.Completed, got, tt.want)
+		}
+	}
+}
+
+func TestPriorities(t *testing.T) {
+	if d := Descriptor(NewLinearClient("unused")); !reflect.DeepEqual(d.DefaultPriorities, []string{"Urgent", "High", "Medium", "Low"}) {
+		t.Errorf("DefaultPriorities = %v", d.DefaultPriorities)
+	}
+	for name, want := range map[string]int{"Urgent": 1, "high": 2, "MEDIUM": 3, "Low": 4} {
+		if got := priorityValue(name); got == nil || *got != want {
+			t.Errorf("priorityValue(%q) = %v, want %d", name, got, want)
+		}
+	}
+	for _, name := range []string{"", "No priority", "Critical"} {
+		if got := priorityValue(name); got != nil {
+			t.Errorf("priorityValue(%q) = %d, want nil", name, *got)
+		}
+	}
+
+	fake := newEngineeringTeam()
+	fake.issues = []fakeIssue{
+		{LinearIssue: LinearIssue{Id: "i0", Title: "None", Priority: 0}, teamId: "team-eng"},
+		{LinearIssue: LinearIssue{Id: "i1", Title: "Urgent", Priority: 1}, teamId: "team-eng"},
+		{LinearIssue: LinearIssue{Id: "i4", Title: "Low", Priority: 4}, teamId: "team-eng"},
+	}
+	c := fake.start(t)
+
+	tasks, err := c.GetTasks(context.Background(), "team-eng")
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task.Priority)
+	}
+	if want := []string{"", "Urgent", "Low"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("priorities = %q, want %q", got, want)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	fake := newEngineeringTeam()
+	c := fake.start(t)
+	ctx := context.Background()
+
+	tags, err := c.ListTags(ctx, "team-eng")
+	if err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	if want := []string{"Bug", "Security"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %v, want the team's and the workspace labels %v", tags, want)
+	}
+
+	ids, err := c.resolveLabels(ctx, "team-eng", []string{"bug", "SECURITY", "Regression"})
+	if err != nil {
+		t.Fatalf("resolveLabels: %v", err)
+	}
+	if want := []string{"lb-bug", "lb-sec", "label-4"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("label ids = %v, want %v", ids, want)
+	}
+	if want := []map[string]any{{"name": "Regression", "teamId": "team-eng"}}; !reflect.DeepEqual(fake.createdLabels, want) {
+		t.Errorf("created labels = %v, want %v", fake.createdLabels, want)
+	}
+
+	// The created label is cached; resolving it again creates nothing.
+	if ids, err = c.resolveLabels(ctx, "team-eng", []string{"regression"}); err != nil || !reflect.DeepEqual(ids, []string{"label-4"}) {
+		t.Errorf("resolveLabels again = %v, %v", ids, err)
+	}
+	if len(fake.createdLabels) != 1 || fake.count("Labels") != 1 {
+		t.Errorf("created %d labels with %d label queries, want 1 and 1", len(fake.createdLabels), fake.count("Labels"))
+	}
+}
+
+func TestEstimateAndCycleFields(t *testing.T) {
+	fake := newEngineeringTeam()
+	c := fake.start(t)
+
+	defs, err := c.GetFieldDefinitions(context.Background(), "proj-web")
+	if err != nil {
+		t.Fatalf("GetFieldDefinitions: %v", err)
+	}
+	want := []models.CustomFieldDefinition{
+		{ID: estimateFieldID, Name: "Estimate", ClickUpType: "number"},
+		{ID: cycleFieldID, Name: "Cycle", ClickUpType: "drop_down", Options: []models.CustomFieldOption{
+			{ID: "cy-1", Name: "Kickoff", OrderIndex: 0},
+			{ID: "cy-2", Name: "Cycle 2", OrderIndex: 1},
+			{ID: "cy-3", Name: "Cycle 3", OrderIndex: 2},
+		}},
+	}
+	if !reflect.DeepEqual(defs, want) {
+		t.Errorf("definitions = %+v, want %+v", defs, want)
+	}
+
+	// A team without cycles only has the estimate field.
+	defs, err = c.GetDestFieldDefinitions(context.Background(), "team-ops")
+	if err != nil {
+		t.Fatalf("GetDestFieldDefinitions: %v", err)
+	}
+	if len(defs) != 1 || defs[0].ID != estimateFieldID {
+		t.Errorf("definitions = %+v, want only the estimate", defs)
+	}
+}
+
+func TestGetTasksByContainerParsesIssues(t *testing.T) {
+	fake := newEngineeringTeam()
+	estimate := 5.0
+	ann := LinearUser{Id: "u-ann", Name: "Ann", Email: "ann@example.com"}
+	bob := LinearUser{Id: "u-bob", Name: "Bob", Email: "bob@example.com"}
+	issue := fakeIssue{LinearIssue: LinearIssue{
+		Id:          "issue-1",
+		Identifier:  "ENG-1",
+		Title:       "Fix login",
+		Description: "It **breaks**",
+		Url:         "https://linear.app/acme/issue/ENG-1",
+		Priority:    2,
+		Estimate:    &estimate,
+		DueDate:     "2024-05-31",
+		State:       &LinearWorkflowState{Id: "st-done", Name: "Done", Type: "completed"},
+		Assignee:    &ann,
+		Cycle:       &LinearCycle{Id: "cy-2", Number: 2},
+	}, teamId: "team-eng", projectId: "proj-api"}
+	issue.Subscribers.Nodes = []LinearUser{ann, bob}
+	issue.Labels.Nodes = []LinearLabel{{Id: "lb-bug", Name: "Bug"}}
+	fake.issues = []fakeIssue{issue, {LinearIssue: LinearIssue{Id: "issue-2", Title: "Other project"}, teamId: "team-eng", projectId: "proj-web"}}
+	c := fake.start(t)
+
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	tasks, err := c.GetTasksByContainer(client.WithLocation(context.Background(), loc), "proj-api")
+	if err != nil {
+		t.Fatalf("GetTasksByContainer: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("got %d tasks, want 1", len(tasks))
+	}
+	due := time.Date(2024, 5, 31, 0, 0, 0, 0, loc)
+	want := models.Task{
+		Id:              "issue-1",
+		Name:            "Fix login",
+		Description:     "It **breaks**",
+		RichDescription: "It **breaks**",
+		Status:          "Done",
+		Completed:       true,
+		Priority:        "High",
+		Assignees:       []models.TaskAssignee{{ID: "u-ann", Name: "Ann", Email: "ann@example.com"}},
+		Followers:       []models.TaskAssignee{{ID: "u-bob", Name: "Bob", Email: "bob@example.com"}},
+		Tags:            []string{"Bug"},
+		DueDate:         &due,
+		TimeZone:        "UTC+2",
+		CustomFields: []models.TaskCustomField{
+			{FieldID: estimateFieldID, Value: 5.0},
+			{FieldID: cycleFieldID, Value: float64(1)},
+		},
+	}
+	if !reflect.DeepEqual(tasks[0], want) {
+		t.Errorf("task = %+v\nwant   %+v", tasks[0], want)
+	}
+	if got := c.TaskURL("issue-1"); got != "https://linear.app/acme/issue/ENG-1" {
+		t.Errorf("TaskURL = %q", got)
+	}
+}
+
+func TestCreateTask(t *testing.T) {
+	fake := newEngineeringTeam()
+	c := fake.start(t)
+
+	due := time.Date(2024, 5, 31, 23, 0, 0, 0, time.UTC)
+	created, err := c.CreateTask(context.Background(), "proj-api", "", models.Task{
+		Name:            "Fix login",
+		RichDescription: "See " + converter.MentionLink("u-ann", "Ann"),
+		Status:          "In Progress",
+		Priority:        "Urgent",
+		DueDate:         &due,
+		TimeZone:        "Asia/Tokyo",
+		Assignees:       []models.TaskAssignee{{ID: "u-ann"}, {ID: "u-carl"}},
+		Followers:       []models.TaskAssignee{{ID: "u-bob"}},
+		Tags:            []string{"bug", "Regression"},
+		CustomFields: []models.TaskCustomField{
+			{FieldID: estimateFieldID, Value: 2.6},
+			{FieldID: cycleFieldID, Value: "cy-2"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if created.Id != "issue-new" || created.Status != "In Progress" || created.Completed {
+		t.Errorf("created = %+v", created)
+	}
+	if got := c.TaskURL("issue-new"); got != "https://linear.app/acme/issue/ENG-99" {
+		t.Errorf("TaskURL = %q", got)
+	}
+
+	if len(fake.createdIssues) != 1 {
+		t.Fatalf("created %d issues, want 1", len(fake.createdIssues))
+	}
+	input := fake.createdIssues[0]
+	urgent, estimate := 1, 3
+	want := IssueCreateInput{
+		TeamId:        "team-eng",
+		Title:         "Fix login",
+		Description:   "See @Ann",
+		Priority:      &urgent,
+		Estimate:      &estimate,
+		DueDate:       "2024-06-01", // 23:00 UTC is the next day in Tokyo
+		StateId:       "st-doing",
+		AssigneeId:    "u-ann",
+		SubscriberIds: []string{"u-carl", "u-bob"},
+		LabelIds:      []string{"lb-bug", "label-4"},
+		ProjectId:     "proj-api",
+		CycleId:       "cy-2",
+	}
+	if !reflect.DeepEqual(input, want) {
+		t.Errorf("input = %+v\nwant    %+v", input, want)
+	}
+
+	// Issues created on the team itself are not put in a project.
+	if _, err := c.CreateTask(context.Background(), "team-eng", "team-eng", models.Task{Name: "Loose", Completed: true}); err != nil {
+		t.Fatalf("CreateTask on team: %v", err)
+	}
+	loose := fake.createdIssues[1]
+	if loose.ProjectId != "" || loose.StateId != "st-done" || loose.Priority != nil {
+		t.Errorf("input = %+v, want no project, the completed state and no priority", loose)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	fake := newEngineeringTeam()
+	fake.issues = []fakeIssue{{LinearIssue: LinearIssue{Id: "issue-1"}, teamId: "team-eng"}}
+	c := fake.start(t)
+	ctx := context.Background()
+
+	if err := c.DeleteTask(ctx, "issue-1"); err != nil {
+		t.Errorf("DeleteTask: %v", err)
+	}
+	// Deleting an issue that is already gone succeeds, so rollbacks can be retried.
+	if err := c.DeleteTask(ctx, "issue-gone"); err != nil {
+		t.Errorf("DeleteTask of a missing issue: %v, want nil", err)
+	}
+
+	err := c.CreateComment(ctx, "issue-1", "hi")
+	if err == nil || !strings.Contains(err.Error(), "Linear error: unknown operation CreateComment") {
+		t.Errorf("CreateComment err = %v, want the GraphQL error message", err)
+	}
+
+	bad := NewLinearClientWithEndpoint(c.endpoint, "wrong-key")
+	if _, err := bad.GetTeams(ctx); err == nil || !strings.Contains(err.Error(), "Authentication required") {
+		t.Errorf("GetTeams with a wrong key: err = %v", err)
+	}
+}
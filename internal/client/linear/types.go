@@ -0,0 +1,100 @@
+package linear
+
+import "encoding/json"
+
+type GraphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type GraphQLError struct {
+	Message string `json:"message"`
+}
+
+type GraphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []GraphQLError  `json:"errors"`
+}
+
+type PageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type LinearTeam struct {
+	Id   string `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+type LinearUser struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Active bool   `json:"active"`
+}
+
+type LinearProject struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type LinearWorkflowState struct {
+	Id       string  `json:"id"`
+	Name     string  `json:"name"`
+	Type     string  `json:"type"` // triage, backlog, unstarted, started, completed, canceled
+	Position float64 `json:"position"`
+}
+
+type LinearLabel struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type LinearCycle struct {
+	Id     string `json:"id"`
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+}
+
+type LinearIssue struct {
+	Id          string               `json:"id"`
+	Identifier  string               `json:"identifier"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Url         string               `json:"url"`
+	Priority    int                  `json:"priority"`
+	Estimate    *float64             `json:"estimate"`
+	DueDate     string               `json:"dueDate"`
+	State       *LinearWorkflowState `json:"state"`
+	Assignee    *LinearUser          `json:"assignee"`
+	Subscribers struct {
+		Nodes []LinearUser `json:"nodes"`
+	} `json:"subscribers"`
+	Labels struct {
+		Nodes []LinearLabel `json:"nodes"`
+	} `json:"labels"`
+	Cycle *LinearCycle `json:"cycle"`
+}
+
+// Connection is a page of a GraphQL connection.
+type Connection[T any] struct {
+	Nodes    []T      `json:"nodes"`
+	PageInfo PageInfo `json:"pageInfo"`
+}
+
+// IssueCreateInput is the input of the issueCreate mutation.
+type IssueCreateInput struct {
+	TeamId        string   `json:"teamId"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description,omitempty"`
+	Priority      *int     `json:"priority,omitempty"`
+	Estimate      *int     `json:"estimate,omitempty"`
+	DueDate       string   `json:"dueDate,omitempty"`
+	StateId       string   `json:"stateId,omitempty"`
+	AssigneeId    string   `json:"assigneeId,omitempty"`
+	SubscriberIds []string `json:"subscriberIds,omitempty"`
+	LabelIds      []string `json:"labelIds,omitempty"`
+	ProjectId     string   `json:"projectId,omitempty"`
+	CycleId       string   `json:"cycleId,omitempty"`
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+type locationKey struct{}
+
+// WithLocation returns a context carrying the time zone clients use to interpret and write
+// date-only values (all-day due and start dates).
+func WithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, locationKey{}, loc)
+}
+
+// LocationFromContext returns the time zone set with WithLocation, or UTC.
+func LocationFromContext(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(locationKey{}).(*time.Location); ok && loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// TaskLocation returns the time zone the task's date-only values are anchored in,
+// falling back to the context's time zone.
+func TaskLocation(ctx context.Context, task models.Task) *time.Location {
+	if task.TimeZone != "" {
+		if loc, err := time.LoadLocation(task.TimeZone); err == nil {
+			return loc
+		}
+	}
+	return LocationFromContext(ctx)
+}
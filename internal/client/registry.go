@@ -0,0 +1,181 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/TWRT/integration-mapper/internal/oauth"
+)
+
+// Destination holds the destination settings of a migration, as entered when it was created.
+// What each ID refers to depends on the destination provider.
+type Destination struct {
+	WorkspaceID string
+	ListID      string
+	SpaceID     string
+}
+
+// Descriptor describes an integration provider to the rest of the application: the client
+// serving it, how destination containers are addressed and which settings it requires.
+// The func fields are optional; nil ones fall back to the defaults documented on each.
+type Descriptor struct {
+	// Name is the identifier migrations refer to the provider by ("asana", "clickup").
+	Name        string
+	DisplayName string
+	Client      IntegrationProvider
+
+	// Validate checks the destination settings of a migration targeting this provider.
+	// Default: the workspace ID is required.
+	Validate func(dest Destination) error
+	// ContainerScope returns the ID whose containers are offered as destination containers
+	// (Asana: the project, ClickUp: the space). Default: dest.ListID.
+	ContainerScope func(dest Destination) string
+	// ContainerAddress returns the ID CreateTask expects to place a task in a destination
+	// container (Asana: "project|section"). Default: the container ID itself.
+	ContainerAddress func(dest Destination, containerID string) string
+	// TagScope returns the ID destination tags belong to (Asana: the workspace, ClickUp: the
+	// space). Default: dest.WorkspaceID.
+	TagScope func(dest Destination) string
+	// DefaultPriorities are offered as destination priorities when the client has no
+	// PriorityLookup or the lookup returns nothing.
+	DefaultPriorities []string
+
+	// OAuth is the endpoint users connect their own account through. Nil for providers
+	// only reached with the server's credentials.
+	OAuth *oauth.Endpoint
+	// ConnectionClient builds a client acting as the user behind an OAuth connection. Set
+	// together with OAuth.
+	ConnectionClient func(source oauth.TokenSource) IntegrationProvider
+	// ConnectionRequired marks a provider the server has no credentials of its own for:
+	// Client cannot authenticate, so migrations must reach it through a connection.
+	ConnectionRequired bool
+	// NoCredentials marks a provider whose Client reaches no account, such as one reading
+	// local files. Any user may use it; other clients hold the server's credentials, which
+	// only admins may use.
+	NoCredentials bool
+}
+
+// ValidateDestination checks the destination settings of a new migration.
+func (d Descriptor) ValidateDestination(dest Destination) error {
+	if d.Validate != nil {
+		return d.Validate(dest)
+	}
+	if dest.WorkspaceID == "" {
+		return fmt.Errorf("dest_workspace_id is required")
+	}
+	return nil
+}
+
+// DestContainerScope returns the ID destination containers are listed from.
+func (d Descriptor) DestContainerScope(dest Destination) string {
+	if d.ContainerScope != nil {
+		return d.ContainerScope(dest)
+	}
+	return dest.ListID
+}
+
+// DestContainerAddress returns the ID passed to CreateTask for tasks going to containerID.
+func (d Descriptor) DestContainerAddress(dest Destination, containerID string) string {
+	if d.ContainerAddress != nil {
+		return d.ContainerAddress(dest, containerID)
+	}
+	return containerID
+}
+
+// DestTagScope returns the ID destination tags are listed and created in.
+func (d Descriptor) DestTagScope(dest Destination) string {
+	if d.TagScope != nil {
+		return d.TagScope(dest)
+	}
+	return dest.WorkspaceID
+}
+
+// Capabilities lists the optional client interfaces the provider's client implements.
+func (d Descriptor) Capabilities() []string {
+	checks := []struct {
+		name string
+		ok   bool
+	}{
+		{"ContainerProvider", is[ContainerProvider](d.Client)},
+		{"PriorityLookup", is[PriorityLookup](d.Client)},
+		{"FieldProvider", is[FieldProvider](d.Client)},
+		{"FieldCreator", is[FieldCreator](d.Client)},
+		{"DestFieldProvider", is[DestFieldProvider](d.Client)},
+		{"AssigneeLimiter", is[AssigneeLimiter](d.Client)},
+		{"TaskURLBuilder", is[TaskURLBuilder](d.Client)},
+		{"CommentCreator", is[CommentCreator](d.Client)},
+		{"ChecklistCreator", is[ChecklistCreator](d.Client)},
+		{"CommentReader", is[CommentReader](d.Client)},
+		{"AttachmentReader", is[AttachmentReader](d.Client)},
+		{"TaskDeleter", is[TaskDeleter](d.Client)},
+		{"FieldDeleter", is[FieldDeleter](d.Client)},
+		{"TagLister", is[TagLister](d.Client)},
+		{"TagCreator", is[TagCreator](d.Client)},
+		{"TagDeleter", is[TagDeleter](d.Client)},
+		{"ContainerDeleter", is[ContainerDeleter](d.Client)},
+		{"WorkspaceBrowser", is[WorkspaceBrowser](d.Client)},
+	}
+	var caps []string
+	for _, c := range checks {
+		if c.ok {
+			caps = append(caps, c.name)
+		}
+	}
+	return caps
+}
+
+func is[T any](v any) bool {
+	_, ok := v.(T)
+	return ok
+}
+
+// Registry holds the providers available to migrations, keyed by name.
+type Registry struct {
+	mu          sync.RWMutex
+	descriptors map[string]Descriptor
+}
+
+func NewRegistry() *Registry {
+	return &Registry{descriptors: make(map[string]Descriptor)}
+}
+
+// Register adds a provider. Names must be unique.
+func (r *Registry) Register(d Descriptor) error {
+	if d.Name == "" || d.Client == nil {
+		return fmt.Errorf("provider descriptor needs a name and a client")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.descriptors[d.Name]; dup {
+		return fmt.Errorf("provider %s already registered", d.Name)
+	}
+	r.descriptors[d.Name] = d
+	return nil
+}
+
+// MustRegister is Register for application setup, where a duplicate name is a programming error.
+func (r *Registry) MustRegister(d Descriptor) {
+	if err := r.Register(d); err != nil {
+		panic(err)
+	}
+}
+
+func (r *Registry) Get(name string) (Descriptor, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.descriptors[name]
+	return d, ok
+}
+
+// List returns the registered providers sorted by name.
+func (r *Registry) List() []Descriptor {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]Descriptor, 0, len(r.descriptors))
+	for _, d := range r.descriptors {
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
@@ -0,0 +1,35 @@
+package trello
+
+import (
+	"fmt"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+)
+
+// Name is the provider name migrations use for Trello.
+const Name = "trello"
+
+// Descriptor registers the Trello client. Like ClickUp spaces, the destination board is given
+// as the space and its lists are the destination containers; labels belong to the board.
+func Descriptor(c *TrelloClient) client.Descriptor {
+	return client.Descriptor{
+		Name:        Name,
+		DisplayName: "Trello",
+		Client:      c,
+		Validate: func(dest client.Destination) error {
+			if dest.WorkspaceID == "" {
+				return fmt.Errorf("dest_workspace_id (organization ID) is required")
+			}
+			if dest.SpaceID == "" {
+				return fmt.Errorf("dest_space_id (board ID) is required for Trello destination")
+			}
+			return nil
+		},
+		ContainerScope: func(dest client.Destination) string {
+			return dest.SpaceID
+		},
+		TagScope: func(dest client.Destination) string {
+			return dest.SpaceID
+		},
+	}
+}
@@ -0,0 +1,803 @@
+package trello
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/converter"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// Trello cards have no workflow status; whether the due date is marked complete is reported
+// with the same status names the Asana client uses.
+const (
+	StatusIncomplete = "Incomplete"
+	StatusCompleted  = "Completed"
+)
+
+const cardQuery = "filter=open&members=true&member_fields=fullName,username&checklists=all&customFieldItems=true"
+
+type TrelloClient struct {
+	baseUrl    string
+	apiKey     string
+	token      string
+	httpClient *http.Client
+
+	cacheMu     sync.RWMutex
+	listBoards  map[string]string              // listId → boardId
+	labelCache  map[string][]TrelloLabel       // boardId → labels
+	fieldCache  map[string][]TrelloCustomField // boardId → custom fields
+	memberCache map[string][]models.Member     // organizationId → members
+}
+
+func NewTrelloClient(apiKey, token string) *TrelloClient {
+	return NewTrelloClientWithBaseURL("https://api.trello.com/1", apiKey, token)
+}
+
+// NewTrelloClientWithBaseURL returns a client for a Trello-compatible API at baseUrl,
+// e.g. a local stand-in of the REST API.
+func NewTrelloClientWithBaseURL(baseUrl, apiKey, token string) *TrelloClient {
+	return &TrelloClient{
+		baseUrl:     strings.TrimRight(baseUrl, "/"),
+		apiKey:      apiKey,
+		token:       token,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		listBoards:  make(map[string]string),
+		labelCache:  make(map[string][]TrelloLabel),
+		fieldCache:  make(map[string][]TrelloCustomField),
+		memberCache: make(map[string][]models.Member),
+	}
+}
+
+// apiError is returned for non-2xx responses. Trello answers most errors with a plain-text body.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	if e.message != "" {
+		return fmt.Sprintf("Trello error: %s", e.message)
+	}
+	return fmt.Sprintf("API error status (trello): %d", e.status)
+}
+
+// do sends a request to the Trello API and decodes a JSON response into out (if not nil).
+// path may carry a query string.
+func (c *TrelloClient) do(ctx context.Context, method, path string, reqBody, out any) error {
+	var body io.Reader
+	if reqBody != nil {
+		b, err := json.Marshal(reqBody)
+		if err != nil {
+			return fmt.Errorf("marshal request (trello): %w", err)
+		}
+		body = bytes.NewBuffer(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseUrl+path, body)
+	if err != nil {
+		return fmt.Errorf("build request (trello): %w", err)
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf(`OAuth oauth_consumer_key="%s", oauth_token="%s"`, c.apiKey, c.token))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s (trello): %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response body (trello): %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var jsonErr struct {
+			Message string `json:"message"`
+		}
+		msg := strings.TrimSpace(string(respBody))
+		if json.Unmarshal(respBody, &jsonErr) == nil {
+			msg = jsonErr.Message
+		}
+		return &apiError{status: resp.StatusCode, message: msg}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("parse response (trello): %w", err)
+	}
+	return nil
+}
+
+// ---- Browsing ----
+
+func (c *TrelloClient) GetOrganizations(ctx context.Context) ([]TrelloOrganization, error) {
+	var orgs []TrelloOrganization
+	if err := c.do(ctx, "GET", "/members/me/organizations?fields=name,displayName", nil, &orgs); err != nil {
+		return nil, fmt.Errorf("get organizations (trello): %w", err)
+	}
+	return orgs, nil
+}
+
+func (c *TrelloClient) GetBoards(ctx context.Context, organizationId string) ([]TrelloBoard, error) {
+	var boards []TrelloBoard
+	if err := c.do(ctx, "GET", "/organizations/"+organizationId+"/boards?filter=open&fields=name,closed", nil, &boards); err != nil {
+		return nil, fmt.Errorf("get boards (trello): %w", err)
+	}
+	return boards, nil
+}
+
+func (c *TrelloClient) GetLists(ctx context.Context, boardId string) ([]TrelloList, error) {
+	var lists []TrelloList
+	if err := c.do(ctx, "GET", "/boards/"+boardId+"/lists?filter=open", nil, &lists); err != nil {
+		return nil, fmt.Errorf("get lists (trello): %w", err)
+	}
+	c.cacheMu.Lock()
+	for _, l := range lists {
+		c.listBoards[l.Id] = boardId
+	}
+	c.cacheMu.Unlock()
+	return lists, nil
+}
+
+// ListWorkspaces implements client.WorkspaceBrowser. Trello workspaces are organizations.
+func (c *TrelloClient) ListWorkspaces(ctx context.Context) ([]client.Container, error) {
+	orgs, err := c.GetOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]client.Container, len(orgs))
+	for i, o := range orgs {
+		result[i] = client.Container{ID: o.Id, Name: o.DisplayName}
+	}
+	return result, nil
+}
+
+// ListProjects implements client.WorkspaceBrowser. Trello projects are boards.
+func (c *TrelloClient) ListProjects(ctx context.Context, organizationId string) ([]client.Container, error) {
+	boards, err := c.GetBoards(ctx, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]client.Container, len(boards))
+	for i, b := range boards {
+		result[i] = client.Container{ID: b.Id, Name: b.Name}
+	}
+	return result, nil
+}
+
+// GetSourceContainers returns the open lists of a board.
+func (c *TrelloClient) GetSourceContainers(ctx context.Context, boardId string) ([]client.Container, error) {
+	lists, err := c.GetLists(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	containers := make([]client.Container, len(lists))
+	for i, l := range lists {
+		containers[i] = client.Container{ID: l.Id, Name: l.Name}
+	}
+	return containers, nil
+}
+
+// GetDestContainers returns the open lists of a board (used as destination containers).
+func (c *TrelloClient) GetDestContainers(ctx context.Context, boardId string) ([]client.Container, error) {
+	return c.GetSourceContainers(ctx, boardId)
+}
+
+// GetListStatuses returns the statuses a card can have. Trello lists have no workflow of their own.
+func (c *TrelloClient) GetListStatuses(_ context.Context, _ string) ([]string, error) {
+	return []string{StatusIncomplete, StatusCompleted}, nil
+}
+
+// GetMembers returns the members of a Trello organization. Trello does not expose member
+// e-mail addresses to other members, so Email is left empty.
+func (c *TrelloClient) GetMembers(ctx context.Context, organizationId string) ([]models.Member, error) {
+	c.cacheMu.RLock()
+	cached, ok := c.memberCache[organizationId]
+	c.cacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	var members []TrelloMember
+	if err := c.do(ctx, "GET", "/organizations/"+organizationId+"/members?fields=fullName,username", nil, &members); err != nil {
+		return nil, fmt.Errorf("get members (trello): %w", err)
+	}
+	result := make([]models.Member, len(members))
+	for i, m := range members {
+		result[i] = models.Member{ID: m.Id, Name: memberName(m)}
+	}
+
+	c.cacheMu.Lock()
+	c.memberCache[organizationId] = result
+	c.cacheMu.Unlock()
+	return result, nil
+}
+
+func memberName(m TrelloMember) string {
+	if m.FullName != "" {
+		return m.FullName
+	}
+	return m.Username
+}
+
+// ---- Boards, labels and custom fields ----
+
+// boardOfList returns the board a list belongs to.
+func (c *TrelloClient) boardOfList(ctx context.Context, listId string) (string, error) {
+	c.cacheMu.RLock()
+	boardId, ok := c.listBoards[listId]
+	c.cacheMu.RUnlock()
+	if ok {
+		return boardId, nil
+	}
+
+	var list TrelloList
+	if err := c.do(ctx, "GET", "/lists/"+listId+"?fields=idBoard", nil, &list); err != nil {
+		return "", fmt.Errorf("get list (trello): %w", err)
+	}
+	c.cacheMu.Lock()
+	c.listBoards[listId] = list.IdBoard
+	c.cacheMu.Unlock()
+	return list.IdBoard, nil
+}
+
+func (c *TrelloClient) getLabels(ctx context.Context, boardId string) ([]TrelloLabel, error) {
+	c.cacheMu.RLock()
+	cached, ok := c.labelCache[boardId]
+	c.cacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	var labels []TrelloLabel
+	if err := c.do(ctx, "GET", "/boards/"+boardId+"/labels?limit=1000", nil, &labels); err != nil {
+		return nil, fmt.Errorf("get labels (trello): %w", err)
+	}
+	c.cacheMu.Lock()
+	c.labelCache[boardId] = labels
+	c.cacheMu.Unlock()
+	return labels, nil
+}
+
+// labelName is the tag a label is migrated as. Trello labels may have only a color.
+func labelName(l TrelloLabel) string {
+	if l.Name != "" {
+		return l.Name
+	}
+	return l.Color
+}
+
+// ListTags returns the label names of a board.
+func (c *TrelloClient) ListTags(ctx context.Context, boardId string) ([]string, error) {
+	labels, err := c.getLabels(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		if name := labelName(l); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// resolveLabels returns the IDs of the board labels with the given names, creating the
+// missing ones without a color.
+func (c *TrelloClient) resolveLabels(ctx context.Context, boardId string, names []string) ([]string, error) {
+	labels, err := c.getLabels(ctx, boardId)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]string, len(labels))
+	for _, l := range labels {
+		if key := strings.ToLower(labelName(l)); key != "" {
+			if _, dup := byName[key]; !dup {
+				byName[key] = l.Id
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		if id, ok := byName[strings.ToLower(name)]; ok {
+			ids = append(ids, id)
+			continue
+		}
+		var created TrelloLabel
+		if err := c.do(ctx, "POST", "/labels", CreateLabelRequest{IdBoard: boardId, Name: name}, &created); err != nil {
+			return nil, fmt.Errorf("create label %q (trello): %w", name, err)
+		}
+		byName[strings.ToLower(name)] = created.Id
+		ids = append(ids, created.Id)
+
+		c.cacheMu.Lock()
+		c.labelCache[boardId] = append(c.labelCache[boardId], created)
+		c.cacheMu.Unlock()
+	}
+	return ids, nil
+}
+
+func (c *TrelloClient) getCustomFields(ctx context.Context, boardId string) ([]TrelloCustomField, error) {
+	c.cacheMu.RLock()
+	cached, ok := c.fieldCache[boardId]
+	c.cacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	var fields []TrelloCustomField
+	if err := c.do(ctx, "GET", "/boards/"+boardId+"/customFields", nil, &fields); err != nil {
+		return nil, fmt.Errorf("get custom fields (trello): %w", err)
+	}
+	for i := range fields {
+		sort.SliceStable(fields[i].Options, func(a, b int) bool {
+			return fields[i].Options[a].Pos < fields[i].Options[b].Pos
+		})
+	}
+	c.cacheMu.Lock()
+	c.fieldCache[boardId] = fields
+	c.cacheMu.Unlock()
+	return fields, nil
+}
+
+// trelloTypeToClickUpType maps Trello custom field types onto the ClickUp type names
+// field definitions are described with.
+func trelloTypeToClickUpType(trelloType string) string {
+	switch trelloType {
+	case "text":
+		return "short_text"
+	case "number":
+		return "number"
+	case "date":
+		return "date"
+	case "checkbox":
+		return "checkbox"
+	case "list":
+		return "drop_down"
+	}
+	return trelloType
+}
+
+func fieldDefinitions(fields []TrelloCustomField) []models.CustomFieldDefinition {
+	defs := make([]models.CustomFieldDefinition, 0, len(fields))
+	for _, f := range fields {
+		def := models.CustomFieldDefinition{
+			ID:          f.Id,
+			Name:        f.Name,
+			ClickUpType: trelloTypeToClickUpType(f.Type),
+		}
+		for i, o := range f.Options {
+			def.Options = append(def.Options, models.CustomFieldOption{ID: o.Id, Name: o.Value.Text, OrderIndex: i})
+		}
+		defs = append(defs, def)
+	}
+	return defs
+}
+
+// GetFieldDefinitions returns the custom fields of the board a list belongs to.
+// List fields are described as dropdowns whose order index is the option position.
+func (c *TrelloClient) GetFieldDefinitions(ctx context.Context, listId string) ([]models.CustomFieldDefinition, error) {
+	boardId, err := c.boardOfList(ctx, listId)
+	if err != nil {
+		return nil, fmt.Errorf("get field definitions (trello): %w", err)
+	}
+	fields, err := c.getCustomFields(ctx, boardId)
+	if err != nil {
+		return nil, fmt.Errorf("get field definitions (trello): %w", err)
+	}
+	return fieldDefinitions(fields), nil
+}
+
+// GetDestFieldDefinitions returns the custom fields of a destination board. Checkboxes get
+// "true"/"false" pseudo-options so source options can be mapped onto them.
+func (c *TrelloClient) GetDestFieldDefinitions(ctx context.Context, boardId string) ([]models.CustomFieldDefinition, error) {
+	fields, err := c.getCustomFields(ctx, boardId)
+	if err != nil {
+		return nil, fmt.Errorf("get dest field definitions (trello): %w", err)
+	}
+	defs := fieldDefinitions(fields)
+	for i := range defs {
+		if defs[i].ClickUpType == "checkbox" {
+			defs[i].Options = []models.CustomFieldOption{{ID: "true", Name: "True"}, {ID: "false", Name: "False"}}
+		}
+	}
+	return defs, nil
+}
+
+// ---- Cards ----
+
+// GetTasks returns the open cards of a board.
+func (c *TrelloClient) GetTasks(ctx context.Context, boardId string) ([]models.Task, error) {
+	var cards []TrelloCard
+	if err := c.do(ctx, "GET", "/boards/"+boardId+"/cards?"+cardQuery, nil, &cards); err != nil {
+		return nil, fmt.Errorf("get cards (trello): %w", err)
+	}
+	return c.parseCards(ctx, cards)
+}
+
+// GetTasksByContainer returns the open cards of a list.
+func (c *TrelloClient) GetTasksByContainer(ctx context.Context, listId string) ([]models.Task, error) {
+	var cards []TrelloCard
+	if err := c.do(ctx, "GET", "/lists/"+listId+"/cards?"+cardQuery, nil, &cards); err != nil {
+		return nil, fmt.Errorf("get cards (trello): %w", err)
+	}
+	return c.parseCards(ctx, cards)
+}
+
+func (c *TrelloClient) parseCards(ctx context.Context, cards []TrelloCard) ([]models.Task, error) {
+	loc := client.LocationFromContext(ctx)
+	tasks := make([]models.Task, 0, len(cards))
+	for _, card := range cards {
+		task, err := c.parseCard(ctx, card, loc)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func (c *TrelloClient) parseCard(ctx context.Context, card TrelloCard, loc *time.Location) (models.Task, error) {
+	// Due dates always carry a time in Trello; start dates are picked as calendar days.
+	var dueDate, startDate *time.Time
+	if card.Due != "" {
+		t, err := time.Parse(time.RFC3339, card.Due)
+		if err != nil {
+			return models.Task{}, fmt.Errorf("parse due date (trello): %w", err)
+		}
+		dueDate = &t
+	}
+	if card.Start != "" {
+		t, err := time.Parse(time.RFC3339, card.Start)
+		if err != nil {
+			return models.Task{}, fmt.Errorf("parse start date (trello): %w", err)
+		}
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		startDate = &day
+	}
+
+	assignees := make([]models.TaskAssignee, 0, len(card.Members))
+	for _, m := range card.Members {
+		assignees = append(assignees, models.TaskAssignee{ID: m.Id, Name: memberName(m)})
+	}
+
+	tags := make([]string, 0, len(card.Labels))
+	for _, l := range card.Labels {
+		if name := labelName(l); name != "" {
+			tags = append(tags, name)
+		}
+	}
+
+	checklists := make([]models.Checklist, 0, len(card.Checklists))
+	sort.SliceStable(card.Checklists, func(i, j int) bool { return card.Checklists[i].Pos < card.Checklists[j].Pos })
+	for _, cl := range card.Checklists {
+		sort.SliceStable(cl.CheckItems, func(i, j int) bool { return cl.CheckItems[i].Pos < cl.CheckItems[j].Pos })
+		checklist := models.Checklist{Name: cl.Name}
+		for _, item := range cl.CheckItems {
+			checklist.Items = append(checklist.Items, models.ChecklistItem{Name: item.Name, Checked: item.State == "complete"})
+		}
+		checklists = append(checklists, checklist)
+	}
+
+	customFields, err := c.parseCustomFieldItems(ctx, card)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	status := StatusIncomplete
+	if card.DueComplete {
+		status = StatusCompleted
+	}
+
+	return models.Task{
+		Id:              card.Id,
+		Name:            card.Name,
+		Description:     card.Desc,
+		RichDescription: card.Desc,
+		Status:          status,
+		Completed:       card.DueComplete,
+		Assignees:       assignees,
+		DueDate:         dueDate,
+		DueHasTime:      dueDate != nil,
+		StartDate:       startDate,
+		TimeZone:        loc.String(),
+		Tags:            tags,
+		CustomFields:    customFields,
+		Checklists:      checklists,
+	}, nil
+}
+
+// parseCustomFieldItems converts card custom field values into the ClickUp value shapes
+// the migration works with: list options become dropdown order indexes, dates millisecond strings.
+func (c *TrelloClient) parseCustomFieldItems(ctx context.Context, card TrelloCard) ([]models.TaskCustomField, error) {
+	if len(card.CustomFieldItems) == 0 {
+		return nil, nil
+	}
+	fields, err := c.getCustomFields(ctx, card.IdBoard)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]TrelloCustomField, len(fields))
+	for _, f := range fields {
+		byID[f.Id] = f
+	}
+
+	result := make([]models.TaskCustomField, 0, len(card.CustomFieldItems))
+	for _, item := range card.CustomFieldItems {
+		field, ok := byID[item.IdCustomField]
+		if !ok {
+			continue
+		}
+		var value interface{}
+		switch field.Type {
+		case "list":
+			for i, o := range field.Options {
+				if o.Id == item.IdValue {
+					value = float64(i)
+				}
+			}
+		case "text":
+			if item.Value != nil && item.Value.Text != "" {
+				value = item.Value.Text
+			}
+		case "number":
+			if item.Value != nil {
+				if n, err := strconv.ParseFloat(item.Value.Number, 64); err == nil {
+					value = n
+				}
+			}
+		case "checkbox":
+			if item.Value != nil {
+				value = item.Value.Checked == "true"
+			}
+		case "date":
+			if item.Value != nil {
+				if t, err := time.Parse(time.RFC3339, item.Value.Date); err == nil {
+					value = strconv.FormatInt(t.UnixMilli(), 10)
+				}
+			}
+		}
+		if value != nil {
+			result = append(result, models.TaskCustomField{FieldID: field.Id, Value: value})
+		}
+	}
+	return result, nil
+}
+
+// formatTrelloDate formats a task date for Trello. Date-only values are sent as midnight
+// of their day in loc.
+func formatTrelloDate(t *time.Time, hasTime bool, loc *time.Location) string {
+	if t == nil {
+		return ""
+	}
+	if !hasTime {
+		local := t.In(loc)
+		midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		t = &midnight
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// CreateTask creates a card in a Trello list. Assignees become card members; followers are
+// not written since Trello members can only subscribe themselves to a card. Labels are
+// resolved by name on the list's board and created when missing.
+func (c *TrelloClient) CreateTask(ctx context.Context, listId string, _ string, task models.Task) (*models.Task, error) {
+	boardId, err := c.boardOfList(ctx, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := client.TaskLocation(ctx, task)
+	reqBody := CreateCardRequest{
+		IdList:      listId,
+		Name:        task.Name,
+		Desc:        task.Description,
+		Due:         formatTrelloDate(task.DueDate, task.DueHasTime, loc),
+		Start:       formatTrelloDate(task.StartDate, false, loc),
+		DueComplete: task.Completed || task.Status == StatusCompleted,
+	}
+	if task.RichDescription != "" {
+		reqBody.Desc = converter.PlainMentions(task.RichDescription)
+	}
+	for _, a := range task.Assignees {
+		reqBody.IdMembers = append(reqBody.IdMembers, a.ID)
+	}
+	if len(task.Tags) > 0 {
+		reqBody.IdLabels, err = c.resolveLabels(ctx, boardId, task.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var created TrelloCard
+	if err := c.do(ctx, "POST", "/cards", reqBody, &created); err != nil {
+		return nil, fmt.Errorf("create card (trello): %w", err)
+	}
+
+	// Custom fields can only be set once the card exists. A card whose fields could not be
+	// written is removed again so the task is reported as failed as a whole.
+	if err := c.setCustomFields(ctx, boardId, created.Id, task.CustomFields); err != nil {
+		if delErr := c.DeleteTask(ctx, created.Id); delErr != nil {
+			return nil, errors.Join(err, delErr)
+		}
+		return nil, err
+	}
+
+	status := StatusIncomplete
+	if created.DueComplete {
+		status = StatusCompleted
+	}
+	return &models.Task{
+		Id:        created.Id,
+		Name:      created.Name,
+		Status:    status,
+		Completed: created.DueComplete,
+	}, nil
+}
+
+func (c *TrelloClient) setCustomFields(ctx context.Context, boardId, cardId string, values []models.TaskCustomField) error {
+	if len(values) == 0 {
+		return nil
+	}
+	fields, err := c.getCustomFields(ctx, boardId)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]TrelloCustomField, len(fields))
+	for _, f := range fields {
+		byID[f.Id] = f
+	}
+
+	for _, cf := range values {
+		field, ok := byID[cf.FieldID]
+		if !ok || cf.Value == nil {
+			continue
+		}
+		item, ok := customFieldItem(field, cf.Value)
+		if !ok {
+			continue
+		}
+		path := "/cards/" + cardId + "/customField/" + field.Id + "/item"
+		if err := c.do(ctx, "PUT", path, item, nil); err != nil {
+			return fmt.Errorf("set custom field %q (trello): %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// customFieldItem builds the update for a custom field from a converted task value.
+func customFieldItem(field TrelloCustomField, value interface{}) (UpdateCustomFieldItemRequest, bool) {
+	switch field.Type {
+	case "list":
+		if id, ok := value.(string); ok {
+			return UpdateCustomFieldItemRequest{IdValue: id}, true
+		}
+	case "text":
+		return UpdateCustomFieldItemRequest{Value: &TrelloCustomFieldValue{Text: fmt.Sprintf("%v", value)}}, true
+	case "number":
+		switch v := value.(type) {
+		case float64:
+			return UpdateCustomFieldItemRequest{Value: &TrelloCustomFieldValue{Number: strconv.FormatFloat(v, 'f', -1, 64)}}, true
+		case string:
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				return UpdateCustomFieldItemRequest{Value: &TrelloCustomFieldValue{Number: v}}, true
+			}
+		}
+	case "checkbox":
+		checked := fmt.Sprintf("%v", value) == "true"
+		return UpdateCustomFieldItemRequest{Value: &TrelloCustomFieldValue{Checked: strconv.FormatBool(checked)}}, true
+	case "date":
+		if t, ok := parseFieldDate(value); ok {
+			return UpdateCustomFieldItemRequest{Value: &TrelloCustomFieldValue{Date: t.UTC().Format(time.RFC3339)}}, true
+		}
+	}
+	return UpdateCustomFieldItemRequest{}, false
+}
+
+// parseFieldDate reads a date custom field value: a millisecond timestamp (string or number)
+// or an RFC 3339 / YYYY-MM-DD string.
+func parseFieldDate(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case float64:
+		return time.UnixMilli(int64(v)), true
+	case string:
+		if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.UnixMilli(ms), true
+		}
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, true
+		}
+		if t, err := time.Parse("2006-01-02", v); err == nil {
+			return t, true
+		}
+	case map[string]interface{}:
+		// Asana date values: {"date": "YYYY-MM-DD", "date_time": ...}
+		if s, ok := v["date_time"].(string); ok && s != "" {
+			return parseFieldDate(s)
+		}
+		if s, ok := v["date"].(string); ok {
+			return parseFieldDate(s)
+		}
+	}
+	return time.Time{}, false
+}
+
+// CreateChecklist adds a checklist with its items to a card.
+func (c *TrelloClient) CreateChecklist(ctx context.Context, cardId string, checklist models.Checklist) error {
+	var created TrelloChecklist
+	if err := c.do(ctx, "POST", "/checklists", CreateChecklistRequest{IdCard: cardId, Name: checklist.Name}, &created); err != nil {
+		return fmt.Errorf("create checklist (trello): %w", err)
+	}
+	for _, item := range checklist.Items {
+		path := "/checklists/" + created.Id + "/checkItems"
+		if err := c.do(ctx, "POST", path, CreateCheckItemRequest{Name: item.Name, Checked: item.Checked}, nil); err != nil {
+			return fmt.Errorf("create checklist item (trello): %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateComment adds a comment to a card.
+func (c *TrelloClient) CreateComment(ctx context.Context, cardId, text string) error {
+	path := "/cards/" + cardId + "/actions/comments?text=" + url.QueryEscape(text)
+	if err := c.do(ctx, "POST", path, nil, nil); err != nil {
+		return fmt.Errorf("create comment (trello): %w", err)
+	}
+	return nil
+}
+
+// TaskURL returns the Trello web link of a card.
+func (c *TrelloClient) TaskURL(cardId string) string {
+	return "https://trello.com/c/" + cardId
+}
+
+// deleteResource issues a DELETE against the given API path. A 404 is treated as success
+// so that rollbacks can be retried after partial failures.
+func (c *TrelloClient) deleteResource(ctx context.Context, path, what string) error {
+	err := c.do(ctx, "DELETE", path, nil, nil)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("delete %s (trello): %w", what, err)
+	}
+	return nil
+}
+
+func (c *TrelloClient) DeleteTask(ctx context.Context, cardId string) error {
+	return c.deleteResource(ctx, "/cards/"+cardId, "card")
+}
+
+// DeleteContainer archives a Trello list; lists cannot be deleted through the API.
+func (c *TrelloClient) DeleteContainer(ctx context.Context, listId string) error {
+	err := c.do(ctx, "PUT", "/lists/"+listId+"/closed?value=true", nil, nil)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("archive list (trello): %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,482 @@
+package trello
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// fakeTrello is an in-memory stand-in for the parts of the Trello REST API the client uses.
+type fakeTrello struct {
+	mu           sync.Mutex
+	boards       []TrelloBoard
+	lists        map[string][]TrelloList // boardId → lists
+	cards        map[string][]TrelloCard // boardId → cards
+	labels       map[string][]TrelloLabel
+	customFields map[string][]TrelloCustomField
+
+	createdLabels []CreateLabelRequest
+	createdCards  []CreateCardRequest
+	fieldUpdates  map[string]UpdateCustomFieldItemRequest // fieldId → update
+	failFieldPut  bool
+	deletedCards  []string
+	requests      []string
+}
+
+func newFakeTrello() *fakeTrello {
+	return &fakeTrello{
+		lists:        make(map[string][]TrelloList),
+		cards:        make(map[string][]TrelloCard),
+		labels:       make(map[string][]TrelloLabel),
+		customFields: make(map[string][]TrelloCustomField),
+		fieldUpdates: make(map[string]UpdateCustomFieldItemRequest),
+	}
+}
+
+func (f *fakeTrello) start(t *testing.T) *TrelloClient {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /members/me/organizations", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, []TrelloOrganization{{Id: "org1", Name: "acme", DisplayName: "Acme"}})
+	})
+	mux.HandleFunc("GET /organizations/{id}/boards", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, f.boards)
+	})
+	mux.HandleFunc("GET /organizations/{id}/members", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, []TrelloMember{{Id: "m1", FullName: "Ann Lee"}, {Id: "m2", Username: "bob"}})
+	})
+	mux.HandleFunc("GET /boards/{id}/lists", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, f.lists[r.PathValue("id")])
+	})
+	mux.HandleFunc("GET /boards/{id}/cards", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, f.cards[r.PathValue("id")])
+	})
+	mux.HandleFunc("GET /boards/{id}/labels", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, f.labels[r.PathValue("id")])
+	})
+	mux.HandleFunc("GET /boards/{id}/customFields", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, f.customFields[r.PathValue("id")])
+	})
+	mux.HandleFunc("GET /lists/{id}", func(w http.ResponseWriter, r *http.Request) {
+		for boardId, lists := range f.lists {
+			for _, l := range lists {
+				if l.Id == r.PathValue("id") {
+					writeTestJSON(w, TrelloList{Id: l.Id, IdBoard: boardId})
+					return
+				}
+			}
+		}
+		http.Error(w, "invalid id", http.StatusNotFound)
+	})
+	mux.HandleFunc("POST /labels", func(w http.ResponseWriter, r *http.Request) {
+		var req CreateLabelRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.createdLabels = append(f.createdLabels, req)
+		writeTestJSON(w, TrelloLabel{Id: "new-" + req.Name, Name: req.Name, IdBoard: req.IdBoard})
+	})
+	mux.HandleFunc("POST /cards", func(w http.ResponseWriter, r *http.Request) {
+		var req CreateCardRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.createdCards = append(f.createdCards, req)
+		writeTestJSON(w, TrelloCard{Id: "card-new", Name: req.Name, DueComplete: req.DueComplete})
+	})
+	mux.HandleFunc("PUT /cards/{card}/customField/{field}/item", func(w http.ResponseWriter, r *http.Request) {
+		if f.failFieldPut {
+			http.Error(w, "invalid value for custom field type", http.StatusBadRequest)
+			return
+		}
+		var req UpdateCustomFieldItemRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.fieldUpdates[r.PathValue("field")] = req
+		writeTestJSON(w, map[string]string{})
+	})
+	mux.HandleFunc("DELETE /cards/{id}", func(w http.ResponseWriter, r *http.Request) {
+		f.deletedCards = append(f.deletedCards, r.PathValue("id"))
+		writeTestJSON(w, map[string]string{})
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if !strings.HasPrefix(r.Header.Get("Authorization"), `OAuth oauth_consumer_key="key", oauth_token="token"`) {
+			http.Error(w, "unauthorized permission requested", http.StatusUnauthorized)
+			return
+		}
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return NewTrelloClientWithBaseURL(srv.URL, "key", "token")
+}
+
+func (f *fakeTrello) count(request string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, r := range f.requests {
+		if r == request {
+			n++
+		}
+	}
+	return n
+}
+
+func writeTestJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestBrowseBoardsAndLists(t *testing.T) {
+	fake := newFakeTrello()
+	fake.boards = []TrelloBoard{{Id: "b1", Name: "Roadmap"}, {Id: "b2", Name: "Support"}}
+	fake.lists["b1"] = []TrelloList{{Id: "l1", Name: "To Do"}, {Id: "l2", Name: "Done"}}
+	c := fake.start(t)
+	ctx := context.Background()
+
+	workspaces, err := c.ListWorkspaces(ctx)
+	if err != nil {
+		t.Fatalf("ListWorkspaces: %v", err)
+	}
+	if want := []client.Container{{ID: "org1", Name: "Acme"}}; !reflect.DeepEqual(workspaces, want) {
+		t.Errorf("workspaces = %v, want %v", workspaces, want)
+	}
+
+	projects, err := c.ListProjects(ctx, "org1")
+	if err != nil {
+		t.Fatalf("ListProjects: %v", err)
+	}
+	if want := []client.Container{{ID: "b1", Name: "Roadmap"}, {ID: "b2", Name: "Support"}}; !reflect.DeepEqual(projects, want) {
+		t.Errorf("projects = %v, want %v", projects, want)
+	}
+
+	containers, err := c.GetSourceContainers(ctx, "b1")
+	if err != nil {
+		t.Fatalf("GetSourceContainers: %v", err)
+	}
+	if want := []client.Container{{ID: "l1", Name: "To Do"}, {ID: "l2", Name: "Done"}}; !reflect.DeepEqual(containers, want) {
+		t.Errorf("containers = %v, want %v", containers, want)
+	}
+
+	// Listing the lists of a board remembers which board each list is on.
+	boardId, err := c.boardOfList(ctx, "l2")
+	if err != nil || boardId != "b1" {
+		t.Errorf("boardOfList = %q, %v; want b1", boardId, err)
+	}
+	if n := fake.count("GET /lists/l2"); n != 0 {
+		t.Errorf("boardOfList fetched the list %d times, want it cached", n)
+	}
+
+	members, err := c.GetMembers(ctx, "org1")
+	if err != nil {
+		t.Fatalf("GetMembers: %v", err)
+	}
+	if want := []models.Member{{ID: "m1", Name: "Ann Lee"}, {ID: "m2", Name: "bob"}}; !reflect.DeepEqual(members, want) {
+		t.Errorf("members = %v, want %v", members, want)
+	}
+}
+
+func TestGetTasksParsesCards(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	fake := newFakeTrello()
+	fake.customFields["b1"] = []TrelloCustomField{
+		{Id: "f-list", Name: "Size", Type: "list", Options: []TrelloCustomFieldOption{
+			{Id: "o-large", Value: TrelloCustomFieldValue{Text: "L"}, Pos: 3000},
+			{Id: "o-small", Value: TrelloCustomFieldValue{Text: "S"}, Pos: 1000},
+			{Id: "o-medium", Value: TrelloCustomFieldValue{Text: "M"}, Pos: 2000},
+		}},
+		{Id: "f-text", Name: "Notes", Type: "text"},
+		{Id: "f-number", Name: "Points", Type: "number"},
+		{Id: "f-check", Name: "Blocked", Type: "checkbox"},
+		{Id: "f-date", Name: "Launch", Type: "date"},
+	}
+	fake.cards["b1"] = []TrelloCard{{
+		Id:          "c1",
+		Name:        "Ship it",
+		Desc:        "Details",
+		IdBoard:     "b1",
+		Due:         "2024-03-05T17:30:00.000Z",
+		Start:       "2024-03-01T02:00:00.000Z",
+		DueComplete: true,
+		Members:     []TrelloMember{{Id: "m1", FullName: "Ann Lee"}, {Id: "m2", Username: "bob"}},
+		Labels:      []TrelloLabel{{Id: "lb1", Name: "Bug", Color: "red"}, {Id: "lb2", Color: "green"}},
+		Checklists: []TrelloChecklist{
+			{Name: "Second", Pos: 2000, CheckItems: []TrelloCheckItem{{Name: "b", Pos: 2}, {Name: "a", State: "complete", Pos: 1}}},
+			{Name: "First", Pos: 1000},
+		},
+		CustomFieldItems: []TrelloCustomFieldItem{
+			{IdCustomField: "f-list", IdValue: "o-medium"},
+			{IdCustomField: "f-text", Value: &TrelloCustomFieldValue{Text: "hello"}},
+			{IdCustomField: "f-number", Value: &TrelloCustomFieldValue{Number: "5.5"}},
+			{IdCustomField: "f-check", Value: &TrelloCustomFieldValue{Checked: "true"}},
+			{IdCustomField: "f-date", Value: &TrelloCustomFieldValue{Date: "2024-04-01T00:00:00.000Z"}},
+			{IdCustomField: "f-unknown", Value: &TrelloCustomFieldValue{Text: "ignored"}},
+		},
+	}}
+	c := fake.start(t)
+
+	tasks, err := c.GetTasks(client.WithLocation(context.Background(), loc), "b1")
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("got %d tasks, want 1", len(tasks))
+	}
+	task := tasks[0]
+
+	if task.Id != "c1" || task.Name != "Ship it" || task.Description != "Details" {
+		t.Errorf("task = %+v", task)
+	}
+	if task.Status != StatusCompleted || !task.Completed {
+		t.Errorf("status = %q, completed = %v; want completed", task.Status, task.Completed)
+	}
+	wantDue := time.Date(2024, 3, 5, 17, 30, 0, 0, time.UTC)
+	if task.DueDate == nil || !task.DueDate.Equal(wantDue) || !task.DueHasTime {
+		t.Errorf("due = %v (has time %v), want %v with time", task.DueDate, task.DueHasTime, wantDue)
+	}
+	// Start dates are calendar days: the stored day is kept rather than shifted into loc,
+	// where 02:00 UTC is still February 29th.
+	wantStart := time.Date(2024, 3, 1, 0, 0, 0, 0, loc)
+	if task.StartDate == nil || !task.StartDate.Equal(wantStart) || task.StartHasTime {
+		t.Errorf("start = %v, want %v without time", task.StartDate, wantStart)
+	}
+	if task.TimeZone != "America/New_York" {
+		t.Errorf("time zone = %q", task.TimeZone)
+	}
+
+	wantAssignees := []models.TaskAssignee{{ID: "m1", Name: "Ann Lee"}, {ID: "m2", Name: "bob"}}
+	if !reflect.DeepEqual(task.Assignees, wantAssignees) {
+		t.Errorf("assignees = %v, want %v", task.Assignees, wantAssignees)
+	}
+	if want := []string{"Bug", "green"}; !reflect.DeepEqual(task.Tags, want) {
+		t.Errorf("tags = %v, want %v", task.Tags, want)
+	}
+
+	wantChecklists := []models.Checklist{
+		{Name: "First"},
+		{Name: "Second", Items: []models.ChecklistItem{{Name: "a", Checked: true}, {Name: "b"}}},
+	}
+	if !reflect.DeepEqual(task.Checklists, wantChecklists) {
+		t.Errorf("checklists = %+v, want %+v", task.Checklists, wantChecklists)
+	}
+
+	wantFields := []models.TaskCustomField{
+		{FieldID: "f-list", Value: float64(1)}, // M is the second option by position
+		{FieldID: "f-text", Value: "hello"},
+		{FieldID: "f-number", Value: 5.5},
+		{FieldID: "f-check", Value: true},
+		{FieldID: "f-date", Value: "1711929600000"},
+	}
+	if !reflect.DeepEqual(task.CustomFields, wantFields) {
+		t.Errorf("custom fields = %#v, want %#v", task.CustomFields, wantFields)
+	}
+}
+
+func TestParseCardRejectsInvalidDueDate(t *testing.T) {
+	c := NewTrelloClientWithBaseURL("http://unused", "key", "token")
+	_, err := c.parseCard(context.Background(), TrelloCard{Id: "c1", Due: "tomorrow"}, time.UTC)
+	if err == nil || !strings.Contains(err.Error(), "parse due date") {
+		t.Errorf("err = %v, want a due date parse error", err)
+	}
+}
+
+func TestFieldDefinitionsOrderListOptionsByPos(t *testing.T) {
+	fake := newFakeTrello()
+	fake.lists["b1"] = []TrelloList{{Id: "l1", Name: "To Do"}}
+	fake.customFields["b1"] = []TrelloCustomField{
+		{Id: "f-list", Name: "Size", Type: "list", Options: []TrelloCustomFieldOption{
+			{Id: "o2", Value: TrelloCustomFieldValue{Text: "Large"}, Pos: 2},
+			{Id: "o1", Value: TrelloCustomFieldValue{Text: "Small"}, Pos: 1},
+		}},
+		{Id: "f-check", Name: "Blocked", Type: "checkbox"},
+	}
+	c := fake.start(t)
+	ctx := context.Background()
+
+	defs, err := c.GetFieldDefinitions(ctx, "l1")
+	if err != nil {
+		t.Fatalf("GetFieldDefinitions: %v", err)
+	}
+	want := []models.CustomFieldDefinition{
+		{ID: "f-list", Name: "Size", ClickUpType: "drop_down", Options: []models.CustomFieldOption{
+			{ID: "o1", Name: "Small", OrderIndex: 0},
+			{ID: "o2", Name: "Large", OrderIndex: 1},
+		}},
+		{ID: "f-check", Name: "Blocked", ClickUpType: "checkbox"},
+	}
+	if !reflect.DeepEqual(defs, want) {
+		t.Errorf("definitions = %+v, want %+v", defs, want)
+	}
+
+	destDefs, err := c.GetDestFieldDefinitions(ctx, "b1")
+	if err != nil {
+		t.Fatalf("GetDestFieldDefinitions: %v", err)
+	}
+	if got := destDefs[1].Options; len(got) != 2 || got[0].ID != "true" || got[1].ID != "false" {
+		t.Errorf("checkbox options = %v, want true/false pseudo-options", got)
+	}
+	if n := fake.count("GET /boards/b1/customFields"); n != 1 {
+		t.Errorf("custom fields fetched %d times, want 1", n)
+	}
+}
+
+func TestResolveLabelsCreatesMissingLabels(t *testing.T) {
+	fake := newFakeTrello()
+	fake.labels["b1"] = []TrelloLabel{{Id: "lb1", Name: "Bug"}, {Id: "lb2", Color: "green"}}
+	c := fake.start(t)
+	ctx := context.Background()
+
+	ids, err := c.resolveLabels(ctx, "b1", []string{"bug", "green", "Feature"})
+	if err != nil {
+		t.Fatalf("resolveLabels: %v", err)
+	}
+	if want := []string{"lb1", "lb2", "new-Feature"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+	if want := []CreateLabelRequest{{IdBoard: "b1", Name: "Feature"}}; !reflect.DeepEqual(fake.createdLabels, want) {
+		t.Errorf("created labels = %v, want %v", fake.createdLabels, want)
+	}
+
+	// A created label is reused without being created again.
+	ids, err = c.resolveLabels(ctx, "b1", []string{"feature"})
+	if err != nil {
+		t.Fatalf("resolveLabels: %v", err)
+	}
+	if want := []string{"new-Feature"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+	if len(fake.createdLabels) != 1 {
+		t.Errorf("created %d labels, want 1", len(fake.createdLabels))
+	}
+	if n := fake.count("GET /boards/b1/labels"); n != 1 {
+		t.Errorf("labels fetched %d times, want 1", n)
+	}
+
+	tags, err := c.ListTags(ctx, "b1")
+	if err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	if want := []string{"Bug", "green", "Feature"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %v, want %v", tags, want)
+	}
+}
+
+func TestCreateTask(t *testing.T) {
+	fake := newFakeTrello()
+	fake.lists["b1"] = []TrelloList{{Id: "l1", Name: "To Do"}}
+	fake.labels["b1"] = []TrelloLabel{{Id: "lb1", Name: "Bug"}}
+	fake.customFields["b1"] = []TrelloCustomField{
+		{Id: "f-list", Name: "Size", Type: "list"},
+		{Id: "f-number", Name: "Points", Type: "number"},
+		{Id: "f-check", Name: "Blocked", Type: "checkbox"},
+		{Id: "f-date", Name: "Launch", Type: "date"},
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	c := fake.start(t)
+
+	due := time.Date(2024, 3, 5, 17, 30, 0, 0, time.UTC)
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	created, err := c.CreateTask(context.Background(), "l1", "", models.Task{
+		Name:        "Ship it",
+		Description: "plain",
+		Status:      StatusCompleted,
+		Assignees:   []models.TaskAssignee{{ID: "m1"}},
+		DueDate:     &due,
+		DueHasTime:  true,
+		StartDate:   &start,
+		TimeZone:    "Europe/Berlin",
+		Tags:        []string{"Bug", "Docs"},
+		CustomFields: []models.TaskCustomField{
+			{FieldID: "f-list", Value: "o-medium"},
+			{FieldID: "f-number", Value: 3.0},
+			{FieldID: "f-check", Value: true},
+			{FieldID: "f-date", Value: "2024-04-01"},
+			{FieldID: "f-missing", Value: "x"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if created.Id != "card-new" || created.Status != StatusCompleted || !created.Completed {
+		t.Errorf("created = %+v", created)
+	}
+
+	if len(fake.createdCards) != 1 {
+		t.Fatalf("created %d cards, want 1", len(fake.createdCards))
+	}
+	want := CreateCardRequest{
+		IdList:      "l1",
+		Name:        "Ship it",
+		Desc:        "plain",
+		Due:         "2024-03-05T17:30:00Z",
+		Start:       time.Date(2024, 3, 1, 0, 0, 0, 0, berlin).UTC().Format(time.RFC3339),
+		DueComplete: true,
+		IdMembers:   []string{"m1"},
+		IdLabels:    []string{"lb1", "new-Docs"},
+	}
+	if !reflect.DeepEqual(fake.createdCards[0], want) {
+		t.Errorf("card request = %+v, want %+v", fake.createdCards[0], want)
+	}
+
+	wantUpdates := map[string]UpdateCustomFieldItemRequest{
+		"f-list":   {IdValue: "o-medium"},
+		"f-number": {Value: &TrelloCustomFieldValue{Number: "3"}},
+		"f-check":  {Value: &TrelloCustomFieldValue{Checked: "true"}},
+		"f-date":   {Value: &TrelloCustomFieldValue{Date: "2024-04-01T00:00:00Z"}},
+	}
+	if !reflect.DeepEqual(fake.fieldUpdates, wantUpdates) {
+		t.Errorf("custom field updates = %+v, want %+v", fake.fieldUpdates, wantUpdates)
+	}
+}
+
+func TestCreateTaskRemovesCardWhenCustomFieldsFail(t *testing.T) {
+	fake := newFakeTrello()
+	fake.lists["b1"] = []TrelloList{{Id: "l1", Name: "To Do"}}
+	fake.customFields["b1"] = []TrelloCustomField{{Id: "f-text", Name: "Notes", Type: "text"}}
+	fake.failFieldPut = true
+	c := fake.start(t)
+
+	_, err := c.CreateTask(context.Background(), "l1", "", models.Task{
+		Name:         "Ship it",
+		CustomFields: []models.TaskCustomField{{FieldID: "f-text", Value: "hello"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid value for custom field type") {
+		t.Fatalf("err = %v, want the custom field error", err)
+	}
+	if want := []string{"card-new"}; !reflect.DeepEqual(fake.deletedCards, want) {
+		t.Errorf("deleted cards = %v, want %v", fake.deletedCards, want)
+	}
+	// The list was not listed first, so its board was looked up.
+	if n := fake.count("GET /lists/l1"); n != 1 {
+		t.Errorf("list looked up %d times, want 1", n)
+	}
+}
+
+func TestDeleteTaskIgnoresMissingCards(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "The requested resource was not found.", http.StatusNotFound)
+	}))
+	defer srv.Close()
+	c := NewTrelloClientWithBaseURL(srv.URL, "key", "token")
+
+	if err := c.DeleteTask(context.Background(), "gone"); err != nil {
+		t.Errorf("DeleteTask: %v, want nil for a missing card", err)
+	}
+	if _, err := c.GetBoards(context.Background(), "org1"); err == nil || !strings.Contains(err.Error(), "The requested resource was not found.") {
+		t.Errorf("GetBoards err = %v, want Trello's message", err)
+	}
+}
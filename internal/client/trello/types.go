@@ -0,0 +1,131 @@
+package trello
+
+type TrelloOrganization struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type TrelloBoard struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	Closed bool   `json:"closed"`
+}
+
+type TrelloList struct {
+	Id      string  `json:"id"`
+	Name    string  `json:"name"`
+	Closed  bool    `json:"closed"`
+	IdBoard string  `json:"idBoard"`
+	Pos     float64 `json:"pos"`
+}
+
+type TrelloMember struct {
+	Id       string `json:"id"`
+	FullName string `json:"fullName"`
+	Username string `json:"username"`
+}
+
+type TrelloLabel struct {
+	Id      string `json:"id"`
+	Name    string `json:"name"`
+	Color   string `json:"color"`
+	IdBoard string `json:"idBoard"`
+}
+
+type TrelloCheckItem struct {
+	Id    string  `json:"id"`
+	Name  string  `json:"name"`
+	State string  `json:"state"` // "complete" or "incomplete"
+	Pos   float64 `json:"pos"`
+}
+
+type TrelloChecklist struct {
+	Id         string            `json:"id"`
+	Name       string            `json:"name"`
+	Pos        float64           `json:"pos"`
+	CheckItems []TrelloCheckItem `json:"checkItems"`
+}
+
+type TrelloCustomFieldValue struct {
+	Text    string `json:"text,omitempty"`
+	Number  string `json:"number,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Checked string `json:"checked,omitempty"`
+}
+
+type TrelloCustomFieldItem struct {
+	Id            string                  `json:"id"`
+	IdCustomField string                  `json:"idCustomField"`
+	IdValue       string                  `json:"idValue,omitempty"`
+	Value         *TrelloCustomFieldValue `json:"value,omitempty"`
+}
+
+type TrelloCustomFieldOption struct {
+	Id    string                 `json:"id"`
+	Value TrelloCustomFieldValue `json:"value"`
+	Pos   float64                `json:"pos"`
+}
+
+type TrelloCustomField struct {
+	Id      string                    `json:"id"`
+	Name    string                    `json:"name"`
+	Type    string                    `json:"type"` // text, number, date, checkbox, list
+	Options []TrelloCustomFieldOption `json:"options"`
+}
+
+type TrelloCard struct {
+	Id               string                  `json:"id"`
+	Name             string                  `json:"name"`
+	Desc             string                  `json:"desc"`
+	Closed           bool                    `json:"closed"`
+	DueComplete      bool                    `json:"dueComplete"`
+	Due              string                  `json:"due"`
+	Start            string                  `json:"start"`
+	IdBoard          string                  `json:"idBoard"`
+	IdList           string                  `json:"idList"`
+	IdMembers        []string                `json:"idMembers"`
+	Members          []TrelloMember          `json:"members"`
+	Labels           []TrelloLabel           `json:"labels"`
+	Checklists       []TrelloChecklist       `json:"checklists"`
+	CustomFieldItems []TrelloCustomFieldItem `json:"customFieldItems"`
+	ShortUrl         string                  `json:"shortUrl"`
+}
+
+type CreateCardRequest struct {
+	IdList      string   `json:"idList"`
+	Name        string   `json:"name"`
+	Desc        string   `json:"desc,omitempty"`
+	Due         string   `json:"due,omitempty"`
+	Start       string   `json:"start,omitempty"`
+	DueComplete bool     `json:"dueComplete,omitempty"`
+	IdMembers   []string `json:"idMembers,omitempty"`
+	IdLabels    []string `json:"idLabels,omitempty"`
+}
+
+type CreateLabelRequest struct {
+	IdBoard string `json:"idBoard"`
+	Name    string `json:"name"`
+	Color   string `json:"color,omitempty"`
+}
+
+type CreateChecklistRequest struct {
+	IdCard string `json:"idCard"`
+	Name   string `json:"name"`
+}
+
+type CreateCheckItemRequest struct {
+	Name    string `json:"name"`
+	Checked bool   `json:"checked"`
+}
+
+type CreateCommentRequest struct {
+	Text string `json:"text"`
+}
+
+// UpdateCustomFieldItemRequest sets a custom field on a card: either Value for text, number,
+// date and checkbox fields, or IdValue for list fields.
+type UpdateCustomFieldItemRequest struct {
+	Value   *TrelloCustomFieldValue `json:"value,omitempty"`
+	IdValue string                  `json:"idValue,omitempty"`
+}
@@ -0,0 +1,492 @@
+package converter
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ADFNode is a node of an Atlassian Document Format document, the rich-text format of
+// Jira descriptions and comments.
+type ADFNode struct {
+	Version int            `json:"version,omitempty"` // set on the doc node only
+	Type    string         `json:"type"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Content []*ADFNode     `json:"content,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Marks   []ADFMark      `json:"marks,omitempty"`
+}
+
+type ADFMark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+func (n *ADFNode) attr(name string) string {
+	switch v := n.Attrs[name].(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+// ---- ADF → markdown ----
+
+// ADFToMarkdown converts an ADF document into markdown. Mentions become mention links
+// (see MentionLink); media and other unsupported nodes are dropped.
+func ADFToMarkdown(doc *ADFNode) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimSpace(adfBlocks(doc.Content, ""))
+}
+
+func adfBlocks(nodes []*ADFNode, indent string) string {
+	var parts []string
+	for _, n := range nodes {
+		if s := adfBlock(n, indent); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func adfBlock(n *ADFNode, indent string) string {
+	switch n.Type {
+	case "paragraph":
+		return indentLines(adfInline(n.Content), indent)
+	case "heading":
+		level, _ := strconv.Atoi(n.attr("level"))
+		level = min(max(level, 1), 6)
+		return indent + strings.Repeat("#", level) + " " + adfInline(n.Content)
+	case "bulletList", "orderedList", "taskList":
+		return adfList(n, indent)
+	case "codeBlock":
+		var code strings.Builder
+		for _, c := range n.Content {
+			code.WriteString(c.Text)
+		}
+		return indentLines("```"+n.attr("language")+"\n"+code.String()+"\n```", indent)
+	case "blockquote", "panel":
+		inner := adfBlocks(n.Content, "")
+		var lines []string
+		for _, line := range strings.Split(inner, "\n") {
+			lines = append(lines, strings.TrimRight("> "+line, " "))
+		}
+		return indentLines(strings.Join(lines, "\n"), indent)
+	case "rule":
+		return indent + "---"
+	case "table":
+		var rows []string
+		for _, row := range n.Content {
+			var cells []string
+			for _, cell := range row.Content {
+				cells = append(cells, strings.ReplaceAll(adfBlocks(cell.Content, ""), "\n", " "))
+			}
+			rows = append(rows, "| "+strings.Join(cells, " | ")+" |")
+		}
+		return indentLines(strings.Join(rows, "\n"), indent)
+	}
+	if len(n.Content) > 0 {
+		return adfBlocks(n.Content, indent)
+	}
+	return ""
+}
+
+func adfList(n *ADFNode, indent string) string {
+	var lines []string
+	for i, item := range n.Content {
+		marker := "- "
+		switch n.Type {
+		case "orderedList":
+			start := 1
+			if s, err := strconv.Atoi(n.attr("order")); err == nil {
+				start = s
+			}
+			marker = strconv.Itoa(start+i) + ". "
+		case "taskList":
+			marker = "- [ ] "
+			if item.attr("state") == "DONE" {
+				marker = "- [x] "
+			}
+		}
+
+		// Task items hold inline content directly; list items hold blocks, the first of
+		// which goes on the marker line.
+		if item.Type == "taskItem" {
+			lines = append(lines, indent+marker+adfInline(item.Content))
+			continue
+		}
+		first := true
+		for _, child := range item.Content {
+			switch {
+			case first && child.Type == "paragraph":
+				lines = append(lines, indent+marker+adfInline(child.Content))
+			case child.Type == "bulletList" || child.Type == "orderedList" || child.Type == "taskList":
+				if first {
+					lines = append(lines, indent+marker)
+				}
+				lines = append(lines, adfList(child, indent+"  "))
+			default:
+				if first {
+					lines = append(lines, indent+marker)
+				}
+				lines = append(lines, adfBlock(child, indent+"  "))
+			}
+			first = false
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func adfInline(nodes []*ADFNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(adfMarked(n))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			b.WriteString(MentionLink(n.attr("id"), n.attr("text")))
+		case "emoji":
+			b.WriteString(n.attr("text"))
+		case "inlineCard":
+			b.WriteString(n.attr("url"))
+		case "date":
+			if ms, err := strconv.ParseInt(n.attr("timestamp"), 10, 64); err == nil {
+				b.WriteString(unixMilliDate(ms))
+			}
+		default:
+			b.WriteString(adfInline(n.Content))
+		}
+	}
+	return b.String()
+}
+
+func adfMarked(n *ADFNode) string {
+	var code bool
+	var href string
+	var wrap []string
+	for _, m := range n.Marks {
+		switch m.Type {
+		case "code":
+			code = true
+		case "strong":
+			wrap = append(wrap, "**")
+		case "em":
+			wrap = append(wrap, "*")
+		case "strike":
+			wrap = append(wrap, "~~")
+		case "link":
+			if h, ok := m.Attrs["href"].(string); ok {
+				href = h
+			}
+		}
+	}
+
+	s := markdownEscaper.Replace(n.Text)
+	if code {
+		s = "`" + n.Text + "`"
+	}
+	for _, w := range wrap {
+		s = w + s + w
+	}
+	if href != "" {
+		s = "[" + s + "](" + href + ")"
+	}
+	return s
+}
+
+func unixMilliDate(ms int64) string {
+	return time.UnixMilli(ms).UTC().Format("2006-01-02")
+}
+
+func indentLines(s, indent string) string {
+	if indent == "" {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		lines[i] = indent + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ADFToText returns the plain text of an ADF document, one line per block.
+// Mentions are written as their display text.
+func ADFToText(doc *ADFNode) string {
+	if doc == nil {
+		return ""
+	}
+	var lines []string
+	var walk func(nodes []*ADFNode, prefix string)
+	walk = func(nodes []*ADFNode, prefix string) {
+		for _, n := range nodes {
+			switch n.Type {
+			case "paragraph", "heading", "taskItem":
+				lines = append(lines, prefix+adfPlainInline(n.Content))
+			case "codeBlock":
+				lines = append(lines, adfPlainInline(n.Content))
+			case "listItem":
+				walk(n.Content, prefix+"- ")
+			case "rule":
+				lines = append(lines, "---")
+			default:
+				walk(n.Content, prefix)
+			}
+			if strings.HasSuffix(prefix, "- ") {
+				prefix = strings.Repeat(" ", len(prefix))
+			}
+		}
+	}
+	walk(doc.Content, "")
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func adfPlainInline(nodes []*ADFNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(n.Text)
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention", "emoji":
+			b.WriteString(n.attr("text"))
+		case "inlineCard":
+			b.WriteString(n.attr("url"))
+		default:
+			b.WriteString(adfPlainInline(n.Content))
+		}
+	}
+	return b.String()
+}
+
+// ---- markdown → ADF ----
+
+// MarkdownToADF converts markdown into an ADF document. Mention links become ADF mentions
+// of the linked account ID.
+func MarkdownToADF(md string) *ADFNode {
+	b := &adfBuilder{doc: &ADFNode{Version: 1, Type: "doc"}, mentionNames: make(map[string]string)}
+	for _, m := range Mentions(md) {
+		b.mentionNames[m.ID] = m.Name
+	}
+	b.stack = []*ADFNode{b.doc}
+	if err := b.build(renderBlocks(md)); err != nil {
+		// renderBlocks always produces well-formed markup; fall back to plain text regardless.
+		return TextToADF(md)
+	}
+	return b.doc
+}
+
+// TextToADF wraps plain text into an ADF document, one paragraph per blank-line separated block.
+func TextToADF(text string) *ADFNode {
+	doc := &ADFNode{Version: 1, Type: "doc"}
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if strings.TrimSpace(para) == "" {
+			continue
+		}
+		p := &ADFNode{Type: "paragraph"}
+		for i, line := range strings.Split(para, "\n") {
+			if i > 0 {
+				p.Content = append(p.Content, &ADFNode{Type: "hardBreak"})
+			}
+			if line != "" {
+				p.Content = append(p.Content, &ADFNode{Type: "text", Text: line})
+			}
+		}
+		doc.Content = append(doc.Content, p)
+	}
+	return doc
+}
+
+// adfBuilder turns the HTML rendered by renderBlocks into ADF. Block elements map onto
+// block nodes; text outside any block is collected into paragraphs, where a single newline
+// is a hard break and a blank line starts a new paragraph.
+type adfBuilder struct {
+	doc      *ADFNode
+	stack    []*ADFNode // open block nodes
+	marks    []ADFMark
+	para     *ADFNode
+	newlines int
+	inPre    bool
+
+	mentionNames map[string]string // account ID → name, the markup only carries the ID
+}
+
+func (b *adfBuilder) top() *ADFNode {
+	return b.stack[len(b.stack)-1]
+}
+
+func (b *adfBuilder) openBlock(n *ADFNode) {
+	b.closePara()
+	b.top().Content = append(b.top().Content, n)
+	b.stack = append(b.stack, n)
+}
+
+func (b *adfBuilder) closeBlock(nodeType string) {
+	b.closePara()
+	for i := len(b.stack) - 1; i > 0; i-- {
+		if b.stack[i].Type == nodeType {
+			b.stack = b.stack[:i]
+			return
+		}
+	}
+}
+
+func (b *adfBuilder) closePara() {
+	b.para = nil
+	b.newlines = 0
+}
+
+// inline returns the node inline content is added to, opening a paragraph when the
+// current block only holds blocks.
+func (b *adfBuilder) inline() *ADFNode {
+	top := b.top()
+	if top.Type == "heading" || top.Type == "codeBlock" {
+		return top
+	}
+	if b.para == nil {
+		b.para = &ADFNode{Type: "paragraph"}
+		top.Content = append(top.Content, b.para)
+	} else if b.newlines > 0 {
+		b.para.Content = append(b.para.Content, &ADFNode{Type: "hardBreak"})
+	}
+	b.newlines = 0
+	return b.para
+}
+
+func (b *adfBuilder) build(markup string) error {
+	dec := xml.NewDecoder(strings.NewReader("<body>" + markup + "</body>"))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("parse markup: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			b.start(t)
+		case xml.EndElement:
+			b.end(t.Name.Local)
+		case xml.CharData:
+			b.text(string(t))
+		}
+	}
+}
+
+func (b *adfBuilder) start(t xml.StartElement) {
+	switch t.Name.Local {
+	case "h1", "h2":
+		level := 1
+		if t.Name.Local == "h2" {
+			level = 2
+		}
+		b.openBlock(&ADFNode{Type: "heading", Attrs: map[string]any{"level": level}})
+	case "ul":
+		b.openBlock(&ADFNode{Type: "bulletList"})
+	case "ol":
+		b.openBlock(&ADFNode{Type: "orderedList"})
+	case "li":
+		b.openBlock(&ADFNode{Type: "listItem"})
+	case "blockquote":
+		b.openBlock(&ADFNode{Type: "blockquote"})
+	case "pre":
+		b.openBlock(&ADFNode{Type: "codeBlock"})
+		b.inPre = true
+	case "hr":
+		b.closePara()
+		b.top().Content = append(b.top().Content, &ADFNode{Type: "rule"})
+	case "strong":
+		b.marks = append(b.marks, ADFMark{Type: "strong"})
+	case "em":
+		b.marks = append(b.marks, ADFMark{Type: "em"})
+	case "s":
+		b.marks = append(b.marks, ADFMark{Type: "strike"})
+	case "code":
+		b.marks = append(b.marks, ADFMark{Type: "code"})
+	case "a":
+		if gid := attr(t, "data-asana-gid"); gid != "" {
+			parent := b.inline()
+			mention := &ADFNode{Type: "mention", Attrs: map[string]any{"id": gid}}
+			if name := b.mentionNames[gid]; name != "" {
+				mention.Attrs["text"] = "@" + name
+			}
+			parent.Content = append(parent.Content, mention)
+			b.marks = append(b.marks, ADFMark{Type: "mention"})
+			return
+		}
+		b.marks = append(b.marks, ADFMark{Type: "link", Attrs: map[string]any{"href": attr(t, "href")}})
+	}
+}
+
+func (b *adfBuilder) end(name string) {
+	switch name {
+	case "h1", "h2":
+		b.closeBlock("heading")
+	case "ul":
+		b.closeBlock("bulletList")
+	case "ol":
+		b.closeBlock("orderedList")
+	case "li":
+		b.closeBlock("listItem")
+	case "blockquote":
+		b.closeBlock("blockquote")
+	case "pre":
+		b.closeBlock("codeBlock")
+		b.inPre = false
+	case "strong", "em", "s", "code", "a":
+		if len(b.marks) > 0 {
+			b.marks = b.marks[:len(b.marks)-1]
+		}
+	}
+}
+
+func (b *adfBuilder) text(s string) {
+	if s == "" {
+		return
+	}
+	if b.inPre {
+		b.top().Content = append(b.top().Content, &ADFNode{Type: "text", Text: s})
+		return
+	}
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			b.newlines++
+			if b.newlines >= 2 {
+				b.closePara()
+			}
+		}
+		if line == "" {
+			continue
+		}
+		b.addText(line)
+	}
+}
+
+func (b *adfBuilder) addText(s string) {
+	var marks []ADFMark
+	for _, m := range b.marks {
+		if m.Type == "mention" {
+			return // mention text is carried by the mention node itself
+		}
+		marks = append(marks, m)
+	}
+	parent := b.inline()
+	parent.Content = append(parent.Content, &ADFNode{Type: "text", Text: s, Marks: marks})
+}
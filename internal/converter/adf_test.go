@@ -0,0 +1,120 @@
+package converter
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Markdown written to Jira and read back, in memory or through the JSON Jira stores,
+// comes out unchanged.
+func TestADFRoundTrip(t *testing.T) {
+	for _, md := range []string{
+		"# Title\n\nSome **bold**, *em* and ~~gone~~ text.",
+		"## Sub\n\nA [link](https://example.com) and `code`.",
+		"- one\n- two\n  - nested\n- three",
+		"1. first\n2. second",
+		"> quoted\n> more",
+		"```\nfunc main() {}\n```",
+		"line one\nline two\n\nnext para",
+		"Ping [@Ann Lee](mention:user/acc-1) please",
+		`Escaped \*stars\* and \_under\_`,
+		"above\n\n---\n\nbelow",
+	} {
+		doc := MarkdownToADF(md)
+		if got := ADFToMarkdown(doc); got != md {
+			t.Errorf("in memory: %q came back as %q", md, got)
+		}
+
+		b, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatalf("marshal %q: %v", md, err)
+		}
+		var stored ADFNode
+		if err := json.Unmarshal(b, &stored); err != nil {
+			t.Fatalf("unmarshal %q: %v", md, err)
+		}
+		if got := ADFToMarkdown(&stored); got != md {
+			t.Errorf("through JSON: %q came back as %q", md, got)
+		}
+	}
+}
+
+func TestMarkdownToADFMentions(t *testing.T) {
+	doc := MarkdownToADF("Ping [@Ann Lee](mention:user/acc-1)")
+	para := doc.Content[0]
+	if len(para.Content) != 2 {
+		t.Fatalf("paragraph content = %+v, want text and mention", para.Content)
+	}
+	mention := para.Content[1]
+	if mention.Type != "mention" || mention.attr("id") != "acc-1" || mention.attr("text") != "@Ann Lee" {
+		t.Errorf("mention = %+v, want account acc-1 shown as @Ann Lee", mention)
+	}
+}
+
+// Jira documents use nodes the markdown writer never produces; they are converted as far as
+// markdown can express them.
+func TestADFToMarkdownFromJira(t *testing.T) {
+	const doc = `{"version":1,"type":"doc","content":[
+		{"type":"heading","attrs":{"level":3},"content":[{"type":"text","text":"Plan"}]},
+		{"type":"taskList","content":[
+			{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"spec"}]},
+			{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"build"}]}
+		]},
+		{"type":"orderedList","attrs":{"order":3},"content":[
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"third"}]}]}
+		]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Due "},
+			{"type":"date","attrs":{"timestamp":"1717200000000"}},
+			{"type":"text","text":" by "},
+			{"type":"mention","attrs":{"id":"acc-2","text":"@Bob"}},
+			{"type":"text","text":" "},
+			{"type":"emoji","attrs":{"shortName":":tada:","text":"🎉"}},
+			{"type":"hardBreak"},
+			{"type":"inlineCard","attrs":{"url":"https://example.com/x"}}
+		]},
+		{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"x := 1"}]},
+		{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph","content":[{"type":"text","text":"Note"}]}]},
+		{"type":"table","content":[
+			{"type":"tableRow","content":[
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]}]},
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"b"}]}]}
+			]}
+		]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"id":"m1"}}]}
+	]}`
+	var n ADFNode
+	if err := json.Unmarshal([]byte(doc), &n); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	want := "### Plan\n\n" +
+		"- [x] spec\n- [ ] build\n\n" +
+		"3. third\n\n" +
+		"Due 2024-06-01 by [@Bob](mention:user/acc-2) 🎉\nhttps://example.com/x\n\n" +
+		"```go\nx := 1\n```\n\n" +
+		"> Note\n\n" +
+		"| a | b |"
+	if got := ADFToMarkdown(&n); got != want {
+		t.Errorf("markdown =\n%s\nwant\n%s", got, want)
+	}
+
+	wantText := "Plan\nspec\nbuild\n- third\nDue  by @Bob 🎉\nhttps://example.com/x\nx := 1\nNote\na\nb"
+	if got := ADFToText(&n); got != wantText {
+		t.Errorf("text = %q, want %q", got, wantText)
+	}
+}
+
+func TestTextToADF(t *testing.T) {
+	doc := TextToADF("first\r\nsecond\n\n\n\nthird")
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `{"version":1,"type":"doc","content":[` +
+		`{"type":"paragraph","content":[{"type":"text","text":"first"},{"type":"hardBreak"},{"type":"text","text":"second"}]},` +
+		`{"type":"paragraph","content":[{"type":"text","text":"third"}]}]}`
+	if string(b) != want {
+		t.Errorf("doc = %s\nwant %s", b, want)
+	}
+}
@@ -0,0 +1,28 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// ChecklistsMarkdown renders checklists as markdown task lists, each under its name in bold.
+func ChecklistsMarkdown(checklists []models.Checklist) string {
+	var b strings.Builder
+	for i, cl := range checklists {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if cl.Name != "" {
+			b.WriteString("**" + markdownEscaper.Replace(cl.Name) + "**\n")
+		}
+		for _, item := range cl.Items {
+			box := "[ ]"
+			if item.Checked {
+				box = "[x]"
+			}
+			b.WriteString("- " + box + " " + markdownEscaper.Replace(item.Name) + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
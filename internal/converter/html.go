@@ -0,0 +1,227 @@
+package converter
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+	"~", `\~`,
+)
+
+type htmlList struct {
+	ordered bool
+	index   int
+}
+
+// htmlConverter walks Asana rich text and writes markdown. Elements whose rendering depends
+// on their whole content (links, blockquotes) are written into a nested buffer first.
+type htmlConverter struct {
+	buffers   []*strings.Builder
+	lists     []htmlList
+	inPre     int
+	linkHrefs []string // "" for plain links without href, mentionScheme+gid for mentions
+}
+
+func (c *htmlConverter) out() *strings.Builder {
+	return c.buffers[len(c.buffers)-1]
+}
+
+func (c *htmlConverter) push() {
+	c.buffers = append(c.buffers, &strings.Builder{})
+}
+
+func (c *htmlConverter) pop() string {
+	b := c.out()
+	c.buffers = c.buffers[:len(c.buffers)-1]
+	return b.String()
+}
+
+func (c *htmlConverter) ensureLineStart() {
+	s := c.out().String()
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		c.out().WriteString("\n")
+	}
+}
+
+// AsanaHTMLToMarkdown converts Asana html_notes into markdown. User mentions become
+// mention links (see MentionLink); unsupported elements keep only their text.
+func AsanaHTMLToMarkdown(htmlNotes string) (string, error) {
+	dec := xml.NewDecoder(strings.NewReader(htmlNotes))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	c := &htmlConverter{}
+	c.push()
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("parse html notes: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			c.start(t)
+		case xml.EndElement:
+			c.end(t.Name.Local)
+		case xml.CharData:
+			c.text(string(t))
+		}
+	}
+
+	for len(c.buffers) > 1 {
+		text := c.pop()
+		c.out().WriteString(text)
+	}
+	return strings.TrimSpace(c.out().String()), nil
+}
+
+func (c *htmlConverter) start(t xml.StartElement) {
+	switch strings.ToLower(t.Name.Local) {
+	case "strong", "b":
+		c.out().WriteString("**")
+	case "em", "i":
+		c.out().WriteString("*")
+	case "s", "del", "strike":
+		c.out().WriteString("~~")
+	case "code":
+		if c.inPre == 0 {
+			c.out().WriteString("`")
+		}
+	case "pre":
+		c.ensureLineStart()
+		c.out().WriteString("```\n")
+		c.inPre++
+	case "h1":
+		c.ensureLineStart()
+		c.out().WriteString("# ")
+	case "h2", "h3", "h4", "h5", "h6":
+		c.ensureLineStart()
+		c.out().WriteString("## ")
+	case "ul", "ol":
+		c.ensureLineStart()
+		c.lists = append(c.lists, htmlList{ordered: strings.EqualFold(t.Name.Local, "ol")})
+	case "li":
+		c.ensureLineStart()
+		if len(c.lists) == 0 {
+			c.out().WriteString("- ")
+			return
+		}
+		c.out().WriteString(strings.Repeat("  ", len(c.lists)-1))
+		l := &c.lists[len(c.lists)-1]
+		l.index++
+		if l.ordered {
+			c.out().WriteString(strconv.Itoa(l.index) + ". ")
+		} else {
+			c.out().WriteString("- ")
+		}
+	case "blockquote":
+		c.ensureLineStart()
+		c.push()
+	case "hr":
+		c.ensureLineStart()
+		c.out().WriteString("---\n")
+	case "br":
+		c.out().WriteString("\n")
+	case "a":
+		// Mentions read from Asana carry data-asana-type="user"; the short form written by
+		// MarkdownToAsanaHTML only has the gid.
+		href := attr(t, "href")
+		gid, resourceType := attr(t, "data-asana-gid"), attr(t, "data-asana-type")
+		if gid != "" && (strings.EqualFold(resourceType, "user") || (resourceType == "" && href == "")) {
+			href = mentionScheme + gid
+		}
+		c.linkHrefs = append(c.linkHrefs, href)
+		c.push()
+	}
+}
+
+func (c *htmlConverter) end(name string) {
+	switch strings.ToLower(name) {
+	case "strong", "b":
+		c.out().WriteString("**")
+	case "em", "i":
+		c.out().WriteString("*")
+	case "s", "del", "strike":
+		c.out().WriteString("~~")
+	case "code":
+		if c.inPre == 0 {
+			c.out().WriteString("`")
+		}
+	case "pre":
+		if c.inPre > 0 {
+			c.inPre--
+		}
+		c.ensureLineStart()
+		c.out().WriteString("```\n")
+	case "h1", "h2", "h3", "h4", "h5", "h6", "li":
+		c.ensureLineStart()
+	case "ul", "ol":
+		if len(c.lists) > 0 {
+			c.lists = c.lists[:len(c.lists)-1]
+		}
+		c.ensureLineStart()
+	case "blockquote":
+		if len(c.buffers) < 2 {
+			return
+		}
+		inner := strings.TrimSpace(c.pop())
+		for _, line := range strings.Split(inner, "\n") {
+			c.out().WriteString(strings.TrimRight("> "+line, " ") + "\n")
+		}
+	case "a":
+		if len(c.linkHrefs) == 0 || len(c.buffers) < 2 {
+			return
+		}
+		href := c.linkHrefs[len(c.linkHrefs)-1]
+		c.linkHrefs = c.linkHrefs[:len(c.linkHrefs)-1]
+		text := c.pop()
+		switch {
+		case strings.HasPrefix(href, mentionScheme):
+			c.out().WriteString(MentionLink(strings.TrimPrefix(href, mentionScheme), text))
+		case href == "":
+			c.out().WriteString(text)
+		default:
+			if text == "" {
+				text = markdownEscaper.Replace(href)
+			}
+			c.out().WriteString("[" + text + "](" + href + ")")
+		}
+	}
+}
+
+func (c *htmlConverter) text(s string) {
+	if c.inPre > 0 {
+		c.out().WriteString(s)
+		return
+	}
+	// Whitespace between list items is markup formatting, not content.
+	if len(c.lists) > 0 && strings.TrimSpace(s) == "" {
+		return
+	}
+	c.out().WriteString(markdownEscaper.Replace(s))
+}
+
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if strings.EqualFold(a.Name.Local, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
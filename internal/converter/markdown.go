@@ -0,0 +1,247 @@
+package converter
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	hrPattern       = regexp.MustCompile(`^\s{0,3}([-*_])(\s*([-*_])){2,}\s*$`)
+	listItemPattern = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	quotePattern    = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	fencePattern    = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+)
+
+type mdList struct {
+	ordered bool
+	indent  int
+}
+
+// MarkdownToAsanaHTML renders markdown into the rich-text subset accepted by Asana html_notes.
+// Mention links become Asana mentions; anything else unsupported is kept as escaped text.
+func MarkdownToAsanaHTML(md string) string {
+	return "<body>" + renderBlocks(md) + "</body>"
+}
+
+func renderBlocks(md string) string {
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+
+	var out strings.Builder
+	var lists []mdList
+
+	closeLists := func(toIndent int) {
+		for len(lists) > 0 && lists[len(lists)-1].indent > toIndent {
+			if lists[len(lists)-1].ordered {
+				out.WriteString("</li></ol>")
+			} else {
+				out.WriteString("</li></ul>")
+			}
+			lists = lists[:len(lists)-1]
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			closeLists(-1)
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), m[1]); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString("<pre>" + html.EscapeString(strings.Join(code, "\n")) + "</pre>")
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			closeLists(-1)
+			out.WriteString("\n")
+			continue
+		}
+
+		if m := listItemPattern.FindStringSubmatch(line); m != nil && !hrPattern.MatchString(line) {
+			indent := len(strings.ReplaceAll(m[1], "\t", "    "))
+			ordered := !strings.ContainsAny(m[2][:1], "-*+")
+			closeLists(indent)
+			top := len(lists) - 1
+			switch {
+			case top >= 0 && lists[top].indent == indent && lists[top].ordered == ordered:
+				out.WriteString("</li>")
+			case top >= 0 && lists[top].indent == indent:
+				closeLists(indent - 1)
+				fallthrough
+			default:
+				lists = append(lists, mdList{ordered: ordered, indent: indent})
+				if ordered {
+					out.WriteString("<ol>")
+				} else {
+					out.WriteString("<ul>")
+				}
+			}
+			out.WriteString("<li>" + renderInline(m[3]))
+			continue
+		}
+		closeLists(-1)
+
+		if hrPattern.MatchString(line) {
+			out.WriteString("<hr/>")
+			continue
+		}
+
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			tag := "h2"
+			if len(m[1]) == 1 {
+				tag = "h1"
+			}
+			out.WriteString("<" + tag + ">" + renderInline(m[2]) + "</" + tag + ">")
+			continue
+		}
+
+		if quotePattern.MatchString(line) {
+			var quoted []string
+			for ; i < len(lines); i++ {
+				m := quotePattern.FindStringSubmatch(lines[i])
+				if m == nil {
+					break
+				}
+				quoted = append(quoted, m[1])
+			}
+			i--
+			out.WriteString("<blockquote>" + strings.Trim(renderBlocks(strings.Join(quoted, "\n")), "\n") + "</blockquote>")
+			continue
+		}
+
+		out.WriteString(renderInline(line) + "\n")
+	}
+	closeLists(-1)
+
+	return strings.TrimRight(out.String(), "\n")
+}
+
+// renderInline renders emphasis, code spans, strikethrough and links of a single line.
+// Tags are kept properly nested so the result is always well-formed.
+func renderInline(s string) string {
+	var out strings.Builder
+	var open []string
+	runes := []rune(s)
+
+	toggle := func(tag string) {
+		idx := -1
+		for j := len(open) - 1; j >= 0; j-- {
+			if open[j] == tag {
+				idx = j
+				break
+			}
+		}
+		if idx == -1 {
+			out.WriteString("<" + tag + ">")
+			open = append(open, tag)
+			return
+		}
+		reopen := open[idx+1:]
+		for j := len(open) - 1; j >= idx; j-- {
+			out.WriteString("</" + open[j] + ">")
+		}
+		open = append(open[:idx], reopen...)
+		for _, t := range reopen {
+			out.WriteString("<" + t + ">")
+		}
+	}
+	isOpen := func(tag string) bool {
+		for _, t := range open {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	}
+	wordChar := func(j int) bool {
+		return j >= 0 && j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]))
+	}
+	hasPrefix := func(j int, p string) bool {
+		return strings.HasPrefix(string(runes[j:]), p)
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes) && strings.ContainsRune("\\`*_{}[]()#+-.!~>|", runes[i+1]):
+			out.WriteString(html.EscapeString(string(runes[i+1])))
+			i++
+		case r == '`':
+			end := indexRune(runes, i+1, '`')
+			if end == -1 {
+				out.WriteString("`")
+				continue
+			}
+			out.WriteString("<code>" + html.EscapeString(string(runes[i+1:end])) + "</code>")
+			i = end
+		case hasPrefix(i, "**") || hasPrefix(i, "__"):
+			toggle("strong")
+			i++
+		case hasPrefix(i, "~~"):
+			toggle("s")
+			i++
+		case r == '*':
+			toggle("em")
+		case r == '_' && ((!isOpen("em") && !wordChar(i-1)) || (isOpen("em") && !wordChar(i+1))):
+			toggle("em")
+		case r == '[':
+			text, target, width, ok := parseLink(runes[i:])
+			if !ok {
+				out.WriteString("[")
+				continue
+			}
+			if strings.HasPrefix(target, mentionScheme) {
+				out.WriteString(`<a data-asana-gid="` + html.EscapeString(strings.TrimPrefix(target, mentionScheme)) + `"/>`)
+			} else {
+				out.WriteString(`<a href="` + html.EscapeString(target) + `">` + renderInline(text) + "</a>")
+			}
+			i += width - 1
+		default:
+			out.WriteString(html.EscapeString(string(r)))
+		}
+	}
+
+	for j := len(open) - 1; j >= 0; j-- {
+		out.WriteString("</" + open[j] + ">")
+	}
+	return out.String()
+}
+
+// parseLink parses "[text](target)" at the start of runes and returns the number of runes consumed.
+func parseLink(runes []rune) (text, target string, width int, ok bool) {
+	depth := 0
+	for i, r := range runes {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth != 0 {
+				continue
+			}
+			if i+1 >= len(runes) || runes[i+1] != '(' {
+				return "", "", 0, false
+			}
+			end := indexRune(runes, i+2, ')')
+			if end == -1 {
+				return "", "", 0, false
+			}
+			return string(runes[1:i]), strings.TrimSpace(string(runes[i+2 : end])), end + 1, true
+		}
+	}
+	return "", "", 0, false
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
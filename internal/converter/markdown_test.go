@@ -0,0 +1,74 @@
+package converter
+
+import "testing"
+
+func TestMarkdownToAsanaHTML(t *testing.T) {
+	for _, tc := range []struct {
+		name, md, want string
+	}{
+		{"bullet list", "- one\n- two", "<ul><li>one</li><li>two</li></ul>"},
+		{"ordered list", "1. first\n2) second", "<ol><li>first</li><li>second</li></ol>"},
+		{"nested list", "- one\n  - nested\n- two", "<ul><li>one<ul><li>nested</li></ul></li><li>two</li></ul>"},
+		{"link", "A [link](https://example.com/a?b=1&c=2) here", `A <a href="https://example.com/a?b=1&amp;c=2">link</a> here`},
+		{"bold and italic", "**bold**, __strong__, *em* and _under_", "<strong>bold</strong>, <strong>strong</strong>, <em>em</em> and <em>under</em>"},
+		{"strikethrough", "~~gone~~", "<s>gone</s>"},
+		{"inline code", "Use `x < y` and `**raw**`", "Use <code>x &lt; y</code> and <code>**raw**</code>"},
+		{"code fence", "```go\nif a < b {\n}\n```", "<pre>if a &lt; b {\n}</pre>"},
+		{"escaped markdown", `Escaped \*stars\* and \_under\_ and \[brackets\]`, "Escaped *stars* and _under_ and [brackets]"},
+		{"html special characters", `Tom & Jerry <script>"`, "Tom &amp; Jerry &lt;script&gt;&#34;"},
+		{"heading", "# Title\n\nBody", "<h1>Title</h1>\nBody"},
+		{"blockquote", "> quote", "<blockquote>quote</blockquote>"},
+		{"mention", "Ping [@Ann Lee](mention:user/123)", `Ping <a data-asana-gid="123"/>`},
+	} {
+		if got := MarkdownToAsanaHTML(tc.md); got != "<body>"+tc.want+"</body>" {
+			t.Errorf("%s: MarkdownToAsanaHTML(%q) = %q, want %q", tc.name, tc.md, got, "<body>"+tc.want+"</body>")
+		}
+	}
+}
+
+// Markdown written to Asana and read back comes out unchanged.
+func TestAsanaHTMLRoundTrip(t *testing.T) {
+	for _, md := range []string{
+		"- one\n- two",
+		"1. first\n2. second",
+		"- one\n  - nested\n- two",
+		"A [link](https://example.com/a?b=1&c=2) here",
+		"**bold** and *em* and ~~gone~~",
+		"Use `x < y` inline",
+		"```\nif a < b {\n}\n```",
+		`Escaped \*stars\* and \_under\_ and \[brackets\]`,
+		"Tom & Jerry <script>",
+		"# Title\n\nBody",
+		"## Sub",
+		"> quote",
+		"line one\nline two\n\nnext",
+	} {
+		got, err := AsanaHTMLToMarkdown(MarkdownToAsanaHTML(md))
+		if err != nil {
+			t.Errorf("AsanaHTMLToMarkdown(%q): %v", md, err)
+			continue
+		}
+		if got != md {
+			t.Errorf("%q came back as %q", md, got)
+		}
+	}
+}
+
+// Asana fills in the name of the mentions it returns; links to other Asana resources stay links.
+func TestAsanaHTMLToMarkdownMentions(t *testing.T) {
+	for _, tc := range []struct {
+		html, want string
+	}{
+		{`<body>Ping <a data-asana-gid="123" data-asana-type="user" data-asana-accessible="true">@Ann Lee</a></body>`, "Ping [@Ann Lee](mention:user/123)"},
+		{`<body>See <a data-asana-gid="456" data-asana-type="task" href="https://app.asana.com/0/0/456">Spec</a></body>`, "See [Spec](https://app.asana.com/0/0/456)"},
+	} {
+		got, err := AsanaHTMLToMarkdown(tc.html)
+		if err != nil {
+			t.Errorf("AsanaHTMLToMarkdown(%q): %v", tc.html, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("AsanaHTMLToMarkdown(%q) = %q, want %q", tc.html, got, tc.want)
+		}
+	}
+}
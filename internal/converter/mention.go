@@ -0,0 +1,58 @@
+// Package converter translates task descriptions between Asana's rich-text HTML (html_notes)
+// and markdown, the format rich descriptions are carried in between clients.
+package converter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// User mentions are carried inside markdown as links with a mention:user/<id> target,
+// e.g. [@Ana Souza](mention:user/1203). The ID belongs to the side the text was read from
+// until it is rewritten with RewriteMentions.
+const mentionScheme = "mention:user/"
+
+var mentionPattern = regexp.MustCompile(`\[@([^\]]*)\]\(mention:user/([^)\s]+)\)`)
+
+type Mention struct {
+	ID   string
+	Name string
+}
+
+// MentionLink returns the markdown form of a mention of the given user.
+func MentionLink(id, name string) string {
+	name = strings.NewReplacer("[", "", "]", "").Replace(strings.TrimPrefix(name, "@"))
+	return "[@" + name + "](" + mentionScheme + id + ")"
+}
+
+// Mentions returns the distinct users mentioned in the markdown, in order of appearance.
+func Mentions(md string) []Mention {
+	seen := make(map[string]bool)
+	var result []Mention
+	for _, m := range mentionPattern.FindAllStringSubmatch(md, -1) {
+		if seen[m[2]] {
+			continue
+		}
+		seen[m[2]] = true
+		result = append(result, Mention{ID: m[2], Name: m[1]})
+	}
+	return result
+}
+
+// RewriteMentions replaces the user ID of every mention with the one returned by resolve.
+// Mentions that cannot be resolved are turned into plain "@Name" text.
+func RewriteMentions(md string, resolve func(id string) (string, bool)) string {
+	return mentionPattern.ReplaceAllStringFunc(md, func(link string) string {
+		m := mentionPattern.FindStringSubmatch(link)
+		if id, ok := resolve(m[2]); ok && id != "" {
+			return MentionLink(id, m[1])
+		}
+		return "@" + m[1]
+	})
+}
+
+// PlainMentions turns every mention into plain "@Name" text, for destinations that cannot
+// express mentions in descriptions.
+func PlainMentions(md string) string {
+	return RewriteMentions(md, func(string) (string, bool) { return "", false })
+}
@@ -26,16 +26,47 @@ type TaskCustomField struct {
 	Value   interface{}
 }
 
+type ChecklistItem struct {
+	Name    string
+	Checked bool
+}
+
+type Checklist struct {
+	Name  string
+	Items []ChecklistItem
+}
+
 type Task struct {
 	Id              string
 	Name            string
 	Description     string
+	RichDescription string // markdown, mentions as converter.MentionLink; empty when the source has no rich text
 	Status          string
 	Completed       bool
 	Assignees       []TaskAssignee
+	Followers       []TaskAssignee // watchers/collaborators that are not assignees
 	DueDate         *time.Time
+	DueHasTime      bool // DueDate carries a time of day; otherwise it is an all-day date at midnight in TimeZone
+	StartDate       *time.Time
+	StartHasTime    bool
+	TimeZone        string // IANA zone that all-day dates are anchored in
 	Tags            []string
 	Priority        string
 	CustomFields    []TaskCustomField
+	Checklists      []Checklist
 	DestContainerID string // transient: set during execution to route the task to the correct destination container
 }
+
+type Comment struct {
+	ID        string
+	Author    TaskAssignee
+	Text      string
+	CreatedAt time.Time
+}
+
+type Attachment struct {
+	ID   string
+	Name string
+	URL  string // download link; may require the client's credentials or expire
+	Size int64
+}
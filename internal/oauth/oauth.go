@@ -0,0 +1,196 @@
+// Package oauth implements the parts of the OAuth 2.0 authorization-code flow the
+// providers need: the authorize URL, exchanging the code, refreshing tokens and an HTTP
+// transport that sends the current access token with every request.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Endpoint is where a provider authorizes users and issues tokens.
+type Endpoint struct {
+	AuthURL  string
+	TokenURL string
+	// TokenParamsInQuery sends the token request parameters in the query string instead
+	// of a form body, as ClickUp expects.
+	TokenParamsInQuery bool
+}
+
+// Config is an application registered with a provider.
+type Config struct {
+	Endpoint     Endpoint
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	HTTPClient   *http.Client // nil means a client with a 10 second timeout
+}
+
+// Token is an access token and what is needed to renew it.
+type Token struct {
+	AccessToken  string
+	RefreshToken string     // empty when the provider issues no refresh tokens
+	ExpiresAt    *time.Time // nil for tokens that do not expire
+	// AccountName names the provider account the token acts as, when the token response
+	// says so.
+	AccountName string
+}
+
+// Expired reports whether the token expires within leeway of now.
+func (t Token) Expired(now time.Time, leeway time.Duration) bool {
+	return t.ExpiresAt != nil && !now.Add(leeway).Before(*t.ExpiresAt)
+}
+
+// AuthCodeURL returns the URL the user is sent to in order to grant access. state comes
+// back unchanged on the redirect and ties it to the request that started the flow.
+func (c Config) AuthCodeURL(state string) string {
+	q := url.Values{
+		"client_id":     {c.ClientID},
+		"redirect_uri":  {c.RedirectURL},
+		"response_type": {"code"},
+		"state":         {state},
+	}
+	sep := "?"
+	if strings.Contains(c.Endpoint.AuthURL, "?") {
+		sep = "&"
+	}
+	return c.Endpoint.AuthURL + sep + q.Encode()
+}
+
+// Exchange trades the code the provider redirected back with for a token.
+func (c Config) Exchange(ctx context.Context, code string) (Token, error) {
+	return c.requestToken(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {c.RedirectURL},
+	})
+}
+
+// Refresh renews an expired token. Providers that do not rotate refresh tokens leave
+// RefreshToken empty in the result; the old one stays valid.
+func (c Config) Refresh(ctx context.Context, refreshToken string) (Token, error) {
+	return c.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"redirect_uri":  {c.RedirectURL},
+	})
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Error        string `json:"error"`
+	ErrorDesc    string `json:"error_description"`
+	Data         *struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"data"` // Asana describes the authorizing user here
+}
+
+func (c Config) requestToken(ctx context.Context, params url.Values) (Token, error) {
+	params.Set("client_id", c.ClientID)
+	params.Set("client_secret", c.ClientSecret)
+
+	var req *http.Request
+	var err error
+	if c.Endpoint.TokenParamsInQuery {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint.TokenURL+"?"+params.Encode(), nil)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint.TokenURL, strings.NewReader(params.Encode()))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return Token{}, fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		// The URL of a *url.Error may carry the client secret and the code.
+		if ue, ok := err.(*url.Error); ok {
+			err = ue.Err
+		}
+		return Token{}, fmt.Errorf("token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Token{}, fmt.Errorf("read token response: %w", err)
+	}
+	// Only the provider's error fields go into errors, never the body, which may hold tokens.
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return Token{}, fmt.Errorf("token request: status %d: unexpected response", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK || tr.AccessToken == "" {
+		msg := tr.ErrorDesc
+		if msg == "" {
+			msg = tr.Error
+		}
+		if msg == "" {
+			msg = "no access token in response"
+		}
+		return Token{}, fmt.Errorf("token request: status %d: %s", resp.StatusCode, msg)
+	}
+
+	token := Token{AccessToken: tr.AccessToken, RefreshToken: tr.RefreshToken}
+	if tr.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+		token.ExpiresAt = &expiresAt
+	}
+	if tr.Data != nil {
+		token.AccountName = tr.Data.Name
+		if tr.Data.Email != "" {
+			token.AccountName = fmt.Sprintf("%s <%s>", tr.Data.Name, tr.Data.Email)
+		}
+	}
+	return token, nil
+}
+
+// TokenSource returns a valid access token, refreshing it when needed.
+type TokenSource interface {
+	AccessToken(ctx context.Context) (string, error)
+}
+
+// Transport sets the Authorization header of every request to the current token of
+// Source, so a client built on it keeps working across token refreshes.
+type Transport struct {
+	Source TokenSource
+	// Prefix goes before the token, "Bearer " unless set.
+	Prefix string
+	Base   http.RoundTripper // nil means http.DefaultTransport
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Source.AccessToken(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("get access token: %w", err)
+	}
+	prefix := t.Prefix
+	if prefix == "" {
+		prefix = "Bearer "
+	}
+	// RoundTrippers must not modify the request they are given.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", prefix+token)
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type ArchiveExportStatus string
+
+const (
+	ArchiveExportStatusRunning   ArchiveExportStatus = "running"
+	ArchiveExportStatusCompleted ArchiveExportStatus = "completed"
+	ArchiveExportStatusFailed    ArchiveExportStatus = "failed"
+)
+
+// ArchiveExport is a job writing a source project into an archive file.
+type ArchiveExport struct {
+	ID           int64 `json:"id"`
+	Provider     string
+	WorkspaceID  string
+	ProjectID    string
+	FileName     string // archive file in the archive directory, set once completed
+	Status       ArchiveExportStatus
+	TotalTasks   int
+	ErrorMessage string
+	CreatedAt    time.Time
+	CompletedAt  *time.Time
+	OwnerID      *int64 // user who started the export; nil for exports only admins see
+}
+
+type ArchiveExportRepository struct {
+	db *sql.DB
+}
+
+func NewArchiveExportRepository(db *sql.DB) *ArchiveExportRepository {
+	return &ArchiveExportRepository{db: db}
+}
+
+func (r *ArchiveExportRepository) Create(export *ArchiveExport) (int64, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO archive_exports (provider, workspace_id, project_id, status, owner_id)
+		VALUES (?, ?, ?, ?, ?)
+	`, export.Provider, export.WorkspaceID, export.ProjectID, export.Status, export.OwnerID)
+	if err != nil {
+		return 0, fmt.Errorf("create archive export: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+func (r *ArchiveExportRepository) Complete(id int64, fileName string, totalTasks int) error {
+	_, err := r.db.Exec(`
+		UPDATE archive_exports
+		SET status = ?, file_name = ?, total_tasks = ?, completed_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, ArchiveExportStatusCompleted, fileName, totalTasks, id)
+	if err != nil {
+		return fmt.Errorf("complete archive export: %w", err)
+	}
+	return nil
+}
+
+func (r *ArchiveExportRepository) Fail(id int64, errorMessage string) error {
+	_, err := r.db.Exec(`
+		UPDATE archive_exports
+		SET status = ?, error_message = ?, completed_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, ArchiveExportStatusFailed, errorMessage, id)
+	if err != nil {
+		return fmt.Errorf("fail archive export: %w", err)
+	}
+	return nil
+}
+
+const archiveExportColumns = `
+	id, provider, workspace_id, project_id, file_name, status, total_tasks, error_message, created_at, completed_at, owner_id
+`
+
+func scanArchiveExport(row rowScanner) (ArchiveExport, error) {
+	var e ArchiveExport
+	var workspaceID, fileName, errorMessage sql.NullString
+	err := row.Scan(&e.ID, &e.Provider, &workspaceID, &e.ProjectID, &fileName, &e.Status, &e.TotalTasks,
+		&errorMessage, &e.CreatedAt, &e.CompletedAt, &e.OwnerID)
+	if err != nil {
+		return ArchiveExport{}, err
+	}
+	e.WorkspaceID = workspaceID.String
+	e.FileName = fileName.String
+	e.ErrorMessage = errorMessage.String
+	return e, nil
+}
+
+func (r *ArchiveExportRepository) GetArchiveExport(id int64) (ArchiveExport, error) {
+	e, err := scanArchiveExport(r.db.QueryRow(`SELECT `+archiveExportColumns+` FROM archive_exports WHERE id = ?`, id))
+	if err != nil {
+		return ArchiveExport{}, fmt.Errorf("get archive export: %w", err)
+	}
+	return e, nil
+}
+
+func (r *ArchiveExportRepository) GetArchiveExports() ([]ArchiveExport, error) {
+	return r.queryExports(`SELECT ` + archiveExportColumns + ` FROM archive_exports ORDER BY id DESC`)
+}
+
+// GetByOwnerID returns the exports of a user, newest first.
+func (r *ArchiveExportRepository) GetByOwnerID(ownerID int64) ([]ArchiveExport, error) {
+	return r.queryExports(`SELECT `+archiveExportColumns+` FROM archive_exports WHERE owner_id = ? ORDER BY id DESC`, ownerID)
+}
+
+func (r *ArchiveExportRepository) queryExports(query string, args ...any) ([]ArchiveExport, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get archive exports: %w", err)
+	}
+	defer rows.Close()
+
+	var exports []ArchiveExport
+	for rows.Next() {
+		e, err := scanArchiveExport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan archive export: %w", err)
+		}
+		exports = append(exports, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate archive exports: %w", err)
+	}
+	return exports, nil
+}
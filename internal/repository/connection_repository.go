@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Connection is a user's OAuth grant to an account of a provider. Migrations using it act
+// as that account. The tokens are stored encrypted and never leave the server.
+type Connection struct {
+	ID           int64 `json:"id"`
+	UserID       int64
+	Provider     string
+	AccountName  string
+	AccessToken  []byte     `json:"-"` // encrypted
+	RefreshToken []byte     `json:"-"` // encrypted; nil when the provider issues none
+	ExpiresAt    *time.Time // nil for access tokens that do not expire
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type ConnectionRepository struct {
+	db *sql.DB
+}
+
+func NewConnectionRepository(db *sql.DB) *ConnectionRepository {
+	return &ConnectionRepository{db: db}
+}
+
+func (r *ConnectionRepository) Create(conn *Connection) (int64, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO connections (user_id, provider, account_name, access_token, refresh_token, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, conn.UserID, conn.Provider, conn.AccountName, conn.AccessToken, conn.RefreshToken, conn.ExpiresAt)
+	if err != nil {
+		return 0, fmt.Errorf("create connection: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+const connectionColumns = `
+	id, user_id, provider, account_name, access_token, refresh_token, expires_at, created_at, updated_at
+`
+
+func scanConnection(row rowScanner) (Connection, error) {
+	var c Connection
+	err := row.Scan(&c.ID, &c.UserID, &c.Provider, &c.AccountName, &c.AccessToken, &c.RefreshToken,
+		&c.ExpiresAt, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return Connection{}, err
+	}
+	return c, nil
+}
+
+// GetConnection returns a connection. The error wraps sql.ErrNoRows when it does not
+// exist.
+func (r *ConnectionRepository) GetConnection(id int64) (Connection, error) {
+	c, err := scanConnection(r.db.QueryRow(`SELECT `+connectionColumns+` FROM connections WHERE id = ?`, id))
+	if err != nil {
+		return Connection{}, fmt.Errorf("get connection: %w", err)
+	}
+	return c, nil
+}
+
+func (r *ConnectionRepository) GetByUserID(userID int64) ([]Connection, error) {
+	rows, err := r.db.Query(`SELECT `+connectionColumns+` FROM connections WHERE user_id = ? ORDER BY id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user connections: %w", err)
+	}
+	defer rows.Close()
+
+	var connections []Connection
+	for rows.Next() {
+		c, err := scanConnection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan connection: %w", err)
+		}
+		connections = append(connections, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate user connections: %w", err)
+	}
+	return connections, nil
+}
+
+// UpdateTokens stores refreshed tokens. A nil refreshToken keeps the stored one, for
+// providers that do not rotate refresh tokens.
+func (r *ConnectionRepository) UpdateTokens(id int64, accessToken, refreshToken []byte, expiresAt *time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE connections
+		SET access_token = ?, refresh_token = COALESCE(?, refresh_token), expires_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, accessToken, refreshToken, expiresAt, id)
+	if err != nil {
+		return fmt.Errorf("update connection tokens: %w", err)
+	}
+	return nil
+}
+
+func (r *ConnectionRepository) Delete(id int64) error {
+	if _, err := r.db.Exec(`DELETE FROM connections WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete connection: %w", err)
+	}
+	return nil
+}
+
+// CreateOAuthState records the state of an authorization a user started, so the
+// provider's redirect can be matched to that user.
+func (r *ConnectionRepository) CreateOAuthState(state string, userID int64, provider string) error {
+	_, err := r.db.Exec(`INSERT INTO oauth_states (state, user_id, provider) VALUES (?, ?, ?)`, state, userID, provider)
+	if err != nil {
+		return fmt.Errorf("create oauth state: %w", err)
+	}
+	return nil
+}
+
+// ConsumeOAuthState deletes a state created after notBefore and returns the user and
+// provider it was created for, so each state is used once. States older than notBefore
+// are dropped too. The error wraps sql.ErrNoRows for an unknown or expired state.
+func (r *ConnectionRepository) ConsumeOAuthState(state string, notBefore time.Time) (int64, string, error) {
+	cutoff := sqliteTime(notBefore)
+	if _, err := r.db.Exec(`DELETE FROM oauth_states WHERE created_at < ?`, cutoff); err != nil {
+		return 0, "", fmt.Errorf("delete expired oauth states: %w", err)
+	}
+
+	var userID int64
+	var provider string
+	err := r.db.QueryRow(`
+		DELETE FROM oauth_states WHERE state = ? AND created_at >= ? RETURNING user_id, provider
+	`, state, cutoff).Scan(&userID, &provider)
+	if err != nil {
+		return 0, "", fmt.Errorf("consume oauth state: %w", err)
+	}
+	return userID, provider, nil
+}
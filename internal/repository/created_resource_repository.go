@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CreatedResourceType identifies what kind of destination object a migration created
+// besides tasks. These are tracked so a rollback can optionally remove them.
+type CreatedResourceType string
+
+const (
+	CreatedResourceTypeCustomField CreatedResourceType = "custom_field"
+	CreatedResourceTypeTag         CreatedResourceType = "tag"
+	CreatedResourceTypeContainer   CreatedResourceType = "container"
+)
+
+type CreatedResource struct {
+	ID          int64
+	MigrationID int64
+	Type        CreatedResourceType
+	ResourceID  string
+	Name        string
+	Deleted     bool
+	CreatedAt   time.Time
+}
+
+type CreatedResourceRepository struct {
+	db *sql.DB
+}
+
+func NewCreatedResourceRepository(db *sql.DB) *CreatedResourceRepository {
+	return &CreatedResourceRepository{db: db}
+}
+
+// Create records a destination resource created by a migration. Recording the same
+// resource twice is a no-op.
+func (r *CreatedResourceRepository) Create(resource *CreatedResource) error {
+	_, err := r.db.Exec(`
+		INSERT OR IGNORE INTO created_resources (migration_id, type, resource_id, name)
+		VALUES (?, ?, ?, ?)
+	`, resource.MigrationID, resource.Type, resource.ResourceID, resource.Name)
+	if err != nil {
+		return fmt.Errorf("create created resource: %w", err)
+	}
+	return nil
+}
+
+// GetPendingByMigrationID returns the resources of a migration that have not been deleted yet,
+// in creation order.
+func (r *CreatedResourceRepository) GetPendingByMigrationID(migrationID int64) ([]CreatedResource, error) {
+	rows, err := r.db.Query(`
+		SELECT id, migration_id, type, resource_id, name, deleted, created_at
+		FROM created_resources
+		WHERE migration_id = ? AND deleted = 0
+		ORDER BY id ASC
+	`, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get created resources: %w", err)
+	}
+	defer rows.Close()
+
+	var resources []CreatedResource
+	for rows.Next() {
+		var res CreatedResource
+		var name sql.NullString
+		var deleted int
+		if err := rows.Scan(&res.ID, &res.MigrationID, &res.Type, &res.ResourceID, &name, &deleted, &res.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan created resource: %w", err)
+		}
+		res.Name = name.String
+		res.Deleted = deleted != 0
+		resources = append(resources, res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate created resources: %w", err)
+	}
+	return resources, nil
+}
+
+func (r *CreatedResourceRepository) MarkDeleted(id int64) error {
+	_, err := r.db.Exec(`UPDATE created_resources SET deleted = 1 WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("mark created resource deleted: %w", err)
+	}
+	return nil
+}
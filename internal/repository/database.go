@@ -8,8 +8,16 @@ import (
 	_ "modernc.org/sqlite"
 )
 
+// busyTimeout makes a connection wait for a concurrent writer instead of failing with
+// SQLITE_BUSY, which matters once several migrations of a batch run at the same time.
+const busyTimeout = "_pragma=busy_timeout(5000)"
+
 func InitDB(dbPath string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite", dbPath)
+	dsn := dbPath + "?" + busyTimeout
+	if strings.Contains(dbPath, "?") {
+		dsn = dbPath + "&" + busyTimeout
+	}
+	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("Error trying to open DB: %w", err)
 	}
@@ -95,6 +103,101 @@ func createTables(db *sql.DB) error {
         created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
         FOREIGN KEY (migration_id) REFERENCES migrations(id)
     );
+
+    CREATE TABLE IF NOT EXISTS created_resources (
+        id            INTEGER PRIMARY KEY AUTOINCREMENT,
+        migration_id  INTEGER NOT NULL,
+        type          TEXT NOT NULL,
+        resource_id   TEXT NOT NULL,
+        name          TEXT,
+        deleted       INTEGER NOT NULL DEFAULT 0,
+        created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (migration_id) REFERENCES migrations(id),
+        UNIQUE (migration_id, type, resource_id)
+    );
+
+    CREATE TABLE IF NOT EXISTS mapping_templates (
+        id          INTEGER PRIMARY KEY AUTOINCREMENT,
+        name        TEXT NOT NULL UNIQUE,
+        source      TEXT NOT NULL,
+        destination TEXT NOT NULL,
+        content     TEXT NOT NULL,
+        created_at  DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE TABLE IF NOT EXISTS archive_exports (
+        id            INTEGER PRIMARY KEY AUTOINCREMENT,
+        provider      TEXT NOT NULL,
+        workspace_id  TEXT,
+        project_id    TEXT NOT NULL,
+        file_name     TEXT,
+        status        TEXT NOT NULL,
+        total_tasks   INTEGER DEFAULT 0,
+        error_message TEXT,
+        created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+        completed_at  DATETIME
+    );
+
+    CREATE TABLE IF NOT EXISTS migration_batches (
+        id                INTEGER PRIMARY KEY AUTOINCREMENT,
+        name              TEXT NOT NULL,
+        source            TEXT NOT NULL,
+        destination       TEXT NOT NULL,
+        dest_workspace_id TEXT,
+        concurrency       INTEGER NOT NULL DEFAULT 1,
+        routes            TEXT NOT NULL,
+        status            TEXT NOT NULL,
+        created_at        DATETIME DEFAULT CURRENT_TIMESTAMP,
+        started_at        DATETIME,
+        completed_at      DATETIME
+    );
+
+    CREATE TABLE IF NOT EXISTS migration_jobs (
+        id               INTEGER PRIMARY KEY AUTOINCREMENT,
+        migration_id     INTEGER NOT NULL,
+        status           TEXT NOT NULL,
+        worker_id        TEXT,
+        attempts         INTEGER NOT NULL DEFAULT 0,
+        lease_expires_at DATETIME,
+        error            TEXT,
+        created_at       DATETIME DEFAULT CURRENT_TIMESTAMP,
+        started_at       DATETIME,
+        finished_at      DATETIME,
+        FOREIGN KEY (migration_id) REFERENCES migrations(id)
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_migration_jobs_status
+        ON migration_jobs (status, id);
+
+    CREATE TABLE IF NOT EXISTS users (
+        id           INTEGER PRIMARY KEY AUTOINCREMENT,
+        name         TEXT NOT NULL,
+        email        TEXT NOT NULL UNIQUE,
+        role         TEXT NOT NULL,
+        api_key_hash TEXT NOT NULL UNIQUE,
+        created_at   DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE TABLE IF NOT EXISTS connections (
+        id            INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id       INTEGER NOT NULL,
+        provider      TEXT NOT NULL,
+        account_name  TEXT NOT NULL DEFAULT '',
+        access_token  BLOB NOT NULL,
+        refresh_token BLOB,
+        expires_at    DATETIME,
+        created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+        updated_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (user_id) REFERENCES users(id)
+    );
+
+    CREATE TABLE IF NOT EXISTS oauth_states (
+        state      TEXT PRIMARY KEY,
+        user_id    INTEGER NOT NULL,
+        provider   TEXT NOT NULL,
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (user_id) REFERENCES users(id)
+    );
     `
 
 	if _, err := db.Exec(schema); err != nil {
@@ -109,15 +212,63 @@ func runMigrations(db *sql.DB) error {
 	}
 
 	// Ensure enabled column exists on container_mappings (legacy migration)
-	if _, err := db.Exec(`ALTER TABLE container_mappings ADD COLUMN enabled INTEGER NOT NULL DEFAULT 1`); err != nil {
-		if !strings.Contains(err.Error(), "duplicate column") {
-			return fmt.Errorf("alter container_mappings add enabled: %w", err)
+	if err := addColumnIfMissing(db, "container_mappings", "enabled INTEGER NOT NULL DEFAULT 1"); err != nil {
+		return err
+	}
+
+	for _, column := range []string{
+		"rollback_total_tasks INTEGER DEFAULT 0",
+		"rollback_completed_tasks INTEGER DEFAULT 0",
+		"rollback_failed_tasks INTEGER DEFAULT 0",
+		"rolled_back_at DATETIME",
+		"excluded_tasks INTEGER DEFAULT 0",
+		"filter_rules TEXT",
+		"time_zone TEXT",
+		"backlink_mode TEXT",
+		"backlink_field_id TEXT",
+		"reverse_backlink INTEGER DEFAULT 0",
+		"batch_id INTEGER REFERENCES migration_batches(id)",
+		"container_strategy TEXT",
+		"scheduled_at DATETIME",
+		"schedule TEXT",
+		"owner_id INTEGER REFERENCES users(id)",
+		"source_connection_id INTEGER REFERENCES connections(id)",
+		"dest_connection_id INTEGER REFERENCES connections(id)",
+	} {
+		if err := addColumnIfMissing(db, "migrations", column); err != nil {
+			return err
+		}
+	}
+	for _, column := range []string{
+		"owner_id INTEGER REFERENCES users(id)",
+		"source_connection_id INTEGER REFERENCES connections(id)",
+		"dest_connection_id INTEGER REFERENCES connections(id)",
+	} {
+		if err := addColumnIfMissing(db, "migration_batches", column); err != nil {
+			return err
+		}
+	}
+	for _, table := range []string{"mapping_templates", "archive_exports"} {
+		if err := addColumnIfMissing(db, table, "owner_id INTEGER REFERENCES users(id)"); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// addColumnIfMissing runs ALTER TABLE ... ADD COLUMN, ignoring the error SQLite
+// returns when the column already exists. The table name and column definition
+// are compile-time constants, never user input.
+func addColumnIfMissing(db *sql.DB, table, columnDef string) error {
+	if _, err := db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + columnDef); err != nil {
+		if !strings.Contains(err.Error(), "duplicate column") {
+			return fmt.Errorf("alter %s add %s: %w", table, strings.Fields(columnDef)[0], err)
+		}
+	}
+	return nil
+}
+
 // migrateAddSourceContainerID recreates migration_mappings to add source_container_id
 // if the column does not yet exist.
 func migrateAddSourceContainerID(db *sql.DB) error {
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrDuplicateTemplateName is returned when a mapping template name is already taken.
+var ErrDuplicateTemplateName = errors.New("mapping template name already exists")
+
+// MappingContent is the mapping configuration of a migration in a form that does not depend
+// on its source container IDs: containers are identified by name and option mappings by
+// source option name, so it can be applied to other migrations between the same providers.
+type MappingContent struct {
+	Assignees  []AssigneeValueMapping `json:"assignees,omitempty"`
+	Tags       []ValueMapping         `json:"tags,omitempty"`
+	Containers []ContainerContent     `json:"containers,omitempty"`
+}
+
+type ValueMapping struct {
+	SourceValue string `json:"source_value"`
+	DestValue   string `json:"dest_value,omitempty"`
+	Skip        bool   `json:"skip,omitempty"` // tags only: drop the tag
+}
+
+// AssigneeValueMapping maps a source user, identified by ID and email, onto a destination member.
+type AssigneeValueMapping struct {
+	SourceValue string `json:"source_value"`
+	Email       string `json:"email,omitempty"`
+	Name        string `json:"name,omitempty"`
+	DestValue   string `json:"dest_value"`
+	Skip        bool   `json:"skip,omitempty"`
+}
+
+type ContainerContent struct {
+	SourceName   string               `json:"source_name"`
+	Enabled      bool                 `json:"enabled"`
+	DestID       string               `json:"dest_id,omitempty"`
+	DestName     string               `json:"dest_name,omitempty"`
+	Statuses     []ValueMapping       `json:"statuses,omitempty"`
+	Priorities   []ValueMapping       `json:"priorities,omitempty"`
+	CustomFields []CustomFieldContent `json:"custom_fields,omitempty"`
+}
+
+type CustomFieldContent struct {
+	FieldID     string `json:"field_id"`
+	FieldName   string `json:"field_name"`
+	Enabled     bool   `json:"enabled"`
+	DestFieldID string `json:"dest_field_id,omitempty"`
+	Conversion  string `json:"conversion,omitempty"`
+	// OptionMappings maps source option names (or IDs of options without a name, such as
+	// checkbox values) to destination option IDs.
+	OptionMappings map[string]string `json:"option_mappings,omitempty"`
+}
+
+// MappingTemplate is a named mapping configuration saved from a migration.
+type MappingTemplate struct {
+	ID          int64 `json:"id"`
+	Name        string
+	Source      string
+	Destination string
+	Content     MappingContent
+	CreatedAt   time.Time
+	OwnerID     *int64 // user who saved the template; nil for templates only admins see
+}
+
+type MappingTemplateRepository struct {
+	db *sql.DB
+}
+
+func NewMappingTemplateRepository(db *sql.DB) *MappingTemplateRepository {
+	return &MappingTemplateRepository{db: db}
+}
+
+func (r *MappingTemplateRepository) Create(template *MappingTemplate) (int64, error) {
+	content, err := json.Marshal(template.Content)
+	if err != nil {
+		return 0, fmt.Errorf("marshal mapping template: %w", err)
+	}
+	result, err := r.db.Exec(`
+		INSERT INTO mapping_templates (name, source, destination, content, owner_id)
+		VALUES (?, ?, ?, ?, ?)
+	`, template.Name, template.Source, template.Destination, string(content), template.OwnerID)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return 0, fmt.Errorf("create mapping template %q: %w", template.Name, ErrDuplicateTemplateName)
+		}
+		return 0, fmt.Errorf("create mapping template: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+const mappingTemplateColumns = `id, name, source, destination, content, created_at, owner_id`
+
+func scanMappingTemplate(row rowScanner) (MappingTemplate, error) {
+	var t MappingTemplate
+	var content string
+	if err := row.Scan(&t.ID, &t.Name, &t.Source, &t.Destination, &content, &t.CreatedAt, &t.OwnerID); err != nil {
+		return MappingTemplate{}, err
+	}
+	if err := json.Unmarshal([]byte(content), &t.Content); err != nil {
+		return MappingTemplate{}, fmt.Errorf("parse mapping template content: %w", err)
+	}
+	return t, nil
+}
+
+func (r *MappingTemplateRepository) GetMappingTemplate(id int64) (MappingTemplate, error) {
+	t, err := scanMappingTemplate(r.db.QueryRow(`SELECT `+mappingTemplateColumns+` FROM mapping_templates WHERE id = ?`, id))
+	if err != nil {
+		return MappingTemplate{}, fmt.Errorf("get mapping template: %w", err)
+	}
+	return t, nil
+}
+
+func (r *MappingTemplateRepository) GetMappingTemplates() ([]MappingTemplate, error) {
+	return r.queryTemplates(`SELECT ` + mappingTemplateColumns + ` FROM mapping_templates ORDER BY name ASC`)
+}
+
+// GetByOwnerID returns the templates of a user, by name.
+func (r *MappingTemplateRepository) GetByOwnerID(ownerID int64) ([]MappingTemplate, error) {
+	return r.queryTemplates(`SELECT `+mappingTemplateColumns+` FROM mapping_templates WHERE owner_id = ? ORDER BY name ASC`, ownerID)
+}
+
+func (r *MappingTemplateRepository) queryTemplates(query string, args ...any) ([]MappingTemplate, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get mapping templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []MappingTemplate
+	for rows.Next() {
+		t, err := scanMappingTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan mapping template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate mapping templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (r *MappingTemplateRepository) Delete(id int64) error {
+	result, err := r.db.Exec(`DELETE FROM mapping_templates WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete mapping template: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete mapping template rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("delete mapping template %d: %w", id, sql.ErrNoRows)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type MigrationBatchStatus string
+
+const (
+	MigrationBatchStatusPending             MigrationBatchStatus = "pending"
+	MigrationBatchStatusRunning             MigrationBatchStatus = "running"
+	MigrationBatchStatusCompleted           MigrationBatchStatus = "completed"
+	MigrationBatchStatusCompletedWithErrors MigrationBatchStatus = "completed_with_errors"
+	MigrationBatchStatusFailed              MigrationBatchStatus = "failed"
+)
+
+// BatchRoute sends one source project to its destination. Routes are stored as JSON on
+// the batch, and every route becomes one migration of the batch.
+type BatchRoute struct {
+	SourceProjectID string `json:"source_project_id"`
+	DestListID      string `json:"dest_list_id"`
+	DestSpaceID     string `json:"dest_space_id,omitempty"`
+}
+
+// MigrationBatch groups the migrations of several source projects that share their
+// destination workspace and assignee mappings and are started together.
+type MigrationBatch struct {
+	ID              int64 `json:"id"`
+	Name            string
+	Source          string
+	Destination     string
+	DestWorkspaceID string
+	Concurrency     int // number of migrations of the batch that run at the same time
+	Routes          []BatchRoute
+	Status          MigrationBatchStatus
+	OwnerID         *int64 // user who created the batch; nil for batches only admins see
+	// SourceConnectionID and DestConnectionID are passed on to the migrations of the batch.
+	SourceConnectionID *int64
+	DestConnectionID   *int64
+	CreatedAt          time.Time
+	StartedAt          *time.Time
+	CompletedAt        *time.Time
+}
+
+type MigrationBatchRepository struct {
+	db *sql.DB
+}
+
+func NewMigrationBatchRepository(db *sql.DB) *MigrationBatchRepository {
+	return &MigrationBatchRepository{db: db}
+}
+
+func (r *MigrationBatchRepository) Create(batch *MigrationBatch) (int64, error) {
+	routes, err := json.Marshal(batch.Routes)
+	if err != nil {
+		return 0, fmt.Errorf("marshal batch routes: %w", err)
+	}
+	result, err := r.db.Exec(`
+		INSERT INTO migration_batches (name, source, destination, dest_workspace_id, concurrency, routes, status, owner_id,
+			source_connection_id, dest_connection_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, batch.Name, batch.Source, batch.Destination, batch.DestWorkspaceID, batch.Concurrency, string(routes), batch.Status, batch.OwnerID,
+		batch.SourceConnectionID, batch.DestConnectionID)
+	if err != nil {
+		return 0, fmt.Errorf("create migration batch: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+func (r *MigrationBatchRepository) Start(id int64) error {
+	_, err := r.db.Exec(`
+		UPDATE migration_batches SET status = ?, started_at = CURRENT_TIMESTAMP, completed_at = NULL WHERE id = ?
+	`, MigrationBatchStatusRunning, id)
+	if err != nil {
+		return fmt.Errorf("start migration batch: %w", err)
+	}
+	return nil
+}
+
+func (r *MigrationBatchRepository) Complete(id int64, status MigrationBatchStatus) error {
+	_, err := r.db.Exec(`UPDATE migration_batches SET status = ?, completed_at = CURRENT_TIMESTAMP WHERE id = ?`, status, id)
+	if err != nil {
+		return fmt.Errorf("complete migration batch: %w", err)
+	}
+	return nil
+}
+
+const migrationBatchColumns = `
+	id, name, source, destination, dest_workspace_id, concurrency, routes, status, created_at, started_at, completed_at, owner_id,
+	source_connection_id, dest_connection_id
+`
+
+func scanMigrationBatch(row rowScanner) (MigrationBatch, error) {
+	var b MigrationBatch
+	var destWorkspaceID sql.NullString
+	var routes string
+	err := row.Scan(&b.ID, &b.Name, &b.Source, &b.Destination, &destWorkspaceID, &b.Concurrency, &routes,
+		&b.Status, &b.CreatedAt, &b.StartedAt, &b.CompletedAt, &b.OwnerID,
+		&b.SourceConnectionID, &b.DestConnectionID)
+	if err != nil {
+		return MigrationBatch{}, err
+	}
+	b.DestWorkspaceID = destWorkspaceID.String
+	if err := json.Unmarshal([]byte(routes), &b.Routes); err != nil {
+		return MigrationBatch{}, fmt.Errorf("parse batch routes: %w", err)
+	}
+	return b, nil
+}
+
+func (r *MigrationBatchRepository) GetMigrationBatch(id int64) (MigrationBatch, error) {
+	b, err := scanMigrationBatch(r.db.QueryRow(`SELECT `+migrationBatchColumns+` FROM migration_batches WHERE id = ?`, id))
+	if err != nil {
+		return MigrationBatch{}, fmt.Errorf("get migration batch: %w", err)
+	}
+	return b, nil
+}
+
+func (r *MigrationBatchRepository) GetMigrationBatches() ([]MigrationBatch, error) {
+	return r.queryBatches(`SELECT ` + migrationBatchColumns + ` FROM migration_batches ORDER BY id DESC`)
+}
+
+// GetByStatus returns the batches in a status, oldest first.
+func (r *MigrationBatchRepository) GetByStatus(status MigrationBatchStatus) ([]MigrationBatch, error) {
+	return r.queryBatches(`SELECT `+migrationBatchColumns+` FROM migration_batches WHERE status = ? ORDER BY id`, status)
+}
+
+// GetByOwnerID returns the batches of a user, newest first.
+func (r *MigrationBatchRepository) GetByOwnerID(ownerID int64) ([]MigrationBatch, error) {
+	return r.queryBatches(`SELECT `+migrationBatchColumns+` FROM migration_batches WHERE owner_id = ? ORDER BY id DESC`, ownerID)
+}
+
+func (r *MigrationBatchRepository) queryBatches(query string, args ...any) ([]MigrationBatch, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get migration batches: %w", err)
+	}
+	defer rows.Close()
+
+	var batches []MigrationBatch
+	for rows.Next() {
+		b, err := scanMigrationBatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan migration batch: %w", err)
+		}
+		batches = append(batches, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate migration batches: %w", err)
+	}
+	return batches, nil
+}
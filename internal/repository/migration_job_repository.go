@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type MigrationJobStatus string
+
+const (
+	MigrationJobStatusQueued  MigrationJobStatus = "queued"
+	MigrationJobStatusRunning MigrationJobStatus = "running"
+	MigrationJobStatusDone    MigrationJobStatus = "done"
+	MigrationJobStatusFailed  MigrationJobStatus = "failed"
+)
+
+// MigrationJob is one queued run of a migration. A worker holds a running job through a
+// lease it renews with heartbeats; a job whose lease expired, because its worker died, is
+// handed to the next worker that claims a job.
+type MigrationJob struct {
+	ID             int64 `json:"id"`
+	MigrationID    int64
+	Status         MigrationJobStatus
+	WorkerID       string
+	Attempts       int
+	LeaseExpiresAt *time.Time
+	Error          string
+	CreatedAt      time.Time
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+}
+
+type MigrationJobRepository struct {
+	db *sql.DB
+}
+
+func NewMigrationJobRepository(db *sql.DB) *MigrationJobRepository {
+	return &MigrationJobRepository{db: db}
+}
+
+func (r *MigrationJobRepository) Enqueue(migrationID int64) (int64, error) {
+	result, err := r.db.Exec(`INSERT INTO migration_jobs (migration_id, status) VALUES (?, ?)`, migrationID, MigrationJobStatusQueued)
+	if err != nil {
+		return 0, fmt.Errorf("enqueue migration job: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+const migrationJobColumns = `
+	id, migration_id, status, worker_id, attempts, lease_expires_at, error, created_at, started_at, finished_at
+`
+
+func scanMigrationJob(row rowScanner) (MigrationJob, error) {
+	var j MigrationJob
+	var workerID, errorMessage sql.NullString
+	err := row.Scan(&j.ID, &j.MigrationID, &j.Status, &workerID, &j.Attempts, &j.LeaseExpiresAt, &errorMessage,
+		&j.CreatedAt, &j.StartedAt, &j.FinishedAt)
+	if err != nil {
+		return MigrationJob{}, err
+	}
+	j.WorkerID = workerID.String
+	j.Error = errorMessage.String
+	return j, nil
+}
+
+// Claim leases the oldest job that is queued or whose lease has expired to workerID, as
+// long as fewer than maxRunning jobs hold a live lease. It returns nil when there is no
+// job to run. The check and the claim are one statement, so workers in several processes
+// sharing the database never exceed maxRunning or claim the same job.
+func (r *MigrationJobRepository) Claim(workerID string, now time.Time, lease time.Duration, maxRunning int) (*MigrationJob, error) {
+	return r.claim("", nil, workerID, now, lease, maxRunning)
+}
+
+// ClaimMigration is Claim restricted to the jobs of one migration, for a process that
+// runs only the migration it started.
+func (r *MigrationJobRepository) ClaimMigration(migrationID int64, workerID string, now time.Time, lease time.Duration, maxRunning int) (*MigrationJob, error) {
+	return r.claim("AND migration_id = ?", []any{migrationID}, workerID, now, lease, maxRunning)
+}
+
+// claim runs the Claim statement; filter further restricts the claimable jobs with args.
+func (r *MigrationJobRepository) claim(filter string, args []any, workerID string, now time.Time, lease time.Duration, maxRunning int) (*MigrationJob, error) {
+	query := `
+		UPDATE migration_jobs
+		SET status = ?, worker_id = ?, lease_expires_at = ?, attempts = attempts + 1,
+		    started_at = COALESCE(started_at, CURRENT_TIMESTAMP)
+		WHERE id = (
+			SELECT id FROM migration_jobs
+			WHERE (status = ? OR (status = ? AND lease_expires_at < ?)) ` + filter + `
+			ORDER BY id
+			LIMIT 1
+		)
+		AND (SELECT COUNT(*) FROM migration_jobs WHERE status = ? AND lease_expires_at >= ?) < ?
+		RETURNING ` + migrationJobColumns
+
+	nowText := sqliteTime(now)
+	queryArgs := []any{
+		MigrationJobStatusRunning, workerID, sqliteTime(now.Add(lease)),
+		MigrationJobStatusQueued, MigrationJobStatusRunning, nowText,
+	}
+	queryArgs = append(queryArgs, args...)
+	queryArgs = append(queryArgs, MigrationJobStatusRunning, nowText, maxRunning)
+	j, err := scanMigrationJob(r.db.QueryRow(query, queryArgs...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim migration job: %w", err)
+	}
+	return &j, nil
+}
+
+// Heartbeat extends the lease of a running job. It reports false when workerID no longer
+// holds the job, because the lease expired and another worker claimed it.
+func (r *MigrationJobRepository) Heartbeat(id int64, workerID string, leaseExpiresAt time.Time) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE migration_jobs SET lease_expires_at = ? WHERE id = ? AND worker_id = ? AND status = ?
+	`, sqliteTime(leaseExpiresAt), id, workerID, MigrationJobStatusRunning)
+	if err != nil {
+		return false, fmt.Errorf("renew migration job lease: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("renew migration job lease: %w", err)
+	}
+	return n > 0, nil
+}
+
+// Finish records the outcome of a job. It changes nothing when workerID no longer holds
+// the job.
+func (r *MigrationJobRepository) Finish(id int64, workerID string, status MigrationJobStatus, errorMessage string) error {
+	_, err := r.db.Exec(`
+		UPDATE migration_jobs SET status = ?, error = ?, lease_expires_at = NULL, finished_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND worker_id = ?
+	`, status, errorMessage, id, workerID)
+	if err != nil {
+		return fmt.Errorf("finish migration job: %w", err)
+	}
+	return nil
+}
+
+// Release puts a running job held by workerID back in the queue and takes back the
+// attempt its claim counted. It reports false when workerID no longer holds the job.
+func (r *MigrationJobRepository) Release(id int64, workerID string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE migration_jobs
+		SET status = ?, worker_id = NULL, lease_expires_at = NULL, attempts = MAX(attempts - 1, 0)
+		WHERE id = ? AND worker_id = ? AND status = ?
+	`, MigrationJobStatusQueued, id, workerID, MigrationJobStatusRunning)
+	if err != nil {
+		return false, fmt.Errorf("release migration job: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("release migration job: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r *MigrationJobRepository) GetMigrationJob(id int64) (MigrationJob, error) {
+	j, err := scanMigrationJob(r.db.QueryRow(`SELECT `+migrationJobColumns+` FROM migration_jobs WHERE id = ?`, id))
+	if err != nil {
+		return MigrationJob{}, fmt.Errorf("get migration job: %w", err)
+	}
+	return j, nil
+}
+
+// GetLatestByMigrationID returns the most recent job of a migration, or nil if it was
+// never queued.
+func (r *MigrationJobRepository) GetLatestByMigrationID(migrationID int64) (*MigrationJob, error) {
+	j, err := scanMigrationJob(r.db.QueryRow(`
+		SELECT `+migrationJobColumns+` FROM migration_jobs WHERE migration_id = ? ORDER BY id DESC LIMIT 1
+	`, migrationID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get latest migration job: %w", err)
+	}
+	return &j, nil
+}
+
+// QueuePosition returns the position of a queued job in the queue, 1 being the next job
+// to run.
+func (r *MigrationJobRepository) QueuePosition(id int64) (int, error) {
+	var ahead int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM migration_jobs WHERE status = ? AND id < ?`, MigrationJobStatusQueued, id).Scan(&ahead)
+	if err != nil {
+		return 0, fmt.Errorf("get queue position: %w", err)
+	}
+	return ahead + 1, nil
+}
@@ -14,6 +14,7 @@ const (
 	MappingTypePriority    MappingType = "priority"
 	MappingTypeAssignee    MappingType = "assignee"
 	MappingTypeCustomField MappingType = "custom_field"
+	MappingTypeTag         MappingType = "tag"
 )
 
 type MappingStatus string
@@ -31,16 +32,31 @@ type AssigneeMetadata struct {
 	Email string `json:"email"`
 }
 
+type CustomFieldOptionMetadata struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	OrderIndex int    `json:"orderindex"`
+}
+
+// CustomFieldMetadata is stored on custom_field rows. Options are refreshed on every discovery;
+// Conversion and OptionMap are the user's configuration for how values are carried over.
 type CustomFieldMetadata struct {
-	Name      string `json:"name"`
-	FieldType string `json:"field_type"`
+	Name       string                      `json:"name"`
+	FieldType  string                      `json:"field_type"`
+	Options    []CustomFieldOptionMetadata `json:"options,omitempty"`
+	Conversion string                      `json:"conversion,omitempty"`
+	OptionMap  map[string]string           `json:"option_map,omitempty"` // source option ID → dest option ID
 }
 
 type CustomFieldRow struct {
-	FieldID   string
-	FieldName string
-	FieldType string
-	Enabled   bool
+	FieldID     string
+	FieldName   string
+	FieldType   string
+	Enabled     bool
+	DestFieldID *string // nil: find or create a destination field by name
+	Conversion  string
+	Options     []CustomFieldOptionMetadata
+	OptionMap   map[string]string
 }
 
 type MigrationMapping struct {
@@ -97,6 +113,46 @@ func (r *MigrationMappingRepository) UpsertPending(
 	return nil
 }
 
+// UpsertMapped inserts a global (NULL container) mapping row that is already 'mapped' to destValue
+// if it does not exist yet. Used for types with a sensible default, such as tags keeping their name.
+func (r *MigrationMappingRepository) UpsertMapped(
+	migrationID int64,
+	mappingType MappingType,
+	sourceValue string,
+	destValue string,
+) error {
+	_, err := r.db.Exec(`
+		INSERT OR IGNORE INTO migration_mappings
+			(migration_id, type, source_value, dest_value, source_container_id, status)
+		VALUES (?, ?, ?, ?, NULL, 'mapped')
+	`, migrationID, mappingType, sourceValue, destValue)
+	if err != nil {
+		return fmt.Errorf("upsert mapped mapping: %w", err)
+	}
+	return nil
+}
+
+// SkipMapping clears dest_value and marks a global (NULL container) mapping as 'skipped',
+// meaning the source value is dropped during execution.
+func (r *MigrationMappingRepository) SkipMapping(migrationID int64, mappingType MappingType, sourceValue string) error {
+	result, err := r.db.Exec(`
+		UPDATE migration_mappings
+		SET dest_value = NULL, status = 'skipped', updated_at = CURRENT_TIMESTAMP
+		WHERE migration_id = ? AND type = ? AND source_value = ? AND source_container_id IS NULL
+	`, migrationID, mappingType, sourceValue)
+	if err != nil {
+		return fmt.Errorf("skip mapping: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("skip mapping rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("mapping not found: migration=%d type=%s source=%s", migrationID, mappingType, sourceValue)
+	}
+	return nil
+}
+
 // UpdateMapping sets dest_value and marks a specific mapping as 'mapped'.
 // sourceContainerID nil targets global (NULL container) rows; non-nil targets per-container rows.
 func (r *MigrationMappingRepository) UpdateMapping(
@@ -181,7 +237,7 @@ func (r *MigrationMappingRepository) GetByMigrationIDAndContainer(
 	return scanMappings(rows)
 }
 
-// GetGlobalByMigrationID returns all global (NULL source_container_id) mapping rows — i.e., assignees.
+// GetGlobalByMigrationID returns all global (NULL source_container_id) mapping rows — i.e., assignees and tags.
 func (r *MigrationMappingRepository) GetGlobalByMigrationID(migrationID int64) ([]MigrationMapping, error) {
 	rows, err := r.db.Query(`
 		SELECT id, migration_id, type, source_value, dest_value, source_container_id, status, metadata, created_at, updated_at
@@ -196,6 +252,19 @@ func (r *MigrationMappingRepository) GetGlobalByMigrationID(migrationID int64) (
 	return scanMappings(rows)
 }
 
+// DeletePending removes the status, priority and assignee rows that have not been mapped yet,
+// so that a following discovery only re-creates the ones still present in the source.
+func (r *MigrationMappingRepository) DeletePending(migrationID int64) error {
+	_, err := r.db.Exec(`
+		DELETE FROM migration_mappings
+		WHERE migration_id = ? AND status = 'pending' AND type IN ('status', 'priority', 'assignee')
+	`, migrationID)
+	if err != nil {
+		return fmt.Errorf("delete pending mappings: %w", err)
+	}
+	return nil
+}
+
 func scanMappings(rows *sql.Rows) ([]MigrationMapping, error) {
 	var mappings []MigrationMapping
 	for rows.Next() {
@@ -253,12 +322,14 @@ func (r *MigrationMappingRepository) AllMapped(migrationID int64) (bool, error)
 	return count == 0, nil
 }
 
-// UpsertCustomField inserts a custom field mapping row for a specific container if it doesn't exist.
+// UpsertCustomField inserts a custom field mapping row for a specific container if it doesn't exist,
+// and refreshes the known source options of an existing row without touching its configuration.
 func (r *MigrationMappingRepository) UpsertCustomField(
 	migrationID int64, fieldID, fieldName, fieldType string,
+	options []CustomFieldOptionMetadata,
 	sourceContainerID *string,
 ) error {
-	b, err := json.Marshal(CustomFieldMetadata{Name: fieldName, FieldType: fieldType})
+	b, err := json.Marshal(CustomFieldMetadata{Name: fieldName, FieldType: fieldType, Options: options})
 	if err != nil {
 		return fmt.Errorf("marshal custom field metadata: %w", err)
 	}
@@ -270,6 +341,63 @@ func (r *MigrationMappingRepository) UpsertCustomField(
 	if err != nil {
 		return fmt.Errorf("upsert custom field: %w", err)
 	}
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("marshal custom field options: %w", err)
+	}
+	_, err = r.db.Exec(`
+		UPDATE migration_mappings
+		SET metadata = json_set(COALESCE(metadata, '{}'), '$.options', json(?))
+		WHERE migration_id = ? AND type = 'custom_field' AND source_value = ? AND source_container_id IS ?
+	`, string(optionsJSON), migrationID, fieldID, sourceContainerID)
+	if err != nil {
+		return fmt.Errorf("refresh custom field options: %w", err)
+	}
+	return nil
+}
+
+// UpdateCustomFieldMapping stores the destination field, value conversion and per-option mapping
+// chosen for a source custom field. An empty destFieldID resets to the default name-based lookup.
+func (r *MigrationMappingRepository) UpdateCustomFieldMapping(
+	migrationID int64, fieldID string, sourceContainerID *string,
+	destFieldID, conversion string, optionMap map[string]string,
+) error {
+	var metaStr sql.NullString
+	err := r.db.QueryRow(`
+		SELECT metadata FROM migration_mappings
+		WHERE migration_id = ? AND type = 'custom_field' AND source_value = ? AND source_container_id IS ?
+	`, migrationID, fieldID, sourceContainerID).Scan(&metaStr)
+	if err != nil {
+		return fmt.Errorf("get custom field mapping: %w", err)
+	}
+
+	var meta CustomFieldMetadata
+	if metaStr.Valid {
+		if err := json.Unmarshal([]byte(metaStr.String), &meta); err != nil {
+			return fmt.Errorf("parse custom field metadata: %w", err)
+		}
+	}
+	meta.Conversion = conversion
+	meta.OptionMap = optionMap
+
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("marshal custom field metadata: %w", err)
+	}
+
+	var destValue *string
+	if destFieldID != "" {
+		destValue = &destFieldID
+	}
+	_, err = r.db.Exec(`
+		UPDATE migration_mappings
+		SET dest_value = ?, metadata = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE migration_id = ? AND type = 'custom_field' AND source_value = ? AND source_container_id IS ?
+	`, destValue, string(b), migrationID, fieldID, sourceContainerID)
+	if err != nil {
+		return fmt.Errorf("update custom field mapping: %w", err)
+	}
 	return nil
 }
 
@@ -310,14 +438,14 @@ func (r *MigrationMappingRepository) GetCustomFields(
 
 	if containerID == nil {
 		rows, err = r.db.Query(`
-			SELECT source_value, status, metadata
+			SELECT source_value, dest_value, status, metadata
 			FROM migration_mappings
 			WHERE migration_id = ? AND type = 'custom_field' AND source_container_id IS NULL
 			ORDER BY created_at ASC
 		`, migrationID)
 	} else {
 		rows, err = r.db.Query(`
-			SELECT source_value, status, metadata
+			SELECT source_value, dest_value, status, metadata
 			FROM migration_mappings
 			WHERE migration_id = ? AND type = 'custom_field' AND source_container_id = ?
 			ORDER BY created_at ASC
@@ -327,52 +455,65 @@ func (r *MigrationMappingRepository) GetCustomFields(
 		return nil, fmt.Errorf("get custom fields: %w", err)
 	}
 	defer rows.Close()
+	return scanCustomFieldRows(rows)
+}
+
+// GetEnabledCustomFields returns the enabled custom field rows of a migration across all
+// containers, keyed by source field ID (used during execution to build the custom field mapping).
+// When a field is enabled in several containers, the row with a configured destination field wins.
+func (r *MigrationMappingRepository) GetEnabledCustomFields(
+	migrationID int64,
+) (map[string]CustomFieldRow, error) {
+	rows, err := r.db.Query(`
+		SELECT source_value, dest_value, status, metadata
+		FROM migration_mappings
+		WHERE migration_id = ? AND type = 'custom_field' AND status = 'enabled'
+		ORDER BY dest_value IS NULL, updated_at DESC
+	`, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get enabled custom fields: %w", err)
+	}
+	defer rows.Close()
 
+	fields, err := scanCustomFieldRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]CustomFieldRow, len(fields))
+	for _, f := range fields {
+		if _, ok := result[f.FieldID]; !ok {
+			result[f.FieldID] = f
+		}
+	}
+	return result, nil
+}
+
+func scanCustomFieldRows(rows *sql.Rows) ([]CustomFieldRow, error) {
 	var result []CustomFieldRow
 	for rows.Next() {
 		var fieldID, status string
-		var metaStr sql.NullString
-		if err := rows.Scan(&fieldID, &status, &metaStr); err != nil {
+		var destValue, metaStr sql.NullString
+		if err := rows.Scan(&fieldID, &destValue, &status, &metaStr); err != nil {
 			return nil, fmt.Errorf("scan custom field: %w", err)
 		}
 		row := CustomFieldRow{
 			FieldID: fieldID,
 			Enabled: status == string(MappingStatusEnabled),
 		}
+		if destValue.Valid {
+			row.DestFieldID = &destValue.String
+		}
 		if metaStr.Valid {
 			var meta CustomFieldMetadata
 			if err := json.Unmarshal([]byte(metaStr.String), &meta); err == nil {
 				row.FieldName = meta.Name
 				row.FieldType = meta.FieldType
+				row.Options = meta.Options
+				row.Conversion = meta.Conversion
+				row.OptionMap = meta.OptionMap
 			}
 		}
 		result = append(result, row)
 	}
 	return result, rows.Err()
 }
-
-// GetEnabledCustomFieldIDs returns all distinct enabled custom field IDs for a migration,
-// across all containers (used during execution to build the custom field mapping).
-func (r *MigrationMappingRepository) GetEnabledCustomFieldIDs(
-	migrationID int64,
-) (map[string]bool, error) {
-	rows, err := r.db.Query(`
-		SELECT DISTINCT source_value
-		FROM migration_mappings
-		WHERE migration_id = ? AND type = 'custom_field' AND status = 'enabled'
-	`, migrationID)
-	if err != nil {
-		return nil, fmt.Errorf("get enabled custom field IDs: %w", err)
-	}
-	defer rows.Close()
-
-	result := make(map[string]bool)
-	for rows.Next() {
-		var fieldID string
-		if err := rows.Scan(&fieldID); err != nil {
-			return nil, fmt.Errorf("scan custom field ID: %w", err)
-		}
-		result[fieldID] = true
-	}
-	return result, rows.Err()
-}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -11,14 +13,28 @@ type MigrationStatus string
 const (
 	MigrationStatusPendingConfiguration MigrationStatus = "pending_configuration"
 	MigrationStatusReadyToStart         MigrationStatus = "ready_to_start"
+	MigrationStatusQueued               MigrationStatus = "queued"
 	MigrationStatusRunning              MigrationStatus = "running"
 	MigrationStatusCompleted            MigrationStatus = "completed"
 	MigrationStatusCompletedWithErrors  MigrationStatus = "completed_with_errors"
 	MigrationStatusFailed               MigrationStatus = "failed"
+	MigrationStatusRollingBack          MigrationStatus = "rolling_back"
+	MigrationStatusRolledBack           MigrationStatus = "rolled_back"
+	MigrationStatusRolledBackWithErrors MigrationStatus = "rolled_back_with_errors"
 )
 
+// FilterRule restricts which source tasks a migration picks up. Rules are stored as
+// JSON on the migration and a task must match all of them to be migrated.
+type FilterRule struct {
+	Field    string   `json:"field"`
+	Operator string   `json:"operator"`
+	Value    string   `json:"value,omitempty"`
+	Values   []string `json:"values,omitempty"`
+	FieldID  string   `json:"field_id,omitempty"` // custom field ID, only for field "custom_field"
+}
+
 type Migration struct {
-	ID              int64  `json:"id"`
+	ID              int64 `json:"id"`
 	Source          string
 	Destination     string
 	SourceProjectID string
@@ -29,8 +45,30 @@ type Migration struct {
 	TotalTasks      int
 	CompletedTasks  int
 	FailedTasks     int
-	StartedAt       time.Time
-	CompletedAt     *time.Time
+	ExcludedTasks   int
+	FilterRules     []FilterRule
+	TimeZone        string // IANA zone used for all-day dates; empty means UTC
+	BacklinkMode    string // how created tasks link back to their source task; empty disables it
+	BacklinkFieldID string // destination custom field receiving the source URL, for BacklinkMode "field"
+	ReverseBacklink bool   // also comment the destination URL on the source task
+	BatchID         *int64 // batch the migration belongs to, if any
+	// ContainerStrategy is how source containers are recreated: "list" (default) maps each
+	// onto a destination container, "status" puts them all in DestListID as task statuses.
+	ContainerStrategy string
+	ScheduledAt       *time.Time // next time the scheduler starts the migration, in UTC
+	Schedule          string     // cron expression for recurring delta runs; empty for a one-off run
+	OwnerID           *int64     // user who created the migration; nil for migrations only admins see
+	// SourceConnectionID and DestConnectionID are the connections the migration reaches
+	// its providers through; nil uses the server's credentials.
+	SourceConnectionID *int64
+	DestConnectionID   *int64
+	StartedAt          time.Time
+	CompletedAt        *time.Time
+
+	RollbackTotalTasks     int
+	RollbackCompletedTasks int
+	RollbackFailedTasks    int
+	RolledBackAt           *time.Time
 }
 
 type MigrationRepository struct {
@@ -41,11 +79,30 @@ func NewMigrationRepository(db *sql.DB) *MigrationRepository {
 	return &MigrationRepository{db: db}
 }
 
+func marshalFilterRules(rules []FilterRule) (*string, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("marshal filter rules: %w", err)
+	}
+	s := string(b)
+	return &s, nil
+}
+
 func (r *MigrationRepository) Create(migration *Migration) (int64, error) {
+	filterRules, err := marshalFilterRules(migration.FilterRules)
+	if err != nil {
+		return 0, err
+	}
+
 	query := `
 		INSERT INTO migrations
-			(source, destination, source_project_id, dest_list_id, dest_workspace_id, dest_space_id, status, total_tasks)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			(source, destination, source_project_id, dest_list_id, dest_workspace_id, dest_space_id, status, total_tasks, filter_rules, time_zone,
+			 backlink_mode, backlink_field_id, reverse_backlink, batch_id, container_strategy, owner_id,
+			 source_connection_id, dest_connection_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := r.db.Exec(query,
@@ -57,6 +114,16 @@ func (r *MigrationRepository) Create(migration *Migration) (int64, error) {
 		migration.DestSpaceID,
 		migration.Status,
 		migration.TotalTasks,
+		filterRules,
+		migration.TimeZone,
+		migration.BacklinkMode,
+		migration.BacklinkFieldID,
+		migration.ReverseBacklink,
+		migration.BatchID,
+		migration.ContainerStrategy,
+		migration.OwnerID,
+		migration.SourceConnectionID,
+		migration.DestConnectionID,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("create migration: %w", err)
@@ -83,6 +150,23 @@ func (r *MigrationRepository) UpdateStatus(id int64, status MigrationStatus) err
 	return nil
 }
 
+// MarkQueued moves a migration that is in one of the from statuses to queued. It
+// reports false, changing nothing, when the migration is in another status, so
+// concurrent requests cannot both queue it.
+func (r *MigrationRepository) MarkQueued(id int64, from []MigrationStatus) (bool, error) {
+	in, args := statusIn(from)
+	query := `UPDATE migrations SET status = ? WHERE id = ? AND status IN (` + in + `)`
+	result, err := r.db.Exec(query, append([]any{MigrationStatusQueued, id}, args...)...)
+	if err != nil {
+		return false, fmt.Errorf("mark migration queued: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("mark migration queued: %w", err)
+	}
+	return n > 0, nil
+}
+
 func (r *MigrationRepository) Complete(id int64, status MigrationStatus) error {
 	query := `UPDATE migrations SET status = ?, completed_at = CURRENT_TIMESTAMP WHERE id = ?`
 	_, err := r.db.Exec(query, status, id)
@@ -101,18 +185,138 @@ func (r *MigrationRepository) UpdateTotalTasks(id int64, totalTasks int) error {
 	return nil
 }
 
-func (r *MigrationRepository) GetMigration(id int64) (Migration, error) {
+func (r *MigrationRepository) UpdateExcludedTasks(id int64, excludedTasks int) error {
+	query := `UPDATE migrations SET excluded_tasks = ? WHERE id = ?`
+	_, err := r.db.Exec(query, excludedTasks, id)
+	if err != nil {
+		return fmt.Errorf("update excluded tasks: %w", err)
+	}
+	return nil
+}
+
+func (r *MigrationRepository) UpdateFilterRules(id int64, rules []FilterRule) error {
+	filterRules, err := marshalFilterRules(rules)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE migrations SET filter_rules = ? WHERE id = ?`
+	if _, err := r.db.Exec(query, filterRules, id); err != nil {
+		return fmt.Errorf("update filter rules: %w", err)
+	}
+	return nil
+}
+
+// StartRollback moves a migration that is in one of the from statuses to rolling_back.
+// It reports false, changing nothing, when the migration is in another status, so
+// concurrent requests cannot both start a rollback.
+func (r *MigrationRepository) StartRollback(id int64, totalTasks int, from []MigrationStatus) (bool, error) {
+	in, args := statusIn(from)
 	query := `
-		SELECT id, source, destination, source_project_id, dest_list_id, dest_workspace_id, dest_space_id,
-		       status, total_tasks, completed_tasks, failed_tasks, started_at, completed_at
-		FROM migrations
-		WHERE id = ?
+		UPDATE migrations
+		SET status = ?, rollback_total_tasks = ?, rollback_completed_tasks = 0, rollback_failed_tasks = 0, rolled_back_at = NULL
+		WHERE id = ? AND status IN (` + in + `)
 	`
+	result, err := r.db.Exec(query, append([]any{MigrationStatusRollingBack, totalTasks, id}, args...)...)
+	if err != nil {
+		return false, fmt.Errorf("start migration rollback: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("start migration rollback: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r *MigrationRepository) UpdateRollbackProgress(id int64, completed, failed int) error {
+	query := `UPDATE migrations SET rollback_completed_tasks = ?, rollback_failed_tasks = ? WHERE id = ?`
+	_, err := r.db.Exec(query, completed, failed, id)
+	if err != nil {
+		return fmt.Errorf("update rollback progress: %w", err)
+	}
+	return nil
+}
+
+func (r *MigrationRepository) CompleteRollback(id int64, status MigrationStatus) error {
+	query := `UPDATE migrations SET status = ?, rolled_back_at = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err := r.db.Exec(query, status, id)
+	if err != nil {
+		return fmt.Errorf("complete migration rollback: %w", err)
+	}
+	return nil
+}
+
+// statusIn returns the placeholders and arguments of an IN (...) list of statuses.
+func statusIn(statuses []MigrationStatus) (string, []any) {
+	args := make([]any, len(statuses))
+	for i, st := range statuses {
+		args[i] = st
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(statuses)), ", "), args
+}
+
+// sqliteTime formats t the way SQLite's CURRENT_TIMESTAMP does, so stored times compare
+// correctly as text.
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(time.DateTime)
+}
+
+// UpdateSchedule sets when the scheduler next starts the migration. A nil scheduledAt
+// clears the schedule.
+func (r *MigrationRepository) UpdateSchedule(id int64, scheduledAt *time.Time, schedule string) error {
+	var at *string
+	if scheduledAt != nil {
+		s := sqliteTime(*scheduledAt)
+		at = &s
+	}
+	query := `UPDATE migrations SET scheduled_at = ?, schedule = ? WHERE id = ?`
+	if _, err := r.db.Exec(query, at, schedule, id); err != nil {
+		return fmt.Errorf("update migration schedule: %w", err)
+	}
+	return nil
+}
+
+// GetDueScheduled returns the migrations whose scheduled time is at or before now.
+func (r *MigrationRepository) GetDueScheduled(now time.Time) ([]Migration, error) {
+	query := `SELECT ` + migrationColumns + ` FROM migrations WHERE scheduled_at IS NOT NULL AND scheduled_at <= ? ORDER BY scheduled_at, id`
+
+	rows, err := r.db.Query(query, sqliteTime(now))
+	if err != nil {
+		return nil, fmt.Errorf("get due migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var migrations []Migration
+	for rows.Next() {
+		m, err := scanMigration(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan migration: %w", err)
+		}
+		migrations = append(migrations, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate due migrations: %w", err)
+	}
+	return migrations, nil
+}
+
+const migrationColumns = `
+	id, source, destination, source_project_id, dest_list_id, dest_workspace_id, dest_space_id,
+	status, total_tasks, completed_tasks, failed_tasks, started_at, completed_at,
+	rollback_total_tasks, rollback_completed_tasks, rollback_failed_tasks, rolled_back_at,
+	excluded_tasks, filter_rules, time_zone, backlink_mode, backlink_field_id, reverse_backlink,
+	batch_id, container_strategy, scheduled_at, schedule, owner_id, source_connection_id, dest_connection_id
+`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
 
+func scanMigration(row rowScanner) (Migration, error) {
 	var m Migration
-	var destWorkspaceID, destSpaceID sql.NullString
+	var destWorkspaceID, destSpaceID, filterRules, timeZone, backlinkMode, backlinkFieldID, containerStrategy, schedule sql.NullString
+	var reverseBacklink sql.NullBool
 
-	err := r.db.QueryRow(query, id).Scan(
+	err := row.Scan(
 		&m.ID,
 		&m.Source,
 		&m.Destination,
@@ -126,9 +330,31 @@ func (r *MigrationRepository) GetMigration(id int64) (Migration, error) {
 		&m.FailedTasks,
 		&m.StartedAt,
 		&m.CompletedAt,
+		&m.RollbackTotalTasks,
+		&m.RollbackCompletedTasks,
+		&m.RollbackFailedTasks,
+		&m.RolledBackAt,
+		&m.ExcludedTasks,
+		&filterRules,
+		&timeZone,
+		&backlinkMode,
+		&backlinkFieldID,
+		&reverseBacklink,
+		&m.BatchID,
+		&containerStrategy,
+		&m.ScheduledAt,
+		&schedule,
+		&m.OwnerID,
+		&m.SourceConnectionID,
+		&m.DestConnectionID,
 	)
 	if err != nil {
-		return Migration{}, fmt.Errorf("get migration: %w", err)
+		return Migration{}, err
+	}
+	if filterRules.Valid && filterRules.String != "" {
+		if err := json.Unmarshal([]byte(filterRules.String), &m.FilterRules); err != nil {
+			return Migration{}, fmt.Errorf("parse filter rules: %w", err)
+		}
 	}
 
 	if destWorkspaceID.Valid {
@@ -137,17 +363,29 @@ func (r *MigrationRepository) GetMigration(id int64) (Migration, error) {
 	if destSpaceID.Valid {
 		m.DestSpaceID = destSpaceID.String
 	}
+	m.TimeZone = timeZone.String
+	m.BacklinkMode = backlinkMode.String
+	m.BacklinkFieldID = backlinkFieldID.String
+	m.ReverseBacklink = reverseBacklink.Bool
+	m.ContainerStrategy = containerStrategy.String
+	m.Schedule = schedule.String
+
+	return m, nil
+}
+
+func (r *MigrationRepository) GetMigration(id int64) (Migration, error) {
+	query := `SELECT ` + migrationColumns + ` FROM migrations WHERE id = ?`
+
+	m, err := scanMigration(r.db.QueryRow(query, id))
+	if err != nil {
+		return Migration{}, fmt.Errorf("get migration: %w", err)
+	}
 
 	return m, nil
 }
 
 func (r *MigrationRepository) GetMigrations() ([]Migration, error) {
-	query := `
-		SELECT id, source, destination, source_project_id, dest_list_id, dest_workspace_id, dest_space_id,
-		       status, total_tasks, completed_tasks, failed_tasks, started_at, completed_at
-		FROM migrations
-		ORDER BY started_at DESC
-	`
+	query := `SELECT ` + migrationColumns + ` FROM migrations ORDER BY started_at DESC`
 
 	rows, err := r.db.Query(query)
 	if err != nil {
@@ -157,35 +395,10 @@ func (r *MigrationRepository) GetMigrations() ([]Migration, error) {
 
 	var migrations []Migration
 	for rows.Next() {
-		var m Migration
-		var destWorkspaceID, destSpaceID sql.NullString
-
-		err := rows.Scan(
-			&m.ID,
-			&m.Source,
-			&m.Destination,
-			&m.SourceProjectID,
-			&m.DestListID,
-			&destWorkspaceID,
-			&destSpaceID,
-			&m.Status,
-			&m.TotalTasks,
-			&m.CompletedTasks,
-			&m.FailedTasks,
-			&m.StartedAt,
-			&m.CompletedAt,
-		)
+		m, err := scanMigration(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan migration: %w", err)
 		}
-
-		if destWorkspaceID.Valid {
-			m.DestWorkspaceID = destWorkspaceID.String
-		}
-		if destSpaceID.Valid {
-			m.DestSpaceID = destSpaceID.String
-		}
-
 		migrations = append(migrations, m)
 	}
 
@@ -195,3 +408,51 @@ func (r *MigrationRepository) GetMigrations() ([]Migration, error) {
 
 	return migrations, nil
 }
+
+// GetByBatchID returns the migrations of a batch in the order they were created.
+func (r *MigrationRepository) GetByBatchID(batchID int64) ([]Migration, error) {
+	query := `SELECT ` + migrationColumns + ` FROM migrations WHERE batch_id = ? ORDER BY id`
+
+	rows, err := r.db.Query(query, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("get batch migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var migrations []Migration
+	for rows.Next() {
+		m, err := scanMigration(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan migration: %w", err)
+		}
+		migrations = append(migrations, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate batch migrations: %w", err)
+	}
+	return migrations, nil
+}
+
+// GetByOwnerID returns the migrations of a user, newest first.
+func (r *MigrationRepository) GetByOwnerID(ownerID int64) ([]Migration, error) {
+	query := `SELECT ` + migrationColumns + ` FROM migrations WHERE owner_id = ? ORDER BY started_at DESC`
+
+	rows, err := r.db.Query(query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("get user migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var migrations []Migration
+	for rows.Next() {
+		m, err := scanMigration(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan migration: %w", err)
+		}
+		migrations = append(migrations, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate user migrations: %w", err)
+	}
+	return migrations, nil
+}
@@ -9,8 +9,9 @@ import (
 type TaskMappingStatus string
 
 const (
-	TaskMappingStatusSuccess TaskMappingStatus = "success"
-	TaskMappingStatusFailed  TaskMappingStatus = "failed"
+	TaskMappingStatusSuccess    TaskMappingStatus = "success"
+	TaskMappingStatusFailed     TaskMappingStatus = "failed"
+	TaskMappingStatusRolledBack TaskMappingStatus = "rolled_back"
 )
 
 type TaskMapping struct {
@@ -34,20 +35,61 @@ func NewTaskMappingRepository(db *sql.DB) *TaskMappingRepository {
 func (r *TaskMappingRepository) Create(mapping *TaskMapping) error {
 	query := `
 		INSERT INTO task_mappings (migration_id, source_task_id, dest_task_id, status, error_message)
-        VALUES (?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?)
 	`
-	
+
 	_, err := r.db.Exec(query,
-        mapping.MigrationID,
-        mapping.SourceTaskID,
-        mapping.DestTaskID,
-        mapping.Status,
-        mapping.ErrorMessage,
-    )
-	
+		mapping.MigrationID,
+		mapping.SourceTaskID,
+		mapping.DestTaskID,
+		mapping.Status,
+		mapping.ErrorMessage,
+	)
+
+	if err != nil {
+		return fmt.Errorf("create task mapping: %w", err)
+	}
+
+	return nil
+}
+
+// GetByMigrationIDAndStatus returns the task mappings of a migration in the given status,
+// oldest first.
+func (r *TaskMappingRepository) GetByMigrationIDAndStatus(migrationID int64, status TaskMappingStatus) ([]TaskMapping, error) {
+	rows, err := r.db.Query(`
+		SELECT id, migration_id, source_task_id, dest_task_id, status, error_message, created_at
+		FROM task_mappings
+		WHERE migration_id = ? AND status = ?
+		ORDER BY id ASC
+	`, migrationID, status)
+	if err != nil {
+		return nil, fmt.Errorf("get task mappings: %w", err)
+	}
+	defer rows.Close()
+
+	var mappings []TaskMapping
+	for rows.Next() {
+		var m TaskMapping
+		var destTaskID, errorMessage sql.NullString
+		if err := rows.Scan(&m.ID, &m.MigrationID, &m.SourceTaskID, &destTaskID, &m.Status, &errorMessage, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan task mapping: %w", err)
+		}
+		m.DestTaskID = destTaskID.String
+		m.ErrorMessage = errorMessage.String
+		mappings = append(mappings, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate task mappings: %w", err)
+	}
+	return mappings, nil
+}
+
+// UpdateStatus changes the status of a single task mapping and records the error message
+// of the last operation on it (empty clears it).
+func (r *TaskMappingRepository) UpdateStatus(id int64, status TaskMappingStatus, errorMessage string) error {
+	_, err := r.db.Exec(`UPDATE task_mappings SET status = ?, error_message = ? WHERE id = ?`, status, errorMessage, id)
 	if err != nil {
-        return fmt.Errorf("create task mapping: %w", err)
-    }
-    
-    return nil
+		return fmt.Errorf("update task mapping status: %w", err)
+	}
+	return nil
 }
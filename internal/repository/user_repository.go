@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrDuplicateUserEmail is returned when a user with the same email already exists.
+var ErrDuplicateUserEmail = errors.New("a user with this email already exists")
+
+type UserRole string
+
+const (
+	UserRoleAdmin  UserRole = "admin"
+	UserRoleMember UserRole = "member"
+)
+
+// User is an API user. Users authenticate with an API key of which only the hash is
+// stored; members see their own migrations, admins see every migration.
+type User struct {
+	ID        int64 `json:"id"`
+	Name      string
+	Email     string
+	Role      UserRole
+	CreatedAt time.Time
+}
+
+func (u User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) Create(user *User, apiKeyHash string) (int64, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO users (name, email, role, api_key_hash) VALUES (?, ?, ?, ?)
+	`, user.Name, user.Email, user.Role, apiKeyHash)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed: users.email") {
+			return 0, fmt.Errorf("create user %q: %w", user.Email, ErrDuplicateUserEmail)
+		}
+		return 0, fmt.Errorf("create user: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+const userColumns = `id, name, email, role, created_at`
+
+func scanUser(row rowScanner) (User, error) {
+	var u User
+	if err := row.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.CreatedAt); err != nil {
+		return User{}, err
+	}
+	return u, nil
+}
+
+func (r *UserRepository) GetUser(id int64) (User, error) {
+	u, err := scanUser(r.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE id = ?`, id))
+	if err != nil {
+		return User{}, fmt.Errorf("get user: %w", err)
+	}
+	return u, nil
+}
+
+// GetByAPIKeyHash returns the user holding an API key. The error wraps sql.ErrNoRows when
+// no user does.
+func (r *UserRepository) GetByAPIKeyHash(hash string) (User, error) {
+	u, err := scanUser(r.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE api_key_hash = ?`, hash))
+	if err != nil {
+		return User{}, fmt.Errorf("get user by api key: %w", err)
+	}
+	return u, nil
+}
+
+func (r *UserRepository) GetUsers() ([]User, error) {
+	rows, err := r.db.Query(`SELECT ` + userColumns + ` FROM users ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("get users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate users: %w", err)
+	}
+	return users, nil
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/client/archive"
+	"github.com/TWRT/integration-mapper/internal/models"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+type archiveExportRepo interface {
+	Create(export *repository.ArchiveExport) (int64, error)
+	Complete(id int64, fileName string, totalTasks int) error
+	Fail(id int64, errorMessage string) error
+	GetArchiveExport(id int64) (repository.ArchiveExport, error)
+	GetArchiveExports() ([]repository.ArchiveExport, error)
+	GetByOwnerID(ownerID int64) ([]repository.ArchiveExport, error)
+}
+
+// ArchiveService exports source projects into archive files. Finished archives are written
+// to the directory the archive provider reads, so they can be migrated from right away.
+type ArchiveService struct {
+	providers   *client.Registry
+	exportRepo  archiveExportRepo
+	dir         string
+	connections connectionClients
+}
+
+func NewArchiveService(providers *client.Registry, exportRepo archiveExportRepo, dir string, connections connectionClients) *ArchiveService {
+	return &ArchiveService{providers: providers, exportRepo: exportRepo, dir: dir, connections: connections}
+}
+
+// ArchiveServiceProvider is the interface consumed by handlers.
+// Allows substitution with mocks in tests.
+type ArchiveServiceProvider interface {
+	StartExport(input ArchiveExportInput) (int64, error)
+	GetExport(id int64) (repository.ArchiveExport, error)
+	GetExports() ([]repository.ArchiveExport, error)
+	GetExportsFor(user repository.User) ([]repository.ArchiveExport, error)
+	CanAccessExport(user repository.User, exportID int64) (bool, error)
+	OpenExport(id int64) (repository.ArchiveExport, *os.File, error)
+}
+
+type ArchiveExportInput struct {
+	Provider    string
+	WorkspaceID string // members are exported from it when set
+	ProjectID   string
+	OwnerID     *int64 // user starting the export; nil for admin-only exports
+	// ConnectionID is a connection of the owner the project is read through; nil uses
+	// the server's credentials, which needs ServerCredentials.
+	ConnectionID      *int64
+	ServerCredentials bool // the owner may use the server's credentials; admins only
+}
+
+// StartExport validates the input, records the export and runs it in the background.
+func (s *ArchiveService) StartExport(input ArchiveExportInput) (int64, error) {
+	if input.ProjectID == "" {
+		return 0, fmt.Errorf("%w: project_id is required", ErrInvalidInput)
+	}
+	if input.Provider == archive.Name {
+		return 0, fmt.Errorf("%w: archives cannot be exported again", ErrInvalidInput)
+	}
+	d, ok := s.providers.Get(input.Provider)
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown provider %q", ErrInvalidInput, input.Provider)
+	}
+	source, err := s.sourceClient(d, input)
+	if err != nil {
+		return 0, err
+	}
+
+	export := &repository.ArchiveExport{
+		Provider:    input.Provider,
+		WorkspaceID: input.WorkspaceID,
+		ProjectID:   input.ProjectID,
+		Status:      repository.ArchiveExportStatusRunning,
+		OwnerID:     input.OwnerID,
+	}
+	id, err := s.exportRepo.Create(export)
+	if err != nil {
+		return 0, fmt.Errorf("create archive export: %w", err)
+	}
+	export.ID = id
+
+	// Create an independent context — not tied to the HTTP request lifecycle.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+	go func() {
+		defer cancel()
+		s.executeExport(ctx, source, *export)
+	}()
+	return id, nil
+}
+
+// sourceClient returns the client an export reads its project with.
+func (s *ArchiveService) sourceClient(d client.Descriptor, input ArchiveExportInput) (client.IntegrationProvider, error) {
+	if input.ConnectionID == nil {
+		c, err := serverClient(d, input.ServerCredentials)
+		if err != nil {
+			return nil, fmt.Errorf("%w, set connection_id", err)
+		}
+		return c, nil
+	}
+	if err := s.connections.CheckConnection(*input.ConnectionID, input.OwnerID, d.Name); err != nil {
+		return nil, err
+	}
+	return s.connections.ConnectionClient(*input.ConnectionID, d.Name)
+}
+
+func (s *ArchiveService) GetExport(id int64) (repository.ArchiveExport, error) {
+	export, err := s.exportRepo.GetArchiveExport(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ArchiveExport{}, fmt.Errorf("%w: archive export %d", ErrNotFound, id)
+	}
+	if err != nil {
+		return repository.ArchiveExport{}, fmt.Errorf("get archive export: %w", err)
+	}
+	return export, nil
+}
+
+func (s *ArchiveService) GetExports() ([]repository.ArchiveExport, error) {
+	exports, err := s.exportRepo.GetArchiveExports()
+	if err != nil {
+		return nil, fmt.Errorf("get archive exports: %w", err)
+	}
+	return exports, nil
+}
+
+// OpenExport opens the archive file of a completed export. The caller closes the file.
+func (s *ArchiveService) OpenExport(id int64) (repository.ArchiveExport, *os.File, error) {
+	export, err := s.GetExport(id)
+	if err != nil {
+		return repository.ArchiveExport{}, nil, err
+	}
+	if export.Status != repository.ArchiveExportStatusCompleted {
+		return repository.ArchiveExport{}, nil, fmt.Errorf("%w: export is %s", ErrInvalidMigrationState, export.Status)
+	}
+	f, err := os.Open(filepath.Join(s.dir, export.FileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return repository.ArchiveExport{}, nil, fmt.Errorf("%w: archive file of export %d", ErrNotFound, id)
+	}
+	if err != nil {
+		return repository.ArchiveExport{}, nil, fmt.Errorf("open archive: %w", err)
+	}
+	return export, f, nil
+}
+
+// ---- Execution ----
+
+func (s *ArchiveService) executeExport(ctx context.Context, source client.IntegrationProvider, export repository.ArchiveExport) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("archive export panicked", "export_id", export.ID, "panic", r, "stack", string(debug.Stack()))
+			if err := s.exportRepo.Fail(export.ID, fmt.Sprintf("internal error: %v", r)); err != nil {
+				slog.Error("failed to record export failure", "export_id", export.ID, "error", err)
+			}
+		}
+	}()
+
+	fileName := fmt.Sprintf("%s-%d-%s.zip", export.Provider, export.ID, time.Now().UTC().Format("20060102-150405"))
+	totalTasks, err := s.writeArchive(ctx, source, export, fileName)
+	if err != nil {
+		slog.Error("archive export failed", "export_id", export.ID, "error", err)
+		if err := s.exportRepo.Fail(export.ID, err.Error()); err != nil {
+			slog.Error("failed to record export failure", "export_id", export.ID, "error", err)
+		}
+		return
+	}
+	if err := s.exportRepo.Complete(export.ID, fileName, totalTasks); err != nil {
+		slog.Error("failed to complete archive export", "export_id", export.ID, "error", err)
+		return
+	}
+	slog.Info("archive export completed", "export_id", export.ID, "file", fileName, "tasks", totalTasks)
+}
+
+// writeArchive writes the archive to a temporary file that only replaces fileName once
+// complete, so the archive provider never sees a partial archive.
+func (s *ArchiveService) writeArchive(ctx context.Context, source client.IntegrationProvider, export repository.ArchiveExport, fileName string) (int, error) {
+	tmp, err := os.CreateTemp(s.dir, fileName+".*.partial")
+	if err != nil {
+		return 0, fmt.Errorf("create archive file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // gone after the rename on success
+
+	w := archive.NewWriter(tmp)
+	snapshot, err := s.collect(ctx, source, export, w)
+	if err != nil {
+		return 0, errors.Join(err, tmp.Close())
+	}
+	if err := w.Close(*snapshot); err != nil {
+		return 0, errors.Join(err, tmp.Close())
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("close archive file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, fileName)); err != nil {
+		return 0, fmt.Errorf("save archive file: %w", err)
+	}
+	return len(snapshot.Tasks), nil
+}
+
+// collect reads everything the source offers about the project. Attachment files are
+// streamed into w as they are downloaded.
+func (s *ArchiveService) collect(ctx context.Context, source client.IntegrationProvider, export repository.ArchiveExport, w *archive.Writer) (*archive.Snapshot, error) {
+	snapshot := &archive.Snapshot{Manifest: archive.Manifest{
+		Provider:    export.Provider,
+		WorkspaceID: export.WorkspaceID,
+		ProjectID:   export.ProjectID,
+		ProjectName: s.projectName(ctx, source, export),
+		ExportedAt:  time.Now().UTC(),
+	}}
+
+	if export.WorkspaceID != "" {
+		members, err := source.GetMembers(ctx, export.WorkspaceID)
+		if err != nil {
+			return nil, fmt.Errorf("get members: %w", err)
+		}
+		for _, m := range members {
+			snapshot.Members = append(snapshot.Members, archive.User{ID: m.ID, Name: m.Name, Email: m.Email})
+		}
+	}
+
+	// Without containers the project's tasks are archived under a single unnamed container.
+	containers := []client.Container{{ID: export.ProjectID}}
+	cp, hasContainers := source.(client.ContainerProvider)
+	if hasContainers {
+		var err error
+		if containers, err = cp.GetSourceContainers(ctx, export.ProjectID); err != nil {
+			return nil, fmt.Errorf("get containers: %w", err)
+		}
+	}
+
+	fp, hasFields := source.(client.FieldProvider)
+	for _, ct := range containers {
+		containerID := ct.ID
+		if !hasContainers {
+			containerID = ""
+		}
+		statuses, err := source.GetListStatuses(ctx, ct.ID)
+		if err != nil {
+			slog.Warn("could not get container statuses", "export_id", export.ID, "container_id", ct.ID, "error", err)
+		}
+		if hasContainers {
+			snapshot.Containers = append(snapshot.Containers, archive.Container{ID: ct.ID, Name: ct.Name, Statuses: statuses})
+		}
+
+		if hasFields {
+			defs, err := fp.GetFieldDefinitions(ctx, ct.ID)
+			if err != nil {
+				return nil, fmt.Errorf("get custom fields of %s: %w", ct.ID, err)
+			}
+			for _, d := range defs {
+				snapshot.Fields = append(snapshot.Fields, archive.NewField(d, containerID))
+			}
+		}
+
+		var tasks []archive.Task
+		if hasContainers {
+			modelTasks, err := cp.GetTasksByContainer(ctx, ct.ID)
+			if err != nil {
+				return nil, fmt.Errorf("get tasks of %s: %w", ct.ID, err)
+			}
+			for _, t := range modelTasks {
+				tasks = append(tasks, archive.NewTask(t, containerID))
+			}
+		} else {
+			modelTasks, err := source.GetTasks(ctx, export.ProjectID)
+			if err != nil {
+				return nil, fmt.Errorf("get tasks: %w", err)
+			}
+			for _, t := range modelTasks {
+				tasks = append(tasks, archive.NewTask(t, ""))
+			}
+		}
+		for _, t := range tasks {
+			if err := s.collectTaskDetails(ctx, source, export, w, &t); err != nil {
+				return nil, err
+			}
+			snapshot.Tasks = append(snapshot.Tasks, t)
+		}
+	}
+
+	// Sources that only describe their fields as a destination (Asana) are asked for those.
+	if dfp, ok := source.(client.DestFieldProvider); ok && !hasFields {
+		defs, err := dfp.GetDestFieldDefinitions(ctx, export.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("get custom fields: %w", err)
+		}
+		for _, d := range defs {
+			snapshot.Fields = append(snapshot.Fields, archive.NewField(d, ""))
+		}
+	}
+	return snapshot, nil
+}
+
+// collectTaskDetails adds the link, comments and attachments of a task. An attachment that
+// cannot be downloaded is archived without its file rather than failing the export.
+func (s *ArchiveService) collectTaskDetails(ctx context.Context, source client.IntegrationProvider, export repository.ArchiveExport, w *archive.Writer, task *archive.Task) error {
+	if ub, ok := source.(client.TaskURLBuilder); ok {
+		task.URL = ub.TaskURL(task.ID)
+	}
+	if cr, ok := source.(client.CommentReader); ok {
+		comments, err := cr.GetComments(ctx, task.ID)
+		if err != nil {
+			return fmt.Errorf("get comments of task %s: %w", task.ID, err)
+		}
+		for _, c := range comments {
+			task.Comments = append(task.Comments, archive.NewComment(c))
+		}
+	}
+	ar, ok := source.(client.AttachmentReader)
+	if !ok {
+		return nil
+	}
+	attachments, err := ar.GetAttachments(ctx, task.ID)
+	if err != nil {
+		return fmt.Errorf("get attachments of task %s: %w", task.ID, err)
+	}
+	for _, a := range attachments {
+		archived, err := s.downloadAttachment(ctx, ar, w, a)
+		if err != nil {
+			slog.Warn("could not download attachment", "export_id", export.ID, "task_id", task.ID, "attachment", a.Name, "error", err)
+			archived = archive.Attachment{ID: a.ID, Name: a.Name, URL: a.URL, Size: a.Size}
+		}
+		task.Attachments = append(task.Attachments, archived)
+	}
+	return nil
+}
+
+func (s *ArchiveService) downloadAttachment(ctx context.Context, ar client.AttachmentReader, w *archive.Writer, a models.Attachment) (archive.Attachment, error) {
+	r, err := ar.OpenAttachment(ctx, a)
+	if err != nil {
+		return archive.Attachment{}, err
+	}
+	defer r.Close()
+	return w.AddAttachment(a, r)
+}
+
+// projectName looks the project up by browsing the source; it is informational only.
+func (s *ArchiveService) projectName(ctx context.Context, source client.IntegrationProvider, export repository.ArchiveExport) string {
+	b, ok := source.(client.WorkspaceBrowser)
+	if !ok || export.WorkspaceID == "" {
+		return ""
+	}
+	projects, err := b.ListProjects(ctx, export.WorkspaceID)
+	if err != nil {
+		return ""
+	}
+	for _, p := range projects {
+		if p.ID == export.ProjectID {
+			return p.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/client/archive"
+	"github.com/TWRT/integration-mapper/internal/models"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// fakeSource is a source project with two containers, served from memory.
+type fakeSource struct {
+	containers []client.Container
+	statuses   map[string][]string // container → statuses
+	fields     map[string][]models.CustomFieldDefinition
+	tasks      map[string][]models.Task // container → tasks
+	comments   map[string][]models.Comment
+	files      map[string]string // attachment URL → content; missing files fail to download
+	attached   map[string][]models.Attachment
+}
+
+func (f *fakeSource) GetTasks(context.Context, string) ([]models.Task, error) {
+	return nil, errors.New("tasks are read by container")
+}
+
+func (f *fakeSource) CreateTask(context.Context, string, string, models.Task) (*models.Task, error) {
+	return nil, errors.New("read-only")
+}
+
+func (f *fakeSource) GetMembers(context.Context, string) ([]models.Member, error) {
+	return []models.Member{{ID: "u1", Name: "Ann Lee", Email: "ann@example.com"}, {ID: "u2", Name: "Bob"}}, nil
+}
+
+func (f *fakeSource) GetListStatuses(_ context.Context, id string) ([]string, error) {
+	return f.statuses[id], nil
+}
+
+func (f *fakeSource) GetSourceContainers(context.Context, string) ([]client.Container, error) {
+	return f.containers, nil
+}
+
+func (f *fakeSource) GetTasksByContainer(_ context.Context, id string) ([]models.Task, error) {
+	return f.tasks[id], nil
+}
+
+func (f *fakeSource) GetDestContainers(context.Context, string) ([]client.Container, error) {
+	return f.containers, nil
+}
+
+func (f *fakeSource) GetFieldDefinitions(_ context.Context, id string) ([]models.CustomFieldDefinition, error) {
+	return f.fields[id], nil
+}
+
+func (f *fakeSource) TaskURL(taskId string) string {
+	return "https://tasks.example.com/t/" + taskId
+}
+
+func (f *fakeSource) GetComments(_ context.Context, taskId string) ([]models.Comment, error) {
+	return f.comments[taskId], nil
+}
+
+func (f *fakeSource) GetAttachments(_ context.Context, taskId string) ([]models.Attachment, error) {
+	return f.attached[taskId], nil
+}
+
+func (f *fakeSource) OpenAttachment(_ context.Context, a models.Attachment) (io.ReadCloser, error) {
+	content, ok := f.files[a.URL]
+	if !ok {
+		return nil, errors.New("link expired")
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+// An exported project reads back through the archive provider as it was read from the source.
+func TestArchiveExportRoundTrip(t *testing.T) {
+	due := time.Date(2024, 6, 1, 15, 30, 0, 0, time.UTC)
+	commented := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	source := &fakeSource{
+		containers: []client.Container{{ID: "s1", Name: "Backlog"}, {ID: "s2", Name: "Done"}},
+		statuses:   map[string][]string{"s1": {"Open", "Blocked"}, "s2": {"Closed"}},
+		fields: map[string][]models.CustomFieldDefinition{
+			"s1": {{ID: "cf-stage", Name: "Stage", ClickUpType: "drop_down", Options: []models.CustomFieldOption{
+				{ID: "o1", Name: "Design", OrderIndex: 0},
+				{ID: "o2", Name: "Build", OrderIndex: 1},
+			}}},
+			"s2": {{ID: "cf-points", Name: "Points", ClickUpType: "number"}},
+		},
+		tasks: map[string][]models.Task{
+			"s1": {{
+				Id:              "t1",
+				Name:            "Write spec",
+				Description:     "Plain spec",
+				RichDescription: "**Plain** spec",
+				Status:          "Open",
+				Assignees:       []models.TaskAssignee{{ID: "u1", Name: "Ann Lee", Email: "ann@example.com"}},
+				Followers:       []models.TaskAssignee{{ID: "u2", Name: "Bob"}},
+				DueDate:         &due,
+				DueHasTime:      true,
+				TimeZone:        "UTC",
+				Tags:            []string{"docs"},
+				Priority:        "High",
+				CustomFields:    []models.TaskCustomField{{FieldID: "cf-stage", Value: "o2"}},
+				Checklists: []models.Checklist{{Name: "Review", Items: []models.ChecklistItem{
+					{Name: "Legal", Checked: true},
+					{Name: "Security"},
+				}}},
+			}},
+			"s2": {{
+				Id:           "t2",
+				Name:         "Ship it",
+				Status:       "Closed",
+				Completed:    true,
+				CustomFields: []models.TaskCustomField{{FieldID: "cf-points", Value: float64(3)}},
+			}},
+		},
+		comments: map[string][]models.Comment{
+			"t1": {{ID: "c1", Author: models.TaskAssignee{ID: "u2", Name: "Bob"}, Text: "Looks good", CreatedAt: commented}},
+		},
+		files: map[string]string{"https://files.example.com/spec.pdf": "%PDF-1.7 spec"},
+		attached: map[string][]models.Attachment{
+			"t1": {
+				{ID: "a1", Name: "spec.pdf", URL: "https://files.example.com/spec.pdf", Size: 13},
+				{ID: "a2", Name: "gone.png", URL: "https://files.example.com/gone.png", Size: 99},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	s := &ArchiveService{dir: dir}
+	export := repository.ArchiveExport{ID: 1, Provider: "asana", WorkspaceID: "w1", ProjectID: "p1"}
+	total, err := s.writeArchive(context.Background(), source, export, "export.zip")
+	if err != nil {
+		t.Fatalf("writeArchive: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("archived %d tasks, want 2", total)
+	}
+
+	ctx := context.Background()
+	a := archive.NewArchiveClient(dir)
+
+	containers, err := a.GetSourceContainers(ctx, "export.zip")
+	if err != nil {
+		t.Fatalf("GetSourceContainers: %v", err)
+	}
+	wantContainers := []client.Container{{ID: "export.zip|s1", Name: "Backlog"}, {ID: "export.zip|s2", Name: "Done"}}
+	if !reflect.DeepEqual(containers, wantContainers) {
+		t.Errorf("containers = %+v, want %+v", containers, wantContainers)
+	}
+	statuses, err := a.GetListStatuses(ctx, "export.zip|s1")
+	if err != nil {
+		t.Fatalf("GetListStatuses: %v", err)
+	}
+	if !reflect.DeepEqual(statuses, source.statuses["s1"]) {
+		t.Errorf("statuses = %v, want %v", statuses, source.statuses["s1"])
+	}
+
+	for _, ct := range source.containers {
+		defs, err := a.GetFieldDefinitions(ctx, "export.zip|"+ct.ID)
+		if err != nil {
+			t.Fatalf("GetFieldDefinitions(%s): %v", ct.ID, err)
+		}
+		if !reflect.DeepEqual(defs, source.fields[ct.ID]) {
+			t.Errorf("fields of %s = %+v, want %+v", ct.ID, defs, source.fields[ct.ID])
+		}
+
+		tasks, err := a.GetTasksByContainer(ctx, "export.zip|"+ct.ID)
+		if err != nil {
+			t.Fatalf("GetTasksByContainer(%s): %v", ct.ID, err)
+		}
+		want := make([]models.Task, len(source.tasks[ct.ID]))
+		for i, task := range source.tasks[ct.ID] {
+			task.Id = "export.zip#" + task.Id
+			want[i] = task
+		}
+		if !reflect.DeepEqual(tasks, want) {
+			t.Errorf("tasks of %s =\n%+v\nwant\n%+v", ct.ID, tasks, want)
+		}
+	}
+
+	members, err := a.GetMembers(ctx, archive.WorkspaceID)
+	if err != nil {
+		t.Fatalf("GetMembers: %v", err)
+	}
+	if wantMembers, _ := source.GetMembers(ctx, ""); !reflect.DeepEqual(members, wantMembers) {
+		t.Errorf("members = %+v, want %+v", members, wantMembers)
+	}
+
+	if got := a.TaskURL("export.zip#t1"); got != "https://tasks.example.com/t/t1" {
+		t.Errorf("task URL = %q, want the source link", got)
+	}
+	comments, err := a.GetComments(ctx, "export.zip#t1")
+	if err != nil {
+		t.Fatalf("GetComments: %v", err)
+	}
+	if !reflect.DeepEqual(comments, source.comments["t1"]) {
+		t.Errorf("comments = %+v, want %+v", comments, source.comments["t1"])
+	}
+
+	// The downloaded file is served from the archive; the one that failed keeps its link.
+	attachments, err := a.GetAttachments(ctx, "export.zip#t1")
+	if err != nil {
+		t.Fatalf("GetAttachments: %v", err)
+	}
+	if len(attachments) != 2 {
+		t.Fatalf("got %d attachments, want 2", len(attachments))
+	}
+	r, err := a.OpenAttachment(ctx, attachments[0])
+	if err != nil {
+		t.Fatalf("OpenAttachment(%s): %v", attachments[0].URL, err)
+	}
+	content, err := io.ReadAll(r)
+	r.Close()
+	if err != nil || string(content) != "%PDF-1.7 spec" {
+		t.Errorf("archived spec.pdf = %q, %v; want the downloaded content", content, err)
+	}
+	if missed := attachments[1]; missed.URL != "https://files.example.com/gone.png" {
+		t.Errorf("undownloaded attachment URL = %q, want the original link", missed.URL)
+	}
+	if _, err := a.OpenAttachment(ctx, attachments[1]); err == nil {
+		t.Error("OpenAttachment succeeded for a file that is not in the archive")
+	}
+}
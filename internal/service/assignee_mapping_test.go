@@ -0,0 +1,55 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// Followers and mentioned users are discovered as assignee mappings; skipping the ones
+// without a destination account must let the migration start without them.
+func TestSkippedAssigneesDoNotBlockStart(t *testing.T) {
+	repo := repository.NewMigrationMappingRepository(openTestDB(t))
+	s := &MigrationService{migrationMappingRepo: repo}
+	for _, user := range []string{"assignee", "follower", "mentioned"} {
+		if err := repo.UpsertPending(1, repository.MappingTypeAssignee, user, &repository.AssigneeMetadata{Name: user}, nil); err != nil {
+			t.Fatalf("UpsertPending(%s): %v", user, err)
+		}
+	}
+
+	for _, a := range []AssigneeMappingInput{
+		{SourceValue: "assignee", DestValue: "u-1"},
+		{SourceValue: "follower", Skip: true},
+	} {
+		if err := s.saveAssigneeMapping(1, a); err != nil {
+			t.Fatalf("saveAssigneeMapping(%s): %v", a.SourceValue, err)
+		}
+	}
+	if ok, err := repo.AllMapped(1); err != nil || ok {
+		t.Fatalf("AllMapped = %v, %v; want false while the mentioned user is pending", ok, err)
+	}
+
+	if err := s.saveAssigneeMapping(1, AssigneeMappingInput{SourceValue: "mentioned", Skip: true}); err != nil {
+		t.Fatalf("saveAssigneeMapping(mentioned): %v", err)
+	}
+	if ok, err := repo.AllMapped(1); err != nil || !ok {
+		t.Fatalf("AllMapped = %v, %v; want true once the remaining users are skipped", ok, err)
+	}
+
+	globals, err := repo.GetGlobalByMigrationID(1)
+	if err != nil {
+		t.Fatalf("GetGlobalByMigrationID: %v", err)
+	}
+	resolved := make(map[string]string)
+	for _, m := range globals {
+		if m.DestValue != nil {
+			resolved[m.SourceValue] = *m.DestValue
+		}
+	}
+	_, followers := mapCollaborators(nil, []models.TaskAssignee{{ID: "assignee"}, {ID: "follower"}}, resolved, 0)
+	if want := []models.TaskAssignee{{ID: "u-1"}}; !reflect.DeepEqual(followers, want) {
+		t.Errorf("followers = %v, want %v", followers, want)
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// Back-link modes: how each created task points back to the task it was migrated from.
+const (
+	BacklinkModeNone        = ""
+	BacklinkModeComment     = "comment"     // "Migrated from <url>" comment on the created task
+	BacklinkModeDescription = "description" // footer appended to the description
+	BacklinkModeField       = "field"       // source URL written into a destination custom field
+)
+
+// validateBacklink checks the back-link settings of a new migration against what the
+// source and destination clients support.
+func (s *MigrationService) validateBacklink(input CreateMigrationInput) error {
+	switch input.BacklinkMode {
+	case BacklinkModeNone, BacklinkModeComment, BacklinkModeDescription:
+	case BacklinkModeField:
+		if input.BacklinkFieldID == "" {
+			return fmt.Errorf("%w: backlink_field_id is required for backlink mode %q", ErrInvalidInput, BacklinkModeField)
+		}
+	default:
+		return fmt.Errorf("%w: unknown backlink mode %q", ErrInvalidInput, input.BacklinkMode)
+	}
+	if input.BacklinkMode == BacklinkModeNone && !input.ReverseBacklink {
+		return nil
+	}
+
+	sourceProvider, err := s.getProvider(providerRef{Name: input.Source, ConnectionID: input.SourceConnectionID})
+	if err != nil {
+		return fmt.Errorf("get source provider: %w", err)
+	}
+	destProvider, err := s.getProvider(providerRef{Name: input.Destination, ConnectionID: input.DestConnectionID})
+	if err != nil {
+		return fmt.Errorf("get dest provider: %w", err)
+	}
+
+	if input.BacklinkMode != BacklinkModeNone {
+		if _, ok := sourceProvider.(client.TaskURLBuilder); !ok {
+			return fmt.Errorf("%w: source %s cannot build task links", ErrInvalidInput, input.Source)
+		}
+	}
+	if input.BacklinkMode == BacklinkModeComment {
+		if _, ok := destProvider.(client.CommentCreator); !ok {
+			return fmt.Errorf("%w: destination %s does not support comments", ErrInvalidInput, input.Destination)
+		}
+	}
+	if input.ReverseBacklink {
+		if _, ok := destProvider.(client.TaskURLBuilder); !ok {
+			return fmt.Errorf("%w: destination %s cannot build task links", ErrInvalidInput, input.Destination)
+		}
+		if _, ok := sourceProvider.(client.CommentCreator); !ok {
+			return fmt.Errorf("%w: source %s does not support comments", ErrInvalidInput, input.Source)
+		}
+	}
+	return nil
+}
+
+// applyBacklink adds the link to the source task to a task about to be created, for the
+// modes that are written as part of the task itself.
+func applyBacklink(task *models.Task, exec *taskExecution, sourceTaskID string) {
+	urls, ok := exec.sourceClient.(client.TaskURLBuilder)
+	if !ok {
+		return
+	}
+	sourceURL := urls.TaskURL(sourceTaskID)
+
+	switch exec.migration.BacklinkMode {
+	case BacklinkModeDescription:
+		footer := "Migrated from " + sourceURL
+		if task.Description != "" {
+			task.Description += "\n\n"
+		}
+		task.Description += footer
+		if task.RichDescription != "" {
+			task.RichDescription += "\n\n---\nMigrated from [" + sourceURL + "](" + sourceURL + ")"
+		}
+	case BacklinkModeField:
+		task.CustomFields = append(task.CustomFields, models.TaskCustomField{
+			FieldID: exec.migration.BacklinkFieldID,
+			Value:   sourceURL,
+		})
+	}
+}
+
+// writeBacklinkComments adds the comments that can only be written once the destination task
+// exists: the back-link comment and the optional reverse link on the source task.
+// Failures are logged; they never fail the migrated task.
+func writeBacklinkComments(ctx context.Context, exec *taskExecution, sourceTaskID, destTaskID string) {
+	migration := exec.migration
+
+	if migration.BacklinkMode == BacklinkModeComment {
+		urls, hasURLs := exec.sourceClient.(client.TaskURLBuilder)
+		comments, hasComments := exec.destClient.(client.CommentCreator)
+		if hasURLs && hasComments {
+			if err := comments.CreateComment(ctx, destTaskID, "Migrated from "+urls.TaskURL(sourceTaskID)); err != nil {
+				slog.Warn("could not add backlink comment", "migration_id", migration.ID, "dest_task_id", destTaskID, "error", err)
+			}
+		}
+	}
+
+	if migration.ReverseBacklink {
+		urls, hasURLs := exec.destClient.(client.TaskURLBuilder)
+		comments, hasComments := exec.sourceClient.(client.CommentCreator)
+		if hasURLs && hasComments {
+			if err := comments.CreateComment(ctx, sourceTaskID, "Migrated to "+urls.TaskURL(destTaskID)); err != nil {
+				slog.Warn("could not add reverse backlink comment", "migration_id", migration.ID, "source_task_id", sourceTaskID, "error", err)
+			}
+		}
+	}
+}
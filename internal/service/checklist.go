@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/converter"
+	"github.com/TWRT/integration-mapper/internal/models"
+)
+
+// foldChecklists appends the task's checklists to its description when the destination cannot
+// create checklists, so their content is not lost.
+func foldChecklists(task *models.Task, destClient client.TaskClient) {
+	if len(task.Checklists) == 0 {
+		return
+	}
+	if _, ok := destClient.(client.ChecklistCreator); ok {
+		return
+	}
+	md := converter.ChecklistsMarkdown(task.Checklists)
+	task.Checklists = nil
+	if task.Description != "" {
+		task.Description += "\n\n"
+	}
+	task.Description += md
+	if task.RichDescription != "" {
+		task.RichDescription += "\n\n" + md
+	}
+}
+
+// writeChecklists creates the task's checklists on the created destination task.
+// Failures are logged; they never fail the migrated task.
+func writeChecklists(ctx context.Context, exec *taskExecution, checklists []models.Checklist, destTaskID string) {
+	cc, ok := exec.destClient.(client.ChecklistCreator)
+	if !ok {
+		return
+	}
+	for _, cl := range checklists {
+		if err := cc.CreateChecklist(ctx, destTaskID, cl); err != nil {
+			slog.Warn("could not create checklist", "migration_id", exec.migration.ID, "dest_task_id", destTaskID, "checklist", cl.Name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/oauth"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+const (
+	// oauthStateTTL is how long a user has to grant access after starting an authorization.
+	oauthStateTTL = 10 * time.Minute
+	// tokenRefreshLeeway refreshes access tokens a little before they expire, so a
+	// request is not sent with a token that expires on the way.
+	tokenRefreshLeeway = time.Minute
+)
+
+type connectionRepo interface {
+	Create(conn *repository.Connection) (int64, error)
+	GetConnection(id int64) (repository.Connection, error)
+	GetByUserID(userID int64) ([]repository.Connection, error)
+	UpdateTokens(id int64, accessToken, refreshToken []byte, expiresAt *time.Time) error
+	Delete(id int64) error
+	CreateOAuthState(state string, userID int64, provider string) error
+	ConsumeOAuthState(state string, notBefore time.Time) (int64, string, error)
+}
+
+// connectionClients builds provider clients acting as the user behind a connection.
+type connectionClients interface {
+	ConnectionClient(connectionID int64, provider string) (client.IntegrationProvider, error)
+	CheckConnection(connectionID int64, userID *int64, provider string) error
+}
+
+// OAuthApp is the OAuth application registered with a provider for this server.
+type OAuthApp struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// ConnectionService connects users' own provider accounts through OAuth, stores their
+// tokens encrypted and builds the clients migrations use to act as those accounts.
+type ConnectionService struct {
+	connections connectionRepo
+	providers   *client.Registry
+	cipher      *TokenCipher
+	apps        map[string]oauth.Config // provider name → OAuth app
+
+	mu      sync.Mutex
+	clients map[int64]client.IntegrationProvider // connection ID → client
+}
+
+// NewConnectionService sets up the OAuth apps of the providers that support connections.
+// Providers are redirected back to redirectBaseURL + "/oauth/{provider}/callback". A nil
+// cipher disables connections.
+func NewConnectionService(
+	connections connectionRepo,
+	providers *client.Registry,
+	cipher *TokenCipher,
+	apps map[string]OAuthApp,
+	redirectBaseURL string,
+) *ConnectionService {
+	s := &ConnectionService{
+		connections: connections,
+		providers:   providers,
+		cipher:      cipher,
+		apps:        make(map[string]oauth.Config),
+		clients:     make(map[int64]client.IntegrationProvider),
+	}
+	if cipher == nil {
+		return s
+	}
+	for name, app := range apps {
+		d, ok := providers.Get(name)
+		if !ok || d.OAuth == nil || app.ClientID == "" {
+			continue
+		}
+		s.apps[name] = oauth.Config{
+			Endpoint:     *d.OAuth,
+			ClientID:     app.ClientID,
+			ClientSecret: app.ClientSecret,
+			RedirectURL:  strings.TrimRight(redirectBaseURL, "/") + "/oauth/" + name + "/callback",
+		}
+	}
+	return s
+}
+
+// ConnectionServiceProvider is the interface consumed by handlers.
+// Allows substitution with mocks in tests.
+type ConnectionServiceProvider interface {
+	ConnectableProviders() []string
+	AuthorizeURL(user repository.User, provider string) (string, error)
+	CompleteAuthorization(ctx context.Context, provider, state, code string) (repository.Connection, error)
+	GetConnections(user repository.User) ([]repository.Connection, error)
+	CanAccessConnection(user repository.User, connectionID int64) (bool, error)
+	DeleteConnection(connectionID int64) error
+}
+
+// ConnectableProviders returns the providers users can connect their accounts to.
+func (s *ConnectionService) ConnectableProviders() []string {
+	names := make([]string, 0, len(s.apps))
+	for name := range s.apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AuthorizeURL starts connecting the user's account of a provider. The user is sent to
+// the returned URL to grant access; the provider then redirects to the callback, which
+// completes the connection with CompleteAuthorization.
+func (s *ConnectionService) AuthorizeURL(user repository.User, provider string) (string, error) {
+	app, ok := s.apps[provider]
+	if !ok {
+		return "", fmt.Errorf("%w: connections to %q are not configured", ErrInvalidInput, provider)
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate oauth state: %w", err)
+	}
+	state := hex.EncodeToString(b)
+	if err := s.connections.CreateOAuthState(state, user.ID, provider); err != nil {
+		return "", err
+	}
+	return app.AuthCodeURL(state), nil
+}
+
+// CompleteAuthorization exchanges the code a provider redirected back with for tokens and
+// stores them as a connection of the user who started the authorization.
+func (s *ConnectionService) CompleteAuthorization(ctx context.Context, provider, state, code string) (repository.Connection, error) {
+	app, ok := s.apps[provider]
+	if !ok {
+		return repository.Connection{}, fmt.Errorf("%w: connections to %q are not configured", ErrInvalidInput, provider)
+	}
+	if state == "" || code == "" {
+		return repository.Connection{}, fmt.Errorf("%w: state and code are required", ErrInvalidInput)
+	}
+	userID, stateProvider, err := s.connections.ConsumeOAuthState(state, time.Now().Add(-oauthStateTTL))
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.Connection{}, fmt.Errorf("%w: unknown or expired authorization", ErrInvalidInput)
+	}
+	if err != nil {
+		return repository.Connection{}, err
+	}
+	if stateProvider != provider {
+		return repository.Connection{}, fmt.Errorf("%w: authorization was started for %s", ErrInvalidInput, stateProvider)
+	}
+
+	token, err := app.Exchange(ctx, code)
+	if err != nil {
+		return repository.Connection{}, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	conn := repository.Connection{
+		UserID:      userID,
+		Provider:    provider,
+		AccountName: token.AccountName,
+		ExpiresAt:   token.ExpiresAt,
+	}
+	if conn.AccessToken, conn.RefreshToken, err = s.sealTokens(token); err != nil {
+		return repository.Connection{}, err
+	}
+	id, err := s.connections.Create(&conn)
+	if err != nil {
+		return repository.Connection{}, err
+	}
+	return s.connections.GetConnection(id)
+}
+
+// GetConnections returns the connections of a user. Connections act as a person's
+// account, so admins only see their own too.
+func (s *ConnectionService) GetConnections(user repository.User) ([]repository.Connection, error) {
+	return s.connections.GetByUserID(user.ID)
+}
+
+// CanAccessConnection reports whether a connection belongs to user. It is false for a
+// connection that does not exist.
+func (s *ConnectionService) CanAccessConnection(user repository.User, connectionID int64) (bool, error) {
+	conn, err := s.connections.GetConnection(connectionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return conn.UserID == user.ID, nil
+}
+
+// DeleteConnection forgets a connection's tokens. Migrations still using it fail to
+// reach the provider until they are recreated with another connection.
+func (s *ConnectionService) DeleteConnection(connectionID int64) error {
+	if err := s.connections.Delete(connectionID); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.clients, connectionID)
+	s.mu.Unlock()
+	return nil
+}
+
+// CheckConnection validates a connection chosen for a new migration: it must reach
+// provider and, unless userID is nil, belong to that user.
+func (s *ConnectionService) CheckConnection(connectionID int64, userID *int64, provider string) error {
+	conn, err := s.connections.GetConnection(connectionID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && userID != nil && conn.UserID != *userID) {
+		return fmt.Errorf("%w: connection %d not found", ErrInvalidInput, connectionID)
+	}
+	if err != nil {
+		return err
+	}
+	if conn.Provider != provider {
+		return fmt.Errorf("%w: connection %d is a %s connection, not %s", ErrInvalidInput, connectionID, conn.Provider, provider)
+	}
+	return nil
+}
+
+// ConnectionClient returns a client for provider acting as the account behind a
+// connection. Clients are kept for the life of the service, so their caches survive
+// between runs; tokens are refreshed underneath them as they expire.
+func (s *ConnectionService) ConnectionClient(connectionID int64, provider string) (client.IntegrationProvider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.clients[connectionID]; ok {
+		return c, nil
+	}
+
+	conn, err := s.connections.GetConnection(connectionID)
+	if err != nil {
+		return nil, err
+	}
+	if conn.Provider != provider {
+		return nil, fmt.Errorf("connection %d is a %s connection, not %s", connectionID, conn.Provider, provider)
+	}
+	d, ok := s.providers.Get(provider)
+	if !ok || d.ConnectionClient == nil {
+		return nil, fmt.Errorf("%w: %s does not support connections", ErrUnsupported, provider)
+	}
+	if s.cipher == nil {
+		return nil, errors.New("connections are not configured: no token encryption key")
+	}
+	c := d.ConnectionClient(&connectionTokenSource{service: s, connectionID: connectionID, provider: provider})
+	s.clients[connectionID] = c
+	return c, nil
+}
+
+func (s *ConnectionService) sealTokens(token oauth.Token) (accessToken, refreshToken []byte, err error) {
+	if accessToken, err = s.cipher.Seal(token.AccessToken); err != nil {
+		return nil, nil, err
+	}
+	if token.RefreshToken != "" {
+		if refreshToken, err = s.cipher.Seal(token.RefreshToken); err != nil {
+			return nil, nil, err
+		}
+	}
+	return accessToken, refreshToken, nil
+}
+
+// loadToken decrypts the stored tokens of a connection.
+func (s *ConnectionService) loadToken(connectionID int64) (oauth.Token, error) {
+	conn, err := s.connections.GetConnection(connectionID)
+	if err != nil {
+		return oauth.Token{}, err
+	}
+	token := oauth.Token{ExpiresAt: conn.ExpiresAt}
+	if token.AccessToken, err = s.cipher.Open(conn.AccessToken); err != nil {
+		return oauth.Token{}, err
+	}
+	if conn.RefreshToken != nil {
+		if token.RefreshToken, err = s.cipher.Open(conn.RefreshToken); err != nil {
+			return oauth.Token{}, err
+		}
+	}
+	return token, nil
+}
+
+// refreshToken renews an expired token of a connection and stores the result.
+func (s *ConnectionService) refreshToken(ctx context.Context, connectionID int64, provider string, token oauth.Token) (oauth.Token, error) {
+	app, ok := s.apps[provider]
+	if !ok {
+		return oauth.Token{}, fmt.Errorf("connections to %s are not configured", provider)
+	}
+	if token.RefreshToken == "" {
+		return oauth.Token{}, fmt.Errorf("access token of connection %d expired and cannot be refreshed; connect the account again", connectionID)
+	}
+	refreshed, err := app.Refresh(ctx, token.RefreshToken)
+	if err != nil {
+		return oauth.Token{}, fmt.Errorf("refresh token of connection %d: %w", connectionID, err)
+	}
+	accessToken, refreshToken, err := s.sealTokens(refreshed)
+	if err != nil {
+		return oauth.Token{}, err
+	}
+	if err := s.connections.UpdateTokens(connectionID, accessToken, refreshToken, refreshed.ExpiresAt); err != nil {
+		return oauth.Token{}, err
+	}
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+	return refreshed, nil
+}
+
+// connectionTokenSource hands a connection's access token to its client, loading it from
+// the database once and refreshing it when it expires.
+type connectionTokenSource struct {
+	service      *ConnectionService
+	connectionID int64
+	provider     string
+
+	mu    sync.Mutex
+	token oauth.Token
+}
+
+func (t *connectionTokenSource) AccessToken(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if t.token.AccessToken != "" && !t.token.Expired(now, tokenRefreshLeeway) {
+		return t.token.AccessToken, nil
+	}
+	// Another process may have refreshed the token already.
+	token, err := t.service.loadToken(t.connectionID)
+	if err != nil {
+		return "", err
+	}
+	if token.Expired(now, tokenRefreshLeeway) {
+		if token, err = t.service.refreshToken(ctx, t.connectionID, t.provider, token); err != nil {
+			return "", err
+		}
+	}
+	t.token = token
+	return token.AccessToken, nil
+}
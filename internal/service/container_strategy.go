@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// Container strategies: how the containers of the source (Asana sections, ...) are
+// recreated in the destination.
+const (
+	ContainerStrategyList   = "list"   // each container is mapped onto a destination container
+	ContainerStrategyStatus = "status" // containers become task statuses in the migration's list
+)
+
+func validateContainerStrategy(input CreateMigrationInput) error {
+	switch input.ContainerStrategy {
+	case "", ContainerStrategyList:
+		return nil
+	case ContainerStrategyStatus:
+		if input.DestListID == "" {
+			return fmt.Errorf("%w: dest_list_id is required for the status container strategy", ErrInvalidInput)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: unknown container strategy %q", ErrInvalidInput, input.ContainerStrategy)
+}
+
+// applyContainerStrategy gives tasks the status they are mapped by. With the status strategy
+// an open task takes the name of its container, like a board column; completed tasks keep
+// their own status so they can be mapped onto a closed one.
+func applyContainerStrategy(tasks []models.Task, strategy, containerName string) {
+	if strategy != ContainerStrategyStatus {
+		return
+	}
+	for i := range tasks {
+		if !tasks[i].Completed {
+			tasks[i].Status = containerName
+		}
+	}
+}
+
+// checkStatusStrategyContainers rejects container mappings that would move a container out
+// of the single destination list of a status-strategy migration.
+func checkStatusStrategyContainers(migration repository.Migration, containerMappings []ContainerMappingInput) error {
+	if migration.ContainerStrategy != ContainerStrategyStatus {
+		return nil
+	}
+	for _, cm := range containerMappings {
+		if cm.DestID != nil && *cm.DestID != "" && *cm.DestID != migration.DestListID {
+			return fmt.Errorf("%w: with the status container strategy every container goes to list %s", ErrInvalidInput, migration.DestListID)
+		}
+	}
+	return nil
+}
+
+// routeContainersToStatuses sends the unmapped containers of a status-strategy migration to
+// its destination list and maps their statuses onto destination statuses of the same name.
+// Mappings the user already made are kept, and migrations that started are left alone.
+func (s *MigrationService) routeContainersToStatuses(ctx context.Context, migration repository.Migration) error {
+	if migration.ContainerStrategy != ContainerStrategyStatus || checkMappingsEditable(migration) != nil {
+		return nil
+	}
+
+	containers, err := s.containerMappingRepo.GetByMigrationID(migration.ID)
+	if err != nil {
+		return fmt.Errorf("get container mappings: %w", err)
+	}
+	listName := migration.DestListID
+	for _, c := range s.getAvailableDestContainers(ctx, migration) {
+		if c.ID == migration.DestListID {
+			listName = c.Name
+		}
+	}
+
+	destStatuses, err := s.getAvailableDestStatuses(ctx, destProviderOf(migration), migration.DestListID)
+	if err != nil {
+		slog.Warn("could not load destination statuses, statuses left to map", "migration_id", migration.ID, "error", err)
+	}
+
+	for _, cm := range containers {
+		if cm.DestID == nil {
+			if err := s.containerMappingRepo.UpdateMapping(migration.ID, cm.SourceID, migration.DestListID, listName, true); err != nil {
+				return fmt.Errorf("route container %s: %w", cm.SourceName, err)
+			}
+		}
+
+		mappings, err := s.migrationMappingRepo.GetByMigrationIDAndContainer(migration.ID, cm.SourceID)
+		if err != nil {
+			return fmt.Errorf("get mappings of container %s: %w", cm.SourceID, err)
+		}
+		containerID := cm.SourceID
+		for _, m := range mappings {
+			if m.Type != repository.MappingTypeStatus || m.Status != repository.MappingStatusPending {
+				continue
+			}
+			for _, dest := range destStatuses {
+				if strings.EqualFold(dest, m.SourceValue) {
+					if err := s.migrationMappingRepo.UpdateMapping(migration.ID, repository.MappingTypeStatus, m.SourceValue, &containerID, dest); err != nil {
+						slog.Warn("could not map section status", "container", cm.SourceName, "status", dest, "error", err)
+					}
+					break
+				}
+			}
+		}
+	}
+	return s.refreshReadiness(migration.ID)
+}
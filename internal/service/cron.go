@@ -0,0 +1,149 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed five-field cron expression: minute, hour, day of month, month
+// and day of week. Each field is a bit set of the values it matches.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// As in cron, when both day fields are restricted a day matching either one matches.
+	domAny, dowAny bool
+}
+
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// parseCron parses a cron expression such as "30 2 * * 1-5" or one of the @hourly, @daily,
+// @weekly and @monthly macros. Fields accept *, single values, ranges, steps and lists.
+func parseCron(expr string) (cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[expr]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return cronSchedule{}, fmt.Errorf("cron expression %q must have 5 fields", expr)
+	}
+
+	var c cronSchedule
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return cronSchedule{}, fmt.Errorf("minute: %w", err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return cronSchedule{}, fmt.Errorf("hour: %w", err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return cronSchedule{}, fmt.Errorf("day of month: %w", err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return cronSchedule{}, fmt.Errorf("month: %w", err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return cronSchedule{}, fmt.Errorf("day of week: %w", err)
+	}
+	// 7 is Sunday too.
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	// Like cron, a day field starting with "*" ("*" or "*/2") counts as unrestricted.
+	c.domAny = strings.HasPrefix(fields[2], "*")
+	c.dowAny = strings.HasPrefix(fields[4], "*")
+
+	if c.next(time.Now()).IsZero() {
+		return cronSchedule{}, fmt.Errorf("cron expression %q never matches", expr)
+	}
+	return c, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			rangePart = part[:i]
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil || lo > hi {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", rangePart)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (c cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// next returns the first time after t that matches the schedule, in t's location, or the
+// zero time if nothing matches within five years.
+func (c cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
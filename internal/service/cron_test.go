@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// Monday, 2024-01-01 10:15 UTC.
+	from := time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"@hourly", time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * 1-5", time.Date(2024, 1, 2, 2, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either one matches (the 15th or a Friday).
+		{"0 0 15 * 5", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		// A stepped "*" day field is unrestricted, so both fields must match: an odd day
+		// of the month that is a Friday.
+		{"0 0 */2 * 5", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 */2 * 6", time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC)},
+		{"0 0 10 * */3", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		c, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tt.expr, err)
+		}
+		if got := c.next(from); !got.Equal(tt.want) {
+			t.Errorf("next(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
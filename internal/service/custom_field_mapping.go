@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/models"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// Custom field value conversions. The default carries the value over into a destination
+// field of the equivalent type; the others flatten option-based values.
+const (
+	CustomFieldConversionAuto = ""
+	CustomFieldConversionText = "text" // option names written into a text field
+	CustomFieldConversionTags = "tags" // option names added to the task's tags, no destination field
+)
+
+func isOptionFieldType(clickupType string) bool {
+	switch clickupType {
+	case "drop_down", "labels", "checkbox":
+		return true
+	}
+	return false
+}
+
+// validateCustomFieldMappings checks the custom field mappings of a save payload against the
+// discovered source fields and the fields of the destination before anything is written.
+func (s *MigrationService) validateCustomFieldMappings(ctx context.Context, migration repository.Migration, containerMappings []ContainerMappingInput) error {
+	migrationID := migration.ID
+	var destFields map[string]models.CustomFieldDefinition
+	for _, cm := range containerMappings {
+		var fieldTypes map[string]string
+		for _, cf := range cm.CustomFields {
+			if cf.Mapping == nil {
+				continue
+			}
+			switch cf.Mapping.Conversion {
+			case CustomFieldConversionAuto, CustomFieldConversionText:
+			case CustomFieldConversionTags:
+				if cf.Mapping.DestFieldID != "" || len(cf.Mapping.OptionMappings) > 0 {
+					return fmt.Errorf("%w: custom field %s: the tags conversion does not use a destination field", ErrInvalidInput, cf.FieldID)
+				}
+			default:
+				return fmt.Errorf("%w: custom field %s: unknown conversion %q", ErrInvalidInput, cf.FieldID, cf.Mapping.Conversion)
+			}
+
+			if cf.Mapping.DestFieldID != "" {
+				if destFields == nil {
+					var err error
+					if destFields, err = s.destFieldsByID(ctx, migration); err != nil {
+						return err
+					}
+				}
+				destDef, ok := destFields[cf.Mapping.DestFieldID]
+				if !ok {
+					return fmt.Errorf("%w: custom field %s: destination field %s not found", ErrInvalidInput, cf.FieldID, cf.Mapping.DestFieldID)
+				}
+				if err := checkDestOptions(destDef, cf.Mapping.OptionMappings); err != nil {
+					return fmt.Errorf("%w: custom field %s: %v", ErrInvalidInput, cf.FieldID, err)
+				}
+			}
+
+			if cf.Mapping.Conversion == CustomFieldConversionAuto {
+				continue
+			}
+			if fieldTypes == nil {
+				containerID := cm.SourceID
+				rows, err := s.migrationMappingRepo.GetCustomFields(migrationID, &containerID)
+				if err != nil {
+					return fmt.Errorf("get custom fields: %w", err)
+				}
+				fieldTypes = make(map[string]string, len(rows))
+				for _, r := range rows {
+					fieldTypes[r.FieldID] = r.FieldType
+				}
+			}
+			fieldType, known := fieldTypes[cf.FieldID]
+			if known && cf.Mapping.Conversion == CustomFieldConversionTags && !isOptionFieldType(fieldType) {
+				return fmt.Errorf("%w: custom field %s: only dropdown, label and checkbox fields can be converted to tags", ErrInvalidInput, cf.FieldID)
+			}
+		}
+	}
+	return nil
+}
+
+// destFieldsByID returns the custom fields of the migration's destination by ID.
+func (s *MigrationService) destFieldsByID(ctx context.Context, migration repository.Migration) (map[string]models.CustomFieldDefinition, error) {
+	destProvider, err := s.getProvider(destProviderOf(migration))
+	if err != nil {
+		return nil, fmt.Errorf("get dest provider: %w", err)
+	}
+	dfp, ok := destProvider.(client.DestFieldProvider)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has no custom fields to map onto", ErrInvalidInput, migration.Destination)
+	}
+	defs, err := dfp.GetDestFieldDefinitions(ctx, s.getDestContainerID(migration))
+	if err != nil {
+		return nil, fmt.Errorf("get destination custom fields: %w", err)
+	}
+	byID := make(map[string]models.CustomFieldDefinition, len(defs))
+	for _, d := range defs {
+		byID[d.ID] = d
+	}
+	return byID, nil
+}
+
+// checkDestOptions checks that the destination options of an option mapping exist on the
+// destination field.
+func checkDestOptions(destDef models.CustomFieldDefinition, optionMappings map[string]string) error {
+	if len(optionMappings) == 0 {
+		return nil
+	}
+	if len(destDef.Options) == 0 {
+		return fmt.Errorf("destination field %s has no options", destDef.ID)
+	}
+	for _, destOption := range optionMappings {
+		if !slices.ContainsFunc(destDef.Options, func(o models.CustomFieldOption) bool { return o.ID == destOption }) {
+			return fmt.Errorf("option %s not found on destination field %s", destOption, destDef.ID)
+		}
+	}
+	return nil
+}
+
+// sourceFieldOptions returns the options of an option-based source field in the order
+// they should be paired with destination options. Checkboxes get two pseudo-options.
+func sourceFieldOptions(def models.CustomFieldDefinition) []models.CustomFieldOption {
+	switch def.ClickUpType {
+	case "drop_down":
+		sorted := make([]models.CustomFieldOption, len(def.Options))
+		copy(sorted, def.Options)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].OrderIndex < sorted[j].OrderIndex
+		})
+		return sorted
+	case "labels":
+		return def.Options
+	case "checkbox":
+		return []models.CustomFieldOption{{ID: "true", Name: "True"}, {ID: "false", Name: "False"}}
+	}
+	return nil
+}
+
+// optionKey is the key a source option is looked up by when converting a task value:
+// ClickUp dropdown values are order indexes, labels are option IDs.
+func optionKey(clickupType string, o models.CustomFieldOption) string {
+	if clickupType == "drop_down" {
+		return strconv.Itoa(o.OrderIndex)
+	}
+	return o.ID
+}
+
+// matchFieldOptions pairs source options with destination options: an explicit user mapping
+// (source option ID → dest option ID) wins, then a case-insensitive name match, then,
+// when positional is set, the option at the same position.
+func matchFieldOptions(
+	clickupType string,
+	sourceOptions []models.CustomFieldOption,
+	destOptions []models.CustomFieldOption,
+	userMap map[string]string,
+	positional bool,
+) map[string]string {
+	byName := make(map[string]string, len(destOptions))
+	for _, d := range destOptions {
+		if d.Name == "" {
+			continue
+		}
+		if _, dup := byName[strings.ToLower(d.Name)]; !dup {
+			byName[strings.ToLower(d.Name)] = d.ID
+		}
+	}
+
+	result := make(map[string]string, len(sourceOptions))
+	for i, o := range sourceOptions {
+		key := optionKey(clickupType, o)
+		if dest := userMap[o.ID]; dest != "" {
+			result[key] = dest
+			continue
+		}
+		if dest, ok := byName[strings.ToLower(o.Name)]; ok {
+			result[key] = dest
+			continue
+		}
+		if positional && i < len(destOptions) {
+			result[key] = destOptions[i].ID
+		}
+	}
+	return result
+}
+
+// sourceOptionNames resolves a raw option-based source value to the names of the selected options.
+func sourceOptionNames(entry customFieldEntry, value interface{}) []string {
+	lookup := func(key string) string {
+		for _, o := range entry.sourceOptions {
+			if optionKey(entry.clickupType, o) == key {
+				return o.Name
+			}
+		}
+		return ""
+	}
+
+	var names []string
+	switch entry.clickupType {
+	case "drop_down":
+		if n, ok := value.(float64); ok {
+			if name := lookup(strconv.Itoa(int(n))); name != "" {
+				names = append(names, name)
+			}
+		}
+	case "labels":
+		for _, id := range labelOptionIDs(value) {
+			if name := lookup(id); name != "" {
+				names = append(names, name)
+			}
+		}
+	case "checkbox":
+		if key := checkboxKey(value); key != "" {
+			names = append(names, lookup(key))
+		}
+	}
+	return names
+}
+
+// labelOptionIDs extracts the selected option IDs from a ClickUp labels value, which is either
+// a list of IDs or a list of option objects.
+func labelOptionIDs(value interface{}) []string {
+	arr, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	ids := make([]string, 0, len(arr))
+	for _, item := range arr {
+		switch v := item.(type) {
+		case string:
+			ids = append(ids, v)
+		case map[string]interface{}:
+			if id, ok := v["id"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func checkboxKey(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case float64:
+		if v != 0 {
+			return "true"
+		}
+		return "false"
+	}
+	return ""
+}
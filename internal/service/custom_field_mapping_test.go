@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+type fakeFieldProvider []models.CustomFieldDefinition
+
+func (f fakeFieldProvider) GetFieldDefinitions(context.Context, string) ([]models.CustomFieldDefinition, error) {
+	return f, nil
+}
+
+// A destination that lists its fields but cannot create them, like Trello, Jira or Linear,
+// still gets the values of fields mapped onto one of its existing fields.
+func TestBuildCustomFieldMappingWithoutFieldCreator(t *testing.T) {
+	source := fakeFieldProvider{
+		{ID: "cf-stage", Name: "Stage", ClickUpType: "drop_down", Options: []models.CustomFieldOption{
+			{ID: "o-todo", Name: "Todo", OrderIndex: 0},
+			{ID: "o-done", Name: "Done", OrderIndex: 1},
+		}},
+		{ID: "cf-notes", Name: "Notes", ClickUpType: "text"},
+		{ID: "cf-owner", Name: "Owner", ClickUpType: "short_text"},
+	}
+	destFields := []models.CustomFieldDefinition{
+		{ID: "tf-status", Name: "Status", ClickUpType: "drop_down", Options: []models.CustomFieldOption{
+			{ID: "opt-done", Name: "done"},
+			{ID: "opt-open", Name: "Open"},
+		}},
+		{ID: "tf-notes", Name: "Notes", ClickUpType: "text"},
+	}
+	stage, notes := "tf-status", "tf-notes"
+	enabled := map[string]repository.CustomFieldRow{
+		"cf-stage": {FieldID: "cf-stage", Enabled: true, DestFieldID: &stage, OptionMap: map[string]string{"o-todo": "opt-open"}},
+		"cf-notes": {FieldID: "cf-notes", Enabled: true, DestFieldID: &notes},
+		// Without a configured destination the field would have to be created.
+		"cf-owner": {FieldID: "cf-owner", Enabled: true},
+	}
+
+	s := &MigrationService{}
+	mapping := s.buildCustomFieldMapping(context.Background(), source, nil, destFields, []string{"list"}, enabled, repository.Migration{ID: 1})
+
+	if _, ok := mapping["cf-owner"]; ok {
+		t.Error("field without a configured destination was mapped although no field can be created")
+	}
+	for _, tt := range []struct {
+		value float64
+		want  string
+	}{
+		{0, "opt-open"}, // explicit option mapping
+		{1, "opt-done"}, // matched by name
+	} {
+		got, _ := convertTaskCustomFields([]models.TaskCustomField{
+			{FieldID: "cf-stage", Value: tt.value},
+			{FieldID: "cf-notes", Value: "see spec"},
+			{FieldID: "cf-owner", Value: "Ann"},
+		}, mapping)
+		want := []models.TaskCustomField{
+			{FieldID: "tf-status", Value: tt.want},
+			{FieldID: "tf-notes", Value: "see spec"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("stage %v: fields = %+v, want %+v", tt.value, got, want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package service
+
+import "errors"
+
+// ErrInvalidMigrationState is returned when an operation is not allowed in the migration's current status.
+var ErrInvalidMigrationState = errors.New("invalid migration state")
+
+// ErrInvalidInput is returned when caller-provided configuration fails validation.
+var ErrInvalidInput = errors.New("invalid input")
+
+// ErrNotFound is returned when the record an operation targets does not exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrUnauthenticated is returned when an API key does not belong to any user.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// ErrUnknownProvider is returned for provider names that are not registered.
+var ErrUnknownProvider = errors.New("unknown provider")
+
+// ErrUnsupported is returned when a provider's client lacks the capability an operation needs.
+var ErrUnsupported = errors.New("not supported by provider")
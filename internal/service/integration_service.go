@@ -22,6 +22,8 @@ type clickupProvider interface {
 	GetListCustomFields(ctx context.Context, listId string) ([]clickup.ClickUpCustomField, error)
 }
 
+// IntegrationService browses Asana and ClickUp with the server's credentials, for the
+// admin-only legacy routes; other callers browse through ProviderService and a connection.
 type IntegrationService struct {
 	asanaClient   asanaProvider
 	clickupClient clickupProvider
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+const (
+	// jobLease is how long a claimed job stays with its worker without a heartbeat. When
+	// a worker dies its job is picked up again once the lease runs out; tasks the dead
+	// worker already created are skipped, see withoutMigrated.
+	jobLease             = 2 * time.Minute
+	jobHeartbeatInterval = 30 * time.Second
+	jobPollInterval      = 2 * time.Second
+	// maxJobAttempts bounds how often a job is reclaimed after its worker died, so a
+	// migration that crashes the server is not retried forever.
+	maxJobAttempts = 3
+)
+
+var (
+	// errJobLeaseLost is the cause a run is cancelled with when another worker took over its job.
+	errJobLeaseLost = errors.New("migration job lease lost")
+	// errWorkerStopped is the cause a run is cancelled with when its worker shuts down. The
+	// job goes back to the queue for the next worker.
+	errWorkerStopped = errors.New("migration worker stopped")
+)
+
+// runHandedOver reports whether a run was cancelled because its job is left to another
+// worker, so the run must not record an outcome.
+func runHandedOver(ctx context.Context) bool {
+	cause := context.Cause(ctx)
+	return errors.Is(cause, errJobLeaseLost) || errors.Is(cause, errWorkerStopped)
+}
+
+type migrationJobRepo interface {
+	Enqueue(migrationID int64) (int64, error)
+	Claim(workerID string, now time.Time, lease time.Duration, maxRunning int) (*repository.MigrationJob, error)
+	ClaimMigration(migrationID int64, workerID string, now time.Time, lease time.Duration, maxRunning int) (*repository.MigrationJob, error)
+	Heartbeat(id int64, workerID string, leaseExpiresAt time.Time) (bool, error)
+	Finish(id int64, workerID string, status repository.MigrationJobStatus, errorMessage string) error
+	Release(id int64, workerID string) (bool, error)
+	GetMigrationJob(id int64) (repository.MigrationJob, error)
+	GetLatestByMigrationID(migrationID int64) (*repository.MigrationJob, error)
+	QueuePosition(id int64) (int, error)
+}
+
+// MigrationJobState is the latest queued run of a migration.
+type MigrationJobState struct {
+	Job           repository.MigrationJob
+	QueuePosition int // 1 for the next job to run; 0 once the job left the queue
+}
+
+// queueableStatuses are the statuses a migration can be queued from.
+var queueableStatuses = []repository.MigrationStatus{
+	repository.MigrationStatusPendingConfiguration,
+	repository.MigrationStatusReadyToStart,
+	repository.MigrationStatusCompleted,
+	repository.MigrationStatusCompletedWithErrors,
+	repository.MigrationStatusFailed,
+	repository.MigrationStatusRolledBack,
+	repository.MigrationStatusRolledBackWithErrors,
+}
+
+// enqueueMigration checks that a migration can start and queues it.
+func (s *MigrationService) enqueueMigration(migrationID int64) (int64, error) {
+	migration, _, _, err := s.prepareStart(migrationID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Mark the migration queued first, so a worker claiming the job at once is not
+	// overwritten. The update only applies from a queueable status, so two requests
+	// cannot both queue the migration.
+	queued, err := s.migrationRepo.MarkQueued(migrationID, queueableStatuses)
+	if err != nil {
+		return 0, fmt.Errorf("update migration status: %w", err)
+	}
+	if !queued {
+		return 0, fmt.Errorf("%w: migration %d is already queued, running or rolling back", ErrInvalidMigrationState, migrationID)
+	}
+	jobID, err := s.migrationJobRepo.Enqueue(migrationID)
+	if err != nil {
+		if rerr := s.migrationRepo.UpdateStatus(migrationID, migration.Status); rerr != nil {
+			slog.Error("failed to restore migration status", "migration_id", migrationID, "error", rerr)
+		}
+		return 0, fmt.Errorf("enqueue migration: %w", err)
+	}
+	slog.Info("migration queued", "migration_id", migrationID, "job_id", jobID)
+	return jobID, nil
+}
+
+// GetMigrationJob returns the latest job of a migration, or nil if it was never started.
+func (s *MigrationService) GetMigrationJob(migrationID int64) (*MigrationJobState, error) {
+	job, err := s.migrationJobRepo.GetLatestByMigrationID(migrationID)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	state := &MigrationJobState{Job: *job}
+	if job.Status == repository.MigrationJobStatusQueued {
+		if state.QueuePosition, err = s.migrationJobRepo.QueuePosition(job.ID); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+// RunWorkers runs queued migrations until ctx is done, at most maxConcurrent at a time
+// across every process sharing the database.
+func (s *MigrationService) RunWorkers(ctx context.Context, maxConcurrent int) {
+	maxConcurrent = max(maxConcurrent, 1)
+	host, _ := os.Hostname()
+
+	var wg sync.WaitGroup
+	for i := range maxConcurrent {
+		workerID := fmt.Sprintf("%s-%d-%d", host, os.Getpid(), i+1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runWorker(ctx, workerID, maxConcurrent)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *MigrationService) runWorker(ctx context.Context, workerID string, maxRunning int) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for ctx.Err() == nil {
+		job, err := s.migrationJobRepo.Claim(workerID, time.Now(), jobLease, maxRunning)
+		if err != nil {
+			slog.Error("failed to claim migration job", "worker_id", workerID, "error", err)
+		}
+		if job != nil {
+			s.runJob(ctx, job, workerID)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunMigration runs the queued job of one migration in this process, once fewer than
+// maxConcurrent jobs run across every process sharing the database. It returns when the
+// job finished, wherever it ran, or when ctx is done. Jobs of other migrations are left
+// to the workers, see RunWorkers.
+func (s *MigrationService) RunMigration(ctx context.Context, migrationID int64, maxConcurrent int) {
+	host, _ := os.Hostname()
+	workerID := fmt.Sprintf("%s-%d-m%d", host, os.Getpid(), migrationID)
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for ctx.Err() == nil {
+		job, err := s.migrationJobRepo.ClaimMigration(migrationID, workerID, time.Now(), jobLease, max(maxConcurrent, 1))
+		if err != nil {
+			slog.Error("failed to claim migration job", "migration_id", migrationID, "worker_id", workerID, "error", err)
+		}
+		if job != nil {
+			s.runJob(ctx, job, workerID)
+			return
+		}
+		// Another worker may hold the job; claim it should that worker die.
+		latest, err := s.migrationJobRepo.GetLatestByMigrationID(migrationID)
+		if err != nil {
+			slog.Error("failed to get migration job", "migration_id", migrationID, "error", err)
+		} else if latest == nil || (latest.Status != repository.MigrationJobStatusQueued && latest.Status != repository.MigrationJobStatusRunning) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+}
+
+// runJob runs a claimed job to completion, renewing its lease while the migration runs.
+// When ctx is done first, the run stops before its next task and the job is queued again.
+func (s *MigrationService) runJob(ctx context.Context, job *repository.MigrationJob, workerID string) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("panic in runJob", "job_id", job.ID, "migration_id", job.MigrationID, "panic", r, "stack", string(debug.Stack()))
+			if err := s.migrationJobRepo.Finish(job.ID, workerID, repository.MigrationJobStatusFailed, fmt.Sprint(r)); err != nil {
+				slog.Error("failed to finish migration job", "job_id", job.ID, "error", err)
+			}
+		}
+	}()
+
+	fail := func(msg string) {
+		slog.Error("migration job failed", "job_id", job.ID, "migration_id", job.MigrationID, "error", msg)
+		if err := s.migrationJobRepo.Finish(job.ID, workerID, repository.MigrationJobStatusFailed, msg); err != nil {
+			slog.Error("failed to finish migration job", "job_id", job.ID, "error", err)
+		}
+		if err := s.migrationRepo.Complete(job.MigrationID, repository.MigrationStatusFailed); err != nil {
+			slog.Error("failed to complete migration", "migration_id", job.MigrationID, "error", err)
+		}
+	}
+	if job.Attempts > maxJobAttempts {
+		fail(fmt.Sprintf("gave up after %d attempts", maxJobAttempts))
+		return
+	}
+	migration, sourceProvider, destProvider, err := s.prepareStart(job.MigrationID)
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	if err := s.migrationRepo.UpdateStatus(migration.ID, repository.MigrationStatusRunning); err != nil {
+		fail(err.Error())
+		return
+	}
+	slog.Info("running migration job", "job_id", job.ID, "migration_id", job.MigrationID, "worker_id", workerID, "attempt", job.Attempts)
+
+	// The heartbeat cancels the run when another worker took the job over, so the two do
+	// not create the same tasks, and the worker's shutdown cancels it so the job can be
+	// queued again; either way the run stops before its next task. The cause is set here
+	// rather than inherited from ctx, so the run can tell a shutdown from a failure.
+	leaseCtx, stopRun := context.WithCancelCause(context.WithoutCancel(ctx))
+	defer stopRun(nil)
+	stopOnShutdown := context.AfterFunc(ctx, func() { stopRun(errWorkerStopped) })
+	defer stopOnShutdown()
+	stop := make(chan struct{})
+	go s.heartbeatJob(job.ID, workerID, stop, stopRun)
+
+	runCtx, cancel := context.WithTimeout(leaseCtx, 2*time.Hour)
+	runCtx = client.WithLocation(runCtx, migrationLocation(migration))
+	s.executeMigration(runCtx, sourceProvider, destProvider, migration)
+	cancel()
+	close(stop)
+	switch cause := context.Cause(leaseCtx); {
+	case errors.Is(cause, errJobLeaseLost):
+		return
+	case errors.Is(cause, errWorkerStopped):
+		s.requeueJob(job, workerID)
+		return
+	}
+
+	status := repository.MigrationJobStatusDone
+	if m, err := s.migrationRepo.GetMigration(migration.ID); err == nil && m.Status == repository.MigrationStatusFailed {
+		status = repository.MigrationJobStatusFailed
+	}
+	if err := s.migrationJobRepo.Finish(job.ID, workerID, status, ""); err != nil {
+		slog.Error("failed to finish migration job", "job_id", job.ID, "error", err)
+	}
+}
+
+// requeueJob puts a job its worker stopped running back in the queue, without counting
+// the interrupted run as an attempt.
+func (s *MigrationService) requeueJob(job *repository.MigrationJob, workerID string) {
+	released, err := s.migrationJobRepo.Release(job.ID, workerID)
+	if err != nil {
+		slog.Error("failed to requeue migration job", "job_id", job.ID, "error", err)
+		return
+	}
+	if !released {
+		return
+	}
+	if _, err := s.migrationRepo.MarkQueued(job.MigrationID, []repository.MigrationStatus{repository.MigrationStatusRunning}); err != nil {
+		slog.Error("failed to mark migration queued", "migration_id", job.MigrationID, "error", err)
+	}
+	slog.Info("migration job queued again after worker shutdown", "job_id", job.ID, "migration_id", job.MigrationID)
+}
+
+// heartbeatJob renews the lease of a running job until stop is closed. It calls loseLease
+// when another worker holds the job.
+func (s *MigrationService) heartbeatJob(jobID int64, workerID string, stop <-chan struct{}, loseLease context.CancelCauseFunc) {
+	ticker := time.NewTicker(jobHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			held, err := s.migrationJobRepo.Heartbeat(jobID, workerID, now.Add(jobLease))
+			if err != nil {
+				slog.Error("failed to renew migration job lease", "job_id", jobID, "error", err)
+			} else if !held {
+				slog.Warn("migration job lease lost to another worker", "job_id", jobID, "worker_id", workerID)
+				loseLease(errJobLeaseLost)
+				return
+			}
+		}
+	}
+}
+
+// waitForJob blocks until a job has finished or ctx is done.
+func (s *MigrationService) waitForJob(ctx context.Context, jobID int64) error {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := s.migrationJobRepo.GetMigrationJob(jobID)
+		if err != nil {
+			return err
+		}
+		if job.Status == repository.MigrationJobStatusDone || job.Status == repository.MigrationJobStatusFailed {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
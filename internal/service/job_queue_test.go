@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := repository.InitDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// A run its worker stopped during shutdown goes back to the queue for the next worker,
+// without using up one of the job's attempts.
+func TestRequeueJobAfterShutdown(t *testing.T) {
+	db := openTestDB(t)
+	migrations := repository.NewMigrationRepository(db)
+	jobs := repository.NewMigrationJobRepository(db)
+	s := &MigrationService{migrationRepo: migrations, migrationJobRepo: jobs}
+
+	id, err := migrations.Create(&repository.Migration{Source: "asana", Destination: "clickup", Status: repository.MigrationStatusQueued})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := jobs.Enqueue(id); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	job, err := jobs.Claim("w1", time.Now(), jobLease, 1)
+	if err != nil || job == nil {
+		t.Fatalf("Claim = %v, %v", job, err)
+	}
+	if err := migrations.UpdateStatus(id, repository.MigrationStatusRunning); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	s.requeueJob(job, "w1")
+
+	requeued, err := jobs.GetMigrationJob(job.ID)
+	if err != nil {
+		t.Fatalf("GetMigrationJob: %v", err)
+	}
+	if requeued.Status != repository.MigrationJobStatusQueued || requeued.Attempts != 0 || requeued.WorkerID != "" {
+		t.Errorf("job = %+v, want queued without worker or attempts", requeued)
+	}
+	if m, err := migrations.GetMigration(id); err != nil || m.Status != repository.MigrationStatusQueued {
+		t.Errorf("migration status = %v, %v; want queued", m.Status, err)
+	}
+
+	next, err := jobs.Claim("w2", time.Now(), jobLease, 1)
+	if err != nil || next == nil || next.ID != job.ID {
+		t.Fatalf("Claim by the next worker = %v, %v; want job %d", next, err, job.ID)
+	}
+
+	// The stopped worker no longer holds the job, so it cannot release it again.
+	s.requeueJob(job, "w1")
+	if j, _ := jobs.GetMigrationJob(job.ID); j.WorkerID != "w2" || j.Status != repository.MigrationJobStatusRunning {
+		t.Errorf("job = %+v, want it still running on w2", j)
+	}
+}
+
+// The CLI claims only the job of the migration it started, never one queued by someone
+// else, and stops waiting once another worker finished that job.
+func TestRunMigrationClaimsOnlyItsOwnJob(t *testing.T) {
+	db := openTestDB(t)
+	migrations := repository.NewMigrationRepository(db)
+	jobs := repository.NewMigrationJobRepository(db)
+	s := &MigrationService{migrationRepo: migrations, migrationJobRepo: jobs}
+
+	var ids []int64
+	for range 2 {
+		id, err := migrations.Create(&repository.Migration{Source: "asana", Destination: "clickup", Status: repository.MigrationStatusQueued})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if _, err := jobs.Enqueue(id); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	other, mine := ids[0], ids[1]
+
+	job, err := jobs.ClaimMigration(mine, "cli", time.Now(), jobLease, 2)
+	if err != nil || job == nil || job.MigrationID != mine {
+		t.Fatalf("ClaimMigration = %+v, %v; want the job of migration %d", job, err, mine)
+	}
+	if again, err := jobs.ClaimMigration(mine, "cli", time.Now(), jobLease, 2); err != nil || again != nil {
+		t.Fatalf("ClaimMigration of a held job = %+v, %v; want none", again, err)
+	}
+	if j, _ := jobs.GetLatestByMigrationID(other); j.Status != repository.MigrationJobStatusQueued {
+		t.Errorf("job of migration %d = %s, want it left queued", other, j.Status)
+	}
+
+	if err := jobs.Finish(job.ID, "cli", repository.MigrationJobStatusDone, ""); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.RunMigration(context.Background(), mine, 2)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunMigration kept waiting for a finished job")
+	}
+	if j, _ := jobs.GetLatestByMigrationID(other); j.Status != repository.MigrationJobStatusQueued {
+		t.Errorf("job of migration %d = %s, want it left queued", other, j.Status)
+	}
+}
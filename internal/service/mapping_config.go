@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/TWRT/integration-mapper/internal/repository"
+	"github.com/TWRT/integration-mapper/internal/yaml"
+)
+
+// MappingDocumentVersion is the version of the mapping documents written by ExportMappings.
+const MappingDocumentVersion = 1
+
+// MappingDocument is the portable form of a migration's mapping configuration, for review
+// and version control. Assignees carry their email, containers are identified by name.
+type MappingDocument struct {
+	Version     int    `json:"version"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	repository.MappingContent
+}
+
+// MappingChange is one mapping that an import changed. Values are rendered as text; an
+// empty From means the mapping was not configured before.
+type MappingChange struct {
+	Type        string
+	Container   string
+	SourceValue string
+	From        string
+	To          string
+}
+
+// ExportMappings renders the mapping configuration of a migration as JSON, or as YAML when
+// format is "yaml".
+func (s *MigrationService) ExportMappings(migrationID int64, format string) ([]byte, error) {
+	if format != "" && format != "json" && format != "yaml" {
+		return nil, fmt.Errorf("%w: unknown format %q, expected json or yaml", ErrInvalidInput, format)
+	}
+	migration, err := s.migrationRepo.GetMigration(migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get migration: %w", err)
+	}
+	content, err := s.mappingContent(migrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := json.MarshalIndent(MappingDocument{
+		Version:        MappingDocumentVersion,
+		Source:         migration.Source,
+		Destination:    migration.Destination,
+		MappingContent: *content,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal mapping document: %w", err)
+	}
+	if format == "yaml" {
+		return yaml.FromJSON(doc)
+	}
+	return append(doc, '\n'), nil
+}
+
+// ImportMappings applies a mapping document (JSON or YAML) to a migration that has not
+// started. Destination values are checked against the live destination first, and the
+// document is applied like a template. It returns the resulting state, what changed and
+// the entries that matched nothing in the migration.
+func (s *MigrationService) ImportMappings(ctx context.Context, migrationID int64, data []byte) (*MappingsState, []MappingChange, []UnmatchedMapping, error) {
+	doc, err := parseMappingDocument(data)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	migration, err := s.migrationRepo.GetMigration(migrationID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("get migration: %w", err)
+	}
+	if doc.Source != migration.Source || doc.Destination != migration.Destination {
+		return nil, nil, nil, fmt.Errorf("%w: document maps %s to %s, migration is %s to %s", ErrInvalidInput,
+			doc.Source, doc.Destination, migration.Source, migration.Destination)
+	}
+
+	if err := checkMappingsEditable(migration); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Refresh the discovered source values so the document is matched against the live source.
+	if _, err := s.SyncMappings(ctx, migrationID); err != nil {
+		return nil, nil, nil, fmt.Errorf("sync mappings: %w", err)
+	}
+	if err := s.validateMappingDestinations(ctx, migration, doc.MappingContent); err != nil {
+		return nil, nil, nil, err
+	}
+
+	before, err := s.mappingContent(migrationID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	state, unmatched, err := s.applyMappingContent(ctx, migration, doc.MappingContent)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	after, err := s.mappingContent(migrationID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return state, diffMappingContent(*before, *after), unmatched, nil
+}
+
+func parseMappingDocument(data []byte) (MappingDocument, error) {
+	raw, err := yaml.ToJSON(data)
+	if err != nil {
+		return MappingDocument{}, fmt.Errorf("%w: %s", ErrInvalidInput, err)
+	}
+	var doc MappingDocument
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return MappingDocument{}, fmt.Errorf("%w: %s must be a %s (quote IDs that look like numbers)", ErrInvalidInput, typeErr.Field, typeErr.Type)
+		}
+		return MappingDocument{}, fmt.Errorf("%w: invalid mapping document: %s", ErrInvalidInput, err)
+	}
+	if doc.Version < 1 || doc.Version > MappingDocumentVersion {
+		return MappingDocument{}, fmt.Errorf("%w: unsupported mapping document version %d", ErrInvalidInput, doc.Version)
+	}
+	return doc, nil
+}
+
+// validateMappingDestinations checks the destination values of a document against what the
+// destination offers. Lists the destination cannot provide are not checked.
+func (s *MigrationService) validateMappingDestinations(ctx context.Context, migration repository.Migration, content repository.MappingContent) error {
+	var problems []string
+
+	if len(content.Assignees) > 0 {
+		if members, err := s.getMembersForDestination(ctx, destProviderOf(migration), migration.DestWorkspaceID); err == nil && len(members) > 0 {
+			ids := make([]string, len(members))
+			for i, m := range members {
+				ids[i] = m.ID
+			}
+			for _, a := range content.Assignees {
+				if !slices.Contains(ids, a.DestValue) {
+					problems = append(problems, fmt.Sprintf("assignee %s: unknown destination member %q", firstNonEmpty(a.Email, a.SourceValue), a.DestValue))
+				}
+			}
+		}
+	}
+
+	var containerIDs []string
+	for _, c := range s.getAvailableDestContainers(ctx, migration) {
+		containerIDs = append(containerIDs, c.ID)
+	}
+	var fieldIDs []string
+	for _, f := range s.getAvailableDestFields(ctx, migration) {
+		fieldIDs = append(fieldIDs, f.ID)
+	}
+	for _, cc := range content.Containers {
+		if cc.DestID == "" {
+			continue
+		}
+		if len(containerIDs) > 0 && !slices.Contains(containerIDs, cc.DestID) {
+			problems = append(problems, fmt.Sprintf("container %s: unknown destination container %q", cc.SourceName, cc.DestID))
+			continue
+		}
+		if statuses, err := s.getAvailableDestStatuses(ctx, destProviderOf(migration), cc.DestID); err == nil && len(statuses) > 0 {
+			for _, v := range cc.Statuses {
+				if !slices.Contains(statuses, v.DestValue) {
+					problems = append(problems, fmt.Sprintf("container %s: status %s: unknown destination status %q", cc.SourceName, v.SourceValue, v.DestValue))
+				}
+			}
+		}
+		if priorities := s.getAvailableDestPrioritiesForState(ctx, destProviderOf(migration), cc.DestID); len(priorities) > 0 {
+			for _, v := range cc.Priorities {
+				if !slices.Contains(priorities, v.DestValue) {
+					problems = append(problems, fmt.Sprintf("container %s: priority %s: unknown destination priority %q", cc.SourceName, v.SourceValue, v.DestValue))
+				}
+			}
+		}
+		for _, cf := range cc.CustomFields {
+			if cf.DestFieldID != "" && len(fieldIDs) > 0 && !slices.Contains(fieldIDs, cf.DestFieldID) {
+				problems = append(problems, fmt.Sprintf("container %s: custom field %s: unknown destination field %q", cc.SourceName, cf.FieldName, cf.DestFieldID))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidInput, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ---- Diff ----
+
+type mappingEntry struct {
+	Type        string
+	Container   string
+	SourceValue string
+}
+
+// flattenMappingContent lists the configured value of every mapping in a content.
+func flattenMappingContent(content repository.MappingContent) ([]mappingEntry, map[mappingEntry]string) {
+	var order []mappingEntry
+	values := make(map[mappingEntry]string)
+	add := func(e mappingEntry, v string) {
+		if _, seen := values[e]; !seen {
+			order = append(order, e)
+		}
+		values[e] = v
+	}
+
+	for _, a := range content.Assignees {
+		v := a.DestValue
+		if a.Skip {
+			v = "(skipped)"
+		}
+		add(mappingEntry{Type: string(repository.MappingTypeAssignee), SourceValue: a.SourceValue}, v)
+	}
+	for _, t := range content.Tags {
+		v := t.DestValue
+		if t.Skip {
+			v = "(dropped)"
+		}
+		add(mappingEntry{Type: string(repository.MappingTypeTag), SourceValue: t.SourceValue}, v)
+	}
+	for _, cc := range content.Containers {
+		v := "(disabled)"
+		if cc.Enabled {
+			v = firstNonEmpty(cc.DestName, cc.DestID)
+		}
+		add(mappingEntry{Type: "container", SourceValue: cc.SourceName}, v)
+		for _, st := range cc.Statuses {
+			add(mappingEntry{Type: string(repository.MappingTypeStatus), Container: cc.SourceName, SourceValue: st.SourceValue}, st.DestValue)
+		}
+		for _, p := range cc.Priorities {
+			add(mappingEntry{Type: string(repository.MappingTypePriority), Container: cc.SourceName, SourceValue: p.SourceValue}, p.DestValue)
+		}
+		for _, cf := range cc.CustomFields {
+			add(mappingEntry{Type: string(repository.MappingTypeCustomField), Container: cc.SourceName, SourceValue: cf.FieldName}, describeCustomFieldContent(cf))
+		}
+	}
+	return order, values
+}
+
+func describeCustomFieldContent(cf repository.CustomFieldContent) string {
+	if !cf.Enabled {
+		return "(disabled)"
+	}
+	parts := []string{"enabled"}
+	if cf.DestFieldID != "" {
+		parts = append(parts, "field "+cf.DestFieldID)
+	}
+	if cf.Conversion != "" {
+		parts = append(parts, "as "+cf.Conversion)
+	}
+	if len(cf.OptionMappings) > 0 {
+		keys := make([]string, 0, len(cf.OptionMappings))
+		for k := range cf.OptionMappings {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		options := make([]string, len(keys))
+		for i, k := range keys {
+			options[i] = k + "→" + cf.OptionMappings[k]
+		}
+		parts = append(parts, "options "+strings.Join(options, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// diffMappingContent returns the mappings whose value differs between two contents.
+func diffMappingContent(before, after repository.MappingContent) []MappingChange {
+	_, old := flattenMappingContent(before)
+	order, updated := flattenMappingContent(after)
+	changes := []MappingChange{}
+	for _, e := range order {
+		if from := old[e]; from != updated[e] {
+			changes = append(changes, MappingChange{Type: e.Type, Container: e.Container, SourceValue: e.SourceValue, From: from, To: updated[e]})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,416 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+type mappingTemplateRepo interface {
+	Create(template *repository.MappingTemplate) (int64, error)
+	GetMappingTemplate(id int64) (repository.MappingTemplate, error)
+	GetMappingTemplates() ([]repository.MappingTemplate, error)
+	GetByOwnerID(ownerID int64) ([]repository.MappingTemplate, error)
+	Delete(id int64) error
+}
+
+// UnmatchedMapping is an entry of a template or imported configuration that matched nothing
+// in the migration it was applied to. Container is the source container name, if any.
+type UnmatchedMapping struct {
+	Type        string // "container", "assignee", "tag", "status", "priority", "custom_field" or "custom_field_option"
+	Container   string
+	SourceValue string
+}
+
+// ---- Templates ----
+
+// SaveMappingTemplate saves the mapping configuration of a migration as a named template
+// owned by ownerID.
+func (s *MigrationService) SaveMappingTemplate(migrationID int64, name string, ownerID *int64) (repository.MappingTemplate, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return repository.MappingTemplate{}, fmt.Errorf("%w: template name is required", ErrInvalidInput)
+	}
+	migration, err := s.migrationRepo.GetMigration(migrationID)
+	if err != nil {
+		return repository.MappingTemplate{}, fmt.Errorf("get migration: %w", err)
+	}
+	content, err := s.mappingContent(migrationID)
+	if err != nil {
+		return repository.MappingTemplate{}, err
+	}
+
+	template := repository.MappingTemplate{
+		Name:        name,
+		Source:      migration.Source,
+		Destination: migration.Destination,
+		Content:     *content,
+		OwnerID:     ownerID,
+	}
+	id, err := s.mappingTemplateRepo.Create(&template)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateTemplateName) {
+			return repository.MappingTemplate{}, fmt.Errorf("%w: %s", ErrInvalidInput, err)
+		}
+		return repository.MappingTemplate{}, fmt.Errorf("save mapping template: %w", err)
+	}
+	return s.mappingTemplateRepo.GetMappingTemplate(id)
+}
+
+func (s *MigrationService) GetMappingTemplate(id int64) (repository.MappingTemplate, error) {
+	template, err := s.mappingTemplateRepo.GetMappingTemplate(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.MappingTemplate{}, fmt.Errorf("%w: mapping template %d", ErrNotFound, id)
+	}
+	if err != nil {
+		return repository.MappingTemplate{}, fmt.Errorf("get mapping template: %w", err)
+	}
+	return template, nil
+}
+
+func (s *MigrationService) GetMappingTemplates() ([]repository.MappingTemplate, error) {
+	templates, err := s.mappingTemplateRepo.GetMappingTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("get mapping templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (s *MigrationService) DeleteMappingTemplate(id int64) error {
+	err := s.mappingTemplateRepo.Delete(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: mapping template %d", ErrNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("delete mapping template: %w", err)
+	}
+	return nil
+}
+
+// ApplyMappingTemplate applies a template to a migration that has not started yet. Template
+// entries that match nothing in the migration are returned; mappings the template does not
+// cover are left as they are.
+func (s *MigrationService) ApplyMappingTemplate(ctx context.Context, migrationID, templateID int64) (*MappingsState, []UnmatchedMapping, error) {
+	template, err := s.GetMappingTemplate(templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	migration, err := s.migrationRepo.GetMigration(migrationID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get migration: %w", err)
+	}
+	if template.Source != migration.Source || template.Destination != migration.Destination {
+		return nil, nil, fmt.Errorf("%w: template %q maps %s to %s, migration is %s to %s", ErrInvalidInput,
+			template.Name, template.Source, template.Destination, migration.Source, migration.Destination)
+	}
+	return s.applyMappingContent(ctx, migration, template.Content)
+}
+
+// ---- Content ----
+
+// mappingContent reads the saved mapping configuration of a migration.
+func (s *MigrationService) mappingContent(migrationID int64) (*repository.MappingContent, error) {
+	content := &repository.MappingContent{}
+
+	globals, err := s.migrationMappingRepo.GetGlobalByMigrationID(migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get global mappings: %w", err)
+	}
+	for _, m := range globals {
+		switch m.Type {
+		case repository.MappingTypeAssignee:
+			var a repository.AssigneeValueMapping
+			switch {
+			case m.Status == repository.MappingStatusSkipped:
+				a = repository.AssigneeValueMapping{SourceValue: m.SourceValue, Skip: true}
+			case m.Status == repository.MappingStatusMapped && m.DestValue != nil:
+				a = repository.AssigneeValueMapping{SourceValue: m.SourceValue, DestValue: *m.DestValue}
+			default:
+				continue
+			}
+			if m.Metadata != nil {
+				a.Email = m.Metadata.Email
+				a.Name = m.Metadata.Name
+			}
+			content.Assignees = append(content.Assignees, a)
+		case repository.MappingTypeTag:
+			switch {
+			case m.Status == repository.MappingStatusSkipped:
+				content.Tags = append(content.Tags, repository.ValueMapping{SourceValue: m.SourceValue, Skip: true})
+			case m.Status == repository.MappingStatusMapped && m.DestValue != nil:
+				content.Tags = append(content.Tags, repository.ValueMapping{SourceValue: m.SourceValue, DestValue: *m.DestValue})
+			}
+		}
+	}
+
+	containers, err := s.containerMappingRepo.GetByMigrationID(migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get container mappings: %w", err)
+	}
+	for _, cm := range containers {
+		cc := repository.ContainerContent{SourceName: cm.SourceName, Enabled: cm.Enabled}
+		if cm.DestID != nil {
+			cc.DestID = *cm.DestID
+		}
+		if cm.DestName != nil {
+			cc.DestName = *cm.DestName
+		}
+
+		mappings, err := s.migrationMappingRepo.GetByMigrationIDAndContainer(migrationID, cm.SourceID)
+		if err != nil {
+			return nil, fmt.Errorf("get mappings of container %s: %w", cm.SourceID, err)
+		}
+		for _, m := range mappings {
+			if m.DestValue == nil || *m.DestValue == "" {
+				continue
+			}
+			v := repository.ValueMapping{SourceValue: m.SourceValue, DestValue: *m.DestValue}
+			switch m.Type {
+			case repository.MappingTypeStatus:
+				cc.Statuses = append(cc.Statuses, v)
+			case repository.MappingTypePriority:
+				cc.Priorities = append(cc.Priorities, v)
+			}
+		}
+
+		containerID := cm.SourceID
+		fields, err := s.migrationMappingRepo.GetCustomFields(migrationID, &containerID)
+		if err != nil {
+			return nil, fmt.Errorf("get custom fields of container %s: %w", cm.SourceID, err)
+		}
+		for _, f := range fields {
+			cf := repository.CustomFieldContent{
+				FieldID:    f.FieldID,
+				FieldName:  f.FieldName,
+				Enabled:    f.Enabled,
+				Conversion: f.Conversion,
+			}
+			if f.DestFieldID != nil {
+				cf.DestFieldID = *f.DestFieldID
+			}
+			if len(f.OptionMap) > 0 {
+				names := make(map[string]string, len(f.Options))
+				for _, o := range f.Options {
+					if o.Name != "" {
+						names[o.ID] = o.Name
+					}
+				}
+				cf.OptionMappings = make(map[string]string, len(f.OptionMap))
+				for sourceID, destID := range f.OptionMap {
+					key := sourceID
+					if name, ok := names[sourceID]; ok {
+						key = name
+					}
+					cf.OptionMappings[key] = destID
+				}
+			}
+			cc.CustomFields = append(cc.CustomFields, cf)
+		}
+		content.Containers = append(content.Containers, cc)
+	}
+	return content, nil
+}
+
+// applyMappingContent matches a mapping configuration against the discovered mappings of a
+// migration and saves the matching entries through SaveMappings. Assignees match by source
+// value, then email; containers by name; custom fields by ID, then name; options by name.
+func (s *MigrationService) applyMappingContent(ctx context.Context, migration repository.Migration, content repository.MappingContent) (*MappingsState, []UnmatchedMapping, error) {
+	if err := checkMappingsEditable(migration); err != nil {
+		return nil, nil, err
+	}
+	unmatched := []UnmatchedMapping{}
+
+	globals, err := s.migrationMappingRepo.GetGlobalByMigrationID(migration.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get global mappings: %w", err)
+	}
+	assigneeByID := make(map[string]string)
+	assigneeByEmail := make(map[string]string)
+	tags := make(map[string]bool)
+	for _, m := range globals {
+		switch m.Type {
+		case repository.MappingTypeAssignee:
+			assigneeByID[m.SourceValue] = m.SourceValue
+			if m.Metadata != nil && m.Metadata.Email != "" {
+				assigneeByEmail[strings.ToLower(m.Metadata.Email)] = m.SourceValue
+			}
+		case repository.MappingTypeTag:
+			tags[m.SourceValue] = true
+		}
+	}
+
+	var assignees []AssigneeMappingInput
+	for _, a := range content.Assignees {
+		source, ok := assigneeByID[a.SourceValue]
+		if !ok && a.Email != "" {
+			source, ok = assigneeByEmail[strings.ToLower(a.Email)]
+		}
+		if !ok {
+			unmatched = append(unmatched, UnmatchedMapping{Type: string(repository.MappingTypeAssignee), SourceValue: firstNonEmpty(a.Email, a.SourceValue)})
+			continue
+		}
+		assignees = append(assignees, AssigneeMappingInput{SourceValue: source, DestValue: a.DestValue, Skip: a.Skip})
+	}
+
+	var tagInputs []TagMappingInput
+	for _, t := range content.Tags {
+		if !tags[t.SourceValue] {
+			unmatched = append(unmatched, UnmatchedMapping{Type: string(repository.MappingTypeTag), SourceValue: t.SourceValue})
+			continue
+		}
+		tagInputs = append(tagInputs, TagMappingInput{SourceValue: t.SourceValue, DestValue: t.DestValue, Drop: t.Skip})
+	}
+
+	containers, err := s.containerMappingRepo.GetByMigrationID(migration.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get container mappings: %w", err)
+	}
+	containerByName := make(map[string]repository.ContainerMapping, len(containers))
+	for _, cm := range containers {
+		if _, dup := containerByName[cm.SourceName]; !dup {
+			containerByName[cm.SourceName] = cm
+		}
+	}
+
+	var containerInputs []ContainerMappingInput
+	for _, cc := range content.Containers {
+		cm, ok := containerByName[cc.SourceName]
+		if !ok {
+			unmatched = append(unmatched, UnmatchedMapping{Type: "container", SourceValue: cc.SourceName})
+			continue
+		}
+		input, containerUnmatched, err := s.containerInputFromContent(migration.ID, cm, cc)
+		if err != nil {
+			return nil, nil, err
+		}
+		containerInputs = append(containerInputs, input)
+		unmatched = append(unmatched, containerUnmatched...)
+	}
+
+	state, err := s.SaveMappings(ctx, migration.ID, assignees, tagInputs, containerInputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return state, unmatched, nil
+}
+
+func checkMappingsEditable(migration repository.Migration) error {
+	if migration.Status != repository.MigrationStatusPendingConfiguration && migration.Status != repository.MigrationStatusReadyToStart {
+		return fmt.Errorf("%w: mappings cannot be changed once a migration has started", ErrInvalidMigrationState)
+	}
+	return nil
+}
+
+// containerInputFromContent builds the save payload of one matched container.
+func (s *MigrationService) containerInputFromContent(migrationID int64, cm repository.ContainerMapping, cc repository.ContainerContent) (ContainerMappingInput, []UnmatchedMapping, error) {
+	var unmatched []UnmatchedMapping
+	input := ContainerMappingInput{SourceID: cm.SourceID, Enabled: cc.Enabled}
+	if cc.DestID != "" {
+		destID, destName := cc.DestID, cc.DestName
+		input.DestID = &destID
+		input.DestName = &destName
+	} else if cm.DestID != nil {
+		// Keep the container's current destination when the content has none.
+		input.DestID = cm.DestID
+		input.DestName = cm.DestName
+	}
+
+	mappings, err := s.migrationMappingRepo.GetByMigrationIDAndContainer(migrationID, cm.SourceID)
+	if err != nil {
+		return ContainerMappingInput{}, nil, fmt.Errorf("get mappings of container %s: %w", cm.SourceID, err)
+	}
+	known := make(map[repository.MappingType]map[string]bool)
+	for _, m := range mappings {
+		if known[m.Type] == nil {
+			known[m.Type] = make(map[string]bool)
+		}
+		known[m.Type][m.SourceValue] = true
+	}
+	match := func(t repository.MappingType, values []repository.ValueMapping) []FieldMappingInput {
+		var result []FieldMappingInput
+		for _, v := range values {
+			if !known[t][v.SourceValue] {
+				unmatched = append(unmatched, UnmatchedMapping{Type: string(t), Container: cc.SourceName, SourceValue: v.SourceValue})
+				continue
+			}
+			result = append(result, FieldMappingInput{SourceValue: v.SourceValue, DestValue: v.DestValue})
+		}
+		return result
+	}
+	input.StatusMappings = match(repository.MappingTypeStatus, cc.Statuses)
+	input.PriorityMappings = match(repository.MappingTypePriority, cc.Priorities)
+
+	containerID := cm.SourceID
+	fields, err := s.migrationMappingRepo.GetCustomFields(migrationID, &containerID)
+	if err != nil {
+		return ContainerMappingInput{}, nil, fmt.Errorf("get custom fields of container %s: %w", cm.SourceID, err)
+	}
+	for _, cf := range cc.CustomFields {
+		var field *repository.CustomFieldRow
+		for i := range fields {
+			if fields[i].FieldID == cf.FieldID {
+				field = &fields[i]
+				break
+			}
+		}
+		for i := 0; field == nil && i < len(fields); i++ {
+			if cf.FieldName != "" && strings.EqualFold(fields[i].FieldName, cf.FieldName) {
+				field = &fields[i]
+			}
+		}
+		if field == nil {
+			unmatched = append(unmatched, UnmatchedMapping{Type: string(repository.MappingTypeCustomField), Container: cc.SourceName, SourceValue: firstNonEmpty(cf.FieldName, cf.FieldID)})
+			continue
+		}
+
+		selection := CustomFieldSelection{FieldID: field.FieldID, Enabled: cf.Enabled}
+		if cf.DestFieldID != "" || cf.Conversion != "" || len(cf.OptionMappings) > 0 {
+			selection.Mapping = &CustomFieldMappingInput{DestFieldID: cf.DestFieldID, Conversion: cf.Conversion}
+			if len(cf.OptionMappings) > 0 {
+				selection.Mapping.OptionMappings = make(map[string]string, len(cf.OptionMappings))
+			}
+			for key, destID := range cf.OptionMappings {
+				sourceID, ok := optionIDFor(*field, key)
+				if !ok {
+					unmatched = append(unmatched, UnmatchedMapping{Type: "custom_field_option", Container: cc.SourceName, SourceValue: field.FieldName + ": " + key})
+					continue
+				}
+				selection.Mapping.OptionMappings[sourceID] = destID
+			}
+		}
+		input.CustomFields = append(input.CustomFields, selection)
+	}
+	return input, unmatched, nil
+}
+
+// optionIDFor resolves an option mapping key, an option name or ID, to a source option ID.
+// Checkbox fields have no stored options; their "true"/"false" keys are used as they are.
+func optionIDFor(field repository.CustomFieldRow, key string) (string, bool) {
+	for _, o := range field.Options {
+		if o.Name == key {
+			return o.ID, true
+		}
+	}
+	for _, o := range field.Options {
+		if o.ID == key {
+			return o.ID, true
+		}
+	}
+	if field.FieldType == "checkbox" && (key == "true" || key == "false") {
+		return key, true
+	}
+	return "", false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
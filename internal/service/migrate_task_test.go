@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// failingTaskMappings cannot store task mappings.
+type failingTaskMappings struct{}
+
+func (failingTaskMappings) Create(*repository.TaskMapping) error {
+	return errors.New("database is locked")
+}
+
+func (failingTaskMappings) GetByMigrationIDAndStatus(int64, repository.TaskMappingStatus) ([]repository.TaskMapping, error) {
+	return nil, nil
+}
+
+func (failingTaskMappings) UpdateStatus(int64, repository.TaskMappingStatus, string) error {
+	return nil
+}
+
+// fakeDest creates tasks and records the ones it is asked to delete.
+type fakeDest struct {
+	deleted []string
+}
+
+func (d *fakeDest) GetTasks(context.Context, string) ([]models.Task, error) {
+	return nil, nil
+}
+
+func (d *fakeDest) CreateTask(_ context.Context, _ string, _ string, task models.Task) (*models.Task, error) {
+	return &models.Task{Id: "dest-" + task.Id, Name: task.Name}, nil
+}
+
+func (d *fakeDest) DeleteTask(_ context.Context, taskId string) error {
+	d.deleted = append(d.deleted, taskId)
+	return nil
+}
+
+// A task whose mapping cannot be recorded fails, and the destination task is removed so a
+// rerun does not create it twice.
+func TestMigrateTaskFailsWhenMappingIsNotRecorded(t *testing.T) {
+	dest := &fakeDest{}
+	s := &MigrationService{taskMappingRepo: failingTaskMappings{}}
+	exec := &taskExecution{migration: repository.Migration{ID: 1}, destClient: dest}
+
+	if !s.migrateTask(context.Background(), exec, models.Task{Id: "t1", Name: "Ship it"}, "list", nil, nil) {
+		t.Fatal("migrateTask stopped the run")
+	}
+	if exec.successCount != 0 || exec.failCount != 1 {
+		t.Errorf("success, failed = %d, %d; want 0, 1", exec.successCount, exec.failCount)
+	}
+	if !reflect.DeepEqual(dest.deleted, []string{"dest-t1"}) {
+		t.Errorf("deleted = %v, want the unrecorded task dest-t1", dest.deleted)
+	}
+}
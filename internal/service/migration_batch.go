@@ -0,0 +1,481 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// maxBatchConcurrency bounds how many migrations of a batch run at once, so a large batch
+// does not exhaust the rate limits of the source and destination APIs.
+const maxBatchConcurrency = 4
+
+// batchTimeout bounds how long a batch waits for its migrations, queueing included.
+const batchTimeout = 24 * time.Hour
+
+type migrationBatchRepo interface {
+	Create(batch *repository.MigrationBatch) (int64, error)
+	Start(id int64) error
+	Complete(id int64, status repository.MigrationBatchStatus) error
+	GetMigrationBatch(id int64) (repository.MigrationBatch, error)
+	GetMigrationBatches() ([]repository.MigrationBatch, error)
+	GetByStatus(status repository.MigrationBatchStatus) ([]repository.MigrationBatch, error)
+	GetByOwnerID(ownerID int64) ([]repository.MigrationBatch, error)
+}
+
+// CreateBatchInput configures a batch. Every route becomes a migration with the shared
+// settings of the batch.
+type CreateBatchInput struct {
+	Name            string
+	Source          string
+	Destination     string
+	DestWorkspaceID string
+	Concurrency     int // 0 runs the migrations one after another
+	Routes          []repository.BatchRoute
+	Filters         []repository.FilterRule
+	TimeZone        string
+	BacklinkMode    string
+	BacklinkFieldID string
+	ReverseBacklink bool
+
+	ContainerStrategy string
+	OwnerID           *int64 // owns the batch and its migrations
+	// SourceConnectionID and DestConnectionID are used by every migration of the batch.
+	SourceConnectionID *int64
+	DestConnectionID   *int64
+	ServerCredentials  bool // see CreateMigrationInput
+}
+
+// BatchProgress rolls up the progress of the migrations of a batch.
+type BatchProgress struct {
+	Migrations     int
+	Finished       int // migrations that completed, with or without errors, or failed
+	TotalTasks     int
+	CompletedTasks int
+	FailedTasks    int
+	ExcludedTasks  int
+}
+
+// BatchState is a batch with its migrations and their rolled-up progress.
+type BatchState struct {
+	Batch      repository.MigrationBatch
+	Migrations []repository.Migration
+	Progress   BatchProgress
+}
+
+// BatchAssigneesState lists the assignees found across the migrations of a batch. An
+// assignee is mapped once every migration it appears in maps it.
+type BatchAssigneesState struct {
+	Assignees            []AssigneeMappingItem
+	AvailableDestMembers []models.Member
+}
+
+// CreateBatch creates a batch and one migration per route. Routes are validated up front;
+// if discovery fails for a route the batch is marked failed and the error returned.
+func (s *MigrationService) CreateBatch(ctx context.Context, input CreateBatchInput) (*BatchState, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return nil, fmt.Errorf("%w: batch name is required", ErrInvalidInput)
+	}
+	if len(input.Routes) == 0 {
+		return nil, fmt.Errorf("%w: a batch needs at least one route", ErrInvalidInput)
+	}
+	if input.Concurrency < 0 || input.Concurrency > maxBatchConcurrency {
+		return nil, fmt.Errorf("%w: concurrency must be between 1 and %d", ErrInvalidInput, maxBatchConcurrency)
+	}
+	if input.Concurrency == 0 {
+		input.Concurrency = 1
+	}
+
+	seen := make(map[string]bool, len(input.Routes))
+	inputs := make([]CreateMigrationInput, len(input.Routes))
+	for i, route := range input.Routes {
+		if route.SourceProjectID == "" {
+			return nil, fmt.Errorf("%w: route %d: source_project_id is required", ErrInvalidInput, i+1)
+		}
+		if seen[route.SourceProjectID] {
+			return nil, fmt.Errorf("%w: project %s is routed more than once", ErrInvalidInput, route.SourceProjectID)
+		}
+		seen[route.SourceProjectID] = true
+
+		inputs[i] = CreateMigrationInput{
+			Source:          input.Source,
+			Destination:     input.Destination,
+			SourceProjectID: route.SourceProjectID,
+			DestListID:      route.DestListID,
+			DestWorkspaceID: input.DestWorkspaceID,
+			DestSpaceID:     route.DestSpaceID,
+			Filters:         input.Filters,
+			TimeZone:        input.TimeZone,
+			BacklinkMode:    input.BacklinkMode,
+			BacklinkFieldID: input.BacklinkFieldID,
+			ReverseBacklink: input.ReverseBacklink,
+
+			ContainerStrategy:  input.ContainerStrategy,
+			OwnerID:            input.OwnerID,
+			SourceConnectionID: input.SourceConnectionID,
+			DestConnectionID:   input.DestConnectionID,
+			ServerCredentials:  input.ServerCredentials,
+		}
+		if err := s.validateProviders(inputs[i]); err != nil {
+			return nil, fmt.Errorf("route %s: %w", route.SourceProjectID, err)
+		}
+	}
+
+	batchID, err := s.migrationBatchRepo.Create(&repository.MigrationBatch{
+		Name:            input.Name,
+		Source:          input.Source,
+		Destination:     input.Destination,
+		DestWorkspaceID: input.DestWorkspaceID,
+		Concurrency:     input.Concurrency,
+		Routes:          input.Routes,
+		Status:          repository.MigrationBatchStatusPending,
+		OwnerID:         input.OwnerID,
+
+		SourceConnectionID: input.SourceConnectionID,
+		DestConnectionID:   input.DestConnectionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create migration batch: %w", err)
+	}
+
+	for _, in := range inputs {
+		if _, _, err := s.createMigration(ctx, in, &batchID); err != nil {
+			if cerr := s.migrationBatchRepo.Complete(batchID, repository.MigrationBatchStatusFailed); cerr != nil {
+				slog.Error("failed to mark batch failed", "batch_id", batchID, "error", cerr)
+			}
+			return nil, fmt.Errorf("create migration for project %s: %w", in.SourceProjectID, err)
+		}
+	}
+	return s.GetBatch(batchID)
+}
+
+func (s *MigrationService) GetBatch(id int64) (*BatchState, error) {
+	batch, err := s.migrationBatchRepo.GetMigrationBatch(id)
+	if err != nil {
+		return nil, fmt.Errorf("get migration batch: %w", err)
+	}
+	return s.batchState(batch)
+}
+
+func (s *MigrationService) GetBatches() ([]BatchState, error) {
+	batches, err := s.migrationBatchRepo.GetMigrationBatches()
+	if err != nil {
+		return nil, fmt.Errorf("get migration batches: %w", err)
+	}
+	return s.batchStates(batches)
+}
+
+func (s *MigrationService) batchStates(batches []repository.MigrationBatch) ([]BatchState, error) {
+	states := make([]BatchState, 0, len(batches))
+	for _, b := range batches {
+		state, err := s.batchState(b)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, *state)
+	}
+	return states, nil
+}
+
+func (s *MigrationService) batchState(batch repository.MigrationBatch) (*BatchState, error) {
+	migrations, err := s.migrationRepo.GetByBatchID(batch.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get batch migrations: %w", err)
+	}
+	state := &BatchState{Batch: batch, Migrations: migrations}
+	state.Progress.Migrations = len(migrations)
+	for _, m := range migrations {
+		state.Progress.TotalTasks += m.TotalTasks
+		state.Progress.CompletedTasks += m.CompletedTasks
+		state.Progress.FailedTasks += m.FailedTasks
+		state.Progress.ExcludedTasks += m.ExcludedTasks
+		if migrationFinished(m.Status) {
+			state.Progress.Finished++
+		}
+	}
+	return state, nil
+}
+
+func migrationFinished(status repository.MigrationStatus) bool {
+	switch status {
+	case repository.MigrationStatusCompleted, repository.MigrationStatusCompletedWithErrors, repository.MigrationStatusFailed:
+		return true
+	}
+	return false
+}
+
+// ---- Shared assignees ----
+
+// GetBatchAssignees merges the assignees discovered by the migrations of a batch.
+func (s *MigrationService) GetBatchAssignees(ctx context.Context, id int64) (*BatchAssigneesState, error) {
+	batch, err := s.migrationBatchRepo.GetMigrationBatch(id)
+	if err != nil {
+		return nil, fmt.Errorf("get migration batch: %w", err)
+	}
+	migrations, err := s.migrationRepo.GetByBatchID(id)
+	if err != nil {
+		return nil, fmt.Errorf("get batch migrations: %w", err)
+	}
+
+	var assignees []AssigneeMappingItem
+	index := make(map[string]int)
+	for _, m := range migrations {
+		globals, err := s.migrationMappingRepo.GetGlobalByMigrationID(m.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get global mappings of migration %d: %w", m.ID, err)
+		}
+		for _, g := range globals {
+			if g.Type != repository.MappingTypeAssignee {
+				continue
+			}
+			i, ok := index[g.SourceValue]
+			if !ok {
+				item := AssigneeMappingItem{MappingItem: MappingItem{SourceValue: g.SourceValue, DestValue: g.DestValue, Status: string(g.Status)}}
+				if g.Metadata != nil {
+					item.Name = g.Metadata.Name
+					item.Email = g.Metadata.Email
+				}
+				index[g.SourceValue] = len(assignees)
+				assignees = append(assignees, item)
+				continue
+			}
+			// One migration still waiting for a mapping keeps the shared assignee pending.
+			if g.Status != repository.MappingStatusMapped {
+				assignees[i].Status = string(g.Status)
+			}
+			if assignees[i].DestValue == nil {
+				assignees[i].DestValue = g.DestValue
+			}
+		}
+	}
+
+	members, err := s.getMembersForDestination(ctx, providerRef{Name: batch.Destination, ConnectionID: batch.DestConnectionID}, batch.DestWorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("get destination members: %w", err)
+	}
+	return &BatchAssigneesState{Assignees: assignees, AvailableDestMembers: members}, nil
+}
+
+// SaveBatchAssignees saves assignee mappings in every migration of the batch that has not
+// started yet and knows the assignee.
+func (s *MigrationService) SaveBatchAssignees(ctx context.Context, id int64, assignees []AssigneeMappingInput) (*BatchAssigneesState, error) {
+	migrations, err := s.migrationRepo.GetByBatchID(id)
+	if err != nil {
+		return nil, fmt.Errorf("get batch migrations: %w", err)
+	}
+	for _, m := range migrations {
+		if checkMappingsEditable(m) != nil {
+			continue
+		}
+		globals, err := s.migrationMappingRepo.GetGlobalByMigrationID(m.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get global mappings of migration %d: %w", m.ID, err)
+		}
+		known := make(map[string]bool, len(globals))
+		for _, g := range globals {
+			if g.Type == repository.MappingTypeAssignee {
+				known[g.SourceValue] = true
+			}
+		}
+		for _, a := range assignees {
+			if !known[a.SourceValue] {
+				continue
+			}
+			if err := s.saveAssigneeMapping(m.ID, a); err != nil {
+				slog.Warn("could not save batch assignee mapping", "migration_id", m.ID, "source", a.SourceValue, "error", err)
+			}
+		}
+		if err := s.refreshReadiness(m.ID); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetBatchAssignees(ctx, id)
+}
+
+// ---- Execution ----
+
+// StartBatch runs the migrations of a batch in the background, at most Concurrency at a
+// time. Every migration that has not run yet must be ready to start; migrations that
+// already ran on their own are left as they are. The batch state lives in its migrations
+// and their jobs, so a server restart picks the batch up again, see ResumeBatches.
+func (s *MigrationService) StartBatch(id int64) error {
+	batch, err := s.migrationBatchRepo.GetMigrationBatch(id)
+	if err != nil {
+		return fmt.Errorf("get migration batch: %w", err)
+	}
+	if batch.Status != repository.MigrationBatchStatusPending {
+		return fmt.Errorf("%w: batch is %s", ErrInvalidMigrationState, batch.Status)
+	}
+	migrations, err := s.migrationRepo.GetByBatchID(id)
+	if err != nil {
+		return fmt.Errorf("get batch migrations: %w", err)
+	}
+
+	ready := 0
+	var notReady []string
+	for _, m := range migrations {
+		switch m.Status {
+		case repository.MigrationStatusReadyToStart:
+			ready++
+		case repository.MigrationStatusPendingConfiguration:
+			notReady = append(notReady, fmt.Sprintf("%d (%s)", m.ID, m.SourceProjectID))
+		}
+	}
+	if len(notReady) > 0 {
+		return fmt.Errorf("%w: migrations %s are not fully mapped", ErrInvalidMigrationState, strings.Join(notReady, ", "))
+	}
+	if ready == 0 {
+		return fmt.Errorf("%w: batch has no migration left to run", ErrInvalidMigrationState)
+	}
+
+	if err := s.migrationBatchRepo.Start(id); err != nil {
+		return fmt.Errorf("start migration batch: %w", err)
+	}
+	// Create an independent context — not tied to the HTTP request lifecycle.
+	ctx, cancel := context.WithTimeout(context.Background(), batchTimeout)
+	go func() {
+		defer cancel()
+		s.executeBatch(ctx, batch, migrations)
+	}()
+	return nil
+}
+
+// ResumeBatches picks up the batches that were running when the server stopped. Their
+// queued and running migrations are waited for and the ones not started yet are queued,
+// as StartBatch would have done. A resumed batch stops with ctx and is resumed again on
+// the next start.
+func (s *MigrationService) ResumeBatches(ctx context.Context) error {
+	batches, err := s.migrationBatchRepo.GetByStatus(repository.MigrationBatchStatusRunning)
+	if err != nil {
+		return fmt.Errorf("get running batches: %w", err)
+	}
+	for _, batch := range batches {
+		migrations, err := s.migrationRepo.GetByBatchID(batch.ID)
+		if err != nil {
+			return fmt.Errorf("get migrations of batch %d: %w", batch.ID, err)
+		}
+		slog.Info("resuming migration batch", "batch_id", batch.ID)
+		batchCtx, cancel := context.WithTimeout(ctx, batchTimeout)
+		go func() {
+			defer cancel()
+			s.executeBatch(batchCtx, batch, migrations)
+		}()
+	}
+	return nil
+}
+
+// executeBatch runs the migrations of a started batch and records its outcome. Migrations
+// that are ready to start are queued; queued and running ones are waited for.
+func (s *MigrationService) executeBatch(ctx context.Context, batch repository.MigrationBatch, migrations []repository.Migration) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("panic in executeBatch", "batch_id", batch.ID, "panic", r, "stack", string(debug.Stack()))
+			s.completeBatch(batch.ID, repository.MigrationBatchStatusFailed)
+		}
+	}()
+
+	var queue []repository.Migration
+	for _, m := range migrations {
+		switch m.Status {
+		case repository.MigrationStatusReadyToStart, repository.MigrationStatusQueued, repository.MigrationStatusRunning:
+			queue = append(queue, m)
+		}
+	}
+	slog.Info("running migration batch", "batch_id", batch.ID, "migrations", len(queue), "concurrency", batch.Concurrency)
+
+	sem := make(chan struct{}, max(batch.Concurrency, 1))
+	var wg sync.WaitGroup
+	for _, m := range queue {
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.runBatchMigration(ctx, m)
+		}()
+	}
+	wg.Wait()
+
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		// The server is stopping; the batch stays running for ResumeBatches.
+		slog.Info("migration batch interrupted", "batch_id", batch.ID)
+		return
+	case ctx.Err() != nil:
+		slog.Error("migration batch timed out, leaving the rest of its migrations unstarted", "batch_id", batch.ID)
+	}
+
+	migrations, err := s.migrationRepo.GetByBatchID(batch.ID)
+	if err != nil {
+		slog.Error("failed to load batch migrations", "batch_id", batch.ID, "error", err)
+		s.completeBatch(batch.ID, repository.MigrationBatchStatusFailed)
+		return
+	}
+	s.completeBatch(batch.ID, batchOutcome(migrations))
+}
+
+func (s *MigrationService) completeBatch(id int64, status repository.MigrationBatchStatus) {
+	if err := s.migrationBatchRepo.Complete(id, status); err != nil {
+		slog.Error("failed to complete migration batch", "batch_id", id, "status", status, "error", err)
+	}
+}
+
+// runBatchMigration queues one migration of a batch, unless it is already queued or
+// running, and waits for it to finish. The batch concurrency bounds how many of its
+// migrations are queued at once.
+func (s *MigrationService) runBatchMigration(ctx context.Context, m repository.Migration) {
+	var jobID int64
+	if m.Status == repository.MigrationStatusReadyToStart {
+		var err error
+		if jobID, err = s.enqueueMigration(m.ID); err != nil {
+			slog.Error("batch migration cannot start", "migration_id", m.ID, "error", err)
+			if err := s.migrationRepo.Complete(m.ID, repository.MigrationStatusFailed); err != nil {
+				slog.Error("failed to complete migration", "migration_id", m.ID, "error", err)
+			}
+			return
+		}
+	} else {
+		job, err := s.migrationJobRepo.GetLatestByMigrationID(m.ID)
+		if err != nil || job == nil {
+			slog.Error("no job to wait for in batch migration", "migration_id", m.ID, "error", err)
+			return
+		}
+		jobID = job.ID
+	}
+	if err := s.waitForJob(ctx, jobID); err != nil && !errors.Is(err, context.Canceled) {
+		slog.Error("failed to wait for batch migration", "migration_id", m.ID, "job_id", jobID, "error", err)
+	}
+}
+
+// batchOutcome derives the final status of a batch from its migrations.
+func batchOutcome(migrations []repository.Migration) repository.MigrationBatchStatus {
+	failed := 0
+	for _, m := range migrations {
+		switch m.Status {
+		case repository.MigrationStatusCompleted:
+		case repository.MigrationStatusFailed:
+			failed++
+		default:
+			return repository.MigrationBatchStatusCompletedWithErrors
+		}
+	}
+	switch {
+	case failed == len(migrations):
+		return repository.MigrationBatchStatusFailed
+	case failed > 0:
+		return repository.MigrationBatchStatusCompletedWithErrors
+	}
+	return repository.MigrationBatchStatusCompleted
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// A batch left running by a stopped server waits for its migrations again on the next
+// start and records its outcome once they are done.
+func TestResumeBatchesCompletesInterruptedBatch(t *testing.T) {
+	db := openTestDB(t)
+	batches := repository.NewMigrationBatchRepository(db)
+	migrations := repository.NewMigrationRepository(db)
+	jobs := repository.NewMigrationJobRepository(db)
+	s := &MigrationService{migrationBatchRepo: batches, migrationRepo: migrations, migrationJobRepo: jobs}
+
+	batchID, err := batches.Create(&repository.MigrationBatch{Name: "b", Source: "asana", Destination: "clickup", Concurrency: 2})
+	if err != nil {
+		t.Fatalf("Create batch: %v", err)
+	}
+	if err := batches.Start(batchID); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	newMigration := func(status repository.MigrationStatus) int64 {
+		id, err := migrations.Create(&repository.Migration{Source: "asana", Destination: "clickup", Status: status, BatchID: &batchID})
+		if err != nil {
+			t.Fatalf("Create migration: %v", err)
+		}
+		return id
+	}
+	newMigration(repository.MigrationStatusCompleted)
+	running := newMigration(repository.MigrationStatusRunning)
+	jobID, err := jobs.Enqueue(running)
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if _, err := jobs.Claim("w1", time.Now(), jobLease, 1); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+
+	if err := s.ResumeBatches(context.Background()); err != nil {
+		t.Fatalf("ResumeBatches: %v", err)
+	}
+	if b, _ := batches.GetMigrationBatch(batchID); b.Status != repository.MigrationBatchStatusRunning {
+		t.Fatalf("batch status = %s while a migration still runs, want running", b.Status)
+	}
+
+	if err := migrations.Complete(running, repository.MigrationStatusCompleted); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if err := jobs.Finish(jobID, "w1", repository.MigrationJobStatusDone, ""); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	deadline := time.Now().Add(3 * jobPollInterval)
+	for {
+		b, err := batches.GetMigrationBatch(batchID)
+		if err != nil {
+			t.Fatalf("GetMigrationBatch: %v", err)
+		}
+		if b.Status == repository.MigrationBatchStatusCompleted {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("batch status = %s, want completed", b.Status)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// ValidationReport lists what keeps a migration from starting.
+type ValidationReport struct {
+	MigrationID int64
+	Status      repository.MigrationStatus
+	Ready       bool
+	Problems    []string
+}
+
+// DryRunContainer is what a run would migrate from one source container.
+type DryRunContainer struct {
+	SourceID        string
+	SourceName      string
+	DestID          string
+	Tasks           int // tasks a run would create
+	Excluded        int // tasks left out by the filter rules
+	AlreadyMigrated int // tasks created by an earlier run
+}
+
+// DryRunReport is what starting a migration now would do, without writing anything.
+type DryRunReport struct {
+	MigrationID     int64
+	Containers      []DryRunContainer // empty for sources without containers
+	Tasks           int
+	Excluded        int
+	AlreadyMigrated int
+}
+
+// ValidateMigration checks a migration's mappings and providers without calling the
+// provider APIs.
+func (s *MigrationService) ValidateMigration(migrationID int64) (*ValidationReport, error) {
+	migration, err := s.migrationRepo.GetMigration(migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get migration: %w", err)
+	}
+	report := &ValidationReport{MigrationID: migrationID, Status: migration.Status}
+
+	if _, err := s.getProvider(sourceProviderOf(migration)); err != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("source: %v", err))
+	}
+	if _, err := s.getProvider(destProviderOf(migration)); err != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("destination: %v", err))
+	}
+
+	globals, err := s.migrationMappingRepo.GetGlobalByMigrationID(migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get global mappings: %w", err)
+	}
+	for _, m := range globals {
+		if m.Status == repository.MappingStatusPending {
+			report.Problems = append(report.Problems, fmt.Sprintf("%s %q is not mapped", m.Type, m.SourceValue))
+		}
+	}
+
+	containers, err := s.containerMappingRepo.GetByMigrationID(migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get container mappings: %w", err)
+	}
+	for _, cm := range containers {
+		if !cm.Enabled {
+			continue
+		}
+		if cm.Status == repository.ContainerMappingStatusPending {
+			report.Problems = append(report.Problems, fmt.Sprintf("container %q has no destination", cm.SourceName))
+		}
+		mappings, err := s.migrationMappingRepo.GetByMigrationIDAndContainer(migrationID, cm.SourceID)
+		if err != nil {
+			return nil, fmt.Errorf("get mappings of container %s: %w", cm.SourceID, err)
+		}
+		for _, m := range mappings {
+			if m.Status == repository.MappingStatusPending {
+				report.Problems = append(report.Problems, fmt.Sprintf("%s %q in container %q is not mapped", m.Type, m.SourceValue, cm.SourceName))
+			}
+		}
+	}
+
+	report.Ready = len(report.Problems) == 0
+	return report, nil
+}
+
+// DryRunMigration counts the source tasks a run would migrate, the way executeMigration
+// selects them, without creating anything in the destination.
+func (s *MigrationService) DryRunMigration(ctx context.Context, migrationID int64) (*DryRunReport, error) {
+	migration, err := s.migrationRepo.GetMigration(migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get migration: %w", err)
+	}
+	sourceProvider, err := s.getProvider(sourceProviderOf(migration))
+	if err != nil {
+		return nil, fmt.Errorf("get source provider: %w", err)
+	}
+	migrated, err := s.loadMigratedTaskIDs(migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("load migrated tasks: %w", err)
+	}
+	containerMappings, err := s.containerMappingRepo.GetByMigrationID(migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get container mappings: %w", err)
+	}
+	ctx = client.WithLocation(ctx, migrationLocation(migration))
+
+	report := &DryRunReport{MigrationID: migrationID}
+	cp, hasContainerProvider := sourceProvider.(client.ContainerProvider)
+	if !hasContainerProvider || len(containerMappings) == 0 {
+		tasks, err := sourceProvider.GetTasks(ctx, migration.SourceProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("get source tasks: %w", err)
+		}
+		tasks, excluded := splitByFilters(tasks, migration.FilterRules)
+		remaining := withoutMigrated(tasks, migrated)
+		report.Tasks = len(remaining)
+		report.Excluded = excluded
+		report.AlreadyMigrated = len(tasks) - len(remaining)
+		return report, nil
+	}
+
+	for _, cm := range containerMappings {
+		if !cm.Enabled || cm.DestID == nil {
+			continue
+		}
+		tasks, err := cp.GetTasksByContainer(ctx, cm.SourceID)
+		if err != nil {
+			return nil, fmt.Errorf("get tasks of container %s: %w", cm.SourceName, err)
+		}
+		tasks, excluded := splitByFilters(tasks, migration.FilterRules)
+		remaining := withoutMigrated(tasks, migrated)
+		c := DryRunContainer{
+			SourceID:        cm.SourceID,
+			SourceName:      cm.SourceName,
+			DestID:          *cm.DestID,
+			Tasks:           len(remaining),
+			Excluded:        excluded,
+			AlreadyMigrated: len(tasks) - len(remaining),
+		}
+		report.Containers = append(report.Containers, c)
+		report.Tasks += c.Tasks
+		report.Excluded += c.Excluded
+		report.AlreadyMigrated += c.AlreadyMigrated
+	}
+	return report, nil
+}
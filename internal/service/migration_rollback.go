@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// RollbackOptions selects which resources, besides the created tasks, a rollback removes.
+type RollbackOptions struct {
+	RemoveCustomFields bool
+	RemoveTags         bool
+	// RemoveContainers deletes the destination lists created for the migration. Only
+	// workspace migrations create lists; asking for it on other migrations is rejected.
+	RemoveContainers bool
+}
+
+func (o RollbackOptions) removes(t repository.CreatedResourceType) bool {
+	switch t {
+	case repository.CreatedResourceTypeCustomField:
+		return o.RemoveCustomFields
+	case repository.CreatedResourceTypeTag:
+		return o.RemoveTags
+	case repository.CreatedResourceTypeContainer:
+		return o.RemoveContainers
+	}
+	return false
+}
+
+// rollbackableStatuses are the statuses a rollback can start from.
+var rollbackableStatuses = []repository.MigrationStatus{
+	repository.MigrationStatusCompleted,
+	repository.MigrationStatusCompletedWithErrors,
+	repository.MigrationStatusFailed,
+	repository.MigrationStatusRolledBackWithErrors,
+}
+
+func isRollbackable(status repository.MigrationStatus) bool {
+	return slices.Contains(rollbackableStatuses, status)
+}
+
+// RollbackMigration deletes, in the background, every destination task the migration
+// created successfully and, depending on opts, the custom fields, tags and containers
+// it created. Rollbacks that ended with errors can be started again; already deleted
+// tasks are not touched twice.
+func (s *MigrationService) RollbackMigration(migrationID int64, opts RollbackOptions) error {
+	migration, err := s.migrationRepo.GetMigration(migrationID)
+	if err != nil {
+		return fmt.Errorf("get migration: %w", err)
+	}
+	if !isRollbackable(migration.Status) {
+		return fmt.Errorf("%w: cannot roll back a migration in status %q", ErrInvalidMigrationState, migration.Status)
+	}
+
+	destProvider, err := s.getProvider(destProviderOf(migration))
+	if err != nil {
+		return fmt.Errorf("get dest provider: %w", err)
+	}
+	deleter, ok := destProvider.(client.TaskDeleter)
+	if !ok {
+		return fmt.Errorf("%w: destination %s cannot delete tasks", ErrUnsupported, migration.Destination)
+	}
+
+	if opts.RemoveContainers {
+		if err := s.checkCreatedContainers(migrationID); err != nil {
+			return err
+		}
+	}
+
+	mappings, err := s.taskMappingRepo.GetByMigrationIDAndStatus(migrationID, repository.TaskMappingStatusSuccess)
+	if err != nil {
+		return fmt.Errorf("load migrated tasks: %w", err)
+	}
+
+	// The status is checked again as part of the update, so only one of two concurrent
+	// requests starts the rollback.
+	started, err := s.migrationRepo.StartRollback(migrationID, len(mappings), rollbackableStatuses)
+	if err != nil {
+		return fmt.Errorf("start rollback: %w", err)
+	}
+	if !started {
+		return fmt.Errorf("%w: migration %d is already being rolled back or changed status", ErrInvalidMigrationState, migrationID)
+	}
+
+	// Create an independent context — not tied to the HTTP request lifecycle.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+
+	go func() {
+		defer cancel()
+		s.executeRollback(ctx, destProvider, deleter, migration, mappings, opts)
+	}()
+
+	return nil
+}
+
+func (s *MigrationService) executeRollback(
+	ctx context.Context,
+	destProvider client.IntegrationProvider,
+	deleter client.TaskDeleter,
+	migration repository.Migration,
+	mappings []repository.TaskMapping,
+	opts RollbackOptions,
+) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("panic in executeRollback",
+				"migration_id", migration.ID,
+				"panic", r,
+				"stack", string(debug.Stack()),
+			)
+			s.completeRollback(migration.ID, repository.MigrationStatusRolledBackWithErrors)
+		}
+	}()
+
+	slog.Info("starting rollback", "migration_id", migration.ID, "destination", migration.Destination, "total_tasks", len(mappings))
+
+	successCount := 0
+	failCount := 0
+	for _, m := range mappings {
+		if err := deleter.DeleteTask(ctx, m.DestTaskID); err != nil {
+			slog.Error("failed to delete task", "migration_id", migration.ID, "dest_task_id", m.DestTaskID, "error", err)
+			if err := s.taskMappingRepo.UpdateStatus(m.ID, repository.TaskMappingStatusSuccess, "rollback: "+err.Error()); err != nil {
+				slog.Error("failed to record rollback failure", "migration_id", migration.ID, "dest_task_id", m.DestTaskID, "error", err)
+			}
+			failCount++
+		} else {
+			if err := s.taskMappingRepo.UpdateStatus(m.ID, repository.TaskMappingStatusRolledBack, ""); err != nil {
+				slog.Error("failed to mark task rolled back", "migration_id", migration.ID, "dest_task_id", m.DestTaskID, "error", err)
+			}
+			successCount++
+		}
+		if (successCount+failCount)%10 == 0 {
+			s.updateRollbackProgress(migration.ID, successCount, failCount)
+		}
+	}
+	s.updateRollbackProgress(migration.ID, successCount, failCount)
+
+	resourceFailures := s.deleteCreatedResources(ctx, destProvider, migration.ID, opts)
+
+	finalStatus := repository.MigrationStatusRolledBack
+	if failCount > 0 || resourceFailures > 0 {
+		finalStatus = repository.MigrationStatusRolledBackWithErrors
+	}
+	s.completeRollback(migration.ID, finalStatus)
+	slog.Info("rollback finished", "migration_id", migration.ID, "status", finalStatus, "deleted_tasks", successCount, "failed_tasks", failCount)
+}
+
+func (s *MigrationService) updateRollbackProgress(migrationID int64, completed, failed int) {
+	if err := s.migrationRepo.UpdateRollbackProgress(migrationID, completed, failed); err != nil {
+		slog.Error("failed to update rollback progress", "migration_id", migrationID, "error", err)
+	}
+}
+
+func (s *MigrationService) completeRollback(migrationID int64, status repository.MigrationStatus) {
+	if err := s.migrationRepo.CompleteRollback(migrationID, status); err != nil {
+		slog.Error("failed to complete rollback", "migration_id", migrationID, "status", status, "error", err)
+	}
+}
+
+// checkCreatedContainers rejects removing containers from a migration that created none,
+// which would otherwise silently remove nothing.
+func (s *MigrationService) checkCreatedContainers(migrationID int64) error {
+	resources, err := s.createdResourceRepo.GetPendingByMigrationID(migrationID)
+	if err != nil {
+		return fmt.Errorf("load created resources: %w", err)
+	}
+	for _, res := range resources {
+		if res.Type == repository.CreatedResourceTypeContainer {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: migration %d created no containers to remove", ErrInvalidInput, migrationID)
+}
+
+// deleteCreatedResources removes the selected kinds of resources recorded for the migration.
+// Containers are deleted last since they may only be removable once empty.
+// It returns the number of resources that could not be deleted.
+func (s *MigrationService) deleteCreatedResources(
+	ctx context.Context,
+	destProvider client.IntegrationProvider,
+	migrationID int64,
+	opts RollbackOptions,
+) int {
+	resources, err := s.createdResourceRepo.GetPendingByMigrationID(migrationID)
+	if err != nil {
+		slog.Error("failed to load created resources", "migration_id", migrationID, "error", err)
+		return 1
+	}
+
+	failures := 0
+	for _, resourceType := range []repository.CreatedResourceType{
+		repository.CreatedResourceTypeCustomField,
+		repository.CreatedResourceTypeTag,
+		repository.CreatedResourceTypeContainer,
+	} {
+		if !opts.removes(resourceType) {
+			continue
+		}
+		for _, res := range resources {
+			if res.Type != resourceType {
+				continue
+			}
+			if err := deleteResource(ctx, destProvider, res); err != nil {
+				slog.Error("failed to delete created resource", "migration_id", migrationID, "type", res.Type, "resource_id", res.ResourceID, "error", err)
+				failures++
+				continue
+			}
+			if err := s.createdResourceRepo.MarkDeleted(res.ID); err != nil {
+				slog.Warn("could not mark resource deleted", "migration_id", migrationID, "resource_id", res.ResourceID, "error", err)
+			}
+		}
+	}
+	return failures
+}
+
+func deleteResource(ctx context.Context, destProvider client.IntegrationProvider, res repository.CreatedResource) error {
+	switch res.Type {
+	case repository.CreatedResourceTypeCustomField:
+		if d, ok := destProvider.(client.FieldDeleter); ok {
+			return d.DeleteCustomField(ctx, res.ResourceID)
+		}
+	case repository.CreatedResourceTypeTag:
+		if d, ok := destProvider.(client.TagDeleter); ok {
+			return d.DeleteTag(ctx, res.ResourceID)
+		}
+	case repository.CreatedResourceTypeContainer:
+		if d, ok := destProvider.(client.ContainerDeleter); ok {
+			return d.DeleteContainer(ctx, res.ResourceID)
+		}
+	}
+	return fmt.Errorf("%w: destination cannot delete %s resources", ErrUnsupported, res.Type)
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/converter"
 	"github.com/TWRT/integration-mapper/internal/models"
 	"github.com/TWRT/integration-mapper/internal/repository"
 )
@@ -21,28 +22,44 @@ type migrationRepo interface {
 	Create(migration *repository.Migration) (int64, error)
 	UpdateProgress(id int64, completed, failed int) error
 	UpdateStatus(id int64, status repository.MigrationStatus) error
+	MarkQueued(id int64, from []repository.MigrationStatus) (bool, error)
 	Complete(id int64, status repository.MigrationStatus) error
 	UpdateTotalTasks(id int64, totalTasks int) error
+	UpdateExcludedTasks(id int64, excludedTasks int) error
+	UpdateFilterRules(id int64, rules []repository.FilterRule) error
 	GetMigration(id int64) (repository.Migration, error)
 	GetMigrations() ([]repository.Migration, error)
+	GetByBatchID(batchID int64) ([]repository.Migration, error)
+	GetByOwnerID(ownerID int64) ([]repository.Migration, error)
+	UpdateSchedule(id int64, scheduledAt *time.Time, schedule string) error
+	GetDueScheduled(now time.Time) ([]repository.Migration, error)
+	StartRollback(id int64, totalTasks int, from []repository.MigrationStatus) (bool, error)
+	UpdateRollbackProgress(id int64, completed, failed int) error
+	CompleteRollback(id int64, status repository.MigrationStatus) error
 }
 
 type taskMappingRepo interface {
 	Create(mapping *repository.TaskMapping) error
+	GetByMigrationIDAndStatus(migrationID int64, status repository.TaskMappingStatus) ([]repository.TaskMapping, error)
+	UpdateStatus(id int64, status repository.TaskMappingStatus, errorMessage string) error
 }
 
 type migrationMappingRepo interface {
 	UpsertPending(migrationID int64, mappingType repository.MappingType, sourceValue string, metadata *repository.AssigneeMetadata, sourceContainerID *string) error
+	UpsertMapped(migrationID int64, mappingType repository.MappingType, sourceValue string, destValue string) error
 	UpdateMapping(migrationID int64, mappingType repository.MappingType, sourceValue string, sourceContainerID *string, destValue string) error
+	SkipMapping(migrationID int64, mappingType repository.MappingType, sourceValue string) error
 	MarkContainerMappingsSkipped(migrationID int64, containerID string) error
 	ReactivateContainerMappings(migrationID int64, containerID string) error
 	GetByMigrationIDAndContainer(migrationID int64, containerID string) ([]repository.MigrationMapping, error)
 	GetGlobalByMigrationID(migrationID int64) ([]repository.MigrationMapping, error)
 	AllMapped(migrationID int64) (bool, error)
-	UpsertCustomField(migrationID int64, fieldID, fieldName, fieldType string, sourceContainerID *string) error
+	DeletePending(migrationID int64) error
+	UpsertCustomField(migrationID int64, fieldID, fieldName, fieldType string, options []repository.CustomFieldOptionMetadata, sourceContainerID *string) error
 	UpdateCustomFieldEnabled(migrationID int64, fieldID string, enabled bool, sourceContainerID *string) error
+	UpdateCustomFieldMapping(migrationID int64, fieldID string, sourceContainerID *string, destFieldID, conversion string, optionMap map[string]string) error
 	GetCustomFields(migrationID int64, containerID *string) ([]repository.CustomFieldRow, error)
-	GetEnabledCustomFieldIDs(migrationID int64) (map[string]bool, error)
+	GetEnabledCustomFields(migrationID int64) (map[string]repository.CustomFieldRow, error)
 }
 
 type containerMappingRepo interface {
@@ -52,20 +69,36 @@ type containerMappingRepo interface {
 	AllMapped(migrationID int64) (bool, error)
 }
 
+type createdResourceRepo interface {
+	Create(resource *repository.CreatedResource) error
+	GetPendingByMigrationID(migrationID int64) ([]repository.CreatedResource, error)
+	MarkDeleted(id int64) error
+}
+
 type MigrationService struct {
-	providers            map[string]client.IntegrationProvider
+	providers            *client.Registry
 	migrationRepo        migrationRepo
 	taskMappingRepo      taskMappingRepo
 	migrationMappingRepo migrationMappingRepo
 	containerMappingRepo containerMappingRepo
+	createdResourceRepo  createdResourceRepo
+	mappingTemplateRepo  mappingTemplateRepo
+	migrationBatchRepo   migrationBatchRepo
+	migrationJobRepo     migrationJobRepo
+	connections          connectionClients
 }
 
 func NewMigrationService(
-	providers map[string]client.IntegrationProvider,
+	providers *client.Registry,
 	migrationRepo migrationRepo,
 	taskMappingRepo taskMappingRepo,
 	migrationMappingRepo migrationMappingRepo,
 	containerMappingRepo containerMappingRepo,
+	createdResourceRepo createdResourceRepo,
+	mappingTemplateRepo mappingTemplateRepo,
+	migrationBatchRepo migrationBatchRepo,
+	migrationJobRepo migrationJobRepo,
+	connections connectionClients,
 ) *MigrationService {
 	return &MigrationService{
 		providers:            providers,
@@ -73,6 +106,11 @@ func NewMigrationService(
 		taskMappingRepo:      taskMappingRepo,
 		migrationMappingRepo: migrationMappingRepo,
 		containerMappingRepo: containerMappingRepo,
+		createdResourceRepo:  createdResourceRepo,
+		mappingTemplateRepo:  mappingTemplateRepo,
+		migrationBatchRepo:   migrationBatchRepo,
+		migrationJobRepo:     migrationJobRepo,
+		connections:          connections,
 	}
 }
 
@@ -81,11 +119,35 @@ func NewMigrationService(
 type MigrationServiceProvider interface {
 	CreateMigration(ctx context.Context, input CreateMigrationInput) (int64, *MappingsState, error)
 	SyncMappings(ctx context.Context, migrationID int64) (*MappingsState, error)
-	SaveMappings(ctx context.Context, migrationID int64, assignees []AssigneeMappingInput, containerMappings []ContainerMappingInput) (*MappingsState, error)
+	SaveMappings(ctx context.Context, migrationID int64, assignees []AssigneeMappingInput, tags []TagMappingInput, containerMappings []ContainerMappingInput) (*MappingsState, error)
+	UpdateFilters(ctx context.Context, migrationID int64, rules []repository.FilterRule) (*MappingsState, error)
 	GetDestContainerOptions(ctx context.Context, migrationID int64, destContainerID string) (statuses []string, priorities []string, err error)
 	StartMigration(migrationID int64) error
+	RollbackMigration(migrationID int64, opts RollbackOptions) error
 	GetMigration(id int64) (repository.Migration, error)
 	GetMigrations() ([]repository.Migration, error)
+	SaveMappingTemplate(migrationID int64, name string, ownerID *int64) (repository.MappingTemplate, error)
+	GetMappingTemplate(id int64) (repository.MappingTemplate, error)
+	GetMappingTemplates() ([]repository.MappingTemplate, error)
+	GetMappingTemplatesFor(user repository.User) ([]repository.MappingTemplate, error)
+	CanAccessMappingTemplate(user repository.User, templateID int64) (bool, error)
+	DeleteMappingTemplate(id int64) error
+	ApplyMappingTemplate(ctx context.Context, migrationID, templateID int64) (*MappingsState, []UnmatchedMapping, error)
+	ExportMappings(migrationID int64, format string) ([]byte, error)
+	ImportMappings(ctx context.Context, migrationID int64, data []byte) (*MappingsState, []MappingChange, []UnmatchedMapping, error)
+	CreateBatch(ctx context.Context, input CreateBatchInput) (*BatchState, error)
+	GetBatch(id int64) (*BatchState, error)
+	GetBatches() ([]BatchState, error)
+	GetBatchAssignees(ctx context.Context, id int64) (*BatchAssigneesState, error)
+	SaveBatchAssignees(ctx context.Context, id int64, assignees []AssigneeMappingInput) (*BatchAssigneesState, error)
+	StartBatch(id int64) error
+	ScheduleMigration(migrationID int64, input ScheduleInput) (repository.Migration, error)
+	UnscheduleMigration(migrationID int64) error
+	GetMigrationJob(migrationID int64) (*MigrationJobState, error)
+	GetMigrationsFor(user repository.User) ([]repository.Migration, error)
+	CanAccessMigration(user repository.User, migrationID int64) (bool, error)
+	GetBatchesFor(user repository.User) ([]BatchState, error)
+	CanAccessBatch(user repository.User, batchID int64) (bool, error)
 }
 
 // ---- Types ----
@@ -107,21 +169,47 @@ type AssigneeMappingItem struct {
 	Email string
 }
 
+// AssigneeMappingInput maps a source user onto a destination member. Skip drops the user,
+// for example a follower or mentioned user without a destination account, so it no longer
+// blocks the migration from starting.
 type AssigneeMappingInput struct {
 	SourceValue string
 	DestValue   string
+	Skip        bool
+}
+
+// TagMappingInput maps a source tag onto a destination tag name. Several source tags mapped to
+// the same destination name are merged; Drop removes the tag from migrated tasks.
+type TagMappingInput struct {
+	SourceValue string
+	DestValue   string
+	Drop        bool
 }
 
 type CustomFieldState struct {
-	ID      string
-	Name    string
-	Type    string
-	Enabled bool
+	ID             string
+	Name           string
+	Type           string
+	Enabled        bool
+	Options        []models.CustomFieldOption
+	DestFieldID    *string
+	Conversion     string
+	OptionMappings map[string]string
 }
 
 type CustomFieldSelection struct {
 	FieldID string
 	Enabled bool
+	Mapping *CustomFieldMappingInput // nil leaves the saved mapping untouched
+}
+
+// CustomFieldMappingInput configures how a source custom field is carried over.
+// An empty DestFieldID keeps the default behaviour of finding or creating a destination
+// field with the same name; OptionMappings maps source option IDs to destination option IDs.
+type CustomFieldMappingInput struct {
+	DestFieldID    string
+	Conversion     string
+	OptionMappings map[string]string
 }
 
 type AvailableContainer struct {
@@ -159,23 +247,107 @@ type ContainerMappingInput struct {
 type MappingsState struct {
 	Assignees               []AssigneeMappingItem
 	AvailableDestMembers    []models.Member
+	Tags                    []MappingItem
+	AvailableDestTags       []string
+	AvailableDestFields     []models.CustomFieldDefinition
 	ContainerMappings       []ContainerMappingDetail
 	AvailableDestContainers []AvailableContainer
 }
 
 // ---- Provider helpers ----
 
-func (s *MigrationService) getProvider(name string) (client.IntegrationProvider, error) {
-	p, ok := s.providers[name]
+// providerRef is a provider of a migration and the connection the migration reaches it
+// through.
+type providerRef struct {
+	Name         string
+	ConnectionID *int64 // nil uses the provider's registered client
+}
+
+func sourceProviderOf(migration repository.Migration) providerRef {
+	return providerRef{Name: migration.Source, ConnectionID: migration.SourceConnectionID}
+}
+
+func destProviderOf(migration repository.Migration) providerRef {
+	return providerRef{Name: migration.Destination, ConnectionID: migration.DestConnectionID}
+}
+
+// getProvider returns the client a provider is reached with: one acting as the account
+// behind the connection, or else the provider's registered client.
+func (s *MigrationService) getProvider(ref providerRef) (client.IntegrationProvider, error) {
+	d, err := s.getDescriptor(ref.Name)
+	if err != nil {
+		return nil, err
+	}
+	if ref.ConnectionID != nil {
+		return s.connections.ConnectionClient(*ref.ConnectionID, ref.Name)
+	}
+	if d.ConnectionRequired {
+		return nil, fmt.Errorf("%s can only be reached through a connection", d.DisplayName)
+	}
+	return d.Client, nil
+}
+
+func (s *MigrationService) getDescriptor(name string) (client.Descriptor, error) {
+	d, ok := s.providers.Get(name)
 	if !ok {
-		return nil, fmt.Errorf("unknown provider: %s", name)
+		return client.Descriptor{}, fmt.Errorf("unknown provider: %s", name)
 	}
-	return p, nil
+	return d, nil
 }
 
-func (s *MigrationService) getMembersForDestination(ctx context.Context, destination, destWorkspaceId string) ([]models.Member, error) {
+// destinationOf returns the destination settings of a migration in the form provider
+// descriptors work with.
+func destinationOf(migration repository.Migration) client.Destination {
+	return client.Destination{
+		WorkspaceID: migration.DestWorkspaceID,
+		ListID:      migration.DestListID,
+		SpaceID:     migration.DestSpaceID,
+	}
+}
+
+// validateProviders checks that source and destination are registered providers, that
+// the destination settings satisfy the destination provider's rules and that the chosen
+// connections belong to the migration's owner.
+func (s *MigrationService) validateProviders(input CreateMigrationInput) error {
+	source, ok := s.providers.Get(input.Source)
+	if !ok {
+		return fmt.Errorf("%w: unknown source %q", ErrInvalidInput, input.Source)
+	}
+	dest, ok := s.providers.Get(input.Destination)
+	if !ok {
+		return fmt.Errorf("%w: unknown destination %q", ErrInvalidInput, input.Destination)
+	}
+	if input.Source == input.Destination {
+		return fmt.Errorf("%w: source and destination must be different", ErrInvalidInput)
+	}
+	err := dest.ValidateDestination(client.Destination{
+		WorkspaceID: input.DestWorkspaceID,
+		ListID:      input.DestListID,
+		SpaceID:     input.DestSpaceID,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidInput, err)
+	}
+	if input.SourceConnectionID != nil {
+		if err := s.connections.CheckConnection(*input.SourceConnectionID, input.OwnerID, input.Source); err != nil {
+			return err
+		}
+	} else if _, err := serverClient(source, input.ServerCredentials); err != nil {
+		return fmt.Errorf("%w, set source_connection_id", err)
+	}
+	if input.DestConnectionID != nil {
+		if err := s.connections.CheckConnection(*input.DestConnectionID, input.OwnerID, input.Destination); err != nil {
+			return err
+		}
+	} else if _, err := serverClient(dest, input.ServerCredentials); err != nil {
+		return fmt.Errorf("%w, set dest_connection_id", err)
+	}
+	return nil
+}
+
+func (s *MigrationService) getMembersForDestination(ctx context.Context, destination providerRef, destWorkspaceId string) ([]models.Member, error) {
 	if destWorkspaceId == "" {
-		return nil, fmt.Errorf("dest_workspace_id is required for destination %s", destination)
+		return nil, fmt.Errorf("dest_workspace_id is required for destination %s", destination.Name)
 	}
 	p, err := s.getProvider(destination)
 	if err != nil {
@@ -184,7 +356,7 @@ func (s *MigrationService) getMembersForDestination(ctx context.Context, destina
 	return p.GetMembers(ctx, destWorkspaceId)
 }
 
-func (s *MigrationService) getAvailableDestStatuses(ctx context.Context, destination, destListId string) ([]string, error) {
+func (s *MigrationService) getAvailableDestStatuses(ctx context.Context, destination providerRef, destListId string) ([]string, error) {
 	p, err := s.getProvider(destination)
 	if err != nil {
 		return nil, err
@@ -192,18 +364,22 @@ func (s *MigrationService) getAvailableDestStatuses(ctx context.Context, destina
 	return p.GetListStatuses(ctx, destListId)
 }
 
-func (s *MigrationService) getAvailableDestPrioritiesForState(ctx context.Context, destination, destListID string) []string {
-	provider, err := s.getProvider(destination)
+func (s *MigrationService) getAvailableDestPrioritiesForState(ctx context.Context, destination providerRef, destListID string) []string {
+	descriptor, err := s.getDescriptor(destination.Name)
 	if err != nil {
-		return getAvailableDestPriorities(destination)
+		return nil
+	}
+	destProvider, err := s.getProvider(destination)
+	if err != nil {
+		return descriptor.DefaultPriorities
 	}
-	lookup, ok := provider.(client.PriorityLookup)
+	lookup, ok := destProvider.(client.PriorityLookup)
 	if !ok {
-		return getAvailableDestPriorities(destination)
+		return descriptor.DefaultPriorities
 	}
 	options, err := lookup.GetProjectCustomFieldOptions(ctx, destListID)
 	if err != nil || len(options) == 0 {
-		return getAvailableDestPriorities(destination)
+		return descriptor.DefaultPriorities
 	}
 	names := make([]string, 0, len(options))
 	for k := range options {
@@ -215,15 +391,54 @@ func (s *MigrationService) getAvailableDestPrioritiesForState(ctx context.Contex
 	return names
 }
 
-func getAvailableDestPriorities(destination string) []string {
-	if destination == "clickup" {
-		return []string{"urgent", "high", "normal", "low"}
+// getDestTagScope returns the ID destination tags belong to, as declared by the destination provider.
+func (s *MigrationService) getDestTagScope(migration repository.Migration) string {
+	descriptor, err := s.getDescriptor(migration.Destination)
+	if err != nil {
+		return migration.DestWorkspaceID
 	}
-	return []string{"High", "Medium", "Low"}
+	return descriptor.DestTagScope(destinationOf(migration))
+}
+
+func (s *MigrationService) getAvailableDestTags(ctx context.Context, migration repository.Migration) []string {
+	destProvider, err := s.getProvider(destProviderOf(migration))
+	if err != nil {
+		return nil
+	}
+	tl, ok := destProvider.(client.TagLister)
+	if !ok {
+		return nil
+	}
+	tags, err := tl.ListTags(ctx, s.getDestTagScope(migration))
+	if err != nil {
+		slog.Warn("could not fetch dest tags", "error", err)
+		return nil
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// getAvailableDestFields lists the custom fields of the destination project that source
+// fields can be mapped onto.
+func (s *MigrationService) getAvailableDestFields(ctx context.Context, migration repository.Migration) []models.CustomFieldDefinition {
+	destProvider, err := s.getProvider(destProviderOf(migration))
+	if err != nil {
+		return nil
+	}
+	dfp, ok := destProvider.(client.DestFieldProvider)
+	if !ok {
+		return nil
+	}
+	defs, err := dfp.GetDestFieldDefinitions(ctx, s.getDestContainerID(migration))
+	if err != nil {
+		slog.Warn("could not fetch dest custom fields", "error", err)
+		return nil
+	}
+	return defs
 }
 
 func (s *MigrationService) getAvailableDestContainers(ctx context.Context, migration repository.Migration) []AvailableContainer {
-	destProvider, err := s.getProvider(migration.Destination)
+	destProvider, err := s.getProvider(destProviderOf(migration))
 	if err != nil {
 		return nil
 	}
@@ -245,10 +460,11 @@ func (s *MigrationService) getAvailableDestContainers(ctx context.Context, migra
 }
 
 func (s *MigrationService) getDestContainerID(migration repository.Migration) string {
-	if migration.DestSpaceID != "" {
-		return migration.DestSpaceID
+	descriptor, err := s.getDescriptor(migration.Destination)
+	if err != nil {
+		return migration.DestListID
 	}
-	return migration.DestListID
+	return descriptor.DestContainerScope(destinationOf(migration))
 }
 
 // ---- Custom field discovery ----
@@ -292,6 +508,14 @@ func (s *MigrationService) discoverCustomFieldsPerContainer(
 	return defsToContainerFields(sourceProjectID, defs)
 }
 
+func toOptionMetadata(options []models.CustomFieldOption) []repository.CustomFieldOptionMetadata {
+	result := make([]repository.CustomFieldOptionMetadata, len(options))
+	for i, o := range options {
+		result[i] = repository.CustomFieldOptionMetadata{ID: o.ID, Name: o.Name, OrderIndex: o.OrderIndex}
+	}
+	return result
+}
+
 func defsToContainerFields(containerID string, defs []models.CustomFieldDefinition) []containerCustomField {
 	result := make([]containerCustomField, len(defs))
 	for i, d := range defs {
@@ -302,18 +526,42 @@ func defsToContainerFields(containerID string, defs []models.CustomFieldDefiniti
 
 // ---- Mapping discovery ----
 
+// collectTaskUsers adds the users a task references (assignees, followers and users mentioned
+// in its description) to users, keyed by source user ID.
+func collectTaskUsers(task models.Task, users map[string]models.TaskAssignee) {
+	for _, a := range task.Assignees {
+		if a.ID != "" {
+			users[a.ID] = a
+		}
+	}
+	for _, f := range task.Followers {
+		if _, known := users[f.ID]; !known && f.ID != "" {
+			users[f.ID] = f
+		}
+	}
+	for _, m := range converter.Mentions(task.RichDescription) {
+		if _, known := users[m.ID]; !known {
+			users[m.ID] = models.TaskAssignee{ID: m.ID, Name: m.Name}
+		}
+	}
+}
+
 // discoverAndUpsertMappingsFromContainers fetches tasks per container and stores
 // status/priority mappings with their source_container_id. Assignees are global (NULL container).
+// Tasks excluded by the migration's filter rules do not contribute mappings.
 func (s *MigrationService) discoverAndUpsertMappingsFromContainers(
 	ctx context.Context,
 	migrationID int64,
 	sourceProvider client.IntegrationProvider,
 	sourceID string,
+	rules []repository.FilterRule,
+	containerStrategy string,
 ) ([]models.Task, error) {
 	cp, hasContainers := sourceProvider.(client.ContainerProvider)
 
 	var allTasks []models.Task
 	globalAssignees := make(map[string]models.TaskAssignee)
+	globalTags := make(map[string]struct{})
 
 	if hasContainers {
 		containers, err := cp.GetSourceContainers(ctx, sourceID)
@@ -326,6 +574,8 @@ func (s *MigrationService) discoverAndUpsertMappingsFromContainers(
 				slog.Warn("could not get tasks for container, skipping", "container", c.Name, "error", err)
 				continue
 			}
+			tasks, _ = splitByFilters(tasks, rules)
+			applyContainerStrategy(tasks, containerStrategy, c.Name)
 
 			uniqueStatuses := make(map[string]struct{})
 			uniquePriorities := make(map[string]struct{})
@@ -336,10 +586,9 @@ func (s *MigrationService) discoverAndUpsertMappingsFromContainers(
 				if task.Priority != "" {
 					uniquePriorities[task.Priority] = struct{}{}
 				}
-				for _, a := range task.Assignees {
-					if a.ID != "" {
-						globalAssignees[a.ID] = a
-					}
+				collectTaskUsers(task, globalAssignees)
+				for _, tag := range task.Tags {
+					globalTags[tag] = struct{}{}
 				}
 			}
 
@@ -362,6 +611,7 @@ func (s *MigrationService) discoverAndUpsertMappingsFromContainers(
 		if err != nil {
 			return nil, fmt.Errorf("get tasks from source: %w", err)
 		}
+		tasks, _ = splitByFilters(tasks, rules)
 		uniqueStatuses := make(map[string]struct{})
 		uniquePriorities := make(map[string]struct{})
 		for _, task := range tasks {
@@ -371,10 +621,9 @@ func (s *MigrationService) discoverAndUpsertMappingsFromContainers(
 			if task.Priority != "" {
 				uniquePriorities[task.Priority] = struct{}{}
 			}
-			for _, a := range task.Assignees {
-				if a.ID != "" {
-					globalAssignees[a.ID] = a
-				}
+			collectTaskUsers(task, globalAssignees)
+			for _, tag := range task.Tags {
+				globalTags[tag] = struct{}{}
 			}
 		}
 		for status := range uniqueStatuses {
@@ -398,6 +647,13 @@ func (s *MigrationService) discoverAndUpsertMappingsFromContainers(
 		}
 	}
 
+	// Tags are global too and keep their name unless remapped, renamed or dropped.
+	for tag := range globalTags {
+		if err := s.migrationMappingRepo.UpsertMapped(migrationID, repository.MappingTypeTag, tag, tag); err != nil {
+			return nil, fmt.Errorf("upsert tag mapping: %w", err)
+		}
+	}
+
 	return allTasks, nil
 }
 
@@ -410,11 +666,19 @@ func (s *MigrationService) loadCustomFieldsStateForContainer(migrationID int64,
 	}
 	result := make([]CustomFieldState, 0, len(dbRows))
 	for _, r := range dbRows {
+		options := make([]models.CustomFieldOption, 0, len(r.Options))
+		for _, o := range r.Options {
+			options = append(options, models.CustomFieldOption{ID: o.ID, Name: o.Name, OrderIndex: o.OrderIndex})
+		}
 		result = append(result, CustomFieldState{
-			ID:      r.FieldID,
-			Name:    r.FieldName,
-			Type:    r.FieldType,
-			Enabled: r.Enabled,
+			ID:             r.FieldID,
+			Name:           r.FieldName,
+			Type:           r.FieldType,
+			Enabled:        r.Enabled,
+			Options:        options,
+			DestFieldID:    r.DestFieldID,
+			Conversion:     r.Conversion,
+			OptionMappings: r.OptionMap,
 		})
 	}
 	return result
@@ -427,13 +691,22 @@ func (s *MigrationService) buildMappingsState(ctx context.Context, migration rep
 		return nil, fmt.Errorf("get global mappings: %w", err)
 	}
 
-	destMembers, err := s.getMembersForDestination(ctx, migration.Destination, migration.DestWorkspaceID)
+	destMembers, err := s.getMembersForDestination(ctx, destProviderOf(migration), migration.DestWorkspaceID)
 	if err != nil {
 		return nil, fmt.Errorf("get destination members: %w", err)
 	}
 
 	var assignees []AssigneeMappingItem
+	var tags []MappingItem
 	for _, m := range globalMappings {
+		if m.Type == repository.MappingTypeTag {
+			tags = append(tags, MappingItem{
+				SourceValue: m.SourceValue,
+				DestValue:   m.DestValue,
+				Status:      string(m.Status),
+			})
+			continue
+		}
 		if m.Type != repository.MappingTypeAssignee {
 			continue
 		}
@@ -467,7 +740,7 @@ func (s *MigrationService) buildMappingsState(ctx context.Context, migration rep
 			DestID:     cm.DestID,
 			DestName:   cm.DestName,
 			Enabled:    cm.Enabled,
-			Status:     cm.Status,
+			Status:     string(cm.Status),
 		}
 
 		// Load per-container status/priority
@@ -494,12 +767,12 @@ func (s *MigrationService) buildMappingsState(ctx context.Context, migration rep
 
 		// Fetch available dest options if destination is set
 		if cm.DestID != nil {
-			if ss, err := s.getAvailableDestStatuses(ctx, migration.Destination, *cm.DestID); err == nil {
+			if ss, err := s.getAvailableDestStatuses(ctx, destProviderOf(migration), *cm.DestID); err == nil {
 				detail.AvailableDestStatuses = ss
 			} else {
 				slog.Warn("could not fetch dest statuses for container", "destID", *cm.DestID, "error", err)
 			}
-			detail.AvailableDestPriorities = s.getAvailableDestPrioritiesForState(ctx, migration.Destination, *cm.DestID)
+			detail.AvailableDestPriorities = s.getAvailableDestPrioritiesForState(ctx, destProviderOf(migration), *cm.DestID)
 		}
 
 		containerDetails = append(containerDetails, detail)
@@ -508,6 +781,9 @@ func (s *MigrationService) buildMappingsState(ctx context.Context, migration rep
 	return &MappingsState{
 		Assignees:               assignees,
 		AvailableDestMembers:    destMembers,
+		Tags:                    tags,
+		AvailableDestTags:       s.getAvailableDestTags(ctx, migration),
+		AvailableDestFields:     s.getAvailableDestFields(ctx, migration),
 		ContainerMappings:       containerDetails,
 		AvailableDestContainers: availableContainers,
 	}, nil
@@ -522,9 +798,59 @@ type CreateMigrationInput struct {
 	DestListID      string
 	DestWorkspaceID string
 	DestSpaceID     string
+	Filters         []repository.FilterRule
+	TimeZone        string // IANA zone for all-day dates, e.g. "America/Sao_Paulo"; empty means UTC
+	BacklinkMode    string
+	BacklinkFieldID string
+	ReverseBacklink bool
+	// ContainerStrategy is "list" (default) or "status"; see ContainerStrategyStatus.
+	ContainerStrategy string
+	OwnerID           *int64 // user creating the migration; nil for admin-only migrations
+	// SourceConnectionID and DestConnectionID are connections of the owner to reach the
+	// providers through; nil uses the server's credentials, which needs ServerCredentials.
+	SourceConnectionID *int64
+	DestConnectionID   *int64
+	ServerCredentials  bool // the creator may use the server's credentials; admins only
+}
+
+// migrationLocation returns the time zone all-day dates of the migration are interpreted in.
+func migrationLocation(migration repository.Migration) *time.Location {
+	if migration.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(migration.TimeZone)
+	if err != nil {
+		slog.Warn("invalid migration time zone, using UTC", "migration_id", migration.ID, "time_zone", migration.TimeZone, "error", err)
+		return time.UTC
+	}
+	return loc
 }
 
 func (s *MigrationService) CreateMigration(ctx context.Context, input CreateMigrationInput) (int64, *MappingsState, error) {
+	return s.createMigration(ctx, input, nil)
+}
+
+// createMigration creates a migration, optionally as part of a batch, and discovers the
+// source values to map.
+func (s *MigrationService) createMigration(ctx context.Context, input CreateMigrationInput, batchID *int64) (int64, *MappingsState, error) {
+	if err := s.validateProviders(input); err != nil {
+		return 0, nil, err
+	}
+	if err := validateFilterRules(input.Filters); err != nil {
+		return 0, nil, err
+	}
+	if input.TimeZone != "" {
+		if _, err := time.LoadLocation(input.TimeZone); err != nil {
+			return 0, nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidInput, input.TimeZone)
+		}
+	}
+	if err := s.validateBacklink(input); err != nil {
+		return 0, nil, err
+	}
+	if err := validateContainerStrategy(input); err != nil {
+		return 0, nil, err
+	}
+
 	migration := &repository.Migration{
 		Source:          input.Source,
 		Destination:     input.Destination,
@@ -533,14 +859,26 @@ func (s *MigrationService) CreateMigration(ctx context.Context, input CreateMigr
 		DestWorkspaceID: input.DestWorkspaceID,
 		DestSpaceID:     input.DestSpaceID,
 		Status:          repository.MigrationStatusPendingConfiguration,
+		FilterRules:     input.Filters,
+		TimeZone:        input.TimeZone,
+		BacklinkMode:    input.BacklinkMode,
+		BacklinkFieldID: input.BacklinkFieldID,
+		ReverseBacklink: input.ReverseBacklink,
+		BatchID:         batchID,
+
+		ContainerStrategy:  input.ContainerStrategy,
+		OwnerID:            input.OwnerID,
+		SourceConnectionID: input.SourceConnectionID,
+		DestConnectionID:   input.DestConnectionID,
 	}
+	ctx = client.WithLocation(ctx, migrationLocation(*migration))
 
 	migrationID, err := s.migrationRepo.Create(migration)
 	if err != nil {
 		return 0, nil, fmt.Errorf("create migration: %w", err)
 	}
 
-	sourceProvider, err := s.getProvider(input.Source)
+	sourceProvider, err := s.getProvider(providerRef{Name: input.Source, ConnectionID: input.SourceConnectionID})
 	if err != nil {
 		return 0, nil, fmt.Errorf("get source provider: %w", err)
 	}
@@ -558,18 +896,21 @@ func (s *MigrationService) CreateMigration(ctx context.Context, input CreateMigr
 		}
 	}
 
-	if _, err := s.discoverAndUpsertMappingsFromContainers(ctx, migrationID, sourceProvider, input.SourceProjectID); err != nil {
+	if _, err := s.discoverAndUpsertMappingsFromContainers(ctx, migrationID, sourceProvider, input.SourceProjectID, input.Filters, input.ContainerStrategy); err != nil {
 		return 0, nil, fmt.Errorf("discover mappings: %w", err)
 	}
 
 	for _, cf := range s.discoverCustomFieldsPerContainer(ctx, sourceProvider, input.SourceProjectID) {
 		containerID := cf.ContainerID
-		if err := s.migrationMappingRepo.UpsertCustomField(migrationID, cf.Def.ID, cf.Def.Name, cf.Def.ClickUpType, &containerID); err != nil {
+		if err := s.migrationMappingRepo.UpsertCustomField(migrationID, cf.Def.ID, cf.Def.Name, cf.Def.ClickUpType, toOptionMetadata(cf.Def.Options), &containerID); err != nil {
 			slog.Warn("could not persist custom field", "field", cf.Def.Name, "error", err)
 		}
 	}
 
 	migration.ID = migrationID
+	if err := s.routeContainersToStatuses(ctx, *migration); err != nil {
+		return 0, nil, err
+	}
 	state, err := s.buildMappingsState(ctx, *migration)
 	if err != nil {
 		return 0, nil, fmt.Errorf("build mappings state: %w", err)
@@ -583,8 +924,9 @@ func (s *MigrationService) SyncMappings(ctx context.Context, migrationID int64)
 	if err != nil {
 		return nil, fmt.Errorf("get migration: %w", err)
 	}
+	ctx = client.WithLocation(ctx, migrationLocation(migration))
 
-	sourceProvider, err := s.getProvider(migration.Source)
+	sourceProvider, err := s.getProvider(sourceProviderOf(migration))
 	if err != nil {
 		return nil, fmt.Errorf("get source provider: %w", err)
 	}
@@ -601,24 +943,84 @@ func (s *MigrationService) SyncMappings(ctx context.Context, migrationID int64)
 		}
 	}
 
-	if _, err := s.discoverAndUpsertMappingsFromContainers(ctx, migrationID, sourceProvider, migration.SourceProjectID); err != nil {
+	if _, err := s.discoverAndUpsertMappingsFromContainers(ctx, migrationID, sourceProvider, migration.SourceProjectID, migration.FilterRules, migration.ContainerStrategy); err != nil {
 		return nil, fmt.Errorf("sync mappings: %w", err)
 	}
 
 	for _, cf := range s.discoverCustomFieldsPerContainer(ctx, sourceProvider, migration.SourceProjectID) {
 		containerID := cf.ContainerID
-		if err := s.migrationMappingRepo.UpsertCustomField(migrationID, cf.Def.ID, cf.Def.Name, cf.Def.ClickUpType, &containerID); err != nil {
+		if err := s.migrationMappingRepo.UpsertCustomField(migrationID, cf.Def.ID, cf.Def.Name, cf.Def.ClickUpType, toOptionMetadata(cf.Def.Options), &containerID); err != nil {
 			slog.Warn("could not persist custom field on sync", "field", cf.Def.Name, "error", err)
 		}
 	}
+	if err := s.routeContainersToStatuses(ctx, migration); err != nil {
+		return nil, err
+	}
 
 	return s.buildMappingsState(ctx, migration)
 }
 
+// UpdateFilters replaces the migration's filter rules and re-runs mapping discovery so that
+// only values from tasks that will actually be migrated remain to be mapped.
+func (s *MigrationService) UpdateFilters(ctx context.Context, migrationID int64, rules []repository.FilterRule) (*MappingsState, error) {
+	if err := validateFilterRules(rules); err != nil {
+		return nil, err
+	}
+
+	migration, err := s.migrationRepo.GetMigration(migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get migration: %w", err)
+	}
+	if migration.Status != repository.MigrationStatusPendingConfiguration && migration.Status != repository.MigrationStatusReadyToStart {
+		return nil, fmt.Errorf("%w: filters cannot be changed in status %q", ErrInvalidMigrationState, migration.Status)
+	}
+
+	if err := s.migrationRepo.UpdateFilterRules(migrationID, rules); err != nil {
+		return nil, fmt.Errorf("update filter rules: %w", err)
+	}
+	if err := s.migrationMappingRepo.DeletePending(migrationID); err != nil {
+		return nil, fmt.Errorf("clear pending mappings: %w", err)
+	}
+
+	state, err := s.SyncMappings(ctx, migrationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Newly discovered values may need mapping, or stale pending ones may be gone.
+	if err := s.refreshReadiness(migrationID); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// refreshReadiness moves the migration between pending_configuration and ready_to_start
+// depending on whether every field and container mapping is complete.
+func (s *MigrationService) refreshReadiness(migrationID int64) error {
+	fieldsMapped, err := s.migrationMappingRepo.AllMapped(migrationID)
+	if err != nil {
+		return fmt.Errorf("check all field mappings done: %w", err)
+	}
+	containersMapped, err := s.containerMappingRepo.AllMapped(migrationID)
+	if err != nil {
+		return fmt.Errorf("check all container mappings done: %w", err)
+	}
+
+	status := repository.MigrationStatusPendingConfiguration
+	if fieldsMapped && containersMapped {
+		status = repository.MigrationStatusReadyToStart
+	}
+	if err := s.migrationRepo.UpdateStatus(migrationID, status); err != nil {
+		return fmt.Errorf("update migration status: %w", err)
+	}
+	return nil
+}
+
 func (s *MigrationService) SaveMappings(
 	ctx context.Context,
 	migrationID int64,
 	assignees []AssigneeMappingInput,
+	tags []TagMappingInput,
 	containerMappings []ContainerMappingInput,
 ) (*MappingsState, error) {
 	migration, err := s.migrationRepo.GetMigration(migrationID)
@@ -626,13 +1028,33 @@ func (s *MigrationService) SaveMappings(
 		return nil, fmt.Errorf("get migration: %w", err)
 	}
 
+	if err := s.validateCustomFieldMappings(ctx, migration, containerMappings); err != nil {
+		return nil, err
+	}
+	if err := checkStatusStrategyContainers(migration, containerMappings); err != nil {
+		return nil, err
+	}
+
 	// Save global assignee mappings
 	for _, a := range assignees {
-		if a.DestValue == "" {
+		if err := s.saveAssigneeMapping(migrationID, a); err != nil {
+			slog.Warn("could not save assignee mapping", "source", a.SourceValue, "error", err)
+		}
+	}
+
+	// Save global tag mappings
+	for _, t := range tags {
+		var err error
+		switch {
+		case t.Drop:
+			err = s.migrationMappingRepo.SkipMapping(migrationID, repository.MappingTypeTag, t.SourceValue)
+		case strings.TrimSpace(t.DestValue) != "":
+			err = s.migrationMappingRepo.UpdateMapping(migrationID, repository.MappingTypeTag, t.SourceValue, nil, strings.TrimSpace(t.DestValue))
+		default:
 			continue
 		}
-		if err := s.migrationMappingRepo.UpdateMapping(migrationID, repository.MappingTypeAssignee, a.SourceValue, nil, a.DestValue); err != nil {
-			slog.Warn("could not save assignee mapping", "source", a.SourceValue, "error", err)
+		if err != nil {
+			slog.Warn("could not save tag mapping", "source", t.SourceValue, "error", err)
 		}
 	}
 
@@ -685,31 +1107,34 @@ func (s *MigrationService) SaveMappings(
 			if err := s.migrationMappingRepo.UpdateCustomFieldEnabled(migrationID, cf.FieldID, cf.Enabled, &cm.SourceID); err != nil {
 				slog.Warn("could not save custom field selection", "container", cm.SourceID, "field", cf.FieldID, "error", err)
 			}
+			if cf.Mapping == nil {
+				continue
+			}
+			if err := s.migrationMappingRepo.UpdateCustomFieldMapping(migrationID, cf.FieldID, &cm.SourceID, cf.Mapping.DestFieldID, cf.Mapping.Conversion, cf.Mapping.OptionMappings); err != nil {
+				slog.Warn("could not save custom field mapping", "container", cm.SourceID, "field", cf.FieldID, "error", err)
+			}
 		}
 	}
 
-	fieldsMapped, err := s.migrationMappingRepo.AllMapped(migrationID)
-	if err != nil {
-		return nil, fmt.Errorf("check all field mappings done: %w", err)
-	}
-	containersMapped, err := s.containerMappingRepo.AllMapped(migrationID)
-	if err != nil {
-		return nil, fmt.Errorf("check all container mappings done: %w", err)
-	}
-
-	if fieldsMapped && containersMapped {
-		if err := s.migrationRepo.UpdateStatus(migrationID, repository.MigrationStatusReadyToStart); err != nil {
-			return nil, fmt.Errorf("update migration status: %w", err)
-		}
-	} else {
-		if err := s.migrationRepo.UpdateStatus(migrationID, repository.MigrationStatusPendingConfiguration); err != nil {
-			return nil, fmt.Errorf("update migration status: %w", err)
-		}
+	if err := s.refreshReadiness(migrationID); err != nil {
+		return nil, err
 	}
 
 	return s.buildMappingsState(ctx, migration)
 }
 
+// saveAssigneeMapping maps or skips a global assignee mapping. An input without a
+// destination that is not skipped leaves the mapping as it is.
+func (s *MigrationService) saveAssigneeMapping(migrationID int64, a AssigneeMappingInput) error {
+	switch {
+	case a.Skip:
+		return s.migrationMappingRepo.SkipMapping(migrationID, repository.MappingTypeAssignee, a.SourceValue)
+	case a.DestValue != "":
+		return s.migrationMappingRepo.UpdateMapping(migrationID, repository.MappingTypeAssignee, a.SourceValue, nil, a.DestValue)
+	}
+	return nil
+}
+
 // GetDestContainerOptions returns the available statuses and priorities for a specific destination container.
 func (s *MigrationService) GetDestContainerOptions(ctx context.Context, migrationID int64, destContainerID string) (statuses []string, priorities []string, err error) {
 	migration, err := s.migrationRepo.GetMigration(migrationID)
@@ -717,58 +1142,53 @@ func (s *MigrationService) GetDestContainerOptions(ctx context.Context, migratio
 		return nil, nil, fmt.Errorf("get migration: %w", err)
 	}
 
-	statuses, err = s.getAvailableDestStatuses(ctx, migration.Destination, destContainerID)
+	statuses, err = s.getAvailableDestStatuses(ctx, destProviderOf(migration), destContainerID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("get dest statuses: %w", err)
 	}
 
-	priorities = s.getAvailableDestPrioritiesForState(ctx, migration.Destination, destContainerID)
+	priorities = s.getAvailableDestPrioritiesForState(ctx, destProviderOf(migration), destContainerID)
 	return statuses, priorities, nil
 }
 
-func (s *MigrationService) StartMigration(migrationID int64) error {
+// prepareStart checks that a migration is fully mapped and resolves its providers.
+func (s *MigrationService) prepareStart(migrationID int64) (repository.Migration, client.IntegrationProvider, client.IntegrationProvider, error) {
 	allMapped, err := s.migrationMappingRepo.AllMapped(migrationID)
 	if err != nil {
-		return fmt.Errorf("check mappings: %w", err)
+		return repository.Migration{}, nil, nil, fmt.Errorf("check mappings: %w", err)
 	}
 	if !allMapped {
-		return fmt.Errorf("there are pending field mappings — configure all before starting")
+		return repository.Migration{}, nil, nil, fmt.Errorf("there are pending field mappings — configure all before starting")
 	}
 
 	containersMapped, err := s.containerMappingRepo.AllMapped(migrationID)
 	if err != nil {
-		return fmt.Errorf("check container mappings: %w", err)
+		return repository.Migration{}, nil, nil, fmt.Errorf("check container mappings: %w", err)
 	}
 	if !containersMapped {
-		return fmt.Errorf("there are unmapped sections/lists — map all containers before starting")
+		return repository.Migration{}, nil, nil, fmt.Errorf("there are unmapped sections/lists — map all containers before starting")
 	}
 
 	migration, err := s.migrationRepo.GetMigration(migrationID)
 	if err != nil {
-		return fmt.Errorf("get migration: %w", err)
+		return repository.Migration{}, nil, nil, fmt.Errorf("get migration: %w", err)
 	}
 
-	sourceProvider, err := s.getProvider(migration.Source)
+	sourceProvider, err := s.getProvider(sourceProviderOf(migration))
 	if err != nil {
-		return fmt.Errorf("get source provider: %w", err)
+		return repository.Migration{}, nil, nil, fmt.Errorf("get source provider: %w", err)
 	}
-	destProvider, err := s.getProvider(migration.Destination)
+	destProvider, err := s.getProvider(destProviderOf(migration))
 	if err != nil {
-		return fmt.Errorf("get dest provider: %w", err)
-	}
-	if err := s.migrationRepo.UpdateStatus(migrationID, repository.MigrationStatusRunning); err != nil {
-		return fmt.Errorf("update migration status: %w", err)
+		return repository.Migration{}, nil, nil, fmt.Errorf("get dest provider: %w", err)
 	}
+	return migration, sourceProvider, destProvider, nil
+}
 
-	// Create an independent context — not tied to the HTTP request lifecycle.
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
-
-	go func() {
-		defer cancel()
-		s.executeMigration(ctx, sourceProvider, destProvider, migration)
-	}()
-
-	return nil
+// StartMigration queues a migration; a queue worker runs it. See RunWorkers.
+func (s *MigrationService) StartMigration(migrationID int64) error {
+	_, err := s.enqueueMigration(migrationID)
+	return err
 }
 
 // ---- Execution ----
@@ -780,6 +1200,69 @@ func mapStatus(taskStatus string, m map[string]string) string {
 	return "to do"
 }
 
+// maxAssignees returns how many assignees a destination task accepts, 0 for no limit.
+func maxAssignees(destClient client.TaskClient) int {
+	if l, ok := destClient.(client.AssigneeLimiter); ok {
+		return l.MaxAssignees()
+	}
+	return 0
+}
+
+// mapCollaborators resolves source assignees and followers to destination users through the
+// assignee mappings. Unmapped and skipped users are dropped. When the destination accepts at most max
+// assignees, the extra ones become followers.
+func mapCollaborators(
+	assignees, followers []models.TaskAssignee,
+	resolved map[string]string,
+	max int,
+) ([]models.TaskAssignee, []models.TaskAssignee) {
+	seen := make(map[string]bool)
+	var destAssignees, destFollowers []models.TaskAssignee
+	for _, a := range assignees {
+		destID, ok := resolved[a.ID]
+		if !ok || seen[destID] {
+			continue
+		}
+		seen[destID] = true
+		if max > 0 && len(destAssignees) >= max {
+			destFollowers = append(destFollowers, models.TaskAssignee{ID: destID})
+			continue
+		}
+		destAssignees = append(destAssignees, models.TaskAssignee{ID: destID})
+	}
+	for _, f := range followers {
+		destID, ok := resolved[f.ID]
+		if !ok || seen[destID] {
+			continue
+		}
+		seen[destID] = true
+		destFollowers = append(destFollowers, models.TaskAssignee{ID: destID})
+	}
+	return destAssignees, destFollowers
+}
+
+// mapTags renames tags through the tag mappings, removes dropped ones and merges tags
+// that end up with the same destination name. Unmapped tags are kept as they are.
+func mapTags(tags []string, m map[string]string, dropped map[string]bool) []string {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if dropped[tag] {
+			continue
+		}
+		if dest, ok := m[tag]; ok {
+			tag = dest
+		}
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
 func mapPriority(taskPriority string, m map[string]string) string {
 	if taskPriority == "" {
 		return ""
@@ -790,6 +1273,22 @@ func mapPriority(taskPriority string, m map[string]string) string {
 	return ""
 }
 
+// taskExecution holds the state shared by every task of a single migration run.
+type taskExecution struct {
+	migration         repository.Migration
+	sourceClient      client.TaskClient
+	destClient        client.TaskClient
+	resolvedAssignees map[string]string
+	maxAssignees      int // 0: no limit
+	cfMapping         map[string]customFieldEntry
+	priorityOptions   map[string]string
+	tagMap            map[string]string
+	droppedTags       map[string]bool
+	knownTags         map[string]bool
+	successCount      int
+	failCount         int
+}
+
 func (s *MigrationService) executeMigration(
 	ctx context.Context,
 	sourceClient client.TaskClient,
@@ -803,49 +1302,67 @@ func (s *MigrationService) executeMigration(
 				"panic", r,
 				"stack", string(debug.Stack()),
 			)
-			s.migrationRepo.Complete(migration.ID, repository.MigrationStatusFailed)
+			s.completeRun(ctx, migration.ID, repository.MigrationStatusFailed)
 		}
 	}()
 
 	containerMappings, err := s.containerMappingRepo.GetByMigrationID(migration.ID)
 	if err != nil {
-		s.migrationRepo.Complete(migration.ID, repository.MigrationStatusFailed)
+		s.completeRun(ctx, migration.ID, repository.MigrationStatusFailed)
 		slog.Error("failed to load container mappings", "migration_id", migration.ID, "error", err)
 		return
 	}
+	destDescriptor, err := s.getDescriptor(migration.Destination)
+	if err != nil {
+		s.completeRun(ctx, migration.ID, repository.MigrationStatusFailed)
+		slog.Error("failed to load destination provider", "migration_id", migration.ID, "error", err)
+		return
+	}
 
 	// Load global assignee mappings (NULL container)
 	globalMappings, err := s.migrationMappingRepo.GetGlobalByMigrationID(migration.ID)
 	if err != nil {
-		s.migrationRepo.Complete(migration.ID, repository.MigrationStatusFailed)
+		s.completeRun(ctx, migration.ID, repository.MigrationStatusFailed)
 		slog.Error("failed to load global mappings", "migration_id", migration.ID, "error", err)
 		return
 	}
 	resolvedAssignees := make(map[string]string)
+	tagMap := make(map[string]string)
+	droppedTags := make(map[string]bool)
 	for _, m := range globalMappings {
-		if m.Type == repository.MappingTypeAssignee && m.DestValue != nil {
-			resolvedAssignees[m.SourceValue] = *m.DestValue
+		switch m.Type {
+		case repository.MappingTypeAssignee:
+			if m.DestValue != nil {
+				resolvedAssignees[m.SourceValue] = *m.DestValue
+			}
+		case repository.MappingTypeTag:
+			if m.Status == repository.MappingStatusSkipped {
+				droppedTags[m.SourceValue] = true
+			} else if m.DestValue != nil {
+				tagMap[m.SourceValue] = *m.DestValue
+			}
 		}
 	}
 
 	// Build custom field mapping (global across all containers for execution simplicity)
 	cfMapping := map[string]customFieldEntry{}
 	if fp, ok := sourceClient.(client.FieldProvider); ok {
-		if fc, ok := destClient.(client.FieldCreator); ok {
-			sourceProvider, _ := sourceClient.(client.IntegrationProvider)
-			listIDs := s.resolveListIDs(ctx, sourceProvider, migration.SourceProjectID)
-			cfMapping = s.buildCustomFieldMapping(ctx, fp, fc, listIDs, migration.DestWorkspaceID, migration.DestListID)
-			enabledIDs, err := s.migrationMappingRepo.GetEnabledCustomFieldIDs(migration.ID)
+		fc, _ := destClient.(client.FieldCreator)
+		var destFields []models.CustomFieldDefinition
+		if dfp, ok := destClient.(client.DestFieldProvider); ok {
+			destFields, err = dfp.GetDestFieldDefinitions(ctx, s.getDestContainerID(migration))
 			if err != nil {
-				slog.Warn("could not load enabled custom field IDs, migrating all fields", "migration_id", migration.ID, "error", err)
-			} else {
-				for id := range cfMapping {
-					if !enabledIDs[id] {
-						delete(cfMapping, id)
-					}
-				}
+				slog.Warn("could not load destination custom fields", "migration_id", migration.ID, "error", err)
 			}
 		}
+		enabledFields, err := s.migrationMappingRepo.GetEnabledCustomFields(migration.ID)
+		if err != nil {
+			slog.Warn("could not load enabled custom fields, migrating all fields", "migration_id", migration.ID, "error", err)
+			enabledFields = nil
+		}
+		sourceProvider, _ := sourceClient.(client.IntegrationProvider)
+		listIDs := s.resolveListIDs(ctx, sourceProvider, migration.SourceProjectID)
+		cfMapping = s.buildCustomFieldMapping(ctx, fp, fc, destFields, listIDs, enabledFields, migration)
 	}
 
 	// Priority options (for Asana destination)
@@ -853,18 +1370,35 @@ func (s *MigrationService) executeMigration(
 	if lookup, ok := destClient.(client.PriorityLookup); ok {
 		options, err := lookup.GetProjectCustomFieldOptions(ctx, migration.DestListID)
 		if err != nil {
-			s.migrationRepo.Complete(migration.ID, repository.MigrationStatusFailed)
+			s.completeRun(ctx, migration.ID, repository.MigrationStatusFailed)
 			slog.Error("failed to fetch priority options", "migration_id", migration.ID, "error", err)
 			return
 		}
 		priorityOptions = options
 	}
 
-	cp, hasContainerProvider := sourceClient.(client.ContainerProvider)
+	exec := &taskExecution{
+		migration:         migration,
+		sourceClient:      sourceClient,
+		destClient:        destClient,
+		resolvedAssignees: resolvedAssignees,
+		maxAssignees:      maxAssignees(destClient),
+		tagMap:            tagMap,
+		droppedTags:       droppedTags,
+		cfMapping:         cfMapping,
+		priorityOptions:   priorityOptions,
+		knownTags:         make(map[string]bool),
+	}
 
-	successCount := 0
-	failCount := 0
-	totalTasks := 0
+	// Tasks created by an earlier run are not migrated again; see withoutMigrated.
+	migrated, err := s.loadMigratedTaskIDs(migration.ID)
+	if err != nil {
+		s.completeRun(ctx, migration.ID, repository.MigrationStatusFailed)
+		slog.Error("failed to load migrated tasks", "migration_id", migration.ID, "error", err)
+		return
+	}
+
+	cp, hasContainerProvider := sourceClient.(client.ContainerProvider)
 
 	if hasContainerProvider && len(containerMappings) > 0 {
 		// First pass: count total tasks
@@ -874,6 +1408,8 @@ func (s *MigrationService) executeMigration(
 			status map[string]string
 			prio   map[string]string
 		}
+		totalTasks := 0
+		excludedTasks := 0
 
 		for _, cm := range containerMappings {
 			if !cm.Enabled {
@@ -887,10 +1423,14 @@ func (s *MigrationService) executeMigration(
 
 			containerTasks, err := cp.GetTasksByContainer(ctx, cm.SourceID)
 			if err != nil {
-				s.migrationRepo.Complete(migration.ID, repository.MigrationStatusFailed)
+				s.completeRun(ctx, migration.ID, repository.MigrationStatusFailed)
 				slog.Error("failed to fetch tasks for container", "container", cm.SourceName, "error", err)
 				return
 			}
+			containerTasks, excluded := splitByFilters(containerTasks, migration.FilterRules)
+			excludedTasks += excluded
+			containerTasks = withoutMigrated(containerTasks, migrated)
+			applyContainerStrategy(containerTasks, migration.ContainerStrategy, cm.SourceName)
 
 			// Load per-container status/priority mappings
 			perContainerMappings, err := s.migrationMappingRepo.GetByMigrationIDAndContainer(migration.ID, cm.SourceID)
@@ -911,10 +1451,7 @@ func (s *MigrationService) executeMigration(
 				}
 			}
 
-			destID := *cm.DestID
-			if migration.Destination == "asana" {
-				destID = migration.DestListID + "|" + *cm.DestID
-			}
+			destID := destDescriptor.DestContainerAddress(destinationOf(migration), *cm.DestID)
 
 			tasksByContainer = append(tasksByContainer, struct {
 				destID string
@@ -926,73 +1463,30 @@ func (s *MigrationService) executeMigration(
 			totalTasks += len(containerTasks)
 		}
 
-		s.migrationRepo.UpdateTotalTasks(migration.ID, totalTasks)
+		s.recordTaskCounts(migration.ID, totalTasks, excludedTasks)
 
 		slog.Info("starting migration",
 			"migration_id", migration.ID,
 			"source", migration.Source,
 			"destination", migration.Destination,
 			"total_tasks", totalTasks,
+			"excluded_tasks", excludedTasks,
 			"custom_fields_mapped", len(cfMapping),
 		)
 
+	groups:
 		for _, group := range tasksByContainer {
 			for _, task := range group.tasks {
-				slog.Info("migrating task", "migration_id", migration.ID, "task_id", task.Id, "task_name", task.Name)
-
-				task.Status = mapStatus(task.Status, group.status)
-				task.Priority = mapPriority(task.Priority, group.prio)
-
-				if task.Priority != "" && len(priorityOptions) > 0 {
-					fieldGid := priorityOptions["__field_gid__"]
-					optionGid := priorityOptions[task.Priority]
-					if fieldGid != "" && optionGid != "" {
-						task.Priority = fieldGid + ":" + optionGid
-					} else {
-						task.Priority = ""
-					}
-				}
-
-				destAssignees := make([]models.TaskAssignee, 0, len(task.Assignees))
-				for _, a := range task.Assignees {
-					if destID, ok := resolvedAssignees[a.ID]; ok {
-						destAssignees = append(destAssignees, models.TaskAssignee{ID: destID})
-					}
-				}
-				task.Assignees = destAssignees
-				task.CustomFields = convertTaskCustomFields(task.CustomFields, cfMapping)
-
-				created, err := destClient.CreateTask(ctx, group.destID, migration.DestWorkspaceID, task)
-				if err != nil {
-					s.taskMappingRepo.Create(&repository.TaskMapping{
-						MigrationID:  migration.ID,
-						SourceTaskID: task.Id,
-						Status:       repository.TaskMappingStatusFailed,
-						ErrorMessage: err.Error(),
-					})
-					slog.Error("failed to migrate task", "migration_id", migration.ID, "task_name", task.Name, "error", err)
-					failCount++
-				} else {
-					s.taskMappingRepo.Create(&repository.TaskMapping{
-						MigrationID:  migration.ID,
-						SourceTaskID: task.Id,
-						DestTaskID:   created.Id,
-						Status:       repository.TaskMappingStatusSuccess,
-					})
-					slog.Info("task migrated", "migration_id", migration.ID, "dest_task_id", created.Id)
-					successCount++
-				}
-				if (successCount+failCount)%10 == 0 {
-					s.migrationRepo.UpdateProgress(migration.ID, successCount, failCount)
+				if !s.migrateTask(ctx, exec, task, group.destID, group.status, group.prio) {
+					break groups
 				}
 			}
 		}
-		s.migrationRepo.UpdateProgress(migration.ID, successCount, failCount)
 	} else {
 		// Non-container source: load all mappings globally
 		allMappings, err := s.migrationMappingRepo.GetGlobalByMigrationID(migration.ID)
 		if err != nil {
-			s.migrationRepo.Complete(migration.ID, repository.MigrationStatusFailed)
+			s.completeRun(ctx, migration.ID, repository.MigrationStatusFailed)
 			slog.Error("failed to load mappings", "migration_id", migration.ID, "error", err)
 			return
 		}
@@ -1012,78 +1506,213 @@ func (s *MigrationService) executeMigration(
 
 		tasks, err := sourceClient.GetTasks(ctx, migration.SourceProjectID)
 		if err != nil {
-			s.migrationRepo.Complete(migration.ID, repository.MigrationStatusFailed)
+			s.completeRun(ctx, migration.ID, repository.MigrationStatusFailed)
 			slog.Error("failed to fetch tasks", "migration_id", migration.ID, "error", err)
 			return
 		}
-		s.migrationRepo.UpdateTotalTasks(migration.ID, len(tasks))
+		tasks, excludedTasks := splitByFilters(tasks, migration.FilterRules)
+		tasks = withoutMigrated(tasks, migrated)
+		s.recordTaskCounts(migration.ID, len(tasks), excludedTasks)
 
 		slog.Info("starting migration",
 			"migration_id", migration.ID,
 			"source", migration.Source,
 			"destination", migration.Destination,
 			"total_tasks", len(tasks),
+			"excluded_tasks", excludedTasks,
 		)
 
 		for _, task := range tasks {
-			task.Status = mapStatus(task.Status, statusMap)
-			task.Priority = mapPriority(task.Priority, priorityMap)
-
-			if task.Priority != "" && len(priorityOptions) > 0 {
-				fieldGid := priorityOptions["__field_gid__"]
-				optionGid := priorityOptions[task.Priority]
-				if fieldGid != "" && optionGid != "" {
-					task.Priority = fieldGid + ":" + optionGid
-				} else {
-					task.Priority = ""
-				}
-			}
-
-			destAssignees := make([]models.TaskAssignee, 0, len(task.Assignees))
-			for _, a := range task.Assignees {
-				if destID, ok := resolvedAssignees[a.ID]; ok {
-					destAssignees = append(destAssignees, models.TaskAssignee{ID: destID})
-				}
-			}
-			task.Assignees = destAssignees
-			task.CustomFields = convertTaskCustomFields(task.CustomFields, cfMapping)
-
 			destContainerID := task.DestContainerID
 			if destContainerID == "" {
 				destContainerID = migration.DestListID
 			}
-			created, err := destClient.CreateTask(ctx, destContainerID, migration.DestWorkspaceID, task)
-			if err != nil {
-				s.taskMappingRepo.Create(&repository.TaskMapping{
-					MigrationID:  migration.ID,
-					SourceTaskID: task.Id,
-					Status:       repository.TaskMappingStatusFailed,
-					ErrorMessage: err.Error(),
-				})
-				slog.Error("failed to migrate task", "migration_id", migration.ID, "task_name", task.Name, "error", err)
-				failCount++
-			} else {
-				s.taskMappingRepo.Create(&repository.TaskMapping{
-					MigrationID:  migration.ID,
-					SourceTaskID: task.Id,
-					DestTaskID:   created.Id,
-					Status:       repository.TaskMappingStatusSuccess,
-				})
-				slog.Info("task migrated", "migration_id", migration.ID, "dest_task_id", created.Id)
-				successCount++
-			}
-			if (successCount+failCount)%10 == 0 {
-				s.migrationRepo.UpdateProgress(migration.ID, successCount, failCount)
+			if !s.migrateTask(ctx, exec, task, destContainerID, statusMap, priorityMap) {
+				break
 			}
 		}
-		s.migrationRepo.UpdateProgress(migration.ID, successCount, failCount)
+	}
+	if runHandedOver(ctx) {
+		slog.Warn("migration run stopped, its job is handed over", "migration_id", migration.ID, "reason", context.Cause(ctx))
+		return
+	}
+	s.recordProgress(exec)
+	if ctx.Err() != nil {
+		slog.Error("migration run stopped before all tasks were migrated", "migration_id", migration.ID, "error", context.Cause(ctx))
+		s.completeRun(ctx, migration.ID, repository.MigrationStatusFailed)
+		return
 	}
 
 	finalStatus := repository.MigrationStatusCompleted
-	if failCount > 0 {
+	if exec.failCount > 0 {
 		finalStatus = repository.MigrationStatusCompletedWithErrors
 	}
-	s.migrationRepo.Complete(migration.ID, finalStatus)
+	s.completeRun(ctx, migration.ID, finalStatus)
+}
+
+// recordTaskCounts stores how many tasks a run migrates and how many the filters exclude.
+// The counts only inform progress reporting, so failures are logged and the run goes on.
+func (s *MigrationService) recordTaskCounts(migrationID int64, total, excluded int) {
+	if err := s.migrationRepo.UpdateTotalTasks(migrationID, total); err != nil {
+		slog.Error("failed to record total tasks", "migration_id", migrationID, "total_tasks", total, "error", err)
+	}
+	if err := s.migrationRepo.UpdateExcludedTasks(migrationID, excluded); err != nil {
+		slog.Error("failed to record excluded tasks", "migration_id", migrationID, "excluded_tasks", excluded, "error", err)
+	}
+}
+
+func (s *MigrationService) recordProgress(exec *taskExecution) {
+	if err := s.migrationRepo.UpdateProgress(exec.migration.ID, exec.successCount, exec.failCount); err != nil {
+		slog.Error("failed to record migration progress", "migration_id", exec.migration.ID, "error", err)
+	}
+}
+
+// completeRun records the final status of a run, unless its job is handed over to another
+// worker, which then owns the migration's status.
+func (s *MigrationService) completeRun(ctx context.Context, migrationID int64, status repository.MigrationStatus) {
+	if runHandedOver(ctx) {
+		slog.Warn("not completing migration, its job is handed over", "migration_id", migrationID, "status", status, "reason", context.Cause(ctx))
+		return
+	}
+	if err := s.migrationRepo.Complete(migrationID, status); err != nil {
+		slog.Error("failed to complete migration", "migration_id", migrationID, "status", status, "error", err)
+	}
+}
+
+// migrateTask applies the migration mappings to a single source task, creates it in the
+// destination container and records the outcome. It reports false without doing anything
+// once ctx is done, so a run that timed out or lost its job stops between tasks.
+func (s *MigrationService) migrateTask(
+	ctx context.Context,
+	exec *taskExecution,
+	task models.Task,
+	destContainerID string,
+	statusMap, priorityMap map[string]string,
+) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	migration := exec.migration
+	slog.Info("migrating task", "migration_id", migration.ID, "task_id", task.Id, "task_name", task.Name)
+
+	task.Status = mapStatus(task.Status, statusMap)
+	task.Priority = mapPriority(task.Priority, priorityMap)
+
+	if task.Priority != "" && len(exec.priorityOptions) > 0 {
+		fieldGid := exec.priorityOptions["__field_gid__"]
+		optionGid := exec.priorityOptions[task.Priority]
+		if fieldGid != "" && optionGid != "" {
+			task.Priority = fieldGid + ":" + optionGid
+		} else {
+			task.Priority = ""
+		}
+	}
+
+	task.Assignees, task.Followers = mapCollaborators(task.Assignees, task.Followers, exec.resolvedAssignees, exec.maxAssignees)
+	task.RichDescription = converter.RewriteMentions(task.RichDescription, func(id string) (string, bool) {
+		destID, ok := exec.resolvedAssignees[id]
+		return destID, ok
+	})
+	customFields, fieldTags := convertTaskCustomFields(task.CustomFields, exec.cfMapping)
+	task.CustomFields = customFields
+	task.Tags = mapTags(append(task.Tags, fieldTags...), exec.tagMap, exec.droppedTags)
+
+	s.ensureDestTags(ctx, exec, task.Tags)
+	foldChecklists(&task, exec.destClient)
+	applyBacklink(&task, exec, task.Id)
+
+	created, err := exec.destClient.CreateTask(ctx, destContainerID, migration.DestWorkspaceID, task)
+	switch {
+	case err != nil:
+		slog.Error("failed to migrate task", "migration_id", migration.ID, "task_name", task.Name, "error", err)
+		if err := s.taskMappingRepo.Create(&repository.TaskMapping{
+			MigrationID:  migration.ID,
+			SourceTaskID: task.Id,
+			Status:       repository.TaskMappingStatusFailed,
+			ErrorMessage: err.Error(),
+		}); err != nil {
+			slog.Error("failed to record failed task", "migration_id", migration.ID, "task_id", task.Id, "error", err)
+		}
+		exec.failCount++
+	case !s.recordMigratedTask(ctx, exec, task.Id, created.Id):
+		exec.failCount++
+	default:
+		slog.Info("task migrated", "migration_id", migration.ID, "dest_task_id", created.Id)
+		writeChecklists(ctx, exec, task.Checklists, created.Id)
+		writeBacklinkComments(ctx, exec, task.Id, created.Id)
+		exec.successCount++
+	}
+	if (exec.successCount+exec.failCount)%10 == 0 {
+		s.recordProgress(exec)
+	}
+	return true
+}
+
+// recordMigratedTask records the destination task created for a source task. Without its
+// mapping the task could neither be rolled back nor skipped on a rerun, so when the mapping
+// cannot be stored the task is deleted again, where the destination allows it, and counts
+// as failed.
+func (s *MigrationService) recordMigratedTask(ctx context.Context, exec *taskExecution, sourceTaskID, destTaskID string) bool {
+	migrationID := exec.migration.ID
+	err := s.taskMappingRepo.Create(&repository.TaskMapping{
+		MigrationID:  migrationID,
+		SourceTaskID: sourceTaskID,
+		DestTaskID:   destTaskID,
+		Status:       repository.TaskMappingStatusSuccess,
+	})
+	if err == nil {
+		return true
+	}
+	slog.Error("failed to record migrated task", "migration_id", migrationID, "task_id", sourceTaskID, "dest_task_id", destTaskID, "error", err)
+	if td, ok := exec.destClient.(client.TaskDeleter); ok {
+		if err := td.DeleteTask(ctx, destTaskID); err != nil {
+			slog.Error("failed to delete unrecorded task", "migration_id", migrationID, "dest_task_id", destTaskID, "error", err)
+		}
+	}
+	return false
+}
+
+// ensureDestTags creates missing destination tags before the task is created, so that
+// tags created by the migration are recorded and can be removed again on rollback.
+// Failures are logged only; the destination client still resolves tags on CreateTask.
+func (s *MigrationService) ensureDestTags(ctx context.Context, exec *taskExecution, tags []string) {
+	tc, ok := exec.destClient.(client.TagCreator)
+	if !ok || exec.migration.DestWorkspaceID == "" {
+		return
+	}
+	workspaceID := exec.migration.DestWorkspaceID
+	for _, name := range tags {
+		key := strings.ToLower(name)
+		if exec.knownTags[key] {
+			continue
+		}
+		_, found, err := tc.FindTag(ctx, workspaceID, name)
+		if err != nil {
+			slog.Warn("could not look up destination tag", "migration_id", exec.migration.ID, "tag", name, "error", err)
+			continue
+		}
+		if !found {
+			gid, err := tc.CreateTag(ctx, workspaceID, name)
+			if err != nil {
+				slog.Warn("could not create destination tag", "migration_id", exec.migration.ID, "tag", name, "error", err)
+				continue
+			}
+			s.recordCreatedResource(exec.migration.ID, repository.CreatedResourceTypeTag, gid, name)
+		}
+		exec.knownTags[key] = true
+	}
+}
+
+func (s *MigrationService) recordCreatedResource(migrationID int64, resourceType repository.CreatedResourceType, resourceID, name string) {
+	err := s.createdResourceRepo.Create(&repository.CreatedResource{
+		MigrationID: migrationID,
+		Type:        resourceType,
+		ResourceID:  resourceID,
+		Name:        name,
+	})
+	if err != nil {
+		slog.Warn("could not record created resource", "migration_id", migrationID, "type", resourceType, "resource_id", resourceID, "error", err)
+	}
 }
 
 // resolveListIDs returns list IDs from a provider for custom field discovery.
@@ -1144,19 +1773,29 @@ func mapClickUpTypeToAsana(t string) string {
 }
 
 type customFieldEntry struct {
-	asanaGID    string
-	asanaType   string
-	clickupType string
-	optionMap   map[string]string
+	asanaGID      string
+	asanaType     string
+	clickupType   string
+	conversion    string
+	sourceOptions []models.CustomFieldOption
+	optionMap     map[string]string
 }
 
+// buildCustomFieldMapping resolves, for every enabled source custom field, the destination field
+// its values go to. Fields with a configured destination use it; the others are matched by name in
+// the destination project or created. fc may be nil, in which case fields without a configured
+// destination are only migrated as tags.
+// A nil enabled map migrates every field with default settings.
 func (s *MigrationService) buildCustomFieldMapping(
 	ctx context.Context,
 	fp client.FieldProvider,
 	fc client.FieldCreator,
+	destFields []models.CustomFieldDefinition,
 	sourceListIDs []string,
-	destWorkspaceId, destProjectId string,
+	enabled map[string]repository.CustomFieldRow,
+	migration repository.Migration,
 ) map[string]customFieldEntry {
+	destWorkspaceId, destProjectId := migration.DestWorkspaceID, migration.DestListID
 	seen := map[string]struct{}{}
 	var defs []models.CustomFieldDefinition
 	for _, lid := range sourceListIDs {
@@ -1173,87 +1812,105 @@ func (s *MigrationService) buildCustomFieldMapping(
 		}
 	}
 
+	destByID := make(map[string]models.CustomFieldDefinition, len(destFields))
+	for _, d := range destFields {
+		destByID[d.ID] = d
+	}
+
 	mapping := make(map[string]customFieldEntry, len(defs))
 
 	for _, def := range defs {
-		asanaType := mapClickUpTypeToAsana(def.ClickUpType)
-
-		var optionNames []string
-		switch def.ClickUpType {
-		case "drop_down":
-			sorted := make([]models.CustomFieldOption, len(def.Options))
-			copy(sorted, def.Options)
-			sort.Slice(sorted, func(i, j int) bool {
-				return sorted[i].OrderIndex < sorted[j].OrderIndex
-			})
-			for _, o := range sorted {
-				optionNames = append(optionNames, o.Name)
-			}
-		case "labels":
-			for _, o := range def.Options {
-				optionNames = append(optionNames, o.Name)
+		var cfg repository.CustomFieldRow
+		if enabled != nil {
+			row, ok := enabled[def.ID]
+			if !ok {
+				continue
 			}
-		case "checkbox":
-			optionNames = []string{"True", "False"}
+			cfg = row
 		}
 
-		fieldGID, optionGIDs, found, lookupErr := fc.GetProjectCustomField(ctx, destProjectId, def.Name)
-		if lookupErr != nil {
-			slog.Warn("could not check existing project fields, will try to create", "field", def.Name, "error", lookupErr)
+		sourceOptions := sourceFieldOptions(def)
+		entry := customFieldEntry{
+			clickupType:   def.ClickUpType,
+			conversion:    cfg.Conversion,
+			sourceOptions: sourceOptions,
 		}
 
-		if !found {
-			var createErr error
-			fieldGID, optionGIDs, createErr = fc.CreateCustomField(ctx, destWorkspaceId, def.Name, asanaType, optionNames)
-			if createErr != nil {
-				if !strings.Contains(createErr.Error(), "already exists with the name") {
-					slog.Warn("could not create custom field in destination, skipping", "field", def.Name, "error", createErr)
-					continue
-				}
-				var findErr error
-				fieldGID, optionGIDs, findErr = fc.FindCustomFieldByName(ctx, destWorkspaceId, def.Name)
-				if findErr != nil {
-					slog.Warn("could not locate existing custom field, skipping", "field", def.Name, "error", findErr)
-					continue
+		if entry.conversion == CustomFieldConversionTags {
+			mapping[def.ID] = entry
+			continue
+		}
+
+		var destOptions []models.CustomFieldOption
+		if cfg.DestFieldID != nil {
+			destDef, ok := destByID[*cfg.DestFieldID]
+			if !ok {
+				slog.Warn("configured destination custom field not found in project, skipping", "field", def.Name, "dest_field_id", *cfg.DestFieldID)
+				continue
+			}
+			entry.asanaGID = destDef.ID
+			entry.asanaType = mapClickUpTypeToAsana(destDef.ClickUpType)
+			if entry.conversion == CustomFieldConversionAuto && entry.asanaType == "text" && isOptionFieldType(def.ClickUpType) {
+				entry.conversion = CustomFieldConversionText
+			}
+			destOptions = destDef.Options
+		} else {
+			if fc == nil {
+				continue
+			}
+			asanaType := mapClickUpTypeToAsana(def.ClickUpType)
+			var optionNames []string
+			if entry.conversion == CustomFieldConversionText {
+				asanaType = "text"
+			} else {
+				for _, o := range sourceOptions {
+					optionNames = append(optionNames, o.Name)
 				}
 			}
 
-			if err := fc.AttachCustomFieldToProject(ctx, destProjectId, fieldGID); err != nil {
-				slog.Warn("could not attach custom field to project, skipping", "field", def.Name, "error", err)
-				continue
+			fieldGID, optionGIDs, found, lookupErr := fc.GetProjectCustomField(ctx, destProjectId, def.Name)
+			if lookupErr != nil {
+				slog.Warn("could not check existing project fields, will try to create", "field", def.Name, "error", lookupErr)
 			}
-		}
 
-		entry := customFieldEntry{
-			asanaGID:    fieldGID,
-			asanaType:   asanaType,
-			clickupType: def.ClickUpType,
-			optionMap:   make(map[string]string),
-		}
+			if !found {
+				var createErr error
+				fieldGID, optionGIDs, createErr = fc.CreateCustomField(ctx, destWorkspaceId, def.Name, asanaType, optionNames)
+				if createErr != nil {
+					if !strings.Contains(createErr.Error(), "already exists with the name") {
+						slog.Warn("could not create custom field in destination, skipping", "field", def.Name, "error", createErr)
+						continue
+					}
+					var findErr error
+					fieldGID, optionGIDs, findErr = fc.FindCustomFieldByName(ctx, destWorkspaceId, def.Name)
+					if findErr != nil {
+						slog.Warn("could not locate existing custom field, skipping", "field", def.Name, "error", findErr)
+						continue
+					}
+				} else {
+					s.recordCreatedResource(migration.ID, repository.CreatedResourceTypeCustomField, fieldGID, def.Name)
+				}
 
-		switch def.ClickUpType {
-		case "drop_down":
-			sorted := make([]models.CustomFieldOption, len(def.Options))
-			copy(sorted, def.Options)
-			sort.Slice(sorted, func(i, j int) bool {
-				return sorted[i].OrderIndex < sorted[j].OrderIndex
-			})
-			for i, o := range sorted {
-				if i < len(optionGIDs) {
-					entry.optionMap[strconv.Itoa(o.OrderIndex)] = optionGIDs[i]
+				if err := fc.AttachCustomFieldToProject(ctx, destProjectId, fieldGID); err != nil {
+					slog.Warn("could not attach custom field to project, skipping", "field", def.Name, "error", err)
+					continue
 				}
 			}
-		case "labels":
-			for i, o := range def.Options {
-				if i < len(optionGIDs) {
-					entry.optionMap[o.ID] = optionGIDs[i]
+
+			entry.asanaGID = fieldGID
+			entry.asanaType = asanaType
+			if destDef, ok := destByID[fieldGID]; ok {
+				destOptions = destDef.Options
+			} else {
+				for _, gid := range optionGIDs {
+					destOptions = append(destOptions, models.CustomFieldOption{ID: gid})
 				}
 			}
-		case "checkbox":
-			if len(optionGIDs) >= 2 {
-				entry.optionMap["true"] = optionGIDs[0]
-				entry.optionMap["false"] = optionGIDs[1]
-			}
+		}
+
+		if entry.conversion != CustomFieldConversionText {
+			// Fields created or matched by name keep the historical positional pairing as a fallback.
+			entry.optionMap = matchFieldOptions(def.ClickUpType, sourceOptions, destOptions, cfg.OptionMap, cfg.DestFieldID == nil)
 		}
 
 		mapping[def.ID] = entry
@@ -1262,11 +1919,14 @@ func (s *MigrationService) buildCustomFieldMapping(
 	return mapping
 }
 
+// convertTaskCustomFields converts source custom field values into destination values.
+// Values of fields converted to tags are returned separately as tag names.
 func convertTaskCustomFields(
 	fields []models.TaskCustomField,
 	mapping map[string]customFieldEntry,
-) []models.TaskCustomField {
+) ([]models.TaskCustomField, []string) {
 	result := make([]models.TaskCustomField, 0, len(fields))
+	var tags []string
 
 	for _, cf := range fields {
 		entry, ok := mapping[cf.FieldID]
@@ -1274,6 +1934,23 @@ func convertTaskCustomFields(
 			continue
 		}
 
+		switch entry.conversion {
+		case CustomFieldConversionTags:
+			tags = append(tags, sourceOptionNames(entry, cf.Value)...)
+			continue
+		case CustomFieldConversionText:
+			var text string
+			if isOptionFieldType(entry.clickupType) {
+				text = strings.Join(sourceOptionNames(entry, cf.Value), ", ")
+			} else {
+				text = strings.Join(customFieldFilterValues(cf.Value), ", ")
+			}
+			if text != "" {
+				result = append(result, models.TaskCustomField{FieldID: entry.asanaGID, Value: text})
+			}
+			continue
+		}
+
 		var converted interface{}
 
 		switch entry.clickupType {
@@ -1295,38 +1972,18 @@ func convertTaskCustomFields(
 				}
 			}
 		case "labels":
-			if arr, ok := cf.Value.([]interface{}); ok {
-				gids := make([]string, 0, len(arr))
-				for _, item := range arr {
-					if m, ok := item.(map[string]interface{}); ok {
-						if id, ok := m["id"].(string); ok {
-							if gid, ok := entry.optionMap[id]; ok {
-								gids = append(gids, gid)
-							}
-						}
-					}
-				}
-				if len(gids) > 0 {
-					converted = gids
+			ids := labelOptionIDs(cf.Value)
+			gids := make([]string, 0, len(ids))
+			for _, id := range ids {
+				if gid, ok := entry.optionMap[id]; ok {
+					gids = append(gids, gid)
 				}
 			}
-		case "checkbox":
-			var key string
-			switch v := cf.Value.(type) {
-			case bool:
-				if v {
-					key = "true"
-				} else {
-					key = "false"
-				}
-			case float64:
-				if v != 0 {
-					key = "true"
-				} else {
-					key = "false"
-				}
+			if len(gids) > 0 {
+				converted = gids
 			}
-			if key != "" {
+		case "checkbox":
+			if key := checkboxKey(cf.Value); key != "" {
 				if gid, ok := entry.optionMap[key]; ok {
 					converted = gid
 				}
@@ -1343,5 +2000,5 @@ func convertTaskCustomFields(
 		}
 	}
 
-	return result
+	return result, tags
 }
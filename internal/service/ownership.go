@@ -0,0 +1,115 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// ownedBy reports whether user may see something owned by ownerID. Admins see everything;
+// things without an owner, such as migrations created from the CLI, only admins see.
+func ownedBy(user repository.User, ownerID *int64) bool {
+	return user.IsAdmin() || (ownerID != nil && *ownerID == user.ID)
+}
+
+// GetMigrationsFor returns the migrations user may see.
+func (s *MigrationService) GetMigrationsFor(user repository.User) ([]repository.Migration, error) {
+	if user.IsAdmin() {
+		return s.GetMigrations()
+	}
+	migrations, err := s.migrationRepo.GetByOwnerID(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get user migrations: %w", err)
+	}
+	return migrations, nil
+}
+
+// CanAccessMigration reports whether user may see and act on a migration. It is false for
+// a migration that does not exist.
+func (s *MigrationService) CanAccessMigration(user repository.User, migrationID int64) (bool, error) {
+	migration, err := s.migrationRepo.GetMigration(migrationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get migration: %w", err)
+	}
+	return ownedBy(user, migration.OwnerID), nil
+}
+
+// GetMappingTemplatesFor returns the mapping templates user may see.
+func (s *MigrationService) GetMappingTemplatesFor(user repository.User) ([]repository.MappingTemplate, error) {
+	if user.IsAdmin() {
+		return s.GetMappingTemplates()
+	}
+	templates, err := s.mappingTemplateRepo.GetByOwnerID(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get user mapping templates: %w", err)
+	}
+	return templates, nil
+}
+
+// CanAccessMappingTemplate reports whether user may see, apply and delete a mapping
+// template. It is false for a template that does not exist.
+func (s *MigrationService) CanAccessMappingTemplate(user repository.User, templateID int64) (bool, error) {
+	template, err := s.mappingTemplateRepo.GetMappingTemplate(templateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get mapping template: %w", err)
+	}
+	return ownedBy(user, template.OwnerID), nil
+}
+
+// GetBatchesFor returns the batches user may see.
+func (s *MigrationService) GetBatchesFor(user repository.User) ([]BatchState, error) {
+	if user.IsAdmin() {
+		return s.GetBatches()
+	}
+	batches, err := s.migrationBatchRepo.GetByOwnerID(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get user migration batches: %w", err)
+	}
+	return s.batchStates(batches)
+}
+
+// CanAccessBatch reports whether user may see and act on a batch. It is false for a batch
+// that does not exist.
+func (s *MigrationService) CanAccessBatch(user repository.User, batchID int64) (bool, error) {
+	batch, err := s.migrationBatchRepo.GetMigrationBatch(batchID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get migration batch: %w", err)
+	}
+	return ownedBy(user, batch.OwnerID), nil
+}
+
+// GetExportsFor returns the archive exports user may see.
+func (s *ArchiveService) GetExportsFor(user repository.User) ([]repository.ArchiveExport, error) {
+	if user.IsAdmin() {
+		return s.GetExports()
+	}
+	exports, err := s.exportRepo.GetByOwnerID(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get user archive exports: %w", err)
+	}
+	return exports, nil
+}
+
+// CanAccessExport reports whether user may see and download an archive export. It is
+// false for an export that does not exist.
+func (s *ArchiveService) CanAccessExport(user repository.User, exportID int64) (bool, error) {
+	export, err := s.exportRepo.GetArchiveExport(exportID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get archive export: %w", err)
+	}
+	return ownedBy(user, export.OwnerID), nil
+}
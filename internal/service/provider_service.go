@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+)
+
+// ProviderInfo describes a registered provider to the frontend.
+type ProviderInfo struct {
+	Name         string   `json:"name"`
+	DisplayName  string   `json:"display_name"`
+	Capabilities []string `json:"capabilities"`
+	// ConnectionRequired is set when the server has no credentials of its own for the
+	// provider, so it can only be used through a connection.
+	ConnectionRequired bool `json:"connection_required"`
+}
+
+// ProviderService exposes registered providers generically, so setting up a migration
+// against a new tool needs no provider-specific endpoints.
+type ProviderService struct {
+	providers   *client.Registry
+	connections connectionClients
+}
+
+func NewProviderService(providers *client.Registry, connections connectionClients) *ProviderService {
+	return &ProviderService{providers: providers, connections: connections}
+}
+
+// ProviderServiceProvider is the interface consumed by handlers.
+// Allows substitution with mocks in tests.
+type ProviderServiceProvider interface {
+	ListProviders() []ProviderInfo
+	ListWorkspaces(ctx context.Context, provider string, access ProviderAccess) ([]client.Container, error)
+	ListProjects(ctx context.Context, provider string, access ProviderAccess, workspaceId string) ([]client.Container, error)
+	ListContainers(ctx context.Context, provider string, access ProviderAccess, projectId string) ([]client.Container, error)
+}
+
+func (s *ProviderService) ListProviders() []ProviderInfo {
+	descriptors := s.providers.List()
+	result := make([]ProviderInfo, len(descriptors))
+	for i, d := range descriptors {
+		result[i] = ProviderInfo{
+			Name:               d.Name,
+			DisplayName:        d.DisplayName,
+			Capabilities:       d.Capabilities(),
+			ConnectionRequired: d.ConnectionRequired,
+		}
+	}
+	return result
+}
+
+// ProviderAccess is how a caller reaches a provider: through one of their connections, or
+// with the server's own credentials, which only admins may use.
+type ProviderAccess struct {
+	ConnectionID      *int64
+	ServerCredentials bool
+}
+
+// client returns the client of a provider, acting as the account behind the connection of
+// access when it is set.
+func (s *ProviderService) client(provider string, access ProviderAccess) (client.IntegrationProvider, error) {
+	d, ok := s.providers.Get(provider)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
+	}
+	if access.ConnectionID != nil {
+		if err := s.connections.CheckConnection(*access.ConnectionID, nil, provider); err != nil {
+			return nil, err
+		}
+		return s.connections.ConnectionClient(*access.ConnectionID, provider)
+	}
+	return serverClient(d, access.ServerCredentials)
+}
+
+// serverClient returns the registered client of a provider reached without a connection.
+// A client holding the server's credentials is refused unless serverCredentials is set.
+func serverClient(d client.Descriptor, serverCredentials bool) (client.IntegrationProvider, error) {
+	if d.ConnectionRequired {
+		return nil, fmt.Errorf("%w: %s can only be reached through a connection", ErrInvalidInput, d.DisplayName)
+	}
+	if !d.NoCredentials && !serverCredentials {
+		return nil, fmt.Errorf("%w: %s can only be reached through a connection of yours", ErrInvalidInput, d.DisplayName)
+	}
+	return d.Client, nil
+}
+
+func (s *ProviderService) browser(provider string, access ProviderAccess) (client.WorkspaceBrowser, error) {
+	c, err := s.client(provider, access)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := c.(client.WorkspaceBrowser)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s cannot be browsed", ErrUnsupported, provider)
+	}
+	return b, nil
+}
+
+func (s *ProviderService) ListWorkspaces(ctx context.Context, provider string, access ProviderAccess) ([]client.Container, error) {
+	b, err := s.browser(provider, access)
+	if err != nil {
+		return nil, err
+	}
+	return b.ListWorkspaces(ctx)
+}
+
+func (s *ProviderService) ListProjects(ctx context.Context, provider string, access ProviderAccess, workspaceId string) ([]client.Container, error) {
+	b, err := s.browser(provider, access)
+	if err != nil {
+		return nil, err
+	}
+	return b.ListProjects(ctx, workspaceId)
+}
+
+// ListContainers returns the containers of a project: Asana sections, ClickUp lists.
+func (s *ProviderService) ListContainers(ctx context.Context, provider string, access ProviderAccess, projectId string) ([]client.Container, error) {
+	c, err := s.client(provider, access)
+	if err != nil {
+		return nil, err
+	}
+	cp, ok := c.(client.ContainerProvider)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has no containers", ErrUnsupported, provider)
+	}
+	return cp.GetSourceContainers(ctx, projectId)
+}
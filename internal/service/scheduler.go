@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// schedulerInterval is how often the scheduler looks for due migrations. Cron schedules
+// have minute resolution, so this keeps runs within a minute of their time.
+const schedulerInterval = 30 * time.Second
+
+// ScheduleInput sets when a migration starts. At is a one-off start time; Cron repeats the
+// migration as delta runs that only pick up source tasks not migrated yet. With only Cron
+// set, the first run is the next time the expression matches.
+type ScheduleInput struct {
+	At   *time.Time
+	Cron string
+}
+
+// ScheduleMigration schedules a ready migration to start later. A recurring schedule may
+// also be set on a completed migration to keep syncing new source tasks.
+func (s *MigrationService) ScheduleMigration(migrationID int64, input ScheduleInput) (repository.Migration, error) {
+	migration, err := s.migrationRepo.GetMigration(migrationID)
+	if err != nil {
+		return repository.Migration{}, fmt.Errorf("get migration: %w", err)
+	}
+
+	switch migration.Status {
+	case repository.MigrationStatusReadyToStart:
+	case repository.MigrationStatusCompleted, repository.MigrationStatusCompletedWithErrors:
+		if input.Cron == "" {
+			return repository.Migration{}, fmt.Errorf("%w: a finished migration can only get a recurring schedule", ErrInvalidMigrationState)
+		}
+	default:
+		return repository.Migration{}, fmt.Errorf("%w: a migration in status %q cannot be scheduled", ErrInvalidMigrationState, migration.Status)
+	}
+
+	at := input.At
+	switch {
+	case input.Cron != "":
+		schedule, err := parseCron(input.Cron)
+		if err != nil {
+			return repository.Migration{}, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		if at == nil {
+			next := schedule.next(time.Now().In(migrationLocation(migration)))
+			at = &next
+		}
+	case at == nil:
+		return repository.Migration{}, fmt.Errorf("%w: scheduled_at or cron is required", ErrInvalidInput)
+	}
+	if !at.After(time.Now()) {
+		return repository.Migration{}, fmt.Errorf("%w: scheduled_at must be in the future", ErrInvalidInput)
+	}
+
+	if err := s.migrationRepo.UpdateSchedule(migrationID, at, input.Cron); err != nil {
+		return repository.Migration{}, err
+	}
+	return s.GetMigration(migrationID)
+}
+
+// UnscheduleMigration removes the schedule of a migration. Runs already started continue.
+func (s *MigrationService) UnscheduleMigration(migrationID int64) error {
+	if _, err := s.migrationRepo.GetMigration(migrationID); err != nil {
+		return fmt.Errorf("get migration: %w", err)
+	}
+	return s.migrationRepo.UpdateSchedule(migrationID, nil, "")
+}
+
+// RunScheduler starts scheduled migrations as they come due, until ctx is done. The
+// schedule lives in the database, so runs missed while the server was down start as soon
+// as it is back.
+func (s *MigrationService) RunScheduler(ctx context.Context) {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	s.startDueMigrations(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.startDueMigrations(now)
+		}
+	}
+}
+
+func (s *MigrationService) startDueMigrations(now time.Time) {
+	due, err := s.migrationRepo.GetDueScheduled(now)
+	if err != nil {
+		slog.Error("failed to load scheduled migrations", "error", err)
+		return
+	}
+
+	for _, migration := range due {
+		// Move the schedule on before starting, so a failing start is not retried every tick.
+		var next *time.Time
+		if migration.Schedule != "" {
+			if schedule, err := parseCron(migration.Schedule); err != nil {
+				slog.Error("invalid migration schedule, clearing it", "migration_id", migration.ID, "schedule", migration.Schedule, "error", err)
+				migration.Schedule = ""
+			} else {
+				t := schedule.next(now.In(migrationLocation(migration)))
+				next = &t
+			}
+		}
+		if err := s.migrationRepo.UpdateSchedule(migration.ID, next, migration.Schedule); err != nil {
+			slog.Error("failed to update migration schedule", "migration_id", migration.ID, "error", err)
+			continue
+		}
+
+		if !scheduledRunAllowed(migration) {
+			slog.Warn("skipping scheduled migration run", "migration_id", migration.ID, "status", migration.Status)
+			continue
+		}
+		slog.Info("starting scheduled migration", "migration_id", migration.ID, "scheduled_at", migration.ScheduledAt)
+		if err := s.StartMigration(migration.ID); err != nil {
+			slog.Error("failed to start scheduled migration", "migration_id", migration.ID, "error", err)
+		}
+	}
+}
+
+// scheduledRunAllowed reports whether the scheduler may start the migration: a first run
+// needs it to be ready, a recurring run needs the previous run to have finished.
+func scheduledRunAllowed(migration repository.Migration) bool {
+	switch migration.Status {
+	case repository.MigrationStatusReadyToStart:
+		return true
+	case repository.MigrationStatusCompleted, repository.MigrationStatusCompletedWithErrors:
+		return migration.Schedule != ""
+	}
+	return false
+}
+
+// loadMigratedTaskIDs returns the source tasks a migration already created successfully.
+func (s *MigrationService) loadMigratedTaskIDs(migrationID int64) (map[string]bool, error) {
+	mappings, err := s.taskMappingRepo.GetByMigrationIDAndStatus(migrationID, repository.TaskMappingStatusSuccess)
+	if err != nil {
+		return nil, err
+	}
+	migrated := make(map[string]bool, len(mappings))
+	for _, m := range mappings {
+		migrated[m.SourceTaskID] = true
+	}
+	return migrated, nil
+}
+
+// withoutMigrated drops the tasks an earlier run already migrated, so that re-running a
+// migration only creates the source tasks added since.
+func withoutMigrated(tasks []models.Task, migrated map[string]bool) []models.Task {
+	if len(migrated) == 0 {
+		return tasks
+	}
+	kept := tasks[:0]
+	for _, task := range tasks {
+		if !migrated[task.Id] {
+			kept = append(kept, task)
+		}
+	}
+	return kept
+}
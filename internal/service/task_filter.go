@@ -0,0 +1,232 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TWRT/integration-mapper/internal/models"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// Filter rule fields.
+const (
+	FilterFieldCompleted   = "completed"
+	FilterFieldStatus      = "status"
+	FilterFieldTag         = "tag"
+	FilterFieldDueDate     = "due_date"
+	FilterFieldAssignee    = "assignee"
+	FilterFieldCustomField = "custom_field"
+)
+
+// Filter rule operators. Not every operator applies to every field, see validateFilterRules.
+const (
+	FilterOpEquals    = "eq"
+	FilterOpNotEquals = "neq"
+	FilterOpIn        = "in"
+	FilterOpNotIn     = "not_in"
+	FilterOpBefore    = "before"
+	FilterOpAfter     = "after"
+	FilterOpIsEmpty   = "is_empty"
+	FilterOpNotEmpty  = "not_empty"
+)
+
+const filterDateLayout = "2006-01-02"
+
+var filterOperatorsByField = map[string][]string{
+	FilterFieldCompleted:   {FilterOpEquals},
+	FilterFieldStatus:      {FilterOpEquals, FilterOpNotEquals, FilterOpIn, FilterOpNotIn},
+	FilterFieldTag:         {FilterOpIn, FilterOpNotIn, FilterOpIsEmpty, FilterOpNotEmpty},
+	FilterFieldDueDate:     {FilterOpBefore, FilterOpAfter, FilterOpIsEmpty, FilterOpNotEmpty},
+	FilterFieldAssignee:    {FilterOpIn, FilterOpNotIn, FilterOpIsEmpty, FilterOpNotEmpty},
+	FilterFieldCustomField: {FilterOpEquals, FilterOpNotEquals, FilterOpIn, FilterOpNotIn, FilterOpIsEmpty, FilterOpNotEmpty},
+}
+
+// validateFilterRules checks that every rule uses a known field/operator combination
+// and carries the value the operator needs.
+func validateFilterRules(rules []repository.FilterRule) error {
+	for i, r := range rules {
+		ops, ok := filterOperatorsByField[r.Field]
+		if !ok {
+			return fmt.Errorf("%w: filter %d: unknown field %q", ErrInvalidInput, i, r.Field)
+		}
+		supported := false
+		for _, op := range ops {
+			if op == r.Operator {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf("%w: filter %d: operator %q is not supported for field %q", ErrInvalidInput, i, r.Operator, r.Field)
+		}
+
+		switch r.Operator {
+		case FilterOpEquals, FilterOpNotEquals:
+			if r.Value == "" {
+				return fmt.Errorf("%w: filter %d: value is required", ErrInvalidInput, i)
+			}
+		case FilterOpIn, FilterOpNotIn:
+			if len(r.Values) == 0 {
+				return fmt.Errorf("%w: filter %d: values are required", ErrInvalidInput, i)
+			}
+		case FilterOpBefore, FilterOpAfter:
+			if _, err := time.Parse(filterDateLayout, r.Value); err != nil {
+				return fmt.Errorf("%w: filter %d: value must be a date (YYYY-MM-DD)", ErrInvalidInput, i)
+			}
+		}
+
+		if r.Field == FilterFieldCompleted {
+			if _, err := strconv.ParseBool(r.Value); err != nil {
+				return fmt.Errorf("%w: filter %d: value must be true or false", ErrInvalidInput, i)
+			}
+		}
+		if r.Field == FilterFieldCustomField && r.FieldID == "" {
+			return fmt.Errorf("%w: filter %d: field_id is required for custom_field", ErrInvalidInput, i)
+		}
+	}
+	return nil
+}
+
+// matchesFilters reports whether the task satisfies every rule. Rules are assumed to be valid.
+func matchesFilters(task models.Task, rules []repository.FilterRule) bool {
+	for _, r := range rules {
+		if !matchesFilter(task, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitByFilters partitions tasks into the ones to migrate and the number excluded by the rules.
+func splitByFilters(tasks []models.Task, rules []repository.FilterRule) ([]models.Task, int) {
+	if len(rules) == 0 {
+		return tasks, 0
+	}
+	included := make([]models.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if matchesFilters(t, rules) {
+			included = append(included, t)
+		}
+	}
+	return included, len(tasks) - len(included)
+}
+
+func matchesFilter(task models.Task, r repository.FilterRule) bool {
+	switch r.Field {
+	case FilterFieldCompleted:
+		want, _ := strconv.ParseBool(r.Value)
+		return task.Completed == want
+
+	case FilterFieldStatus:
+		return matchValues([]string{task.Status}, r)
+
+	case FilterFieldTag:
+		return matchValues(task.Tags, r)
+
+	case FilterFieldDueDate:
+		switch r.Operator {
+		case FilterOpIsEmpty:
+			return task.DueDate == nil
+		case FilterOpNotEmpty:
+			return task.DueDate != nil
+		}
+		if task.DueDate == nil {
+			return false
+		}
+		due := task.DueDate.Format(filterDateLayout)
+		if r.Operator == FilterOpBefore {
+			return due < r.Value
+		}
+		return due > r.Value
+
+	case FilterFieldAssignee:
+		// Assignees can be referenced by source ID or by email.
+		values := make([]string, 0, len(task.Assignees)*2)
+		for _, a := range task.Assignees {
+			values = append(values, a.ID)
+			if a.Email != "" {
+				values = append(values, a.Email)
+			}
+		}
+		return matchValues(values, r)
+
+	case FilterFieldCustomField:
+		var values []string
+		for _, cf := range task.CustomFields {
+			if cf.FieldID == r.FieldID {
+				values = append(values, customFieldFilterValues(cf.Value)...)
+			}
+		}
+		return matchValues(values, r)
+	}
+	return false
+}
+
+// matchValues applies a set-style operator to the task values, case-insensitively.
+func matchValues(values []string, r repository.FilterRule) bool {
+	nonEmpty := values[:0:0]
+	for _, v := range values {
+		if v != "" {
+			nonEmpty = append(nonEmpty, v)
+		}
+	}
+
+	contains := func(candidates []string) bool {
+		for _, v := range nonEmpty {
+			for _, c := range candidates {
+				if strings.EqualFold(v, c) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	switch r.Operator {
+	case FilterOpEquals:
+		return contains([]string{r.Value})
+	case FilterOpNotEquals:
+		return !contains([]string{r.Value})
+	case FilterOpIn:
+		return contains(r.Values)
+	case FilterOpNotIn:
+		return !contains(r.Values)
+	case FilterOpIsEmpty:
+		return len(nonEmpty) == 0
+	case FilterOpNotEmpty:
+		return len(nonEmpty) > 0
+	}
+	return false
+}
+
+// customFieldFilterValues flattens a raw source custom field value into comparable strings.
+// Label values contribute both option IDs and names.
+func customFieldFilterValues(v interface{}) []string {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{val}
+	case float64:
+		return []string{strconv.FormatFloat(val, 'f', -1, 64)}
+	case bool:
+		return []string{strconv.FormatBool(val)}
+	case []interface{}:
+		var out []string
+		for _, item := range val {
+			out = append(out, customFieldFilterValues(item)...)
+		}
+		return out
+	case map[string]interface{}:
+		var out []string
+		for _, key := range []string{"id", "name", "label"} {
+			if s, ok := val[key].(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return []string{fmt.Sprintf("%v", v)}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// TokenCipher encrypts the OAuth tokens of connections before they are stored, with
+// AES-256-GCM. A stored token is the random nonce followed by the sealed token.
+type TokenCipher struct {
+	aead cipher.AEAD
+}
+
+// NewTokenCipher returns a cipher for a 32-byte key.
+func NewTokenCipher(key []byte) (*TokenCipher, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("token encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create token cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create token cipher: %w", err)
+	}
+	return &TokenCipher{aead: aead}, nil
+}
+
+func (c *TokenCipher) Seal(token string) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, []byte(token), nil), nil
+}
+
+func (c *TokenCipher) Open(sealed []byte) (string, error) {
+	if len(sealed) < c.aead.NonceSize() {
+		return "", errors.New("decrypt token: ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	token, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		// Most likely the data was encrypted with another key.
+		return "", fmt.Errorf("decrypt token: %w", err)
+	}
+	return string(token), nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+type userRepo interface {
+	Create(user *repository.User, apiKeyHash string) (int64, error)
+	GetUser(id int64) (repository.User, error)
+	GetByAPIKeyHash(hash string) (repository.User, error)
+	GetUsers() ([]repository.User, error)
+}
+
+// UserService manages API users and authenticates their API keys.
+type UserService struct {
+	users userRepo
+}
+
+func NewUserService(users userRepo) *UserService {
+	return &UserService{users: users}
+}
+
+// UserServiceProvider is the interface consumed by handlers and the auth middleware.
+// Allows substitution with mocks in tests.
+type UserServiceProvider interface {
+	CreateUser(input CreateUserInput) (repository.User, string, error)
+	Authenticate(apiKey string) (repository.User, error)
+	GetUsers() ([]repository.User, error)
+}
+
+type CreateUserInput struct {
+	Name  string
+	Email string
+	Admin bool
+}
+
+// CreateUser creates a user and returns its API key. Only a hash of the key is stored, so
+// this is the only time the key can be shown.
+func (s *UserService) CreateUser(input CreateUserInput) (repository.User, string, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Email = strings.TrimSpace(input.Email)
+	if input.Name == "" {
+		return repository.User{}, "", fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if _, err := mail.ParseAddress(input.Email); err != nil {
+		return repository.User{}, "", fmt.Errorf("%w: invalid email %q", ErrInvalidInput, input.Email)
+	}
+
+	apiKey, err := newAPIKey()
+	if err != nil {
+		return repository.User{}, "", err
+	}
+	user := repository.User{Name: input.Name, Email: input.Email, Role: repository.UserRoleMember}
+	if input.Admin {
+		user.Role = repository.UserRoleAdmin
+	}
+	id, err := s.users.Create(&user, hashAPIKey(apiKey))
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateUserEmail) {
+			return repository.User{}, "", fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		return repository.User{}, "", err
+	}
+	user, err = s.users.GetUser(id)
+	if err != nil {
+		return repository.User{}, "", err
+	}
+	return user, apiKey, nil
+}
+
+// Authenticate returns the user an API key belongs to.
+func (s *UserService) Authenticate(apiKey string) (repository.User, error) {
+	if apiKey == "" {
+		return repository.User{}, ErrUnauthenticated
+	}
+	user, err := s.users.GetByAPIKeyHash(hashAPIKey(apiKey))
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.User{}, ErrUnauthenticated
+	}
+	return user, err
+}
+
+func (s *UserService) GetUsers() ([]repository.User, error) {
+	return s.users.GetUsers()
+}
+
+func newAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate api key: %w", err)
+	}
+	return "im_" + hex.EncodeToString(b), nil
+}
+
+// hashAPIKey hashes an API key for storage. Keys are random, so a plain SHA-256 is enough
+// and lets a key be looked up by its hash.
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+type userContextKey struct{}
+
+// WithUser returns a context carrying the authenticated user.
+func WithUser(ctx context.Context, user repository.User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the authenticated user set by WithUser.
+func UserFromContext(ctx context.Context) (repository.User, bool) {
+	user, ok := ctx.Value(userContextKey{}).(repository.User)
+	return user, ok
+}
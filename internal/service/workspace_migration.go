@@ -0,0 +1,458 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/TWRT/integration-mapper/internal/client"
+	"github.com/TWRT/integration-mapper/internal/client/asana"
+	"github.com/TWRT/integration-mapper/internal/client/clickup"
+	"github.com/TWRT/integration-mapper/internal/repository"
+)
+
+// How Asana projects are recreated in the destination space.
+const (
+	ProjectStrategyList   = "list"   // one list per project
+	ProjectStrategyFolder = "folder" // one folder per project
+)
+
+// How Asana sections are recreated inside a project's container.
+const (
+	SectionStrategyStatus = "status" // sections share the project's list and become statuses
+	SectionStrategyList   = "list"   // one list per section, in the project's folder
+)
+
+type asanaHierarchyProvider interface {
+	GetProjects(ctx context.Context, workspaceId string) ([]asana.GetMultipleProjectsResponse, error)
+	GetTeamProjects(ctx context.Context, teamId string) ([]asana.GetMultipleProjectsResponse, error)
+	GetSections(ctx context.Context, projectId string) ([]asana.AsanaSection, error)
+}
+
+type clickupHierarchyProvider interface {
+	GetLists(ctx context.Context, spaceId string) ([]clickup.ClickUpList, error)
+	GetFolders(ctx context.Context, spaceId string) ([]clickup.ClickUpFolder, error)
+	CreateFolder(ctx context.Context, spaceId, name string) (*clickup.ClickUpFolder, error)
+	CreateList(ctx context.Context, spaceId, name string) (*clickup.ClickUpList, error)
+	CreateFolderList(ctx context.Context, folderId, name string) (*clickup.ClickUpList, error)
+}
+
+// workspaceMigrator is the part of MigrationService a workspace migration drives.
+type workspaceMigrator interface {
+	CreateBatch(ctx context.Context, input CreateBatchInput) (*BatchState, error)
+	GetBatch(id int64) (*BatchState, error)
+	SaveMappings(ctx context.Context, migrationID int64, assignees []AssigneeMappingInput, tags []TagMappingInput, containerMappings []ContainerMappingInput) (*MappingsState, error)
+}
+
+// WorkspaceMigrationService mirrors Asana workspaces in ClickUp. Its clients hold the
+// server's credentials; runs that name connections swap them out, see withConnections.
+type WorkspaceMigrationService struct {
+	asanaClient      asanaHierarchyProvider
+	clickupClient    clickupHierarchyProvider
+	migrations       workspaceMigrator
+	createdResources createdResourceRepo
+	connections      connectionClients
+}
+
+func NewWorkspaceMigrationService(
+	asanaClient asanaHierarchyProvider,
+	clickupClient clickupHierarchyProvider,
+	migrations workspaceMigrator,
+	createdResources createdResourceRepo,
+	connections connectionClients,
+) *WorkspaceMigrationService {
+	return &WorkspaceMigrationService{
+		asanaClient:      asanaClient,
+		clickupClient:    clickupClient,
+		migrations:       migrations,
+		createdResources: createdResources,
+		connections:      connections,
+	}
+}
+
+// WorkspaceMigrationServiceProvider is the interface consumed by handlers.
+type WorkspaceMigrationServiceProvider interface {
+	CreateWorkspaceMigration(ctx context.Context, input WorkspaceMigrationInput) (*WorkspaceMigrationResult, error)
+}
+
+// WorkspaceMigrationInput selects the Asana projects to mirror and where they go.
+type WorkspaceMigrationInput struct {
+	Name              string
+	SourceWorkspaceID string
+	TeamIDs           []string // only migrate the projects of these teams; empty means the whole workspace
+	DestWorkspaceID   string
+	DestSpaceID       string
+	ProjectStrategy   string
+	SectionStrategy   string
+	Concurrency       int
+	DryRun            bool   // only plan the hierarchy, create nothing
+	OwnerID           *int64 // owns the batch of the workspace migration
+	// SourceConnectionID (Asana) and DestConnectionID (ClickUp) are connections of the
+	// owner used to read the hierarchy, create it and run the batch. Without them the
+	// server's credentials are used, which needs ServerCredentials.
+	SourceConnectionID *int64
+	DestConnectionID   *int64
+	ServerCredentials  bool // see CreateMigrationInput
+}
+
+// PlannedContainer is a destination folder or list of the hierarchy. ID is empty for a
+// container a dry run would create.
+type PlannedContainer struct {
+	ID      string
+	Name    string
+	Exists  bool // the container was already in the space and is reused
+	Created bool
+}
+
+// PlannedList is a destination list and the Asana sections that go into it.
+type PlannedList struct {
+	PlannedContainer
+	Sections []client.Container
+}
+
+// WorkspaceProjectPlan is where one Asana project goes.
+type WorkspaceProjectPlan struct {
+	ProjectID   string
+	ProjectName string
+	Folder      *PlannedContainer // set for the folder project strategy
+	Lists       []PlannedList
+}
+
+// WorkspaceMigrationResult is the planned (or created) hierarchy and, unless it was a dry
+// run, the batch that migrates it.
+type WorkspaceMigrationResult struct {
+	Projects []WorkspaceProjectPlan
+	Batch    *BatchState
+}
+
+// CreateWorkspaceMigration mirrors the projects of an Asana workspace in a ClickUp space.
+// Folders and lists are matched by name and created when missing; the projects then become
+// one batch whose sections are already mapped onto their lists.
+func (s *WorkspaceMigrationService) CreateWorkspaceMigration(ctx context.Context, input WorkspaceMigrationInput) (*WorkspaceMigrationResult, error) {
+	if err := validateWorkspaceMigrationInput(&input); err != nil {
+		return nil, err
+	}
+	s, err := s.withConnections(input)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := s.sourceProjects(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("%w: no projects found to migrate", ErrInvalidInput)
+	}
+
+	plans, err := s.planHierarchy(ctx, input, projects)
+	if err != nil {
+		return nil, err
+	}
+	if input.DryRun {
+		return &WorkspaceMigrationResult{Projects: plans}, nil
+	}
+
+	if err := s.createHierarchy(ctx, input.DestSpaceID, plans); err != nil {
+		return nil, err
+	}
+
+	routes := make([]repository.BatchRoute, 0, len(plans))
+	for _, p := range plans {
+		route := repository.BatchRoute{SourceProjectID: p.ProjectID, DestSpaceID: input.DestSpaceID}
+		if len(p.Lists) > 0 {
+			route.DestListID = p.Lists[0].ID
+		}
+		routes = append(routes, route)
+	}
+	containerStrategy := ContainerStrategyList
+	if input.SectionStrategy == SectionStrategyStatus {
+		containerStrategy = ContainerStrategyStatus
+	}
+	batch, err := s.migrations.CreateBatch(ctx, CreateBatchInput{
+		Name:              input.Name,
+		Source:            asana.Name,
+		Destination:       clickup.Name,
+		DestWorkspaceID:   input.DestWorkspaceID,
+		Concurrency:       input.Concurrency,
+		Routes:            routes,
+		ContainerStrategy: containerStrategy,
+		OwnerID:           input.OwnerID,
+
+		SourceConnectionID: input.SourceConnectionID,
+		DestConnectionID:   input.DestConnectionID,
+		ServerCredentials:  input.ServerCredentials,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create batch: %w", err)
+	}
+
+	if err := s.mapSections(ctx, batch, plans); err != nil {
+		return nil, err
+	}
+	batch, err = s.migrations.GetBatch(batch.Batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &WorkspaceMigrationResult{Projects: plans, Batch: batch}, nil
+}
+
+func validateWorkspaceMigrationInput(input *WorkspaceMigrationInput) error {
+	switch {
+	case input.SourceWorkspaceID == "":
+		return fmt.Errorf("%w: source_workspace_id is required", ErrInvalidInput)
+	case input.DestWorkspaceID == "":
+		return fmt.Errorf("%w: dest_workspace_id is required", ErrInvalidInput)
+	case input.DestSpaceID == "":
+		return fmt.Errorf("%w: dest_space_id is required", ErrInvalidInput)
+	}
+	if input.ProjectStrategy == "" {
+		input.ProjectStrategy = ProjectStrategyList
+	}
+	if input.SectionStrategy == "" {
+		input.SectionStrategy = SectionStrategyStatus
+	}
+	if input.ProjectStrategy != ProjectStrategyList && input.ProjectStrategy != ProjectStrategyFolder {
+		return fmt.Errorf("%w: unknown project strategy %q", ErrInvalidInput, input.ProjectStrategy)
+	}
+	if input.SectionStrategy != SectionStrategyStatus && input.SectionStrategy != SectionStrategyList {
+		return fmt.Errorf("%w: unknown section strategy %q", ErrInvalidInput, input.SectionStrategy)
+	}
+	if input.SectionStrategy == SectionStrategyList && input.ProjectStrategy != ProjectStrategyFolder {
+		return fmt.Errorf("%w: sections can only become lists when projects become folders", ErrInvalidInput)
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		input.Name = "Workspace " + input.SourceWorkspaceID
+	}
+	return nil
+}
+
+// withConnections returns a copy of s that reads and creates the hierarchy through the
+// connections of the input, where it names them. The server's clients are only kept for
+// callers allowed to use its credentials.
+func (s *WorkspaceMigrationService) withConnections(input WorkspaceMigrationInput) (*WorkspaceMigrationService, error) {
+	if !input.ServerCredentials {
+		switch {
+		case input.SourceConnectionID == nil:
+			return nil, fmt.Errorf("%w: source_connection_id is required", ErrInvalidInput)
+		case input.DestConnectionID == nil:
+			return nil, fmt.Errorf("%w: dest_connection_id is required", ErrInvalidInput)
+		}
+	}
+	run := *s
+	if input.SourceConnectionID != nil {
+		c, err := s.connectionClient(*input.SourceConnectionID, input.OwnerID, asana.Name)
+		if err != nil {
+			return nil, err
+		}
+		p, ok := c.(asanaHierarchyProvider)
+		if !ok {
+			return nil, fmt.Errorf("%w: asana connection cannot list the workspace hierarchy", ErrUnsupported)
+		}
+		run.asanaClient = p
+	}
+	if input.DestConnectionID != nil {
+		c, err := s.connectionClient(*input.DestConnectionID, input.OwnerID, clickup.Name)
+		if err != nil {
+			return nil, err
+		}
+		p, ok := c.(clickupHierarchyProvider)
+		if !ok {
+			return nil, fmt.Errorf("%w: clickup connection cannot create folders and lists", ErrUnsupported)
+		}
+		run.clickupClient = p
+	}
+	return &run, nil
+}
+
+func (s *WorkspaceMigrationService) connectionClient(connectionID int64, ownerID *int64, provider string) (client.IntegrationProvider, error) {
+	if err := s.connections.CheckConnection(connectionID, ownerID, provider); err != nil {
+		return nil, err
+	}
+	return s.connections.ConnectionClient(connectionID, provider)
+}
+
+// sourceProjects lists the projects of the selected teams, or of the whole workspace.
+func (s *WorkspaceMigrationService) sourceProjects(ctx context.Context, input WorkspaceMigrationInput) ([]asana.GetMultipleProjectsResponse, error) {
+	if len(input.TeamIDs) == 0 {
+		projects, err := s.asanaClient.GetProjects(ctx, input.SourceWorkspaceID)
+		if err != nil {
+			return nil, fmt.Errorf("get projects: %w", err)
+		}
+		return projects, nil
+	}
+
+	var projects []asana.GetMultipleProjectsResponse
+	seen := make(map[string]bool)
+	for _, teamID := range input.TeamIDs {
+		teamProjects, err := s.asanaClient.GetTeamProjects(ctx, teamID)
+		if err != nil {
+			return nil, fmt.Errorf("get projects of team %s: %w", teamID, err)
+		}
+		for _, p := range teamProjects {
+			if !seen[p.Id] {
+				seen[p.Id] = true
+				projects = append(projects, p)
+			}
+		}
+	}
+	return projects, nil
+}
+
+// planHierarchy decides the destination container of every project and section, reusing
+// the folders and lists of the space that already carry the right name.
+func (s *WorkspaceMigrationService) planHierarchy(ctx context.Context, input WorkspaceMigrationInput, projects []asana.GetMultipleProjectsResponse) ([]WorkspaceProjectPlan, error) {
+	spaceLists, err := s.clickupClient.GetLists(ctx, input.DestSpaceID)
+	if err != nil {
+		return nil, fmt.Errorf("get lists: %w", err)
+	}
+	folders, err := s.clickupClient.GetFolders(ctx, input.DestSpaceID)
+	if err != nil {
+		return nil, fmt.Errorf("get folders: %w", err)
+	}
+
+	plans := make([]WorkspaceProjectPlan, 0, len(projects))
+	for _, p := range projects {
+		sections, err := s.asanaClient.GetSections(ctx, p.Id)
+		if err != nil {
+			return nil, fmt.Errorf("get sections of project %s: %w", p.Name, err)
+		}
+		containers := make([]client.Container, len(sections))
+		for i, sec := range sections {
+			containers[i] = client.Container{ID: sec.Gid, Name: sec.Name}
+		}
+
+		plan := WorkspaceProjectPlan{ProjectID: p.Id, ProjectName: p.Name}
+		if input.ProjectStrategy == ProjectStrategyList {
+			plan.Lists = []PlannedList{{PlannedContainer: plannedList(spaceLists, p.Name), Sections: containers}}
+			plans = append(plans, plan)
+			continue
+		}
+
+		folder := PlannedContainer{Name: p.Name}
+		var folderLists []clickup.ClickUpList
+		for _, f := range folders {
+			if f.Name == p.Name {
+				folder.ID, folder.Exists = f.Id, true
+				folderLists = f.Lists
+				break
+			}
+		}
+		plan.Folder = &folder
+		if input.SectionStrategy == SectionStrategyStatus {
+			plan.Lists = []PlannedList{{PlannedContainer: plannedList(folderLists, p.Name), Sections: containers}}
+		} else {
+			for _, c := range containers {
+				plan.Lists = append(plan.Lists, PlannedList{PlannedContainer: plannedList(folderLists, c.Name), Sections: []client.Container{c}})
+			}
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+func plannedList(existing []clickup.ClickUpList, name string) PlannedContainer {
+	for _, l := range existing {
+		if l.Name == name {
+			return PlannedContainer{ID: l.Id, Name: name, Exists: true}
+		}
+	}
+	return PlannedContainer{Name: name}
+}
+
+// createHierarchy creates the planned folders and lists that do not exist yet. Projects of
+// the same name share the containers created for the first of them.
+func (s *WorkspaceMigrationService) createHierarchy(ctx context.Context, spaceID string, plans []WorkspaceProjectPlan) error {
+	createdFolders := make(map[string]string)     // name → ID
+	createdSpaceLists := make(map[string]string)  // name → ID
+	createdFolderLists := make(map[string]string) // folder ID + "/" + name → ID
+	for i := range plans {
+		p := &plans[i]
+		if p.Folder != nil && p.Folder.ID == "" {
+			if id, ok := createdFolders[p.Folder.Name]; ok {
+				p.Folder.ID, p.Folder.Exists = id, true
+			} else {
+				folder, err := s.clickupClient.CreateFolder(ctx, spaceID, p.Folder.Name)
+				if err != nil {
+					return fmt.Errorf("create folder %s: %w", p.Folder.Name, err)
+				}
+				p.Folder.ID, p.Folder.Created = folder.Id, true
+				createdFolders[p.Folder.Name] = folder.Id
+			}
+		}
+
+		for j := range p.Lists {
+			l := &p.Lists[j]
+			if l.ID != "" {
+				continue
+			}
+			if p.Folder == nil {
+				if id, ok := createdSpaceLists[l.Name]; ok {
+					l.ID, l.Exists = id, true
+					continue
+				}
+				list, err := s.clickupClient.CreateList(ctx, spaceID, l.Name)
+				if err != nil {
+					return fmt.Errorf("create list %s: %w", l.Name, err)
+				}
+				l.ID, l.Created = list.Id, true
+				createdSpaceLists[l.Name] = list.Id
+				continue
+			}
+			key := p.Folder.ID + "/" + l.Name
+			if id, ok := createdFolderLists[key]; ok {
+				l.ID, l.Exists = id, true
+				continue
+			}
+			list, err := s.clickupClient.CreateFolderList(ctx, p.Folder.ID, l.Name)
+			if err != nil {
+				return fmt.Errorf("create list %s in folder %s: %w", l.Name, p.Folder.Name, err)
+			}
+			l.ID, l.Created = list.Id, true
+			createdFolderLists[key] = list.Id
+		}
+	}
+	return nil
+}
+
+// mapSections maps every section of the batch's migrations onto its planned list, and
+// records the lists created for a project so rolling its migration back can remove them.
+func (s *WorkspaceMigrationService) mapSections(ctx context.Context, batch *BatchState, plans []WorkspaceProjectPlan) error {
+	byProject := make(map[string]WorkspaceProjectPlan, len(plans))
+	for _, p := range plans {
+		byProject[p.ProjectID] = p
+	}
+
+	for _, m := range batch.Migrations {
+		plan, ok := byProject[m.SourceProjectID]
+		if !ok {
+			continue
+		}
+		var inputs []ContainerMappingInput
+		for _, l := range plan.Lists {
+			destID, destName := l.ID, l.Name
+			for _, sec := range l.Sections {
+				inputs = append(inputs, ContainerMappingInput{SourceID: sec.ID, DestID: &destID, DestName: &destName, Enabled: true})
+			}
+			if l.Created {
+				err := s.createdResources.Create(&repository.CreatedResource{
+					MigrationID: m.ID,
+					Type:        repository.CreatedResourceTypeContainer,
+					ResourceID:  l.ID,
+					Name:        l.Name,
+				})
+				if err != nil {
+					slog.Warn("could not record created list", "migration_id", m.ID, "list", l.Name, "error", err)
+				}
+			}
+		}
+		if len(inputs) == 0 {
+			continue
+		}
+		if _, err := s.migrations.SaveMappings(ctx, m.ID, nil, nil, inputs); err != nil {
+			return fmt.Errorf("map sections of project %s: %w", plan.ProjectName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,388 @@
+// Package yaml converts between JSON and the subset of YAML that configuration documents
+// need: block mappings and sequences of plain, quoted and flow (JSON) scalars. Anchors, tags
+// and multi-line block scalars are not supported. Documents go through JSON so the json
+// struct tags of the types apply.
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	plainPattern  = regexp.MustCompile(`^[A-Za-z_/(][A-Za-z0-9_ ./@()+-]*$`)
+	numberPattern = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)([eE][-+]?\d+)?$`)
+)
+
+// jsonNode is a JSON value that keeps the key order of objects.
+type jsonNode struct {
+	keys   []string    // object keys, in document order
+	values []*jsonNode // object values or array items
+	array  bool
+	object bool
+	scalar any // string, json.Number, bool or nil
+}
+
+// FromJSON renders a JSON document as block-style YAML, keeping the order of object keys.
+func FromJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := decodeNode(dec)
+	if err != nil {
+		return nil, fmt.Errorf("parse json: %w", err)
+	}
+	var out strings.Builder
+	switch {
+	case (root.object || root.array) && len(root.values) > 0:
+		writeBlock(&out, root, 0)
+	default:
+		out.WriteString(inline(root) + "\n")
+	}
+	return []byte(out.String()), nil
+}
+
+func decodeNode(dec *json.Decoder) (*jsonNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		node := &jsonNode{object: t == '{', array: t == '['}
+		for dec.More() {
+			if node.object {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.keys = append(node.keys, keyTok.(string))
+			}
+			child, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.values = append(node.values, child)
+		}
+		if _, err := dec.Token(); err != nil { // closing delimiter
+			return nil, err
+		}
+		return node, nil
+	default:
+		return &jsonNode{scalar: t}, nil
+	}
+}
+
+func writeBlock(out *strings.Builder, node *jsonNode, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for i, child := range node.values {
+		prefix := pad + "- "
+		if node.object {
+			prefix = pad + quoteString(node.keys[i]) + ":"
+		}
+		nested := (child.object || child.array) && len(child.values) > 0
+		switch {
+		case !nested && node.object:
+			out.WriteString(prefix + " " + inline(child) + "\n")
+		case !nested:
+			out.WriteString(prefix + inline(child) + "\n")
+		case node.object:
+			out.WriteString(prefix + "\n")
+			writeBlock(out, child, indent+2)
+		case child.object:
+			// The first key of an object in a sequence goes on the dash line.
+			var item strings.Builder
+			writeBlock(&item, child, indent+2)
+			out.WriteString(prefix + strings.TrimPrefix(item.String(), pad+"  "))
+		default:
+			out.WriteString(pad + "-\n")
+			writeBlock(out, child, indent+2)
+		}
+	}
+}
+
+func inline(node *jsonNode) string {
+	switch {
+	case node.object:
+		return "{}"
+	case node.array:
+		return "[]"
+	}
+	switch v := node.scalar.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return quoteString(v)
+	}
+	return fmt.Sprint(node.scalar)
+}
+
+// quoteString writes a string plain when no YAML reader could take it for anything else,
+// and double-quoted (JSON escaping is valid YAML) otherwise.
+func quoteString(s string) string {
+	if plainPattern.MatchString(s) && !strings.HasSuffix(s, " ") && !isReserved(s) {
+		return s
+	}
+	b, _ := json.Marshal(s) //nolint:errcheck // strings always marshal
+	return string(b)
+}
+
+func isReserved(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~":
+		return true
+	}
+	return false
+}
+
+// ---- Parsing ----
+
+type srcLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+type parser struct {
+	lines []srcLine
+	pos   int
+}
+
+// ToJSON converts a YAML document into JSON. A document that already is JSON is
+// returned unchanged.
+func ToJSON(data []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return trimmed, nil
+	}
+
+	p := &parser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if strings.Contains(raw, "\t") && strings.TrimLeft(raw, "\t ") != strings.TrimLeft(raw, " ") {
+			return nil, fmt.Errorf("yaml line %d: tabs cannot be used for indentation", i+1)
+		}
+		text := stripComment(raw)
+		content := strings.TrimSpace(text)
+		if content == "" || content == "---" || content == "..." {
+			continue
+		}
+		p.lines = append(p.lines, srcLine{num: i + 1, indent: len(text) - len(strings.TrimLeft(text, " ")), text: content})
+	}
+	if len(p.lines) == 0 {
+		return []byte("null"), nil
+	}
+	if first := p.lines[0]; len(p.lines) == 1 && !isSeqItem(first.text) && keyEnd(first.text) == -1 {
+		value, err := parseScalar(first.text, first.num)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+	}
+
+	value, err := p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("yaml line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return json.Marshal(value)
+}
+
+// stripComment removes a trailing "# comment" that is not inside a quoted scalar.
+// Quotes only open a scalar at its start, so apostrophes in plain text are left alone.
+func stripComment(line string) string {
+	var quote, prev rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case (r == '"' || r == '\'') && (prev == 0 || strings.ContainsRune(":-,[{", prev)):
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' '):
+			return strings.TrimRight(line[:i], " ")
+		}
+		if r != ' ' {
+			prev = r
+		}
+	}
+	return strings.TrimRight(line, " ")
+}
+
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *parser) parseBlock(indent int) (any, error) {
+	if isSeqItem(p.lines[p.pos].text) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+func (p *parser) parseSequence(indent int) ([]any, error) {
+	items := []any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isSeqItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		rest := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		switch {
+		case rest == "":
+			p.pos++
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				item, err := p.parseBlock(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			} else {
+				items = append(items, nil)
+			}
+		case isSeqItem(rest) || keyEnd(rest) != -1:
+			// A nested sequence or mapping starting on the dash line: reparse the rest of the
+			// line as the first line of a block indented to where it starts.
+			p.lines[p.pos] = srcLine{num: line.num, indent: indent + len(line.text) - len(rest), text: rest}
+			item, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		default:
+			value, err := parseScalar(rest, line.num)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+			p.pos++
+		}
+	}
+	return items, nil
+}
+
+func (p *parser) parseMapping(indent int) (map[string]any, error) {
+	m := map[string]any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		if isSeqItem(line.text) {
+			return nil, fmt.Errorf("yaml line %d: expected a key, found a list item", line.num)
+		}
+		end := keyEnd(line.text)
+		if end == -1 {
+			return nil, fmt.Errorf("yaml line %d: expected \"key: value\"", line.num)
+		}
+		keyValue, err := parseScalar(strings.TrimSpace(line.text[:end]), line.num)
+		if err != nil {
+			return nil, err
+		}
+		key := fmt.Sprint(keyValue)
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("yaml line %d: duplicate key %q", line.num, key)
+		}
+		rest := strings.TrimSpace(line.text[end+1:])
+		p.pos++
+
+		switch {
+		case rest != "":
+			value, err := parseScalar(rest, line.num)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		case p.pos < len(p.lines) && p.lines[p.pos].indent > indent:
+			value, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		case p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isSeqItem(p.lines[p.pos].text):
+			// Sequences may sit at the indentation of their key.
+			value, err := p.parseSequence(indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		default:
+			m[key] = nil
+		}
+	}
+	return m, nil
+}
+
+// keyEnd returns the index of the colon ending the key of a "key: value" line, or -1.
+func keyEnd(text string) int {
+	if text == "" || text[0] == '{' || text[0] == '[' {
+		return -1
+	}
+	var quote rune
+	for i, r := range text {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case i == 0 && (r == '"' || r == '\''):
+			quote = r
+		case r == ':' && (i == len(text)-1 || text[i+1] == ' '):
+			return i
+		}
+	}
+	return -1
+}
+
+func parseScalar(s string, lineNum int) (any, error) {
+	switch {
+	case s == "":
+		return nil, nil
+	case s[0] == '|' || s[0] == '>':
+		return nil, fmt.Errorf("yaml line %d: block scalars are not supported, use a quoted string", lineNum)
+	case s[0] == '&' || s[0] == '*' || s[0] == '!':
+		return nil, fmt.Errorf("yaml line %d: anchors, aliases and tags are not supported", lineNum)
+	case s[0] == '"':
+		var v string
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, fmt.Errorf("yaml line %d: invalid double-quoted string", lineNum)
+		}
+		return v, nil
+	case s[0] == '\'':
+		if len(s) < 2 || s[len(s)-1] != '\'' {
+			return nil, fmt.Errorf("yaml line %d: invalid single-quoted string", lineNum)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case s[0] == '{' || s[0] == '[':
+		var v any
+		dec := json.NewDecoder(strings.NewReader(s))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("yaml line %d: only JSON-compatible flow collections are supported", lineNum)
+		}
+		return v, nil
+	}
+	switch strings.ToLower(s) {
+	case "null", "~":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if numberPattern.MatchString(s) {
+		if json.Valid([]byte(s)) {
+			return json.Number(s), nil
+		}
+		// Forms JSON lacks, such as "+1", ".5" or "1.".
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("yaml line %d: invalid number %q", lineNum, s)
+		}
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+	}
+	return s, nil
+}
@@ -0,0 +1,196 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// roundTrip renders a JSON document as YAML and parses it back, failing the test when the
+// result differs from the original.
+func roundTrip(t *testing.T, doc string) string {
+	t.Helper()
+	out, err := FromJSON([]byte(doc))
+	if err != nil {
+		t.Fatalf("FromJSON(%s): %v", doc, err)
+	}
+	back, err := ToJSON(out)
+	if err != nil {
+		t.Fatalf("ToJSON of\n%s: %v", out, err)
+	}
+	if !jsonEqual(t, []byte(doc), back) {
+		t.Errorf("round trip of %s\nYAML:\n%s\ngave %s", doc, out, back)
+	}
+	return string(out)
+}
+
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var va, vb any
+	for _, x := range []struct {
+		data []byte
+		v    *any
+	}{{a, &va}, {b, &vb}} {
+		dec := json.NewDecoder(bytes.NewReader(x.data))
+		dec.UseNumber()
+		if err := dec.Decode(x.v); err != nil {
+			t.Fatalf("decode %s: %v", x.data, err)
+		}
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func TestRoundTripReservedWords(t *testing.T) {
+	for _, word := range []string{"true", "false", "True", "yes", "No", "ON", "off", "y", "N", "null", "Null", "~"} {
+		out := roundTrip(t, `{"value": "`+word+`"}`)
+		if !strings.Contains(out, `"`+word+`"`) {
+			t.Errorf("%q was not quoted:\n%s", word, out)
+		}
+	}
+	roundTrip(t, `{"yes": true, "no": false, "null": null}`)
+}
+
+func TestRoundTripNumericLookingStrings(t *testing.T) {
+	for _, s := range []string{"123", "007", "-1", "+1", "1.5", ".5", "1.", "1e3", "0x1F", "1_000", "12:30", "2024-01-31"} {
+		out := roundTrip(t, `{"id": "`+s+`"}`)
+		if !strings.Contains(out, `"`+s+`"`) {
+			t.Errorf("%q was not quoted:\n%s", s, out)
+		}
+	}
+	roundTrip(t, `{"int": 42, "negative": -7, "float": 1.25, "exp": 1e-9, "big": 12345678901234567890}`)
+}
+
+func TestRoundTripSpecialCharacters(t *testing.T) {
+	for _, s := range []string{
+		"key: value",
+		"trailing:",
+		"a # not a comment",
+		"#hashtag",
+		"it's",
+		`say "hi"`,
+		"- dash",
+		"[bracket]",
+		"{brace}",
+		"&anchor",
+		"*alias",
+		"!tag",
+		"|pipe",
+		">fold",
+		"line\nbreak",
+		"tab\there",
+		" leading space",
+		"trailing space ",
+		"",
+		"ünïcødé ✓",
+		"a,b",
+		"100%",
+	} {
+		b, _ := json.Marshal(s)
+		roundTrip(t, `{"value": `+string(b)+`, `+string(b)+`: "key"}`)
+	}
+}
+
+func TestRoundTripEmptyCollections(t *testing.T) {
+	for _, doc := range []string{
+		`{}`,
+		`[]`,
+		`{"object": {}, "array": [], "nested": {"inner": {}}}`,
+		`[{}, [], {"a": []}]`,
+		`{"list": [{}, {"a": {}}]}`,
+	} {
+		roundTrip(t, doc)
+	}
+}
+
+func TestRoundTripNestedSequences(t *testing.T) {
+	for _, doc := range []string{
+		`[[1, 2], [3], []]`,
+		`{"matrix": [[["deep"]], [[]], [["a", "b"], ["c"]]]}`,
+		`[[{"a": 1, "b": [2, 3]}, {"c": null}], [[{"d": "e"}]]]`,
+		`{"items": [{"name": "x", "tags": ["a", "b"], "sub": [{"k": [1]}]}]}`,
+	} {
+		roundTrip(t, doc)
+	}
+}
+
+func TestRoundTripScalarDocuments(t *testing.T) {
+	for _, doc := range []string{`"text"`, `"true"`, `12`, `true`, `null`} {
+		roundTrip(t, doc)
+	}
+}
+
+func TestFromJSONKeepsKeyOrder(t *testing.T) {
+	out, err := FromJSON([]byte(`{"version": 1, "assignees": [{"source": "a@x.com", "dest": "b@y.com"}], "b": {}, "a": "z"}`))
+	if err != nil {
+		t.Fatalf("FromJSON: %v", err)
+	}
+	want := `version: 1
+assignees:
+  - source: a@x.com
+    dest: b@y.com
+b: {}
+a: z
+`
+	if string(out) != want {
+		t.Errorf("FromJSON =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"comments", "# header\na: 1 # trailing\nb: 'x # kept'\nc: \"y # kept\"\n", `{"a": 1, "b": "x # kept", "c": "y # kept"}`},
+		{"plain text with apostrophe", "name: Ann's list\n", `{"name": "Ann's list"}`},
+		{"single quotes", "a: 'it''s'\n", `{"a": "it's"}`},
+		{"sequence at key indentation", "list:\n- a\n- b\nnext: 1\n", `{"list": ["a", "b"], "next": 1}`},
+		{"flow collections", "a: [1, \"two\"]\nb: {\"c\": true}\n", `{"a": [1, "two"], "b": {"c": true}}`},
+		{"null values", "a:\nb: ~\nc: null\n", `{"a": null, "b": null, "c": null}`},
+		{"yaml numbers", "a: +1\nb: .5\nc: 1.\n", `{"a": 1, "b": 0.5, "c": 1}`},
+		{"document markers", "---\na: 1\n...\n", `{"a": 1}`},
+		{"windows line endings", "a: 1\r\nb:\r\n  - x\r\n", `{"a": 1, "b": ["x"]}`},
+		{"json input", ` {"a": [1]} `, `{"a": [1]}`},
+		{"empty document", "# nothing\n", `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToJSON([]byte(tt.yaml))
+			if err != nil {
+				t.Fatalf("ToJSON: %v", err)
+			}
+			if !jsonEqual(t, got, []byte(tt.want)) {
+				t.Errorf("ToJSON = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"tab indentation", "a:\n\t- b\n", "line 2: tabs cannot be used for indentation"},
+		{"block scalar", "a: |\n  text\n", "line 1: block scalars are not supported"},
+		{"anchor", "a: &x 1\n", "line 1: anchors, aliases and tags are not supported"},
+		{"duplicate key", "a: 1\na: 2\n", `line 2: duplicate key "a"`},
+		{"missing colon", "a: 1\njust text\n", `line 2: expected "key: value"`},
+		{"list item in mapping", "a: 1\n- b\n", "line 2: expected a key, found a list item"},
+		{"bad indentation", "a:\n    b: 1\n  c: 2\n", "line 3: unexpected indentation"},
+		{"invalid flow", "a: [1, 2\n", "line 1: only JSON-compatible flow collections are supported"},
+		{"unterminated quote", "a: 'x\n", "line 1: invalid single-quoted string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ToJSON([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ToJSON err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
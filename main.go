@@ -7,11 +7,11 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
 	"github.com/TWRT/integration-mapper/internal/api"
+	"github.com/TWRT/integration-mapper/internal/app"
 	"github.com/TWRT/integration-mapper/internal/repository"
 	"github.com/joho/godotenv"
 )
@@ -22,14 +22,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	asanaToken := os.Getenv("ASANA_TOKEN")
-	clickUpToken := os.Getenv("CLICKUP_TOKEN")
-	if asanaToken == "" || clickUpToken == "" {
-		slog.Error("ASANA_TOKEN and CLICKUP_TOKEN must be set")
+	cfg, err := app.ConfigFromEnv()
+	if err != nil {
+		slog.Error("invalid configuration", "err", err)
 		os.Exit(1)
 	}
 
-	db, err := repository.InitDB("./migrator.db")
+	db, err := repository.InitDB(cfg.DBPath)
 	if err != nil {
 		slog.Error("failed to initialize database", "err", err)
 		os.Exit(1)
@@ -37,8 +36,21 @@ func main() {
 	defer db.Close()
 	slog.Info("database initialized")
 
-	allowedOrigins := strings.Split(os.Getenv("ALLOWED_ORIGINS"), ",")
-	router := api.SetupRouter(db, asanaToken, clickUpToken, allowedOrigins)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	svcs := app.NewServices(db, cfg)
+	go svcs.Migrations.RunScheduler(ctx)
+	workersDone := make(chan struct{})
+	go func() {
+		defer close(workersDone)
+		svcs.Migrations.RunWorkers(ctx, cfg.MaxConcurrentMigrations)
+	}()
+	if err := svcs.Migrations.ResumeBatches(ctx); err != nil {
+		slog.Error("failed to resume migration batches", "err", err)
+	}
+
+	router := api.SetupRouter(svcs, cfg)
 
 	server := &http.Server{
 		Addr:              ":8080",
@@ -49,9 +61,6 @@ func main() {
 		IdleTimeout:       120 * time.Second,
 	}
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
-
 	go func() {
 		slog.Info("server starting", "addr", server.Addr)
 		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -72,4 +81,12 @@ func main() {
 	} else {
 		slog.Info("server stopped cleanly")
 	}
+
+	// Running migrations stop before their next task and go back to the queue.
+	select {
+	case <-workersDone:
+		slog.Info("migration workers stopped")
+	case <-shutdownCtx.Done():
+		slog.Error("migration workers did not stop in time")
+	}
 }